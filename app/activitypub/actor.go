@@ -0,0 +1,136 @@
+package activitypub
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/profile"
+	"html"
+)
+
+const keyBits = 2048
+
+const actorNotFoundErrorText = "actor not found"
+
+var actorNotFoundError = jerr.New(actorNotFoundErrorText)
+
+func IsActorNotFoundError(err error) bool {
+	return jerr.HasError(err, actorNotFoundErrorText)
+}
+
+// GetActor returns an actor not found error for addresses that have never signed a tx. The public key is left out
+// until the actor has a key, which happens the first time an activity is delivered for it.
+func GetActor(baseUrl string, pkHash []byte) (*Actor, error) {
+	_, err := db.GetPublicKeyForPkHash(pkHash)
+	if err != nil {
+		if db.IsRecordNotFoundError(err) {
+			return nil, jerr.Get("error address has no on-chain activity", actorNotFoundError)
+		}
+		return nil, jerr.Get("error getting public key for pk hash", err)
+	}
+	pf, err := profile.GetProfile(pkHash, nil)
+	if err != nil {
+		return nil, jerr.Get("error getting profile", err)
+	}
+	activityPubKey, err := db.GetActivityPubKey(pkHash)
+	if err != nil && ! db.IsRecordNotFoundError(err) {
+		return nil, jerr.Get("error getting activity pub key", err)
+	}
+	address := wallet.GetAddressFromPkHash(pkHash).GetEncoded()
+	actorId := GetActorId(baseUrl, address)
+	var actor = &Actor{
+		Context:           []string{ActivityStreamsNs, SecurityNs},
+		Id:                actorId,
+		Type:              TypePerson,
+		PreferredUsername: address,
+		Name:              html.UnescapeString(pf.Name),
+		Summary:           pf.Profile,
+		Url:               getProfileUrl(baseUrl, address),
+		Inbox:             GetInboxUrl(baseUrl, address),
+		Outbox:            GetOutboxUrl(baseUrl, address),
+		Followers:         GetFollowersUrl(baseUrl, address),
+		Following:         GetFollowingUrl(baseUrl, address),
+	}
+	if activityPubKey != nil {
+		actor.PublicKey = &PublicKey{
+			Id:           GetKeyId(baseUrl, address),
+			Owner:        actorId,
+			PublicKeyPem: activityPubKey.PublicKey,
+		}
+	}
+	if pf.Pic != nil {
+		actor.Icon = &Image{
+			Type: TypeImage,
+			Url:  pf.Pic.Url,
+		}
+	}
+	return actor, nil
+}
+
+// Keys are generated the first time an activity is delivered for an actor, such as accepting a follow.
+func getKey(pkHash []byte) (*db.ActivityPubKey, error) {
+	activityPubKey, err := db.GetActivityPubKey(pkHash)
+	if err == nil {
+		return activityPubKey, nil
+	}
+	if ! db.IsRecordNotFoundError(err) {
+		return nil, jerr.Get("error getting activity pub key", err)
+	}
+	privateKey, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		return nil, jerr.Get("error generating rsa key", err)
+	}
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, jerr.Get("error marshalling public key", err)
+	}
+	privateKeyPem := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	})
+	publicKeyPem := pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: publicKeyBytes,
+	})
+	activityPubKey, err = db.AddActivityPubKey(pkHash, string(privateKeyPem), string(publicKeyPem))
+	if err != nil {
+		return nil, jerr.Get("error saving activity pub key", err)
+	}
+	return activityPubKey, nil
+}
+
+func getPrivateKey(pkHash []byte) (*rsa.PrivateKey, error) {
+	activityPubKey, err := getKey(pkHash)
+	if err != nil {
+		return nil, jerr.Get("error getting actor key", err)
+	}
+	block, _ := pem.Decode([]byte(activityPubKey.PrivateKey))
+	if block == nil {
+		return nil, jerr.New("error decoding private key pem")
+	}
+	privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, jerr.Get("error parsing private key", err)
+	}
+	return privateKey, nil
+}
+
+func parsePublicKey(publicKeyPem string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(publicKeyPem))
+	if block == nil {
+		return nil, jerr.New("error decoding public key pem")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, jerr.Get("error parsing public key", err)
+	}
+	rsaPublicKey, ok := publicKey.(*rsa.PublicKey)
+	if ! ok {
+		return nil, jerr.New("public key is not rsa")
+	}
+	return rsaPublicKey, nil
+}
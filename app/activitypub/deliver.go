@@ -0,0 +1,89 @@
+package activitypub
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"github.com/memocash/memo/app/config"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/profile"
+	"net/http"
+)
+
+// Deliver posts an activity to a remote inbox, signed with the address's actor key.
+func Deliver(baseUrl string, pkHash []byte, inbox string, activity *Activity) error {
+	if ! isHttpsUrl(inbox) {
+		return jerr.New("inbox is not an https url")
+	}
+	activity.Context = ActivityStreamsNs
+	body, err := json.Marshal(activity)
+	if err != nil {
+		return jerr.Get("error marshalling activity", err)
+	}
+	privateKey, err := getPrivateKey(pkHash)
+	if err != nil {
+		return jerr.Get("error getting actor private key", err)
+	}
+	request, err := http.NewRequest(http.MethodPost, inbox, bytes.NewReader(body))
+	if err != nil {
+		return jerr.Get("error creating inbox request", err)
+	}
+	request.Header.Set("Content-Type", ContentType)
+	err = SignRequest(request, GetKeyId(baseUrl, wallet.GetAddressFromPkHash(pkHash).GetEncoded()), privateKey, body)
+	if err != nil {
+		return jerr.Get("error signing inbox request", err)
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return jerr.Get("error posting to inbox", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return jerr.Newf("unexpected inbox response code: %d", response.StatusCode)
+	}
+	return nil
+}
+
+// DeliverPost sends a new post to the author's remote followers in the background. Ids need a public url which isn't available outside
+// of a request, so nothing is sent unless ACTIVITYPUB_BASE_URL is set. Posts hidden by moderation, including all posts from a
+// banned address, are not sent.
+func DeliverPost(memoPost *db.MemoPost) error {
+	baseUrl := config.GetActivityPubBaseUrl()
+	if baseUrl == "" {
+		return nil
+	}
+	hidden, err := profile.IsPostHidden(memoPost)
+	if err != nil {
+		return jerr.Get("error checking if post hidden", err)
+	}
+	if hidden {
+		return nil
+	}
+	remoteFollowers, err := db.GetActivityPubFollowers(memoPost.PkHash)
+	if err != nil {
+		return jerr.Get("error getting remote followers", err)
+	}
+	if len(remoteFollowers) == 0 {
+		return nil
+	}
+	post, err := profile.GetPostByTxHash(memoPost.TxHash, nil)
+	if err != nil {
+		return jerr.Get("error getting post", err)
+	}
+	activity := getCreateActivity(getNote(baseUrl, post))
+	go func() {
+		var sentInboxes = make(map[string]bool)
+		for _, remoteFollower := range remoteFollowers {
+			if sentInboxes[remoteFollower.Inbox] {
+				continue
+			}
+			sentInboxes[remoteFollower.Inbox] = true
+			err := Deliver(baseUrl, memoPost.PkHash, remoteFollower.Inbox, activity)
+			if err != nil {
+				jerr.Get("error delivering post to remote follower", err).Print()
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,8 @@
+package activitypub
+
+import "net/http"
+
+// SetClient lets tests reach local httptest servers, which the public client refuses to dial.
+func SetClient(httpClient *http.Client) {
+	client = httpClient
+}
@@ -0,0 +1,59 @@
+package activitypub
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"github.com/memocash/memo/app/db"
+)
+
+// GetFollowers includes remote followers and followers on chain. Only the most recent on chain followers are listed.
+func GetFollowers(baseUrl string, pkHash []byte) (*OrderedCollection, error) {
+	remoteFollowers, err := db.GetActivityPubFollowers(pkHash)
+	if err != nil {
+		return nil, jerr.Get("error getting remote followers", err)
+	}
+	followerCount, err := db.GetStore().GetFollowerCountForPkHash(pkHash)
+	if err != nil {
+		return nil, jerr.Get("error getting follower count", err)
+	}
+	memoFollows, err := db.GetStore().GetFollowingForPkHash(pkHash, 0)
+	if err != nil {
+		return nil, jerr.Get("error getting followers", err)
+	}
+	var collection = &OrderedCollection{
+		Context:      ActivityStreamsNs,
+		Id:           GetFollowersUrl(baseUrl, wallet.GetAddressFromPkHash(pkHash).GetEncoded()),
+		Type:         TypeOrderedCollection,
+		TotalItems:   followerCount + uint(len(remoteFollowers)),
+		OrderedItems: []interface{}{},
+	}
+	for _, remoteFollower := range remoteFollowers {
+		collection.OrderedItems = append(collection.OrderedItems, remoteFollower.ActorId)
+	}
+	for _, memoFollow := range memoFollows {
+		collection.OrderedItems = append(collection.OrderedItems, GetActorId(baseUrl, memoFollow.GetAddressString()))
+	}
+	return collection, nil
+}
+
+func GetFollowing(baseUrl string, pkHash []byte) (*OrderedCollection, error) {
+	followingCount, err := db.GetStore().GetFollowingCountForPkHash(pkHash)
+	if err != nil {
+		return nil, jerr.Get("error getting following count", err)
+	}
+	memoFollows, err := db.GetStore().GetFollowersForPkHash(pkHash, 0)
+	if err != nil {
+		return nil, jerr.Get("error getting following", err)
+	}
+	var collection = &OrderedCollection{
+		Context:      ActivityStreamsNs,
+		Id:           GetFollowingUrl(baseUrl, wallet.GetAddressFromPkHash(pkHash).GetEncoded()),
+		Type:         TypeOrderedCollection,
+		TotalItems:   followingCount,
+		OrderedItems: []interface{}{},
+	}
+	for _, memoFollow := range memoFollows {
+		collection.OrderedItems = append(collection.OrderedItems, GetActorId(baseUrl, memoFollow.GetFollowAddressString()))
+	}
+	return collection, nil
+}
@@ -0,0 +1,84 @@
+package activitypub
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"github.com/memocash/memo/app/db"
+)
+
+const invalidActivityErrorText = "invalid activity"
+
+var invalidActivityError = jerr.New(invalidActivityErrorText)
+
+func IsInvalidActivityError(err error) bool {
+	return jerr.HasError(err, invalidActivityErrorText)
+}
+
+// HandleInbox processes an activity sent to an address's inbox by a remote actor whose signature has already been
+// verified. Follow and Undo Follow are stored, other activities are ignored since the bridge is read only.
+func HandleInbox(baseUrl string, pkHash []byte, signer *Actor, body []byte) error {
+	var activity IncomingActivity
+	err := json.Unmarshal(body, &activity)
+	if err != nil {
+		return jerr.Get("error parsing activity", invalidActivityError)
+	}
+	if activity.Actor != signer.Id {
+		return jerr.Get("activity actor does not match signer", invalidActivityError)
+	}
+	if ! isHttpsUrl(signer.Inbox) {
+		return jerr.Get("signer inbox is not an https url", invalidActivityError)
+	}
+	actorId := GetActorId(baseUrl, wallet.GetAddressFromPkHash(pkHash).GetEncoded())
+	switch activity.Type {
+	case TypeFollow:
+		var object string
+		err = json.Unmarshal(activity.Object, &object)
+		if err != nil || object != actorId {
+			return jerr.Get("follow object is not this actor", invalidActivityError)
+		}
+		_, err = db.SaveActivityPubFollower(pkHash, signer.Id, signer.Inbox, activity.Id)
+		if err != nil {
+			return jerr.Get("error saving remote follower", err)
+		}
+		go func() {
+			err := sendAccept(baseUrl, pkHash, signer.Inbox, activity)
+			if err != nil {
+				jerr.Get("error sending follow accept", err).Print()
+			}
+		}()
+	case TypeUndo:
+		var undoneActivity IncomingActivity
+		err = json.Unmarshal(activity.Object, &undoneActivity)
+		if err != nil || undoneActivity.Type != TypeFollow {
+			return nil
+		}
+		err = db.RemoveActivityPubFollower(pkHash, signer.Id)
+		if err != nil {
+			return jerr.Get("error removing remote follower", err)
+		}
+	}
+	return nil
+}
+
+func sendAccept(baseUrl string, pkHash []byte, inbox string, follow IncomingActivity) error {
+	actorId := GetActorId(baseUrl, wallet.GetAddressFromPkHash(pkHash).GetEncoded())
+	followIdHash := sha256.Sum256([]byte(follow.Id))
+	err := Deliver(baseUrl, pkHash, inbox, &Activity{
+		Id:    actorId + "#accept-" + hex.EncodeToString(followIdHash[:]),
+		Type:  TypeAccept,
+		Actor: actorId,
+		Object: Activity{
+			Id:     follow.Id,
+			Type:   TypeFollow,
+			Actor:  follow.Actor,
+			Object: actorId,
+		},
+	})
+	if err != nil {
+		return jerr.Get("error delivering accept", err)
+	}
+	return nil
+}
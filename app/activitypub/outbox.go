@@ -0,0 +1,154 @@
+package activitypub
+
+import (
+	"fmt"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/profile"
+	"sort"
+	"time"
+)
+
+const pageSize = 25
+
+type outboxItem struct {
+	Time     time.Time
+	Activity *Activity
+}
+
+// GetOutbox returns the outbox collection, items are on pages starting at GetOutboxPage page 1.
+func GetOutbox(baseUrl string, pkHash []byte) (*OrderedCollection, error) {
+	address := wallet.GetAddressFromPkHash(pkHash).GetEncoded()
+	outboxUrl := GetOutboxUrl(baseUrl, address)
+	return &OrderedCollection{
+		Context: ActivityStreamsNs,
+		Id:      outboxUrl,
+		Type:    TypeOrderedCollection,
+		First:   getPageUrl(outboxUrl, 1),
+	}, nil
+}
+
+// GetOutboxPage returns posts as Create activities and likes as Like activities. Each page has up to 25 of each.
+func GetOutboxPage(baseUrl string, pkHash []byte, page uint) (*OrderedCollection, error) {
+	if page < 1 {
+		page = 1
+	}
+	offset := (page - 1) * pageSize
+	address := wallet.GetAddressFromPkHash(pkHash).GetEncoded()
+	outboxUrl := GetOutboxUrl(baseUrl, address)
+	posts, scannedPosts, err := profile.GetPostsForHash(pkHash, nil, offset)
+	if err != nil {
+		return nil, jerr.Get("error getting posts for hash", err)
+	}
+	memoLikes, err := db.GetRecentMemoLikesForPkHash(pkHash, offset)
+	if err != nil {
+		return nil, jerr.Get("error getting likes for hash", err)
+	}
+	var items []outboxItem
+	for _, post := range posts {
+		note := getNote(baseUrl, post)
+		items = append(items, outboxItem{
+			Time:     note.Published,
+			Activity: getCreateActivity(note),
+		})
+	}
+	for _, memoLike := range memoLikes {
+		items = append(items, outboxItem{
+			Time:     getTime(memoLike.Block, memoLike.CreatedAt),
+			Activity: getLikeActivity(baseUrl, memoLike),
+		})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Time.After(items[j].Time)
+	})
+	var collectionPage = &OrderedCollection{
+		Context:      ActivityStreamsNs,
+		Id:           getPageUrl(outboxUrl, page),
+		Type:         TypeOrderedCollectionPage,
+		PartOf:       outboxUrl,
+		OrderedItems: []interface{}{},
+	}
+	for _, item := range items {
+		collectionPage.OrderedItems = append(collectionPage.OrderedItems, item.Activity)
+	}
+	if scannedPosts == pageSize || len(memoLikes) == pageSize {
+		collectionPage.Next = getPageUrl(outboxUrl, page+1)
+	}
+	return collectionPage, nil
+}
+
+func GetNote(baseUrl string, txHash []byte) (*Note, error) {
+	post, err := profile.GetPostByTxHash(txHash, nil)
+	if err != nil {
+		return nil, jerr.Get("error getting post", err)
+	}
+	note := getNote(baseUrl, post)
+	note.Context = ActivityStreamsNs
+	return note, nil
+}
+
+func GetLike(baseUrl string, txHash []byte) (*Activity, error) {
+	memoLike, err := db.GetStore().GetMemoLike(txHash)
+	if err != nil {
+		return nil, jerr.Get("error getting memo like", err)
+	}
+	activity := getLikeActivity(baseUrl, memoLike)
+	activity.Context = ActivityStreamsNs
+	return activity, nil
+}
+
+func getNote(baseUrl string, post *profile.Post) *Note {
+	txHash := post.Memo.GetTransactionHashString()
+	address := post.Memo.GetAddressString()
+	var note = &Note{
+		Id:           GetNoteId(baseUrl, txHash),
+		Type:         TypeNote,
+		AttributedTo: GetActorId(baseUrl, address),
+		Content:      post.GetMessage(),
+		Url:          getPostUrl(baseUrl, txHash),
+		Published:    getTime(post.Memo.Block, post.Memo.CreatedAt),
+		To:           []string{PublicAddress},
+		Cc:           []string{GetFollowersUrl(baseUrl, address)},
+	}
+	if len(post.Memo.ParentTxHash) > 0 {
+		note.InReplyTo = GetNoteId(baseUrl, post.Memo.GetParentTransactionHashString())
+	}
+	return note
+}
+
+func getCreateActivity(note *Note) *Activity {
+	return &Activity{
+		Id:        note.Id + "#create",
+		Type:      TypeCreate,
+		Actor:     note.AttributedTo,
+		Object:    note,
+		Published: &note.Published,
+		To:        note.To,
+		Cc:        note.Cc,
+	}
+}
+
+func getLikeActivity(baseUrl string, memoLike *db.MemoLike) *Activity {
+	likeTxHash := memoLike.GetTransactionHashString()
+	published := getTime(memoLike.Block, memoLike.CreatedAt)
+	return &Activity{
+		Id:        GetLikeId(baseUrl, likeTxHash),
+		Type:      TypeLike,
+		Actor:     GetActorId(baseUrl, memoLike.GetAddressString()),
+		Object:    GetNoteId(baseUrl, memoLike.GetLikeTransactionHashString()),
+		Published: &published,
+	}
+}
+
+// Block time once confirmed, otherwise the time first seen.
+func getTime(block *db.Block, createdAt time.Time) time.Time {
+	if block != nil {
+		return block.Timestamp
+	}
+	return createdAt
+}
+
+func getPageUrl(collectionUrl string, page uint) string {
+	return fmt.Sprintf("%s?page=%d", collectionUrl, page)
+}
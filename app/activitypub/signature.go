@@ -0,0 +1,177 @@
+package activitypub
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/util"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	signatureHeaders = "(request-target) host date digest"
+	maxClockSkew     = 12 * time.Hour
+	requestTimeout   = 10 * time.Second
+	maxResponseSize  = 1 << 20
+)
+
+// Actor and inbox urls come from remote servers so only public addresses are dialed and redirects are not followed.
+var client = util.GetPublicHttpClient(requestTimeout)
+
+var requiredSignedHeaders = strings.Split(signatureHeaders, " ")
+
+func isHttpsUrl(rawUrl string) bool {
+	parsedUrl, err := url.Parse(rawUrl)
+	return err == nil && parsedUrl.Scheme == "https" && parsedUrl.Host != ""
+}
+
+func getDigest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func getSigningString(request *http.Request, headers []string) (string, error) {
+	var lines []string
+	for _, header := range headers {
+		switch header {
+		case "(request-target)":
+			lines = append(lines, fmt.Sprintf("(request-target): %s %s", strings.ToLower(request.Method), request.URL.RequestURI()))
+		case "host":
+			host := request.Host
+			if host == "" {
+				host = request.URL.Host
+			}
+			lines = append(lines, "host: "+host)
+		default:
+			value := request.Header.Get(header)
+			if value == "" {
+				return "", jerr.Newf("missing signed header: %s", header)
+			}
+			lines = append(lines, header+": "+value)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// Parses a header like keyId="a",algorithm="rsa-sha256",headers="date",signature="b".
+func parseSignatureHeader(header string) map[string]string {
+	var params = make(map[string]string)
+	for _, part := range strings.Split(header, ",") {
+		keyValue := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(keyValue) != 2 {
+			continue
+		}
+		params[keyValue[0]] = strings.Trim(keyValue[1], `"`)
+	}
+	return params
+}
+
+// SignRequest adds Date, Digest and Signature headers using the draft HTTP signatures scheme used by Mastodon.
+func SignRequest(request *http.Request, keyId string, privateKey *rsa.PrivateKey, body []byte) error {
+	request.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	request.Header.Set("Digest", getDigest(body))
+	signingString, err := getSigningString(request, requiredSignedHeaders)
+	if err != nil {
+		return jerr.Get("error getting signing string", err)
+	}
+	hashed := sha256.Sum256([]byte(signingString))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, hashed[:])
+	if err != nil {
+		return jerr.Get("error signing request", err)
+	}
+	request.Header.Set("Signature", fmt.Sprintf(`keyId="%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		keyId, signatureHeaders, base64.StdEncoding.EncodeToString(signature)))
+	return nil
+}
+
+// VerifyRequest checks the request signature against the public key of the signing actor, which is fetched from
+// the remote server. Returns the signing actor.
+func VerifyRequest(request *http.Request, body []byte) (*Actor, error) {
+	params := parseSignatureHeader(request.Header.Get("Signature"))
+	keyId := params["keyId"]
+	if keyId == "" || params["signature"] == "" {
+		return nil, jerr.New("missing signature")
+	}
+	headers := strings.Fields(params["headers"])
+	var signedHeaders = make(map[string]bool)
+	for _, header := range headers {
+		signedHeaders[header] = true
+	}
+	for _, header := range requiredSignedHeaders {
+		if ! signedHeaders[header] {
+			return nil, jerr.Newf("required header not signed: %s", header)
+		}
+	}
+	if request.Header.Get("Digest") != getDigest(body) {
+		return nil, jerr.New("digest does not match body")
+	}
+	date, err := http.ParseTime(request.Header.Get("Date"))
+	if err != nil {
+		return nil, jerr.Get("error parsing date header", err)
+	}
+	if time.Since(date) > maxClockSkew || time.Until(date) > maxClockSkew {
+		return nil, jerr.New("date outside of allowed clock skew")
+	}
+	signingString, err := getSigningString(request, headers)
+	if err != nil {
+		return nil, jerr.Get("error getting signing string", err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(params["signature"])
+	if err != nil {
+		return nil, jerr.Get("error decoding signature", err)
+	}
+	actor, err := FetchActor(strings.SplitN(keyId, "#", 2)[0])
+	if err != nil {
+		return nil, jerr.Get("error fetching signing actor", err)
+	}
+	if actor.PublicKey == nil || actor.PublicKey.Id != keyId {
+		return nil, jerr.New("signing key does not belong to actor")
+	}
+	publicKey, err := parsePublicKey(actor.PublicKey.PublicKeyPem)
+	if err != nil {
+		return nil, jerr.Get("error parsing actor public key", err)
+	}
+	hashed := sha256.Sum256([]byte(signingString))
+	err = rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hashed[:], signature)
+	if err != nil {
+		return nil, jerr.Get("invalid signature", err)
+	}
+	return actor, nil
+}
+
+func FetchActor(actorId string) (*Actor, error) {
+	if ! isHttpsUrl(actorId) {
+		return nil, jerr.New("actor id is not an https url")
+	}
+	request, err := http.NewRequest(http.MethodGet, actorId, nil)
+	if err != nil {
+		return nil, jerr.Get("error creating actor request", err)
+	}
+	request.Header.Set("Accept", ContentType)
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, jerr.Get("error requesting actor", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, jerr.Newf("unexpected actor response code: %d", response.StatusCode)
+	}
+	var actor Actor
+	err = json.NewDecoder(io.LimitReader(response.Body, maxResponseSize)).Decode(&actor)
+	if err != nil {
+		return nil, jerr.Get("error decoding actor", err)
+	}
+	if actor.Id != actorId {
+		return nil, jerr.New("actor id does not match requested id")
+	}
+	return &actor, nil
+}
@@ -0,0 +1,181 @@
+package activitypub_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/activitypub"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/testutil"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const BaseUrl = "https://memo.test/"
+
+const SomeActivity = `{"type":"Follow"}`
+
+// Simulates a remote server hosting the signing actor. Activities posted to the actor's inbox are sent on the
+// returned channel.
+func getRemoteActorServer(t *testing.T) (*httptest.Server, *rsa.PrivateKey, chan []byte) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(jerr.Get("error generating key", err))
+	}
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		t.Fatal(jerr.Get("error marshalling public key", err))
+	}
+	publicKeyPem := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes})
+	var inbox = make(chan []byte, 1)
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/inbox" {
+			body, _ := ioutil.ReadAll(r.Body)
+			inbox <- body
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		actorId := server.URL + "/actor"
+		json.NewEncoder(w).Encode(activitypub.Actor{
+			Id:    actorId,
+			Type:  activitypub.TypePerson,
+			Inbox: server.URL + "/inbox",
+			PublicKey: &activitypub.PublicKey{
+				Id:           actorId + "#main-key",
+				Owner:        actorId,
+				PublicKeyPem: string(publicKeyPem),
+			},
+		})
+	}))
+	activitypub.SetClient(server.Client())
+	return server, privateKey, inbox
+}
+
+func getSignedRequest(t *testing.T, server *httptest.Server, privateKey *rsa.PrivateKey, body []byte) *http.Request {
+	request := httptest.NewRequest(http.MethodPost, "http://memo.test/ap/inbox/address", bytes.NewReader(body))
+	err := activitypub.SignRequest(request, server.URL+"/actor#main-key", privateKey, body)
+	if err != nil {
+		t.Fatal(jerr.Get("error signing request", err))
+	}
+	return request
+}
+
+func TestVerifyRequest(t *testing.T) {
+	server, privateKey, _ := getRemoteActorServer(t)
+	defer server.Close()
+	request := getSignedRequest(t, server, privateKey, []byte(SomeActivity))
+	actor, err := activitypub.VerifyRequest(request, []byte(SomeActivity))
+	if err != nil {
+		t.Fatal(jerr.Get("error verifying signed request", err))
+	}
+	if actor.Id != server.URL+"/actor" {
+		t.Fatal(jerr.New("verified actor does not match signer"))
+	}
+}
+
+func TestVerifyRequestTamperedBody(t *testing.T) {
+	server, privateKey, _ := getRemoteActorServer(t)
+	defer server.Close()
+	request := getSignedRequest(t, server, privateKey, []byte(SomeActivity))
+	_, err := activitypub.VerifyRequest(request, []byte(`{"type":"Undo"}`))
+	if err == nil {
+		t.Fatal(jerr.New("expected tampered body to fail verification"))
+	}
+}
+
+// A valid signature that leaves out (request-target) and host could be replayed against another inbox.
+func TestVerifyRequestMissingSignedHeader(t *testing.T) {
+	server, privateKey, _ := getRemoteActorServer(t)
+	defer server.Close()
+	request := getSignedRequest(t, server, privateKey, []byte(SomeActivity))
+	signingString := "date: " + request.Header.Get("Date") + "\ndigest: " + request.Header.Get("Digest")
+	hashed := sha256.Sum256([]byte(signingString))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, hashed[:])
+	if err != nil {
+		t.Fatal(jerr.Get("error signing request", err))
+	}
+	request.Header.Set("Signature", fmt.Sprintf(`keyId="%s/actor#main-key",algorithm="rsa-sha256",`+
+		`headers="date digest",signature="%s"`, server.URL, base64.StdEncoding.EncodeToString(signature)))
+	_, err = activitypub.VerifyRequest(request, []byte(SomeActivity))
+	if err == nil {
+		t.Fatal(jerr.New("expected signature without (request-target) and host to fail verification"))
+	}
+}
+
+func handleSignedActivity(t *testing.T, server *httptest.Server, privateKey *rsa.PrivateKey, pkHash []byte,
+	activity string) {
+	body := []byte(activity)
+	signer, err := activitypub.VerifyRequest(getSignedRequest(t, server, privateKey, body), body)
+	if err != nil {
+		t.Fatal(jerr.Get("error verifying signed request", err))
+	}
+	err = activitypub.HandleInbox(BaseUrl, pkHash, signer, body)
+	if err != nil {
+		t.Fatal(jerr.Get("error handling inbox activity", err))
+	}
+}
+
+func TestInboxFollow(t *testing.T) {
+	defer testutil.UseSqlite(t)()
+	server, privateKey, inbox := getRemoteActorServer(t)
+	defer server.Close()
+
+	pkHash := bytes.Repeat([]byte{0x01}, 20)
+	localActorId := activitypub.GetActorId(BaseUrl, wallet.GetAddressFromPkHash(pkHash).GetEncoded())
+	remoteActorId := server.URL + "/actor"
+	follow := fmt.Sprintf(`{"id":"%s/follow","type":"Follow","actor":"%s","object":"%s"}`, server.URL,
+		remoteActorId, localActorId)
+	handleSignedActivity(t, server, privateKey, pkHash, follow)
+
+	// The accept is sent in the background, wait for it before reading the db.
+	select {
+	case body := <-inbox:
+		var accept activitypub.IncomingActivity
+		err := json.Unmarshal(body, &accept)
+		if err != nil {
+			t.Fatal(jerr.Get("error parsing accept", err))
+		}
+		var acceptedFollow activitypub.IncomingActivity
+		err = json.Unmarshal(accept.Object, &acceptedFollow)
+		if err != nil {
+			t.Fatal(jerr.Get("error parsing accepted follow", err))
+		}
+		if accept.Type != activitypub.TypeAccept || accept.Actor != localActorId ||
+			acceptedFollow.Id != server.URL+"/follow" {
+			t.Fatal(jerr.New("unexpected accept activity"))
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal(jerr.New("accept not delivered to remote inbox"))
+	}
+
+	followers, err := db.GetActivityPubFollowers(pkHash)
+	if err != nil {
+		t.Fatal(jerr.Get("error getting followers", err))
+	}
+	if len(followers) != 1 || followers[0].ActorId != remoteActorId || followers[0].Inbox != server.URL+"/inbox" {
+		t.Fatal(jerr.New("expected remote actor to be stored as a follower"))
+	}
+
+	undo := fmt.Sprintf(`{"id":"%s/undo","type":"Undo","actor":"%s","object":%s}`, server.URL, remoteActorId, follow)
+	handleSignedActivity(t, server, privateKey, pkHash, undo)
+	followers, err = db.GetActivityPubFollowers(pkHash)
+	if err != nil {
+		t.Fatal(jerr.Get("error getting followers", err))
+	}
+	if len(followers) != 0 {
+		t.Fatal(jerr.New("expected follower to be removed after undo"))
+	}
+}
@@ -0,0 +1,111 @@
+package activitypub
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	ContentType       = "application/activity+json"
+	LdContentType     = `application/ld+json; profile="https://www.w3.org/ns/activitystreams"`
+	WebFingerType     = "application/jrd+json"
+	ActivityStreamsNs = "https://www.w3.org/ns/activitystreams"
+	SecurityNs        = "https://w3id.org/security/v1"
+	PublicAddress     = ActivityStreamsNs + "#Public"
+)
+
+const (
+	TypePerson                = "Person"
+	TypeNote                  = "Note"
+	TypeImage                 = "Image"
+	TypeCreate                = "Create"
+	TypeLike                  = "Like"
+	TypeFollow                = "Follow"
+	TypeAccept                = "Accept"
+	TypeUndo                  = "Undo"
+	TypeOrderedCollection     = "OrderedCollection"
+	TypeOrderedCollectionPage = "OrderedCollectionPage"
+)
+
+type Actor struct {
+	Context           interface{} `json:"@context,omitempty"`
+	Id                string      `json:"id"`
+	Type              string      `json:"type"`
+	PreferredUsername string      `json:"preferredUsername"`
+	Name              string      `json:"name,omitempty"`
+	Summary           string      `json:"summary,omitempty"`
+	Url               string      `json:"url,omitempty"`
+	Inbox             string      `json:"inbox"`
+	Outbox            string      `json:"outbox,omitempty"`
+	Followers         string      `json:"followers,omitempty"`
+	Following         string      `json:"following,omitempty"`
+	Icon              *Image      `json:"icon,omitempty"`
+	PublicKey         *PublicKey  `json:"publicKey,omitempty"`
+}
+
+type PublicKey struct {
+	Id           string `json:"id"`
+	Owner        string `json:"owner"`
+	PublicKeyPem string `json:"publicKeyPem"`
+}
+
+type Image struct {
+	Type string `json:"type"`
+	Url  string `json:"url"`
+}
+
+type Note struct {
+	Context      interface{} `json:"@context,omitempty"`
+	Id           string      `json:"id"`
+	Type         string      `json:"type"`
+	AttributedTo string      `json:"attributedTo"`
+	InReplyTo    string      `json:"inReplyTo,omitempty"`
+	Content      string      `json:"content"`
+	Url          string      `json:"url"`
+	Published    time.Time   `json:"published"`
+	To           []string    `json:"to"`
+	Cc           []string    `json:"cc,omitempty"`
+}
+
+// Activity is used for outgoing activities. Object can be an id or an embedded object.
+type Activity struct {
+	Context   interface{} `json:"@context,omitempty"`
+	Id        string      `json:"id"`
+	Type      string      `json:"type"`
+	Actor     string      `json:"actor"`
+	Object    interface{} `json:"object"`
+	Published *time.Time  `json:"published,omitempty"`
+	To        []string    `json:"to,omitempty"`
+	Cc        []string    `json:"cc,omitempty"`
+}
+
+// IncomingActivity is a received activity, the object is parsed based on the type.
+type IncomingActivity struct {
+	Id     string          `json:"id"`
+	Type   string          `json:"type"`
+	Actor  string          `json:"actor"`
+	Object json.RawMessage `json:"object"`
+}
+
+type OrderedCollection struct {
+	Context      interface{}   `json:"@context,omitempty"`
+	Id           string        `json:"id"`
+	Type         string        `json:"type"`
+	TotalItems   uint          `json:"totalItems"`
+	First        string        `json:"first,omitempty"`
+	PartOf       string        `json:"partOf,omitempty"`
+	Next         string        `json:"next,omitempty"`
+	OrderedItems []interface{} `json:"orderedItems,omitempty"`
+}
+
+type WebFinger struct {
+	Subject string          `json:"subject"`
+	Aliases []string        `json:"aliases,omitempty"`
+	Links   []WebFingerLink `json:"links"`
+}
+
+type WebFingerLink struct {
+	Rel  string `json:"rel"`
+	Type string `json:"type,omitempty"`
+	Href string `json:"href"`
+}
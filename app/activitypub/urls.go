@@ -0,0 +1,50 @@
+package activitypub
+
+import (
+	"github.com/memocash/memo/app/res"
+	"strings"
+)
+
+func getUrl(baseUrl string, path string, id string) string {
+	return baseUrl + strings.TrimLeft(path, "/") + "/" + id
+}
+
+func GetActorId(baseUrl string, address string) string {
+	return getUrl(baseUrl, res.UrlActivityPubActor, address)
+}
+
+func GetKeyId(baseUrl string, address string) string {
+	return GetActorId(baseUrl, address) + "#main-key"
+}
+
+func GetInboxUrl(baseUrl string, address string) string {
+	return getUrl(baseUrl, res.UrlActivityPubInbox, address)
+}
+
+func GetOutboxUrl(baseUrl string, address string) string {
+	return getUrl(baseUrl, res.UrlActivityPubOutbox, address)
+}
+
+func GetFollowersUrl(baseUrl string, address string) string {
+	return getUrl(baseUrl, res.UrlActivityPubFollowers, address)
+}
+
+func GetFollowingUrl(baseUrl string, address string) string {
+	return getUrl(baseUrl, res.UrlActivityPubFollowing, address)
+}
+
+func GetNoteId(baseUrl string, txHash string) string {
+	return getUrl(baseUrl, res.UrlActivityPubNote, txHash)
+}
+
+func GetLikeId(baseUrl string, txHash string) string {
+	return getUrl(baseUrl, res.UrlActivityPubLike, txHash)
+}
+
+func getProfileUrl(baseUrl string, address string) string {
+	return getUrl(baseUrl, res.UrlProfileView, address)
+}
+
+func getPostUrl(baseUrl string, txHash string) string {
+	return getUrl(baseUrl, res.UrlMemoPost, txHash)
+}
@@ -0,0 +1,40 @@
+package activitypub
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"strings"
+)
+
+// GetWebFinger resolves acct:<address>@<host> or an actor url to the address's actor.
+func GetWebFinger(baseUrl string, host string, resource string) (*WebFinger, error) {
+	var addressString string
+	if strings.HasPrefix(resource, "acct:") {
+		parts := strings.SplitN(strings.TrimPrefix(resource, "acct:"), "@", 2)
+		if len(parts) != 2 || ! strings.EqualFold(parts[1], host) {
+			return nil, jerr.Get("resource is not on this host", invalidActivityError)
+		}
+		addressString = parts[0]
+	} else {
+		addressString = strings.TrimPrefix(resource, GetActorId(baseUrl, ""))
+	}
+	address := wallet.GetAddressFromString(addressString)
+	if len(address.GetScriptAddress()) == 0 {
+		return nil, jerr.Get("invalid address", invalidActivityError)
+	}
+	encoded := address.GetEncoded()
+	actorId := GetActorId(baseUrl, encoded)
+	return &WebFinger{
+		Subject: "acct:" + encoded + "@" + host,
+		Aliases: []string{actorId, getProfileUrl(baseUrl, encoded)},
+		Links: []WebFingerLink{{
+			Rel:  "self",
+			Type: ContentType,
+			Href: actorId,
+		}, {
+			Rel:  "http://webfinger.net/rel/profile-page",
+			Type: "text/html",
+			Href: getProfileUrl(baseUrl, encoded),
+		}},
+	}, nil
+}
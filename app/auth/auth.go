@@ -6,7 +6,7 @@ import (
 )
 
 func IsLoggedIn(cookieId string) bool {
-	session, err := db.GetSession(cookieId)
+	session, err := db.GetStore().GetSession(cookieId)
 	if err != nil {
 		panic(jerr.Get("error getting session from db", err))
 		return false
@@ -18,7 +18,7 @@ func IsLoggedIn(cookieId string) bool {
 }
 
 func GetSessionUser(cookieId string) (*db.User, error) {
-	session, err := db.GetSession(cookieId)
+	session, err := db.GetStore().GetSession(cookieId)
 	if err != nil || session.UserId == 0 || session.HasLoggedOut {
 		if err == nil {
 			return nil, jerr.New("Unable to get session user")
@@ -33,7 +33,7 @@ func Login(cookieId string, username string, password string) error {
 		return jerr.Get(MsgPasswordMismatch, err)
 	}
 
-	session, err := db.GetSession(cookieId)
+	session, err := db.GetStore().GetSession(cookieId)
 	if err != nil {
 		return jerr.Get("session not found", err)
 	}
@@ -6,7 +6,7 @@ import (
 )
 
 func Logout(cookieId string) error {
-	session, err := db.GetSession(cookieId)
+	session, err := db.GetStore().GetSession(cookieId)
 	if err != nil {
 		return jerr.Get("Error getting session", err)
 	}
@@ -23,7 +23,7 @@ func Signup(cookieId string, username string, password string) error {
 	if err != nil {
 		return jerr.Get(MsgErrorCreatingUser, err)
 	}
-	session, err := db.GetSession(cookieId)
+	session, err := db.GetStore().GetSession(cookieId)
 	if err != nil {
 		return jerr.Get(MsgErrorGettingSession, err)
 	}
@@ -0,0 +1,94 @@
+package fee
+
+import (
+	"github.com/memocash/memo/app/config"
+	"sync"
+)
+
+// Rates are in satoshis per byte.
+const (
+	MinRate int64 = 1
+	MaxRate int64 = 100
+)
+
+const (
+	PriorityLow    = "low"
+	PriorityNormal = "normal"
+	PriorityHigh   = "high"
+)
+
+const highPriorityMultiplier = 2
+
+var minRelayRates = make(map[string]int64)
+
+var minRelayMutex sync.RWMutex
+
+// SetMinRelayFee records the minimum fee from a peer's feefilter message, which is in satoshis per kilobyte.
+func SetMinRelayFee(peerAddress string, satoshisPerKb int64) {
+	minRelayMutex.Lock()
+	defer minRelayMutex.Unlock()
+	if satoshisPerKb <= 0 {
+		delete(minRelayRates, peerAddress)
+		return
+	}
+	minRelayRates[peerAddress] = (satoshisPerKb + 999) / 1000
+}
+
+// GetMinRelayRate returns the highest minimum relay rate of any peer so transactions are accepted by all of them.
+func GetMinRelayRate() int64 {
+	minRelayMutex.RLock()
+	defer minRelayMutex.RUnlock()
+	var minRelayRate = MinRate
+	for _, rate := range minRelayRates {
+		if rate > minRelayRate {
+			minRelayRate = rate
+		}
+	}
+	return minRelayRate
+}
+
+// GetRate returns the rate to build transactions with. Low priority pays the minimum relay rate, normal pays the
+// configured fee rate and high pays double the configured rate.
+func GetRate(priority string) int64 {
+	switch priority {
+	case PriorityLow:
+		return Clamp(MinRate)
+	case PriorityHigh:
+		return Clamp(config.GetFeeRate() * highPriorityMultiplier)
+	default:
+		return Clamp(config.GetFeeRate())
+	}
+}
+
+// GetRebuildRate returns the rate to use after a transaction built at rate was rejected for an insufficient fee.
+func GetRebuildRate(rate int64) int64 {
+	return Clamp(rate * 2)
+}
+
+// Clamp keeps a rate between the minimum relay rate and MaxRate.
+func Clamp(rate int64) int64 {
+	if minRelayRate := GetMinRelayRate(); rate < minRelayRate {
+		rate = minRelayRate
+	}
+	if rate > MaxRate {
+		rate = MaxRate
+	}
+	return rate
+}
+
+func GetFee(size int, rate int64) int64 {
+	return int64(size) * rate
+}
+
+func IsValidPriority(priority string) bool {
+	for _, validValue := range []string{
+		PriorityLow,
+		PriorityNormal,
+		PriorityHigh,
+	} {
+		if priority == validValue {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package fee_test
+
+import (
+	"github.com/memocash/memo/app/bitcoin/fee"
+	"testing"
+)
+
+func TestMinRelayFee(t *testing.T) {
+	fee.SetMinRelayFee("10.0.0.1:8333", 1000)
+	fee.SetMinRelayFee("10.0.0.2:8333", 2001)
+	if rate := fee.GetMinRelayRate(); rate != 3 {
+		t.Errorf("expected min relay rate 3, got %d", rate)
+	}
+	if rate := fee.GetRate(fee.PriorityLow); rate != 3 {
+		t.Errorf("expected low priority rate 3, got %d", rate)
+	}
+	if rate := fee.GetRebuildRate(fee.MaxRate); rate != fee.MaxRate {
+		t.Errorf("expected rebuild rate capped at %d, got %d", fee.MaxRate, rate)
+	}
+	fee.SetMinRelayFee("10.0.0.2:8333", 0)
+	if rate := fee.GetMinRelayRate(); rate != 1 {
+		t.Errorf("expected min relay rate 1, got %d", rate)
+	}
+}
@@ -7,15 +7,16 @@ import (
 	"github.com/jchavannes/btcd/wire"
 	"github.com/jchavannes/jgo/jerr"
 	"github.com/memocash/memo/app/bitcoin/transaction"
+	"github.com/memocash/memo/app/bitcoin/wallet"
 	"github.com/memocash/memo/app/db"
 	"github.com/memocash/memo/app/obj/user_stats"
 )
 
-const MinCheckHeight = 525000
+var MinCheckHeight = wallet.MinCheckHeight
 
 func onBlock(n *Node, msg *wire.MsgBlock) {
 	block := bchutil.NewBlock(msg)
-	dbBlock, err := db.GetBlockByHash(*block.Hash())
+	dbBlock, err := db.GetStore().GetBlockByHash(*block.Hash())
 	if err != nil {
 		jerr.Getf(err, "error getting dbBlock (%s)", block.Hash().String()).Print()
 		return
@@ -79,7 +80,7 @@ func queueBlocks(n *Node) {
 	if n.NodeStatus.HeightChecked < MinCheckHeight {
 		n.NodeStatus.HeightChecked = MinCheckHeight
 	}
-	blocks, err := db.GetBlocksInHeightRange(n.NodeStatus.HeightChecked+1, n.NodeStatus.HeightChecked+2000)
+	blocks, err := db.GetStore().GetBlocksInHeightRange(n.NodeStatus.HeightChecked+1, n.NodeStatus.HeightChecked+2000)
 	if err != nil {
 		jerr.Get("error getting blocks in height range", err).Print()
 		return
@@ -11,21 +11,32 @@ import (
 
 func setBloomFilters(n *Node) {
 	if n.UserNode {
-		allKeys, err := db.GetAllKeys()
+		allKeys, err := db.GetStore().GetAllKeys()
 		if err != nil {
 			jerr.Get("error getting keys from db", err).Print()
 			return
 		}
-		if len(allKeys) == n.PreviousFilterSize {
+		// Change addresses for HD keys, includes the gap past the last used address
+		allKeyAddresses, err := db.GetAllKeyAddresses()
+		if err != nil {
+			jerr.Get("error getting key addresses from db", err).Print()
+			return
+		}
+		filterSize := len(allKeys) + len(allKeyAddresses)
+		if filterSize == n.PreviousFilterSize {
 			return
 		}
-		n.PreviousFilterSize = len(allKeys)
-		fmt.Printf("Setting bloom filter (keys: %d)...\n", len(allKeys))
-		bloomFilter := bloom.NewFilter(uint32(len(allKeys)*2), 0, 0, wire.BloomUpdateNone)
+		n.PreviousFilterSize = filterSize
+		fmt.Printf("Setting bloom filter (keys: %d, change addresses: %d)...\n", len(allKeys), len(allKeyAddresses))
+		bloomFilter := bloom.NewFilter(uint32(filterSize*2), 0, 0, wire.BloomUpdateNone)
 		for _, key := range allKeys {
 			bloomFilter.Add(key.GetAddress().GetScriptAddress())
 			bloomFilter.Add(key.GetPublicKey().GetSerialized())
 		}
+		for _, keyAddress := range allKeyAddresses {
+			bloomFilter.Add(keyAddress.PkHash)
+			bloomFilter.Add(keyAddress.PublicKey)
+		}
 		n.Peer.QueueMessage(bloomFilter.MsgFilterLoad(), nil)
 	} else {
 		codes := memo.GetAllCodes()
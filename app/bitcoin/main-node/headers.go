@@ -3,24 +3,117 @@ package main_node
 import (
 	"fmt"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/btcd/blockchain"
 	"github.com/jchavannes/btcd/wire"
 	"github.com/jchavannes/jgo/jerr"
 	"github.com/memocash/memo/app/db"
+	"math/big"
 )
 
-func sendGetHeaders(n *Node, startingBlock *chainhash.Hash) {
+const locatorDenseCount = 10
+
+func sendGetHeaders(n *Node, startingBlock *db.Block) {
 	msgGetHeaders := wire.NewMsgGetHeaders()
-	msgGetHeaders.BlockLocatorHashes = []*chainhash.Hash{
-		startingBlock,
-	}
+	msgGetHeaders.BlockLocatorHashes = getBlockLocator(startingBlock)
 	n.Peer.QueueMessage(msgGetHeaders, nil)
 }
 
+// Block locator is the most recent blocks followed by exponentially spaced older blocks back to the earliest
+// saved block. If the starting block has been orphaned the peer can still find where the chains fork.
+func getBlockLocator(startingBlock *db.Block) []*chainhash.Hash {
+	var locator = []*chainhash.Hash{
+		startingBlock.GetChainhash(),
+	}
+	var step uint = 1
+	var height = startingBlock.Height
+	for height > 0 {
+		if len(locator) >= locatorDenseCount {
+			step *= 2
+		}
+		if height > step {
+			height -= step
+		} else {
+			height = 0
+		}
+		block, err := db.GetStore().GetBlockByHeight(height)
+		if err != nil {
+			if ! db.IsRecordNotFoundError(err) {
+				jerr.Get("error getting block for locator", err).Print()
+			}
+			break
+		}
+		locator = append(locator, block.GetChainhash())
+	}
+	return locator
+}
+
+func requestHeadersFromRecentBlock(n *Node) {
+	recentBlock, err := db.GetStore().GetRecentBlock()
+	if err != nil {
+		jerr.Get("error getting recent block", err).Print()
+		return
+	}
+	sendGetHeaders(n, recentBlock)
+}
+
+// A competing branch only replaces the saved chain when it has more total work from the fork. Headers are counted
+// while they connect, so a single stale or invalid header never orphans saved blocks.
+func hasMoreWork(forkBlock *db.Block, headers []*wire.BlockHeader) (bool, error) {
+	var branchWork = new(big.Int)
+	prevHash := forkBlock.GetChainhash().String()
+	for _, header := range headers {
+		if header.PrevBlock.String() != prevHash {
+			break
+		}
+		branchWork.Add(branchWork, blockchain.CalcWork(header.Bits))
+		blockHash := header.BlockHash()
+		prevHash = blockHash.String()
+	}
+	recentBlock, err := db.GetStore().GetRecentBlock()
+	if err != nil {
+		return false, jerr.Get("error getting recent block", err)
+	}
+	savedBlocks, err := db.GetStore().GetBlocksInHeightRange(forkBlock.Height+1, recentBlock.Height)
+	if err != nil {
+		return false, jerr.Get("error getting saved blocks after fork", err)
+	}
+	var savedWork = new(big.Int)
+	for _, savedBlock := range savedBlocks {
+		savedWork.Add(savedWork, blockchain.CalcWork(savedBlock.Bits))
+	}
+	return branchWork.Cmp(savedWork) > 0, nil
+}
+
+// Called when a header connects to a block other than the one saved at the next height. Blocks after the fork
+// are removed and the height checked is rewound so replacing blocks get scanned.
+func handleReorg(n *Node, forkBlock *db.Block) error {
+	orphanedBlocks, err := db.GetStore().RemoveBlocksAboveHeight(forkBlock.Height)
+	if err != nil {
+		return jerr.Get("error removing orphaned blocks", err)
+	}
+	fmt.Printf("Reorg detected at height: %d, orphaned blocks: %d\n", forkBlock.Height, len(orphanedBlocks))
+	for _, blockHashes := range []map[string]*db.Block{n.BlockHashes, n.PrevBlockHashes} {
+		for txHash, block := range blockHashes {
+			if block.Height > forkBlock.Height {
+				delete(blockHashes, txHash)
+			}
+		}
+	}
+	if n.NodeStatus.HeightChecked > forkBlock.Height {
+		n.NodeStatus.HeightChecked = forkBlock.Height
+		err = n.NodeStatus.Save()
+		if err != nil {
+			return jerr.Get("error saving node status", err)
+		}
+	}
+	return nil
+}
+
 func onHeaders(n *Node, msg *wire.MsgHeaders) {
 	var lastBlock *db.Block
-	for _, header := range msg.Headers {
+	for i, header := range msg.Headers {
 		block := db.ConvertMessageHeaderToBlock(header)
-		dbBlock, err := db.GetBlockByHash(*block.GetChainhash())
+		dbBlock, err := db.GetStore().GetBlockByHash(*block.GetChainhash())
 		if err != nil && ! db.IsRecordNotFoundError(err) {
 			jerr.Get("error finding existing block", err).Print()
 			return
@@ -30,18 +123,45 @@ func onHeaders(n *Node, msg *wire.MsgHeaders) {
 			// Block already exists
 			continue
 		}
-		parentBlock, err := db.GetBlockByHash(header.PrevBlock)
+		parentBlock, err := db.GetStore().GetBlockByHash(header.PrevBlock)
 		if err != nil {
+			if db.IsRecordNotFoundError(err) {
+				// A new tip on a branch that isn't saved, e.g. one that was ignored with equal work. Headers
+				// from the saved chain are requested so the whole branch arrives and can be compared.
+				requestHeadersFromRecentBlock(n)
+				return
+			}
 			jerr.Getf(err, "error finding parent block in db (%s)", header.PrevBlock.String()).Print()
 			return
 		}
 		block.Height = parentBlock.Height + 1
+		conflictingBlock, err := db.GetStore().GetBlockByHeight(block.Height)
+		if err != nil && ! db.IsRecordNotFoundError(err) {
+			jerr.Get("error finding block at height", err).Print()
+			return
+		}
+		if conflictingBlock != nil {
+			moreWork, err := hasMoreWork(parentBlock, msg.Headers[i:])
+			if err != nil {
+				jerr.Get("error comparing branch work", err).Print()
+				return
+			}
+			if ! moreWork {
+				fmt.Printf("Ignoring branch at height %d without more work than saved chain\n", block.Height)
+				return
+			}
+			err = handleReorg(n, parentBlock)
+			if err != nil {
+				jerr.Get("error handling reorg", err).Print()
+				return
+			}
+		}
 		err = block.Save()
 		if err != nil {
 			if ! db.IsDuplicateEntryError(err) {
 				jerr.Get("error saving block", err).Print()
 			} else {
-				block, err = db.GetBlockByHash(*block.GetChainhash())
+				block, err = db.GetStore().GetBlockByHash(*block.GetChainhash())
 				if err != nil {
 					jerr.Get("error getting duplicate block", err).Print()
 				}
@@ -64,5 +184,5 @@ func onHeaders(n *Node, msg *wire.MsgHeaders) {
 		jerr.New("Unexpected nil lastBlock").Print()
 		return
 	}
-	sendGetHeaders(n, lastBlock.GetChainhash())
+	sendGetHeaders(n, lastBlock)
 }
@@ -10,7 +10,7 @@ import (
 )
 
 func onMerkleBlock(n *Node, msg *wire.MsgMerkleBlock) {
-	dbBlock, err := db.GetBlockByHash(msg.Header.BlockHash())
+	dbBlock, err := db.GetStore().GetBlockByHash(msg.Header.BlockHash())
 	if err != nil {
 		jerr.Getf(err, "error getting dbBlock (%s)", msg.Header.BlockHash().String()).Print()
 		return
@@ -76,7 +76,7 @@ func queueMerkleBlocks(n *Node, first bool) {
 	if ! first || n.BlocksSyncComplete {
 		initialHeight++
 	}
-	blocks, err := db.GetBlocksInHeightRange(initialHeight, initialHeight+1999)
+	blocks, err := db.GetStore().GetBlocksInHeightRange(initialHeight, initialHeight+1999)
 	if err != nil {
 		jerr.Get("error getting blocks in height range", err).Print()
 		return
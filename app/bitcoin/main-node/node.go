@@ -4,16 +4,15 @@ import (
 	"fmt"
 	"github.com/jchavannes/btcd/peer"
 	"github.com/jchavannes/btcd/wire"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/peers"
 	"github.com/memocash/memo/app/bitcoin/transaction"
 	"github.com/memocash/memo/app/bitcoin/wallet"
-	"github.com/memocash/memo/app/config"
 	"github.com/memocash/memo/app/db"
-	"log"
-	"net"
+	"time"
 )
 
-var ActionNode Node
-var UserNode Node
+const reconnectDelay = 10 * time.Second
 
 type Node struct {
 	Peer               *peer.Peer
@@ -29,35 +28,45 @@ type Node struct {
 	BlocksSyncComplete bool
 }
 
-func StartActionNode() {
-	ActionNode.Start(false)
+func RunActionNode() {
+	keepConnected(false)
 }
 
-func WaitForActionNodeDisconnect() {
-	ActionNode.Peer.WaitForDisconnect()
+func RunUserNode() {
+	keepConnected(true)
 }
 
-func StartUserNode() {
-	UserNode.Start(true)
-}
-
-func WaitForUserNodeDisconnect() {
-	UserNode.Peer.WaitForDisconnect()
+// keepConnected moves on to the next configured peer whenever the current one disconnects or is dropped for
+// misbehaving. Each peer gets a new node so handlers still running for the old peer don't share its state, sync
+// resumes from the saved node status.
+func keepConnected(userNode bool) {
+	var lastAddress string
+	for {
+		var n = &Node{}
+		err := n.Start(userNode, lastAddress)
+		if err != nil {
+			jerr.Get("error starting node", err).Print()
+			time.Sleep(reconnectDelay)
+			continue
+		}
+		lastAddress = n.Peer.Addr()
+		n.Peer.WaitForDisconnect()
+		fmt.Printf("Disconnected from peer: %s\n", lastAddress)
+	}
 }
 
-func (n *Node) Start(userNode bool) {
+func (n *Node) Start(userNode bool, lastAddress string) error {
 	nodeStatus, err := db.GetNodeStatus()
 	if err != nil {
-		log.Fatal(err)
+		return jerr.Get("error getting node status", err)
 	}
 	n.UserNode = userNode
 	transaction.EnableBatchPostProcessing()
-	bitcoinNodeConfig := config.GetBitcoinNode()
 	n.NodeStatus = nodeStatus
-	p, err := peer.NewOutboundPeer(&peer.Config{
+	p, err := peers.ConnectNext("node", &peer.Config{
 		UserAgentName:    "bch-lite-node",
 		UserAgentVersion: "0.1.0",
-		ChainParams:      &wallet.MainNetParams,
+		ChainParams:      &wallet.NetParams,
 		Listeners: peer.MessageListeners{
 			OnVerAck:      n.OnVerAck,
 			OnHeaders:     n.OnHeaders,
@@ -68,17 +77,12 @@ func (n *Node) Start(userNode bool) {
 			OnPing:        n.OnPing,
 			OnMerkleBlock: n.OnMerkleBlock,
 		},
-	}, bitcoinNodeConfig.GetConnectionString())
+	}, lastAddress)
 	if err != nil {
-		log.Fatal(err)
+		return jerr.Get("error connecting to peer", err)
 	}
 	n.Peer = p
-	fmt.Printf("Starting bitcoin node: %s\n", bitcoinNodeConfig.GetConnectionString())
-	conn, err := net.Dial("tcp", bitcoinNodeConfig.GetConnectionString())
-	if err != nil {
-		log.Fatal(err)
-	}
-	p.AssociateConnection(conn)
+	return nil
 }
 
 func (n *Node) OnVerAck(p *peer.Peer, msg *wire.MsgVerAck) {
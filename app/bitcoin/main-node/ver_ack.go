@@ -16,10 +16,10 @@ func onVerAck(n *Node, msg *wire.MsgVerAck) {
 			setBloomFilters(n)
 		}
 	}()
-	block, err := db.GetRecentBlock()
+	block, err := db.GetStore().GetRecentBlock()
 	if err != nil {
 		fmt.Println(jerr.Get("error getting recent block", err))
 		return
 	}
-	sendGetHeaders(n, block.GetChainhash())
+	sendGetHeaders(n, block)
 }
@@ -22,6 +22,8 @@ const (
 	CodePollCreate = 0x10
 	CodePollOption = 0x13
 	CodePollVote   = 0x14
+
+	CodeDirectMessage = 0x15
 )
 
 const (
@@ -50,6 +52,7 @@ func GetAllCodes() [][]byte {
 		{CodePrefix, CodePollVote},
 		{CodePrefix, CodeTopicFollow},
 		{CodePrefix, CodeTopicUnfollow},
+		{CodePrefix, CodeDirectMessage},
 	}
 }
 
@@ -71,6 +74,12 @@ func GetCodeString(code byte) string {
 		return StringMemoUnfollow
 	case CodeSetProfilePicture:
 		return StringMemoSetProfilePic
+	case CodeRepost:
+		return StringMemoRepost
+	case CodeSetImageBaseUrl:
+		return StringMemoImageBaseUrl
+	case CodeAttachPicture:
+		return StringMemoAttachPicture
 	case CodePollCreate:
 		return StringMemoPollQuestion
 	case CodePollOption:
@@ -83,6 +92,8 @@ func GetCodeString(code byte) string {
 		return StringMemoTopicFollow
 	case CodeTopicUnfollow:
 		return StringMemoTopicUnfollow
+	case CodeDirectMessage:
+		return StringMemoDirectMessage
 	default:
 		return "unknown"
 	}
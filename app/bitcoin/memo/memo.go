@@ -7,6 +7,16 @@ const (
 	MaxPollQuestionSize = 209
 	MaxPollOptionSize   = 184
 	MaxVoteCommentSize  = 184
+	MaxImageBaseUrlSize = 217
+	MaxPictureUrlSize   = 184
+	MaxPollRankSize     = 32
+)
+
+// Direct messages are encrypted to the recipient's public key (ECIES). The ciphertext adds 118 bytes to the message
+// padded to a 16 byte block and has to fit in the output after the 20 byte recipient pk hash.
+const (
+	MaxDirectMessageSize       = 63
+	MaxDirectMessageCipherSize = 196
 )
 
 // https://bitcoin.stackexchange.com/questions/1195/how-to-calculate-transaction-size-before-sending-legacy-non-segwit-p2pkh-p2sh
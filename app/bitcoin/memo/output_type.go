@@ -17,9 +17,15 @@ const (
 	OutputTypeMemoTopicUnfollow
 	OutputTypeMemoPollQuestionSingle
 	OutputTypeMemoPollQuestionMulti
+	OutputTypeMemoPollQuestionRank
 	OutputTypeMemoPollOption
 	OutputTypeMemoPollVote
+	OutputTypeMemoPollRankVote
 	OutputTypeMemoSetProfilePic
+	OutputTypeMemoRepost
+	OutputTypeMemoSetImageBaseUrl
+	OutputTypeMemoAttachPicture
+	OutputTypeMemoDirectMessage
 )
 
 const (
@@ -39,6 +45,10 @@ const (
 	StringMemoPollQuestion  = "poll-question"
 	StringMemoPollOption    = "poll-option"
 	StringMemoPollVote      = "poll-vote"
+	StringMemoRepost        = "memo-repost"
+	StringMemoImageBaseUrl  = "memo-set-image-base-url"
+	StringMemoAttachPicture = "memo-attach-picture"
+	StringMemoDirectMessage = "memo-direct-message"
 )
 
 func (s OutputType) String() string {
@@ -67,15 +77,66 @@ func (s OutputType) String() string {
 		return StringMemoTopicFollow
 	case OutputTypeMemoTopicUnfollow:
 		return StringMemoTopicUnfollow
-	case OutputTypeMemoPollQuestionSingle, OutputTypeMemoPollQuestionMulti:
+	case OutputTypeMemoPollQuestionSingle, OutputTypeMemoPollQuestionMulti, OutputTypeMemoPollQuestionRank:
 		return StringMemoPollQuestion
 	case OutputTypeMemoPollOption:
 		return StringMemoPollOption
-	case OutputTypeMemoPollVote:
+	case OutputTypeMemoPollVote, OutputTypeMemoPollRankVote:
 		return StringMemoPollVote
 	case OutputTypeMemoSetProfilePic:
 		return StringMemoSetProfilePic
+	case OutputTypeMemoRepost:
+		return StringMemoRepost
+	case OutputTypeMemoSetImageBaseUrl:
+		return StringMemoImageBaseUrl
+	case OutputTypeMemoAttachPicture:
+		return StringMemoAttachPicture
+	case OutputTypeMemoDirectMessage:
+		return StringMemoDirectMessage
 	default:
 		return "unknown"
 	}
 }
+
+func GetOutputTypeForCode(code byte) OutputType {
+	switch code {
+	case CodeSetName:
+		return OutputTypeMemoSetName
+	case CodePost:
+		return OutputTypeMemoMessage
+	case CodeReply:
+		return OutputTypeMemoReply
+	case CodeLike:
+		return OutputTypeMemoLike
+	case CodeSetProfile:
+		return OutputTypeMemoSetProfile
+	case CodeFollow:
+		return OutputTypeMemoFollow
+	case CodeUnfollow:
+		return OutputTypeMemoUnfollow
+	case CodeSetProfilePicture:
+		return OutputTypeMemoSetProfilePic
+	case CodeRepost:
+		return OutputTypeMemoRepost
+	case CodeSetImageBaseUrl:
+		return OutputTypeMemoSetImageBaseUrl
+	case CodeAttachPicture:
+		return OutputTypeMemoAttachPicture
+	case CodePollCreate:
+		return OutputTypeMemoPollQuestionSingle
+	case CodePollOption:
+		return OutputTypeMemoPollOption
+	case CodePollVote:
+		return OutputTypeMemoPollVote
+	case CodeTopicMessage:
+		return OutputTypeMemoTopicMessage
+	case CodeTopicFollow:
+		return OutputTypeMemoTopicFollow
+	case CodeTopicUnfollow:
+		return OutputTypeMemoTopicUnfollow
+	case CodeDirectMessage:
+		return OutputTypeMemoDirectMessage
+	default:
+		return OutputTypeReturn
+	}
+}
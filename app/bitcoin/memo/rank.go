@@ -0,0 +1,61 @@
+package memo
+
+import (
+	"bytes"
+	"github.com/jchavannes/jgo/jerr"
+	"sort"
+)
+
+// Rankings are encoded as one byte per choice, each byte being the index of the option when all of a poll's options
+// are sorted by tx hash. This keeps the encoding independent of the order options were indexed in.
+func EncodeRanking(optionTxHashes [][]byte, rankedTxHashes [][]byte) ([]byte, error) {
+	sorted := sortTxHashes(optionTxHashes)
+	if len(rankedTxHashes) == 0 {
+		return nil, jerr.New("empty ranking")
+	}
+	if len(rankedTxHashes) > MaxPollRankSize || len(rankedTxHashes) > len(sorted) {
+		return nil, jerr.New("too many ranked options")
+	}
+	var ranking []byte
+	for _, rankedTxHash := range rankedTxHashes {
+		var found bool
+		for i, txHash := range sorted {
+			if bytes.Equal(txHash, rankedTxHash) {
+				if bytes.IndexByte(ranking, byte(i)) != -1 {
+					return nil, jerr.New("option ranked more than once")
+				}
+				ranking = append(ranking, byte(i))
+				found = true
+				break
+			}
+		}
+		if ! found {
+			return nil, jerr.New("ranked option not found in poll")
+		}
+	}
+	return ranking, nil
+}
+
+// Invalid and duplicate indexes are skipped.
+func DecodeRanking(optionTxHashes [][]byte, ranking []byte) [][]byte {
+	sorted := sortTxHashes(optionTxHashes)
+	var rankedTxHashes [][]byte
+	var seen = make(map[byte]bool)
+	for _, index := range ranking {
+		if int(index) >= len(sorted) || seen[index] {
+			continue
+		}
+		seen[index] = true
+		rankedTxHashes = append(rankedTxHashes, sorted[index])
+	}
+	return rankedTxHashes
+}
+
+func sortTxHashes(txHashes [][]byte) [][]byte {
+	sorted := make([][]byte, len(txHashes))
+	copy(sorted, txHashes)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i], sorted[j]) < 0
+	})
+	return sorted
+}
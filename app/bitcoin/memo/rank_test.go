@@ -0,0 +1,35 @@
+package memo_test
+
+import (
+	"bytes"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"testing"
+)
+
+var optionTxHashes = [][]byte{
+	bytes.Repeat([]byte{0x03}, 32),
+	bytes.Repeat([]byte{0x01}, 32),
+	bytes.Repeat([]byte{0x02}, 32),
+}
+
+func TestRanking(t *testing.T) {
+	ranked := [][]byte{optionTxHashes[0], optionTxHashes[2]}
+	ranking, err := memo.EncodeRanking(optionTxHashes, ranked)
+	if err != nil {
+		t.Fatal(jerr.Get("error encoding ranking", err))
+	}
+	if ! bytes.Equal(ranking, []byte{2, 1}) {
+		t.Fatal(jerr.Newf("unexpected ranking: %x", ranking))
+	}
+	// Option order should not matter when decoding.
+	reordered := [][]byte{optionTxHashes[2], optionTxHashes[1], optionTxHashes[0]}
+	decoded := memo.DecodeRanking(reordered, append(ranking, 2, 7))
+	if len(decoded) != 2 || ! bytes.Equal(decoded[0], ranked[0]) || ! bytes.Equal(decoded[1], ranked[1]) {
+		t.Fatal(jerr.New("decoded ranking does not match original"))
+	}
+	_, err = memo.EncodeRanking(optionTxHashes, [][]byte{optionTxHashes[1], optionTxHashes[1]})
+	if err == nil {
+		t.Fatal(jerr.New("expected error for duplicate ranked option"))
+	}
+}
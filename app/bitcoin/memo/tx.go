@@ -8,6 +8,7 @@ type TxInput struct {
 	PkHash      []byte
 	Value       int64
 	PrevOutHash string
+	PkScript    []byte
 }
 
 type Tx struct {
@@ -15,4 +16,8 @@ type Tx struct {
 	SelfPkHash []byte
 	MsgTx      *wire.MsgTx
 	Inputs     []*TxInput
+	// Rate in satoshis per byte the tx was built with. Rebuild builds the same outputs at a higher rate, it is nil for
+	// txs which can not be rebuilt.
+	FeeRate int64
+	Rebuild func(feeRate int64) (*Tx, error)
 }
@@ -0,0 +1,130 @@
+package peers
+
+import (
+	"fmt"
+	"github.com/jchavannes/btcd/peer"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/config"
+	"github.com/memocash/memo/app/db"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+const dialTimeout = 10 * time.Second
+
+var connectedPeers = struct {
+	mutex sync.Mutex
+	peers map[*peer.Peer]*db.Peer
+}{
+	peers: make(map[*peer.Peer]*db.Peer),
+}
+
+// GetCandidates returns the configured nodes ordered by health followed by banned peers, oldest ban first. Banned
+// peers are only reached once every other candidate has failed or is already connected, so a node with every peer
+// banned keeps retrying instead of stopping.
+func GetCandidates() ([]*db.Peer, error) {
+	var candidates []*db.Peer
+	var bannedPeers []*db.Peer
+	for _, nodeConfig := range config.GetBitcoinNodes() {
+		address := nodeConfig.GetConnectionString()
+		tcpAddr, err := net.ResolveTCPAddr("tcp", address)
+		if err != nil {
+			jerr.Getf(err, "error resolving peer address (%s)", address).Print()
+			continue
+		}
+		dbPeer, err := db.GetOrCreatePeer(tcpAddr.IP, uint16(tcpAddr.Port), address)
+		if err != nil {
+			return nil, jerr.Get("error getting peer", err)
+		}
+		if dbPeer.IsBanned() {
+			bannedPeers = append(bannedPeers, dbPeer)
+			continue
+		}
+		candidates = append(candidates, dbPeer)
+	}
+	sort.Stable(db.PeerSortByHealth(candidates))
+	sort.Stable(db.PeerSortByBan(bannedPeers))
+	return append(candidates, bannedPeers...), nil
+}
+
+// Connect tries each candidate in order of health until one connects.
+func Connect(name string, peerConfig *peer.Config) (*peer.Peer, error) {
+	return ConnectNext(name, peerConfig, "")
+}
+
+// ConnectNext is the same as Connect except the last address is tried after every other candidate, so a node that
+// lost its peer moves on to the next one.
+func ConnectNext(name string, peerConfig *peer.Config, lastAddress string) (*peer.Peer, error) {
+	candidates, err := GetCandidates()
+	if err != nil {
+		return nil, jerr.Get("error getting peer candidates", err)
+	}
+	for i, candidate := range candidates {
+		if candidate.Address == lastAddress {
+			candidates = append(append(candidates[:i:i], candidates[i+1:]...), candidate)
+			break
+		}
+	}
+	for _, candidate := range candidates {
+		p, err := connectPeer(name, peerConfig, candidate)
+		if err != nil {
+			jerr.Getf(err, "error connecting to peer (%s)", candidate.Address).Print()
+			continue
+		}
+		return p, nil
+	}
+	return nil, jerr.New("unable to connect to any peer")
+}
+
+func connectPeer(name string, peerConfig *peer.Config, dbPeer *db.Peer) (*peer.Peer, error) {
+	p, err := peer.NewOutboundPeer(peerConfig, dbPeer.Address)
+	if err != nil {
+		return nil, jerr.Get("error creating outbound peer", err)
+	}
+	fmt.Printf("Starting bitcoin %s: %s\n", name, dbPeer.Address)
+	conn, err := net.DialTimeout("tcp", dbPeer.Address, dialTimeout)
+	if err != nil {
+		recordErr := dbPeer.RecordFailure()
+		if recordErr != nil {
+			jerr.Get("error recording peer failure", recordErr).Print()
+		}
+		return nil, jerr.Get("error dialing peer", err)
+	}
+	p.AssociateConnection(conn)
+	err = dbPeer.RecordConnect()
+	if err != nil {
+		jerr.Get("error recording peer connect", err).Print()
+	}
+	connectedPeers.mutex.Lock()
+	connectedPeers.peers[p] = dbPeer
+	connectedPeers.mutex.Unlock()
+	go func() {
+		p.WaitForDisconnect()
+		connectedPeers.mutex.Lock()
+		delete(connectedPeers.peers, p)
+		connectedPeers.mutex.Unlock()
+		err := dbPeer.RecordDisconnect()
+		if err != nil {
+			jerr.Get("error recording peer disconnect", err).Print()
+		}
+	}()
+	return p, nil
+}
+
+// Misbehaving records the peer as misbehaving and disconnects it so callers rotate to another peer.
+func Misbehaving(p *peer.Peer, reason string) {
+	connectedPeers.mutex.Lock()
+	dbPeer, ok := connectedPeers.peers[p]
+	connectedPeers.mutex.Unlock()
+	if ! ok {
+		return
+	}
+	fmt.Printf("Peer misbehaving (%s): %s\n", dbPeer.Address, reason)
+	err := dbPeer.RecordMisbehavior()
+	if err != nil {
+		jerr.Get("error recording peer misbehavior", err).Print()
+	}
+	p.Disconnect()
+}
@@ -0,0 +1,85 @@
+package peers
+
+import (
+	"github.com/jchavannes/btcd/peer"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/db"
+	"sync"
+	"time"
+)
+
+const poolCheckInterval = 10 * time.Second
+
+// Pool keeps up to Size connections open, replacing peers as they disconnect.
+type Pool struct {
+	Name       string
+	Size       int
+	PeerConfig *peer.Config
+	mutex      sync.Mutex
+	peers      []*peer.Peer
+}
+
+func (p *Pool) GetPeers() []*peer.Peer {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	var peers []*peer.Peer
+	for _, connectedPeer := range p.peers {
+		if connectedPeer.Connected() {
+			peers = append(peers, connectedPeer)
+		}
+	}
+	return peers
+}
+
+func (p *Pool) KeepAlive() {
+	for {
+		err := p.fill()
+		if err != nil {
+			jerr.Get("error filling peer pool", err).Print()
+		}
+		time.Sleep(poolCheckInterval)
+	}
+}
+
+func (p *Pool) fill() error {
+	p.mutex.Lock()
+	var connected = make(map[string]bool)
+	var peers []*peer.Peer
+	for _, connectedPeer := range p.peers {
+		if connectedPeer.Connected() {
+			connected[connectedPeer.Addr()] = true
+			peers = append(peers, connectedPeer)
+		}
+	}
+	p.peers = peers
+	p.mutex.Unlock()
+	if len(peers) >= p.Size {
+		return nil
+	}
+	candidates, err := GetCandidates()
+	if err != nil {
+		return jerr.Get("error getting peer candidates", err)
+	}
+	var available []*db.Peer
+	for _, candidate := range candidates {
+		if ! connected[candidate.Address] {
+			available = append(available, candidate)
+		}
+	}
+	for len(peers) < p.Size && len(available) > 0 {
+		newPeer, err := connectPeer(p.Name, p.PeerConfig, available[0])
+		available = available[1:]
+		if err != nil {
+			jerr.Get("error connecting pool peer", err).Print()
+			continue
+		}
+		peers = append(peers, newPeer)
+		p.mutex.Lock()
+		p.peers = append(p.peers, newPeer)
+		p.mutex.Unlock()
+	}
+	if len(peers) == 0 {
+		return jerr.New("no peers connected")
+	}
+	return nil
+}
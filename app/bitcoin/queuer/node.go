@@ -4,10 +4,10 @@ import (
 	"fmt"
 	"github.com/jchavannes/btcd/peer"
 	"github.com/jchavannes/btcd/wire"
+	"github.com/memocash/memo/app/bitcoin/fee"
+	"github.com/memocash/memo/app/bitcoin/peers"
 	"github.com/memocash/memo/app/bitcoin/wallet"
 	"github.com/memocash/memo/app/config"
-	"log"
-	"net"
 	"github.com/memocash/memo/app/metric"
 	"github.com/jchavannes/jgo/jerr"
 )
@@ -15,39 +15,38 @@ import (
 var Node QNode
 
 type QNode struct {
-	Peer *peer.Peer
+	Pool *peers.Pool
+	// Called when a peer rejects a tx.
+	OnTxReject func(p *peer.Peer, msg *wire.MsgReject)
 }
 
 func (n *QNode) Start() {
-	bitcoinNodeConfig := config.GetBitcoinNode()
-	var p, err = peer.NewOutboundPeer(&peer.Config{
-		UserAgentName:    "bch-lite-node",
-		UserAgentVersion: "0.1.0",
-		ChainParams:      &wallet.MainNetParams,
-		DisableRelayTx:   true,
-		Listeners: peer.MessageListeners{
-			OnReject: n.OnReject,
-			OnPing:   n.OnPing,
+	n.Pool = &peers.Pool{
+		Name: "queuer node",
+		Size: config.GetBitcoinNodeConnections(),
+		PeerConfig: &peer.Config{
+			UserAgentName:    "bch-lite-node",
+			UserAgentVersion: "0.1.0",
+			ChainParams:      &wallet.NetParams,
+			DisableRelayTx:   true,
+			Listeners: peer.MessageListeners{
+				OnReject:    n.OnReject,
+				OnPing:      n.OnPing,
+				OnFeeFilter: n.OnFeeFilter,
+			},
 		},
-	}, bitcoinNodeConfig.GetConnectionString())
-	if err != nil {
-		log.Fatal(err)
-	}
-	n.Peer = p
-	fmt.Printf("Starting bitcoin queuer node: %s\n", bitcoinNodeConfig.GetConnectionString())
-	conn, err := net.Dial("tcp", bitcoinNodeConfig.GetConnectionString())
-	if err != nil {
-		log.Fatal(err)
 	}
-	p.AssociateConnection(conn)
 }
 
 func (n *QNode) KeepAlive() {
-	for {
-		n.Peer.WaitForDisconnect()
-		fmt.Println("Queuer disconnected. Restarting.")
-		n.Start()
+	n.Pool.KeepAlive()
+}
+
+func (n *QNode) GetPeers() []*peer.Peer {
+	if n.Pool == nil {
+		return nil
 	}
+	return n.Pool.GetPeers()
 }
 
 func (n *QNode) OnReject(p *peer.Peer, msg *wire.MsgReject) {
@@ -56,10 +55,17 @@ func (n *QNode) OnReject(p *peer.Peer, msg *wire.MsgReject) {
 	if err != nil {
 		jerr.Get("error adding reject metric", err).Print()
 	}
+	if msg.Cmd == wire.CmdTx && n.OnTxReject != nil {
+		go n.OnTxReject(p, msg)
+	}
+}
+
+func (n *QNode) OnFeeFilter(p *peer.Peer, msg *wire.MsgFeeFilter) {
+	fee.SetMinRelayFee(p.Addr(), msg.MinFee)
 }
 
 func (n *QNode) OnPing(p *peer.Peer, msg *wire.MsgPing) {
-	n.Peer.QueueMessage(wire.NewMsgPong(msg.Nonce), nil)
+	p.QueueMessage(wire.NewMsgPong(msg.Nonce), nil)
 }
 
 func StartAndKeepAlive() {
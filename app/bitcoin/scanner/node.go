@@ -9,12 +9,11 @@ import (
 	"github.com/jchavannes/jgo/jerr"
 	"github.com/memocash/memo/app/bitcoin/main-node"
 	"github.com/memocash/memo/app/bitcoin/memo"
+	"github.com/memocash/memo/app/bitcoin/peers"
 	"github.com/memocash/memo/app/bitcoin/transaction"
 	"github.com/memocash/memo/app/bitcoin/wallet"
-	"github.com/memocash/memo/app/config"
 	"github.com/memocash/memo/app/db"
 	"log"
-	"net"
 )
 
 var Node SNode
@@ -30,11 +29,10 @@ type SNode struct {
 }
 
 func (n *SNode) Start() {
-	bitcoinNodeConfig := config.GetBitcoinNode()
-	var p, err = peer.NewOutboundPeer(&peer.Config{
+	var p, err = peers.Connect("scanner node", &peer.Config{
 		UserAgentName:    "bch-lite-node",
 		UserAgentVersion: "0.1.0",
-		ChainParams:      &wallet.MainNetParams,
+		ChainParams:      &wallet.NetParams,
 		DisableRelayTx:   true,
 		Listeners: peer.MessageListeners{
 			OnVerAck:      n.OnVerAck,
@@ -43,17 +41,11 @@ func (n *SNode) Start() {
 			OnMerkleBlock: n.OnMerkleBlock,
 			OnTx:          n.OnTx,
 		},
-	}, bitcoinNodeConfig.GetConnectionString())
+	})
 	if err != nil {
 		log.Fatal(err)
 	}
 	n.Peer = p
-	fmt.Printf("Starting bitcoin queuer node: %s\n", bitcoinNodeConfig.GetConnectionString())
-	conn, err := net.Dial("tcp", bitcoinNodeConfig.GetConnectionString())
-	if err != nil {
-		log.Fatal(err)
-	}
-	p.AssociateConnection(conn)
 }
 
 func (n *SNode) OnVerAck(p *peer.Peer, msg *wire.MsgVerAck) {
@@ -62,7 +54,7 @@ func (n *SNode) OnVerAck(p *peer.Peer, msg *wire.MsgVerAck) {
 	// start scanning
 	var minCheckHeight = uint(main_node.MinCheckHeight)
 	if n.NumBlocksBack > 0 {
-		recentBlock, err := db.GetRecentBlock()
+		recentBlock, err := db.GetStore().GetRecentBlock()
 		if err != nil {
 			jerr.Get("error getting recent block", err).Print()
 			n.Peer.Disconnect()
@@ -92,18 +84,27 @@ func (n *SNode) OnPing(p *peer.Peer, msg *wire.MsgPing) {
 }
 
 func setBloomFilters(n *SNode) {
-	allKeys, err := db.GetAllKeys()
+	allKeys, err := db.GetStore().GetAllKeys()
 	if err != nil {
 		jerr.Get("error getting keys from db", err).Print()
 		return
 	}
+	allKeyAddresses, err := db.GetAllKeyAddresses()
+	if err != nil {
+		jerr.Get("error getting key addresses from db", err).Print()
+		return
+	}
 	codes := memo.GetAllCodes()
-	fmt.Printf("Setting bloom filter (keys: %d, codes: %d)...\n", len(allKeys), len(codes))
-	bloomFilter := bloom.NewFilter(uint32(len(allKeys)*2), 0, 0, wire.BloomUpdateNone)
+	fmt.Printf("Setting bloom filter (keys: %d, change addresses: %d, codes: %d)...\n", len(allKeys), len(allKeyAddresses), len(codes))
+	bloomFilter := bloom.NewFilter(uint32((len(allKeys)+len(allKeyAddresses))*2), 0, 0, wire.BloomUpdateNone)
 	for _, key := range allKeys {
 		bloomFilter.Add(key.GetAddress().GetScriptAddress())
 		bloomFilter.Add(key.GetPublicKey().GetSerialized())
 	}
+	for _, keyAddress := range allKeyAddresses {
+		bloomFilter.Add(keyAddress.PkHash)
+		bloomFilter.Add(keyAddress.PublicKey)
+	}
 	for _, code := range codes {
 		bloomFilter.Add(code)
 	}
@@ -115,7 +116,7 @@ func queueMerkleBlocks(n *SNode, startingBlockHeight uint) error {
 		return jerr.New("blocks already queued")
 	}
 	//fmt.Printf("Queueing more merkle blocks (start: %d, end %d)\n", startingBlockHeight, endingBlockHeight)
-	blocks, err := db.GetBlocksInHeightRange(startingBlockHeight, startingBlockHeight+999)
+	blocks, err := db.GetStore().GetBlocksInHeightRange(startingBlockHeight, startingBlockHeight+999)
 	if err != nil {
 		return jerr.Get("error getting blocks in height range", err)
 	}
@@ -141,7 +142,7 @@ func queueMerkleBlocks(n *SNode, startingBlockHeight uint) error {
 }
 
 func onMerkleBlock(n *SNode, msg *wire.MsgMerkleBlock) {
-	block, err := db.GetBlockByHash(msg.Header.BlockHash())
+	block, err := db.GetStore().GetBlockByHash(msg.Header.BlockHash())
 	if err != nil {
 		jerr.Get("error getting block from db", err).Print()
 		return
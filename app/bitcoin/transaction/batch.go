@@ -2,8 +2,10 @@ package transaction
 
 import (
 	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/activitypub"
 	"github.com/memocash/memo/app/db"
 	"github.com/memocash/memo/app/notify"
+	"github.com/memocash/memo/app/webhook"
 )
 
 var (
@@ -11,6 +13,9 @@ var (
 	followsToNotify      []*db.MemoFollow
 	likesToNotify        []*db.MemoLike
 	repliesToNotify      []*db.MemoPost
+	messagesToNotify     []*db.MemoDirectMessage
+	postsToWebhook       []*db.MemoPost
+	postsToFederate      []*db.MemoPost
 	rootTxHashesToUpdate []*db.MemoPost
 )
 
@@ -49,6 +54,28 @@ func ProcessNotifications() (uint, []error) {
 		numNotifications++
 	}
 	repliesToNotify = []*db.MemoPost{}
+	for _, memoDirectMessage := range messagesToNotify {
+		err := notify.AddDirectMessageNotification(memoDirectMessage, true)
+		if err != nil {
+			errors = append(errors, jerr.Get("error adding direct message notification", err))
+		}
+		numNotifications++
+	}
+	messagesToNotify = []*db.MemoDirectMessage{}
+	for _, memoPost := range postsToWebhook {
+		err := webhook.AddPost(memoPost)
+		if err != nil {
+			errors = append(errors, jerr.Get("error adding post webhooks", err))
+		}
+	}
+	postsToWebhook = []*db.MemoPost{}
+	for _, memoPost := range postsToFederate {
+		err := activitypub.DeliverPost(memoPost)
+		if err != nil {
+			errors = append(errors, jerr.Get("error delivering activity pub post", err))
+		}
+	}
+	postsToFederate = []*db.MemoPost{}
 	return numNotifications, errors
 }
 
@@ -99,6 +126,39 @@ func addReplyNotification(memoPost *db.MemoPost) {
 	}
 }
 
+func addDirectMessageNotification(memoDirectMessage *db.MemoDirectMessage) {
+	if batchPostProcessing {
+		messagesToNotify = append(messagesToNotify, memoDirectMessage)
+		return
+	}
+	err := notify.AddDirectMessageNotification(memoDirectMessage, true)
+	if err != nil {
+		jerr.Get("error adding direct message notification", err).Print()
+	}
+}
+
+func addPostWebhooks(memoPost *db.MemoPost) {
+	if batchPostProcessing {
+		postsToWebhook = append(postsToWebhook, memoPost)
+		return
+	}
+	err := webhook.AddPost(memoPost)
+	if err != nil {
+		jerr.Get("error adding post webhooks", err).Print()
+	}
+}
+
+func federatePost(memoPost *db.MemoPost) {
+	if batchPostProcessing {
+		postsToFederate = append(postsToFederate, memoPost)
+		return
+	}
+	err := activitypub.DeliverPost(memoPost)
+	if err != nil {
+		jerr.Get("error delivering activity pub post", err).Print()
+	}
+}
+
 func updateRootTxHash(memoPost *db.MemoPost) {
 	if batchPostProcessing {
 		rootTxHashesToUpdate = append(rootTxHashesToUpdate, memoPost)
@@ -113,7 +173,7 @@ func updateRootTxHash(memoPost *db.MemoPost) {
 func doUpdateRootTxHash(memoPost *db.MemoPost) error {
 	var parentTxHash = memoPost.ParentTxHash
 	for {
-		prevMemoPost, err := db.GetMemoPost(parentTxHash)
+		prevMemoPost, err := db.GetStore().GetMemoPost(parentTxHash)
 		if err != nil {
 			return jerr.Get("error getting reply post from db", err)
 		}
@@ -3,11 +3,20 @@ package transaction
 import (
 	"github.com/jchavannes/btcd/peer"
 	"github.com/jchavannes/btcd/wire"
+	"github.com/jchavannes/jgo/jerr"
 )
 
+// Broadcast sends the tx to every peer and waits until it has been sent to each.
 func Broadcast(tx *wire.MsgTx, peers []*peer.Peer) error {
+	if len(peers) == 0 {
+		return jerr.New("no peers to broadcast to")
+	}
+	doneChan := make(chan struct{}, len(peers))
 	for _, p := range peers {
-		p.QueueMessage(tx, nil)
+		p.QueueMessage(tx, doneChan)
+	}
+	for range peers {
+		<-doneChan
 	}
 	return nil
 }
@@ -0,0 +1,20 @@
+package build
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+)
+
+func AttachPicture(txHashBytes []byte, url string, privateKeys []*wallet.PrivateKey) (*memo.Tx, error) {
+	transactions := []memo.Output{{
+		Type:    memo.OutputTypeMemoAttachPicture,
+		RefData: txHashBytes,
+		Data:    []byte(url),
+	}}
+	tx, err := Build(transactions, privateKeys)
+	if err != nil {
+		return nil, jerr.Get("error building attach picture tx", err)
+	}
+	return tx, nil
+}
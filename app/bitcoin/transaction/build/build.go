@@ -1,13 +1,20 @@
 package build
 
 import (
-	"github.com/jchavannes/btcd/wire"
 	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/fee"
 	"github.com/memocash/memo/app/bitcoin/memo"
 	"github.com/memocash/memo/app/bitcoin/transaction"
 	"github.com/memocash/memo/app/bitcoin/wallet"
 	"github.com/memocash/memo/app/db"
 	"sort"
+	"time"
+)
+
+// Reservations cover the time between building a tx and it being seen, rejected txs release them early.
+const (
+	reservationTimeout = 5 * time.Minute
+	maxReserveAttempts = 3
 )
 
 const notEnoughValueErrorText = "unable to find enough value to spend"
@@ -18,128 +25,240 @@ func IsNotEnoughValueError(err error) bool {
 	return jerr.HasError(err, notEnoughValueErrorText)
 }
 
-func Build(outputs []memo.Output, privateKey *wallet.PrivateKey) (*memo.Tx, error) {
-	spendableTxOuts, err := db.GetSpendableTransactionOutputsForPkHash(privateKey.GetPublicKey().GetAddress().GetScriptAddress())
+// Build spends from the profile address, payments also spend from any used HD change addresses. The first private
+// key is the profile key, the rest are change address keys.
+func Build(outputs []memo.Output, privateKeys []*wallet.PrivateKey) (*memo.Tx, error) {
+	feeRate, err := getFeeRate(privateKeys[0])
+	if err != nil {
+		return nil, jerr.Get("error getting fee rate", err)
+	}
+	return buildWithFeeRate(outputs, privateKeys, feeRate)
+}
+
+func buildWithFeeRate(outputs []memo.Output, privateKeys []*wallet.PrivateKey, feeRate int64) (*memo.Tx, error) {
+	profileKey := privateKeys[0]
+	pkHash := profileKey.GetPublicKey().GetAddress().GetScriptAddress()
+	changeKey, err := getChangeKey(outputs, profileKey)
+	if err != nil {
+		return nil, jerr.Get("error getting change key", err)
+	}
+	var changeAddress = profileKey.GetPublicKey().GetAddress()
+	if changeKey != nil {
+		changeAddress, err = changeKey.GetChangeAddress()
+		if err != nil {
+			return nil, jerr.Get("error getting change address", err)
+		}
+	}
+	spendingKeys := getSpendingKeys(outputs, privateKeys)
+	memoTx, err := buildAndReserve(func() (*memo.Tx, error) {
+		spendableTxOuts, err := getSpendableTxOutsForKeys(spendingKeys)
+		if err != nil {
+			return nil, jerr.Get("error getting spendable tx outs", err)
+		}
+		spendableTxOuts, err = db.FilterCoinSelection(pkHash, spendableTxOuts)
+		if err != nil {
+			return nil, jerr.Get("error filtering coin selection", err)
+		}
+		sort.Sort(db.TxOutSortByValue(spendableTxOuts))
+		memoTx, _, err := buildWithTxOuts(outputs, spendableTxOuts, spendingKeys, changeAddress, feeRate)
+		if err != nil {
+			return nil, jerr.Get("error creating tx", err)
+		}
+		return memoTx, nil
+	})
+	if err != nil {
+		return nil, jerr.Get("error building tx", err)
+	}
+	err = setBuilt(changeKey)
+	if err != nil {
+		return nil, jerr.Get("error updating after build", err)
+	}
+	memoTx.Rebuild = func(feeRate int64) (*memo.Tx, error) {
+		return buildWithFeeRate(outputs, privateKeys, feeRate)
+	}
+	return memoTx, nil
+}
+
+// The build is retried when another build, possibly on another instance, reserves one of its inputs first. Outputs
+// reserved by the other build are filtered out on the next attempt.
+func buildAndReserve(build func() (*memo.Tx, error)) (*memo.Tx, error) {
+	for attempt := 1; ; attempt++ {
+		memoTx, err := build()
+		if err != nil {
+			return nil, err
+		}
+		err = db.ReserveTxInputs(memoTx.MsgTx, time.Now().Add(reservationTimeout))
+		if err == nil {
+			return memoTx, nil
+		}
+		if ! db.IsOutputReservedError(err) || attempt >= maxReserveAttempts {
+			return nil, jerr.Get("error reserving tx inputs", err)
+		}
+	}
+}
+
+// A used change address is moved past. Coin selections are kept until the tx is seen so a rebuild, e.g. after a low
+// fee reject, spends the same outputs.
+func setBuilt(changeKey *db.Key) error {
+	if changeKey != nil {
+		err := changeKey.SetChangeAddressUsed(changeKey.ChangeIndex)
+		if err != nil {
+			return jerr.Get("error setting change address used", err)
+		}
+	}
+	return nil
+}
+
+func isPayment(outputs []memo.Output) bool {
+	for _, output := range outputs {
+		if output.Type != memo.OutputTypeP2PK {
+			return false
+		}
+	}
+	return true
+}
+
+// Memo actions are attributed to the input address, so they only spend from the profile address. Change outputs are
+// left to payments and consolidation, which sweeps them back to the profile address.
+func getSpendingKeys(outputs []memo.Output, privateKeys []*wallet.PrivateKey) []*wallet.PrivateKey {
+	if ! isPayment(outputs) {
+		return privateKeys[:1]
+	}
+	return privateKeys
+}
+
+// Memo actions are attributed to the input address, so change from them goes back to the profile address to fund
+// later actions. Payments without memo outputs from HD keys send change to a new address.
+func getChangeKey(outputs []memo.Output, privateKey *wallet.PrivateKey) (*db.Key, error) {
+	if ! isPayment(outputs) {
+		return nil, nil
+	}
+	key, err := db.GetKeyFromPublicKey(privateKey.GetPublicKey().GetSerialized())
+	if err != nil {
+		if db.IsRecordNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, jerr.Get("error getting key", err)
+	}
+	if ! key.IsHd() {
+		return nil, nil
+	}
+	return key, nil
+}
+
+func Unsigned(outputs []memo.Output, address wallet.Address) (*memo.Tx, error) {
+	spendableTxOuts, err := getSpendableTxOuts([][]byte{address.GetScriptAddress()})
 	if err != nil {
 		return nil, jerr.Get("error getting spendable tx outs", err)
 	}
 	sort.Sort(db.TxOutSortByValue(spendableTxOuts))
-	memoTx, _, err := buildWithTxOuts(outputs, spendableTxOuts, privateKey)
+	memoTx, _, _, err := buildUnsignedWithTxOuts(outputs, spendableTxOuts, address, address, fee.GetRate(fee.PriorityNormal))
 	if err != nil {
-		return nil, jerr.Get("error creating tx", err)
+		return nil, jerr.Get("error creating unsigned tx", err)
 	}
 	return memoTx, nil
 }
 
-func buildWithTxOuts(outputs []memo.Output, spendableTxOuts []*db.TransactionOut, privateKey *wallet.PrivateKey) (*memo.Tx, []*db.TransactionOut, error) {
-	var minInput = int64(memo.BaseTxFee + memo.InputFeeP2PKH + memo.OutputFeeP2PKH + memo.DustMinimumOutput)
+func buildWithTxOuts(outputs []memo.Output, spendableTxOuts []*db.TransactionOut, privateKeys []*wallet.PrivateKey, changeAddress wallet.Address, feeRate int64) (*memo.Tx, []*db.TransactionOut, error) {
+	memoTx, txOutsToUse, spendableTxOuts, err := buildUnsignedWithTxOuts(outputs, spendableTxOuts, privateKeys[0].GetPublicKey().GetAddress(), changeAddress, feeRate)
+	if err != nil {
+		return nil, nil, jerr.Get("error creating unsigned tx", err)
+	}
+	err = transaction.SignWithKeys(memoTx.MsgTx, txOutsToUse, privateKeys)
+	if err != nil {
+		return nil, nil, jerr.Get("error signing tx", err)
+	}
+	txHash := memoTx.MsgTx.TxHash()
+	var index uint32 = 0
+	spendableTxOuts = append([]*db.TransactionOut{{
+		TransactionHash: txHash.CloneBytes(),
+		PkScript:        memoTx.MsgTx.TxOut[index].PkScript,
+		KeyPkHash:       changeAddress.GetScriptAddress(),
+		Index:           index,
+		Value:           memoTx.MsgTx.TxOut[index].Value,
+	}}, spendableTxOuts...)
+	return memoTx, spendableTxOuts, nil
+}
 
+// Inputs are added until the change left after paying the fee for the signed size of the tx is above dust. Change is
+// the first output.
+func buildUnsignedWithTxOuts(outputs []memo.Output, spendableTxOuts []*db.TransactionOut, address wallet.Address, changeAddress wallet.Address, feeRate int64) (*memo.Tx, []*db.TransactionOut, []*db.TransactionOut, error) {
 	var spendOutputType memo.OutputType
+	var totalOutputValue int64
 	for _, spendOutput := range outputs {
-		switch spendOutput.Type {
-		case memo.OutputTypeP2PK:
-			minInput += memo.OutputFeeP2PKH + spendOutput.Amount
-		default:
+		totalOutputValue += spendOutput.Amount
+		if spendOutput.Type != memo.OutputTypeP2PK {
 			spendOutputType = spendOutput.Type
-			outputFee, err := getMemoOutputFee(spendOutput)
-			if err != nil {
-				return nil, nil, jerr.Get("error getting memo output fee", err)
-			}
-			minInput += outputFee
 		}
 	}
+	outputs = append([]memo.Output{{
+		Type:    memo.OutputTypeP2PK,
+		Address: changeAddress,
+	}}, outputs...)
 
 	var txOutsToUse []*db.TransactionOut
 	var totalInputValue int64
 	for {
 		if len(spendableTxOuts) == 0 {
-			return nil, nil, notEnoughValueError
+			return nil, nil, nil, notEnoughValueError
 		}
 		spendableTxOut := spendableTxOuts[0]
 		spendableTxOuts = spendableTxOuts[1:]
 		txOutsToUse = append(txOutsToUse, spendableTxOut)
 		totalInputValue += spendableTxOut.Value
-		if totalInputValue > minInput {
-			break
+		if totalInputValue < totalOutputValue+memo.DustMinimumOutput {
+			continue
 		}
-		minInput += memo.InputFeeP2PKH
-	}
-
-	var fee = int64(memo.BaseTxFee+len(txOutsToUse)*memo.InputFeeP2PKH) + memo.OutputFeeP2PKH
-
-	var totalOutputValue int64
-	for _, spendOutput := range outputs {
-		totalOutputValue += spendOutput.Amount
-		switch spendOutput.Type {
-		case memo.OutputTypeP2PK:
-			fee += memo.OutputFeeP2PKH
-		default:
-			outputFee, err := getMemoOutputFee(spendOutput)
-			if err != nil {
-				return nil, nil, jerr.Get("error getting memo output fee", err)
-			}
-			fee += outputFee
+		tx, err := transaction.CreateUnsigned(txOutsToUse, outputs)
+		if err != nil {
+			return nil, nil, nil, jerr.Get("error creating tx", err)
 		}
+		var change = totalInputValue - totalOutputValue - getTxFee(tx, feeRate)
+		if change < memo.DustMinimumOutput {
+			continue
+		}
+		tx.TxOut[0].Value = change
+		return &memo.Tx{
+			SelfPkHash: address.GetScriptAddress(),
+			Type:       spendOutputType,
+			MsgTx:      tx,
+			Inputs:     getTxInputs(txOutsToUse),
+			FeeRate:    feeRate,
+		}, txOutsToUse, spendableTxOuts, nil
 	}
+}
 
-	var change = totalInputValue - fee - totalOutputValue
-	if change < memo.DustMinimumOutput {
-		return nil, nil, jerr.New("change value below dust minimum input")
-	}
-	address := privateKey.GetPublicKey().GetAddress()
-	outputs = append([]memo.Output{{
-		Type:    memo.OutputTypeP2PK,
-		Address: address,
-		Amount:  change,
-	}}, outputs...)
-
-	var tx *wire.MsgTx
-	tx, err := transaction.Create(txOutsToUse, privateKey, outputs)
-	if err != nil {
-		return nil, nil, jerr.Get("error creating tx", err)
-	}
+func getTxInputs(txOuts []*db.TransactionOut) []*memo.TxInput {
 	var inputs []*memo.TxInput
-	for _, txOut := range txOutsToUse {
+	for _, txOut := range txOuts {
 		inputs = append(inputs, &memo.TxInput{
 			PkHash:      txOut.KeyPkHash,
 			Value:       txOut.Value,
 			PrevOutHash: txOut.GetHashString(),
+			PkScript:    txOut.PkScript,
 		})
 	}
-	txHash := tx.TxHash()
-	var index uint32 = 0
-	spendableTxOuts = append([]*db.TransactionOut{{
-		TransactionHash: txHash.CloneBytes(),
-		PkScript:        tx.TxOut[index].PkScript,
-		Index:           index,
-		Value:           change,
-	}}, spendableTxOuts...)
-	return &memo.Tx{
-		SelfPkHash: address.GetScriptAddress(),
-		Type:       spendOutputType,
-		MsgTx:      tx,
-		Inputs:     inputs,
-	}, spendableTxOuts, nil
+	return inputs
+}
+
+// Spendable outputs for the profile address and any HD change addresses.
+func getSpendableTxOutsForKeys(privateKeys []*wallet.PrivateKey) ([]*db.TransactionOut, error) {
+	var pkHashes [][]byte
+	for _, privateKey := range privateKeys {
+		pkHashes = append(pkHashes, privateKey.GetPublicKey().GetAddress().GetScriptAddress())
+	}
+	return getSpendableTxOuts(pkHashes)
 }
 
-func getMemoOutputFee(output memo.Output) (int64, error) {
-	switch output.Type {
-	case memo.OutputTypeMemoMessage,
-		memo.OutputTypeMemoLike,
-		memo.OutputTypeMemoSetName,
-		memo.OutputTypeMemoSetProfile,
-		memo.OutputTypeMemoSetProfilePic,
-		memo.OutputTypeMemoFollow, memo.OutputTypeMemoUnfollow,
-		memo.OutputTypeMemoTopicFollow, memo.OutputTypeMemoTopicUnfollow:
-		return int64(memo.OutputFeeOpReturn + len(output.Data)), nil
-	case memo.OutputTypeMemoReply,
-		memo.OutputTypeMemoTopicMessage,
-		memo.OutputTypeMemoPollOption,
-		memo.OutputTypeMemoPollVote:
-		return int64(memo.OutputFeeOpReturn + len(output.Data) + memo.OutputOpDataFee + len(output.RefData)), nil
-	case memo.OutputTypeMemoPollQuestionSingle,
-		memo.OutputTypeMemoPollQuestionMulti:
-		return int64(memo.OutputFeeOpReturn + (memo.OutputOpDataFee+1)*2 + len(output.Data)), nil
-	}
-	return 0, jerr.New("unable to get fee for output type")
+// Outputs reserved by a tx that has not been seen yet are left out.
+func getSpendableTxOuts(pkHashes [][]byte) ([]*db.TransactionOut, error) {
+	spendableTxOuts, err := db.GetSpendableTransactionOutputsForPkHashes(pkHashes)
+	if err != nil {
+		return nil, jerr.Get("error getting spendable tx outs", err)
+	}
+	spendableTxOuts, err = db.FilterReservedTransactionOutputs(spendableTxOuts)
+	if err != nil {
+		return nil, jerr.Get("error filtering reserved tx outs", err)
+	}
+	return spendableTxOuts, nil
 }
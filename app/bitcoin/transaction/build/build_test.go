@@ -0,0 +1,111 @@
+package build_test
+
+import (
+	"bytes"
+	"github.com/jchavannes/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/fee"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"github.com/memocash/memo/app/bitcoin/transaction"
+	"github.com/memocash/memo/app/bitcoin/transaction/build"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/testutil"
+	"testing"
+)
+
+const (
+	FundingValue = 100000
+	SendValue    = 10000
+)
+
+// Saves an unspent output paying the address and returns the hash of the tx it is in.
+func saveFundingOutput(t *testing.T, address wallet.Address) chainhash.Hash {
+	fundingTx, err := transaction.CreateUnsigned(nil, []memo.Output{{
+		Type:    memo.OutputTypeP2PK,
+		Address: address,
+		Amount:  FundingValue,
+	}})
+	if err != nil {
+		t.Fatal(jerr.Get("error creating funding tx", err))
+	}
+	fundingTxHash := fundingTx.TxHash()
+	err = db.TransactionOut{
+		TransactionHash: fundingTxHash.CloneBytes(),
+		KeyPkHash:       address.GetScriptAddress(),
+		Value:           FundingValue,
+		PkScript:        fundingTx.TxOut[0].PkScript,
+	}.Save()
+	if err != nil {
+		t.Fatal(jerr.Get("error saving funding output", err))
+	}
+	return fundingTxHash
+}
+
+func isSpent(memoTx *memo.Tx, txHash chainhash.Hash) bool {
+	for _, txIn := range memoTx.MsgTx.TxIn {
+		if bytes.Equal(txIn.PreviousOutPoint.Hash.CloneBytes(), txHash.CloneBytes()) {
+			return len(txIn.SignatureScript) > 0
+		}
+	}
+	return false
+}
+
+// Memo actions are credited to the address signing their inputs, so they only spend from the profile address.
+// Change on HD change addresses is spent by payments.
+func TestHdChangeSpending(t *testing.T) {
+	defer testutil.UseSqlite(t)()
+	defer testutil.UseMissMemcache(t)()
+
+	key, err := db.GenerateKey("test", "password", 1)
+	if err != nil {
+		t.Fatal(jerr.Get("error generating key", err))
+	}
+	changeAddress, err := key.GetChangeAddress()
+	if err != nil {
+		t.Fatal(jerr.Get("error getting change address", err))
+	}
+	err = key.SetChangeAddressUsed(key.ChangeIndex)
+	if err != nil {
+		t.Fatal(jerr.Get("error setting change address used", err))
+	}
+	privateKeys, err := key.GetSpendingKeys("password")
+	if err != nil {
+		t.Fatal(jerr.Get("error getting spending keys", err))
+	}
+	profileAddress := privateKeys[0].GetPublicKey().GetAddress()
+	changeTxHash := saveFundingOutput(t, changeAddress)
+	memoOutputs := []memo.Output{{
+		Type: memo.OutputTypeMemoMessage,
+		Data: []byte("memo action"),
+	}}
+
+	_, err = build.Build(memoOutputs, privateKeys)
+	if ! build.IsNotEnoughValueError(err) {
+		t.Fatal(jerr.Get("expected memo action not to spend change", err))
+	}
+	hasSpendable, err := db.HasSpendable(key.PkHash)
+	if err != nil {
+		t.Fatal(jerr.Get("error checking has spendable", err))
+	}
+	if hasSpendable {
+		t.Fatal(jerr.New("expected change not to be spendable by memo actions"))
+	}
+
+	sendTx, err := build.Send(profileAddress, SendValue, fee.MinRate, privateKeys)
+	if err != nil {
+		t.Fatal(jerr.Get("error sending from change", err))
+	}
+	if ! isSpent(sendTx, changeTxHash) {
+		t.Fatal(jerr.New("expected payment to spend and sign change output"))
+	}
+
+	profileTxHash := saveFundingOutput(t, profileAddress)
+	memoTx, err := build.Build(memoOutputs, privateKeys)
+	if err != nil {
+		t.Fatal(jerr.Get("error building memo action", err))
+	}
+	if len(memoTx.MsgTx.TxIn) != 1 || ! isSpent(memoTx, profileTxHash) {
+		t.Fatal(jerr.New("expected memo action to spend only the profile output"))
+	}
+}
@@ -0,0 +1,83 @@
+package build
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"github.com/memocash/memo/app/bitcoin/transaction"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"github.com/memocash/memo/app/db"
+	"sort"
+)
+
+// Keeps consolidation transactions well under the standard size limit.
+const MaxConsolidateInputs = 200
+
+const nothingToConsolidateErrorText = "need at least 2 outputs to consolidate"
+
+var nothingToConsolidateError = jerr.New(nothingToConsolidateErrorText)
+
+func IsNothingToConsolidateError(err error) bool {
+	return jerr.HasError(err, nothingToConsolidateErrorText)
+}
+
+// Consolidate sweeps the smallest spendable outputs, including any on HD change addresses, into one output to the
+// profile address. The first private key is the profile key. Run repeatedly when there are more than
+// MaxConsolidateInputs outputs. A fee rate of 0 uses the user's fee priority.
+func Consolidate(feeRate int64, privateKeys []*wallet.PrivateKey) (*memo.Tx, error) {
+	feeRate, err := getSendFeeRate(feeRate, privateKeys[0])
+	if err != nil {
+		return nil, jerr.Get("error getting fee rate", err)
+	}
+	address := privateKeys[0].GetPublicKey().GetAddress()
+	pkHash := address.GetScriptAddress()
+	memoTx, err := buildAndReserve(func() (*memo.Tx, error) {
+		spendableTxOuts, err := getSpendableTxOutsForKeys(privateKeys)
+		if err != nil {
+			return nil, jerr.Get("error getting spendable tx outs", err)
+		}
+		spendableTxOuts, err = db.FilterCoinSelection(pkHash, spendableTxOuts)
+		if err != nil {
+			return nil, jerr.Get("error filtering coin selection", err)
+		}
+		sort.Sort(sort.Reverse(db.TxOutSortByValue(spendableTxOuts)))
+		if len(spendableTxOuts) > MaxConsolidateInputs {
+			spendableTxOuts = spendableTxOuts[:MaxConsolidateInputs]
+		}
+		if len(spendableTxOuts) < 2 {
+			return nil, nothingToConsolidateError
+		}
+		var totalInputValue int64
+		for _, spendableTxOut := range spendableTxOuts {
+			totalInputValue += spendableTxOut.Value
+		}
+		tx, err := transaction.CreateUnsigned(spendableTxOuts, []memo.Output{{
+			Type:    memo.OutputTypeP2PK,
+			Address: address,
+		}})
+		if err != nil {
+			return nil, jerr.Get("error creating tx", err)
+		}
+		tx.TxOut[0].Value = totalInputValue - getTxFee(tx, feeRate)
+		if tx.TxOut[0].Value < memo.DustMinimumOutput {
+			return nil, notEnoughValueError
+		}
+		err = transaction.SignWithKeys(tx, spendableTxOuts, privateKeys)
+		if err != nil {
+			return nil, jerr.Get("error signing tx", err)
+		}
+		return &memo.Tx{
+			SelfPkHash: pkHash,
+			Type:       memo.OutputTypeP2PK,
+			MsgTx:      tx,
+			Inputs:     getTxInputs(spendableTxOuts),
+			FeeRate:    feeRate,
+		}, nil
+	})
+	if err != nil {
+		return nil, jerr.Get("error building tx", err)
+	}
+	memoTx.Rebuild = func(feeRate int64) (*memo.Tx, error) {
+		return Consolidate(feeRate, privateKeys)
+	}
+	return memoTx, nil
+}
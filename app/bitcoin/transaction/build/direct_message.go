@@ -0,0 +1,32 @@
+package build
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+)
+
+// DirectMessage encrypts the message to the recipient's public key. Only the recipient can read it, not even the
+// sender. The pk hash is passed separately since the recipient may sign with an uncompressed key.
+func DirectMessage(recipientPkHash []byte, recipientPublicKey wallet.PublicKey, message string, privateKeys []*wallet.PrivateKey) (*memo.Tx, error) {
+	if len(message) == 0 {
+		return nil, jerr.New("empty message")
+	}
+	if len(message) > memo.MaxDirectMessageSize {
+		return nil, jerr.New("message too large")
+	}
+	encrypted, err := recipientPublicKey.Encrypt([]byte(message))
+	if err != nil {
+		return nil, jerr.Get("error encrypting message", err)
+	}
+	transactions := []memo.Output{{
+		Type:    memo.OutputTypeMemoDirectMessage,
+		RefData: recipientPkHash,
+		Data:    encrypted,
+	}}
+	tx, err := Build(transactions, privateKeys)
+	if err != nil {
+		return nil, jerr.Get("error building direct message tx", err)
+	}
+	return tx, nil
+}
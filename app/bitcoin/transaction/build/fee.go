@@ -0,0 +1,38 @@
+package build
+
+import (
+	"github.com/jchavannes/btcd/wire"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/fee"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"github.com/memocash/memo/app/cache"
+	"github.com/memocash/memo/app/db"
+)
+
+// Largest P2PKH signature script, a low S DER signature with sighash byte and a compressed public key.
+const p2pkhSigScriptSize = 107
+
+// Uses the fee priority from the user's settings, keys without a user pay the normal rate.
+func getFeeRate(privateKey *wallet.PrivateKey) (int64, error) {
+	key, err := db.GetKeyFromPublicKey(privateKey.GetPublicKey().GetSerialized())
+	if err != nil {
+		if db.IsRecordNotFoundError(err) {
+			return fee.GetRate(fee.PriorityNormal), nil
+		}
+		return 0, jerr.Get("error getting key", err)
+	}
+	userSettings, err := cache.GetUserSettings(key.UserId)
+	if err != nil {
+		return 0, jerr.Get("error getting user settings", err)
+	}
+	return fee.GetRate(userSettings.FeePriority), nil
+}
+
+// Fee for the size of the tx once signed, signature scripts are empty when building.
+func getTxFee(tx *wire.MsgTx, feeRate int64) int64 {
+	size := tx.SerializeSize()
+	for _, txIn := range tx.TxIn {
+		size += p2pkhSigScriptSize - len(txIn.SignatureScript)
+	}
+	return fee.GetFee(size, feeRate)
+}
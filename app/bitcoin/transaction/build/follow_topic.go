@@ -6,24 +6,24 @@ import (
 	"github.com/memocash/memo/app/bitcoin/wallet"
 )
 
-func FollowTopic(topicName string, privateKey *wallet.PrivateKey) (*memo.Tx, error) {
+func FollowTopic(topicName string, privateKeys []*wallet.PrivateKey) (*memo.Tx, error) {
 	transactions := []memo.Output{{
 		Type: memo.OutputTypeMemoTopicFollow,
 		Data: []byte(topicName),
 	}}
-	tx, err := Build(transactions, privateKey)
+	tx, err := Build(transactions, privateKeys)
 	if err != nil {
 		return nil, jerr.Get("error building topic follow tx", err)
 	}
 	return tx, nil
 }
 
-func UnfollowTopic(topicName string, privateKey *wallet.PrivateKey) (*memo.Tx, error) {
+func UnfollowTopic(topicName string, privateKeys []*wallet.PrivateKey) (*memo.Tx, error) {
 	transactions := []memo.Output{{
 		Type: memo.OutputTypeMemoTopicUnfollow,
 		Data: []byte(topicName),
 	}}
-	tx, err := Build(transactions, privateKey)
+	tx, err := Build(transactions, privateKeys)
 	if err != nil {
 		return nil, jerr.Get("error building topic unfollow tx", err)
 	}
@@ -6,24 +6,24 @@ import (
 	"github.com/memocash/memo/app/bitcoin/wallet"
 )
 
-func FollowUser(pkHash []byte, privateKey *wallet.PrivateKey) (*memo.Tx, error) {
+func FollowUser(pkHash []byte, privateKeys []*wallet.PrivateKey) (*memo.Tx, error) {
 	transactions := []memo.Output{{
 		Type: memo.OutputTypeMemoFollow,
 		Data: pkHash,
 	}}
-	tx, err := Build(transactions, privateKey)
+	tx, err := Build(transactions, privateKeys)
 	if err != nil {
 		return nil, jerr.Get("error building memo tx", err)
 	}
 	return tx, nil
 }
 
-func UnfollowUser(pkHash []byte, privateKey *wallet.PrivateKey) (*memo.Tx, error) {
+func UnfollowUser(pkHash []byte, privateKeys []*wallet.PrivateKey) (*memo.Tx, error) {
 	transactions := []memo.Output{{
 		Type: memo.OutputTypeMemoUnfollow,
 		Data: pkHash,
 	}}
-	tx, err := Build(transactions, privateKey)
+	tx, err := Build(transactions, privateKeys)
 	if err != nil {
 		return nil, jerr.Get("error building memo tx", err)
 	}
@@ -0,0 +1,19 @@
+package build
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+)
+
+func ImageBaseUrl(url string, privateKeys []*wallet.PrivateKey) (*memo.Tx, error) {
+	transactions := []memo.Output{{
+		Type: memo.OutputTypeMemoSetImageBaseUrl,
+		Data: []byte(url),
+	}}
+	tx, err := Build(transactions, privateKeys)
+	if err != nil {
+		return nil, jerr.Get("error building image base url tx", err)
+	}
+	return tx, nil
+}
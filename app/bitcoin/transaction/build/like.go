@@ -7,7 +7,19 @@ import (
 	"github.com/memocash/memo/app/db"
 )
 
-func Like(likeTxBytes []byte, tip int64, privateKey *wallet.PrivateKey) (*memo.Tx, error) {
+func Like(likeTxBytes []byte, tip int64, privateKeys []*wallet.PrivateKey) (*memo.Tx, error) {
+	transactions, err := LikeOutputs(likeTxBytes, tip)
+	if err != nil {
+		return nil, jerr.Get("error getting like outputs", err)
+	}
+	tx, err := Build(transactions, privateKeys)
+	if err != nil {
+		return nil, jerr.Get("error building like tx", err)
+	}
+	return tx, nil
+}
+
+func LikeOutputs(likeTxBytes []byte, tip int64) ([]memo.Output, error) {
 	transactions := []memo.Output{{
 		Type: memo.OutputTypeMemoLike,
 		Data: likeTxBytes,
@@ -19,7 +31,7 @@ func Like(likeTxBytes []byte, tip int64, privateKey *wallet.PrivateKey) (*memo.T
 		if tip > 1e8 {
 			return nil, jerr.New("error trying to tip too much")
 		}
-		memoPost, err := db.GetMemoPost(likeTxBytes)
+		memoPost, err := db.GetStore().GetMemoPost(likeTxBytes)
 		if err != nil {
 			return nil, jerr.Get("error getting memo_post", err)
 		}
@@ -29,9 +41,5 @@ func Like(likeTxBytes []byte, tip int64, privateKey *wallet.PrivateKey) (*memo.T
 			Amount:  tip,
 		})
 	}
-	tx, err := Build(transactions, privateKey)
-	if err != nil {
-		return nil, jerr.Get("error building like tx", err)
-	}
-	return tx, nil
+	return transactions, nil
 }
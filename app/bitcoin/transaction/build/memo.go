@@ -6,12 +6,12 @@ import (
 	"github.com/memocash/memo/app/bitcoin/wallet"
 )
 
-func MemoMessage(message string, privateKey *wallet.PrivateKey) (*memo.Tx, error) {
+func MemoMessage(message string, privateKeys []*wallet.PrivateKey) (*memo.Tx, error) {
 	transactions := []memo.Output{{
 		Type: memo.OutputTypeMemoMessage,
 		Data: []byte(message),
 	}}
-	tx, err := Build(transactions, privateKey)
+	tx, err := Build(transactions, privateKeys)
 	if err != nil {
 		return nil, jerr.Get("error building memo tx", err)
 	}
@@ -6,12 +6,12 @@ import (
 	"github.com/memocash/memo/app/bitcoin/wallet"
 )
 
-func SetName(name string, privateKey *wallet.PrivateKey) (*memo.Tx, error) {
+func SetName(name string, privateKeys []*wallet.PrivateKey) (*memo.Tx, error) {
 	transactions := []memo.Output{{
 		Type: memo.OutputTypeMemoSetName,
 		Data: []byte(name),
 	}}
-	tx, err := Build(transactions, privateKey)
+	tx, err := Build(transactions, privateKeys)
 	if err != nil {
 		return nil, jerr.Get("error building memo set name tx", err)
 	}
@@ -6,20 +6,29 @@ import (
 	"github.com/memocash/memo/app/bitcoin/wallet"
 	"github.com/memocash/memo/app/db"
 	"sort"
+	"time"
 )
 
-func Poll(pollType memo.PollType, question string, options []string, privateKey *wallet.PrivateKey) ([]*memo.Tx, error) {
+func Poll(pollType memo.PollType, question string, options []string, privateKeys []*wallet.PrivateKey) ([]*memo.Tx, error) {
 	var outputType memo.OutputType
 	switch memo.PollType(pollType) {
 	case memo.PollTypeOne:
 		outputType = memo.OutputTypeMemoPollQuestionSingle
 	case memo.PollTypeAny:
 		outputType = memo.OutputTypeMemoPollQuestionMulti
+	case memo.PollTypeRank:
+		outputType = memo.OutputTypeMemoPollQuestionRank
 	default:
 		return nil, jerr.New("invalid poll type")
 	}
 
-	spendableTxOuts, err := db.GetSpendableTransactionOutputsForPkHash(privateKey.GetPublicKey().GetAddress().GetScriptAddress())
+	profileAddress := privateKeys[0].GetPublicKey().GetAddress()
+	feeRate, err := getFeeRate(privateKeys[0])
+	if err != nil {
+		return nil, jerr.Get("error getting fee rate", err)
+	}
+	profileKeys := privateKeys[:1]
+	spendableTxOuts, err := getSpendableTxOutsForKeys(profileKeys)
 	if err != nil {
 		return nil, jerr.Get("error getting spendable tx outs", err)
 	}
@@ -30,7 +39,7 @@ func Poll(pollType memo.PollType, question string, options []string, privateKey
 		Type:    outputType,
 		Data:    []byte(question),
 		RefData: []byte{byte(len(options))},
-	}}, spendableTxOuts, privateKey)
+	}}, spendableTxOuts, profileKeys, profileAddress, feeRate)
 	if err != nil {
 		return nil, jerr.Get("error creating tx", err)
 	}
@@ -43,12 +52,35 @@ func Poll(pollType memo.PollType, question string, options []string, privateKey
 			Type:    memo.OutputTypeMemoPollOption,
 			Data:    []byte(option),
 			RefData: []byte(questionTxHashBytes),
-		}}, spendableTxOuts, privateKey)
+		}}, spendableTxOuts, profileKeys, profileAddress, feeRate)
 		if err != nil {
 			return nil, jerr.Get("error creating tx", err)
 		}
 		memoTxns = append(memoTxns, memoTx)
 	}
 
+	err = reserveTxs(memoTxns)
+	if err != nil {
+		return nil, jerr.Get("error reserving poll tx inputs", err)
+	}
 	return memoTxns, nil
 }
+
+// Reservations already made are removed if a later tx in the chain conflicts with another build.
+func reserveTxs(memoTxns []*memo.Tx) error {
+	for i, memoTx := range memoTxns {
+		err := db.ReserveTxInputs(memoTx.MsgTx, time.Now().Add(reservationTimeout))
+		if err == nil {
+			continue
+		}
+		for _, reservedTx := range memoTxns[:i] {
+			reservedTxHash := reservedTx.MsgTx.TxHash()
+			removeErr := db.RemoveUtxoReservations(reservedTxHash.CloneBytes())
+			if removeErr != nil {
+				jerr.Get("error removing utxo reservations", removeErr).Print()
+			}
+		}
+		return jerr.Get("error reserving tx inputs", err)
+	}
+	return nil
+}
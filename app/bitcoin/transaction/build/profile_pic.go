@@ -6,12 +6,12 @@ import (
 	"github.com/memocash/memo/app/bitcoin/wallet"
 )
 
-func ProfilePic(url string, privateKey *wallet.PrivateKey) (*memo.Tx, error) {
+func ProfilePic(url string, privateKeys []*wallet.PrivateKey) (*memo.Tx, error) {
 	transactions := []memo.Output{{
 		Type: memo.OutputTypeMemoSetProfilePic,
 		Data: []byte(url),
 	}}
-	tx, err := Build(transactions, privateKey)
+	tx, err := Build(transactions, privateKeys)
 	if err != nil {
 		return nil, jerr.Get("error building profile pic tx", err)
 	}
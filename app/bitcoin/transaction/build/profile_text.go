@@ -6,12 +6,12 @@ import (
 	"github.com/memocash/memo/app/bitcoin/wallet"
 )
 
-func SetProfileText(profileText string, privateKey *wallet.PrivateKey) (*memo.Tx, error) {
+func SetProfileText(profileText string, privateKeys []*wallet.PrivateKey) (*memo.Tx, error) {
 	transactions := []memo.Output{{
 		Type: memo.OutputTypeMemoSetProfile,
 		Data: []byte(profileText),
 	}}
-	tx, err := Build(transactions, privateKey)
+	tx, err := Build(transactions, privateKeys)
 	if err != nil {
 		return nil, jerr.Get("error building memo set profile text tx", err)
 	}
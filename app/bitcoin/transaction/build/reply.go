@@ -6,13 +6,13 @@ import (
 	"github.com/memocash/memo/app/bitcoin/wallet"
 )
 
-func MemoReply(txHashBytes []byte, message string, privateKey *wallet.PrivateKey) (*memo.Tx, error) {
+func MemoReply(txHashBytes []byte, message string, privateKeys []*wallet.PrivateKey) (*memo.Tx, error) {
 	transactions := []memo.Output{{
 		Type:    memo.OutputTypeMemoReply,
 		RefData: txHashBytes,
 		Data:    []byte(message),
 	}}
-	tx, err := Build(transactions, privateKey)
+	tx, err := Build(transactions, privateKeys)
 	if err != nil {
 		return nil, jerr.Get("error building memo reply tx", err)
 	}
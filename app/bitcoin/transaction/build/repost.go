@@ -0,0 +1,20 @@
+package build
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+)
+
+func Repost(txHashBytes []byte, message string, privateKeys []*wallet.PrivateKey) (*memo.Tx, error) {
+	transactions := []memo.Output{{
+		Type:    memo.OutputTypeMemoRepost,
+		RefData: txHashBytes,
+		Data:    []byte(message),
+	}}
+	tx, err := Build(transactions, privateKeys)
+	if err != nil {
+		return nil, jerr.Get("error building memo repost tx", err)
+	}
+	return tx, nil
+}
@@ -0,0 +1,86 @@
+package build
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/fee"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"github.com/memocash/memo/app/bitcoin/transaction"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"github.com/memocash/memo/app/db"
+	"sort"
+)
+
+// Send pays an address from the profile address and any used HD change addresses. The first private key is the
+// profile key, the rest are change address keys. A fee rate of 0 uses the user's fee priority.
+func Send(address wallet.Address, amount int64, feeRate int64, privateKeys []*wallet.PrivateKey) (*memo.Tx, error) {
+	if len(address.GetScriptAddress()) == 0 {
+		return nil, jerr.New("invalid address")
+	}
+	if amount < memo.DustMinimumOutput {
+		return nil, jerr.New("error amount not above dust limit")
+	}
+	profileKey := privateKeys[0]
+	feeRate, err := getSendFeeRate(feeRate, profileKey)
+	if err != nil {
+		return nil, jerr.Get("error getting fee rate", err)
+	}
+	pkHash := profileKey.GetPublicKey().GetAddress().GetScriptAddress()
+	outputs := []memo.Output{{
+		Type:    memo.OutputTypeP2PK,
+		Address: address,
+		Amount:  amount,
+	}}
+	changeKey, err := getChangeKey(outputs, profileKey)
+	if err != nil {
+		return nil, jerr.Get("error getting change key", err)
+	}
+	var changeAddress = profileKey.GetPublicKey().GetAddress()
+	if changeKey != nil {
+		changeAddress, err = changeKey.GetChangeAddress()
+		if err != nil {
+			return nil, jerr.Get("error getting change address", err)
+		}
+	}
+	memoTx, err := buildAndReserve(func() (*memo.Tx, error) {
+		spendableTxOuts, err := getSpendableTxOutsForKeys(privateKeys)
+		if err != nil {
+			return nil, jerr.Get("error getting spendable tx outs", err)
+		}
+		spendableTxOuts, err = db.FilterCoinSelection(pkHash, spendableTxOuts)
+		if err != nil {
+			return nil, jerr.Get("error filtering coin selection", err)
+		}
+		sort.Sort(db.TxOutSortByValue(spendableTxOuts))
+		memoTx, txOutsToUse, _, err := buildUnsignedWithTxOuts(outputs, spendableTxOuts, profileKey.GetPublicKey().GetAddress(), changeAddress, feeRate)
+		if err != nil {
+			return nil, jerr.Get("error creating tx", err)
+		}
+		err = transaction.SignWithKeys(memoTx.MsgTx, txOutsToUse, privateKeys)
+		if err != nil {
+			return nil, jerr.Get("error signing tx", err)
+		}
+		return memoTx, nil
+	})
+	if err != nil {
+		return nil, jerr.Get("error building tx", err)
+	}
+	err = setBuilt(changeKey)
+	if err != nil {
+		return nil, jerr.Get("error updating after build", err)
+	}
+	memoTx.Rebuild = func(feeRate int64) (*memo.Tx, error) {
+		return Send(address, amount, feeRate, privateKeys)
+	}
+	return memoTx, nil
+}
+
+// Rates below the minimum relay rate are raised to it.
+func getSendFeeRate(feeRate int64, profileKey *wallet.PrivateKey) (int64, error) {
+	if feeRate == 0 {
+		return getFeeRate(profileKey)
+	}
+	if feeRate < fee.MinRate || feeRate > fee.MaxRate {
+		return 0, jerr.Newf("error fee rate must be between %d and %d", fee.MinRate, fee.MaxRate)
+	}
+	return fee.Clamp(feeRate), nil
+}
@@ -6,13 +6,13 @@ import (
 	"github.com/memocash/memo/app/bitcoin/wallet"
 )
 
-func TopicMessage(topicName string, message string, privateKey *wallet.PrivateKey) (*memo.Tx, error) {
+func TopicMessage(topicName string, message string, privateKeys []*wallet.PrivateKey) (*memo.Tx, error) {
 	transactions := []memo.Output{{
 		Type:    memo.OutputTypeMemoTopicMessage,
 		RefData: []byte(topicName),
 		Data:    []byte(message),
 	}}
-	tx, err := Build(transactions, privateKey)
+	tx, err := Build(transactions, privateKeys)
 	if err != nil {
 		return nil, jerr.Get("error building memo tx", err)
 	}
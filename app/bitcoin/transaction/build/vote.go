@@ -7,7 +7,7 @@ import (
 	"github.com/memocash/memo/app/db"
 )
 
-func Vote(pollTxBytes []byte, message string, tip int64, privateKey *wallet.PrivateKey) (*memo.Tx, error) {
+func Vote(pollTxBytes []byte, message string, tip int64, privateKeys []*wallet.PrivateKey) (*memo.Tx, error) {
 	transactions := []memo.Output{{
 		Type:    memo.OutputTypeMemoPollVote,
 		Data:    pollTxBytes,
@@ -30,9 +30,48 @@ func Vote(pollTxBytes []byte, message string, tip int64, privateKey *wallet.Priv
 			Amount:  tip,
 		})
 	}
-	tx, err := Build(transactions, privateKey)
+	tx, err := Build(transactions, privateKeys)
 	if err != nil {
 		return nil, jerr.Get("error building vote tx", err)
 	}
 	return tx, nil
 }
+
+// Tips for ranked votes go to the creator of the first choice.
+func RankVote(question *db.MemoPollQuestion, rankedTxHashes [][]byte, message string, tip int64, privateKeys []*wallet.PrivateKey) (*memo.Tx, error) {
+	var optionTxHashes [][]byte
+	for _, option := range question.Options {
+		optionTxHashes = append(optionTxHashes, option.TxHash)
+	}
+	ranking, err := memo.EncodeRanking(optionTxHashes, rankedTxHashes)
+	if err != nil {
+		return nil, jerr.Get("error encoding ranking", err)
+	}
+	transactions := []memo.Output{{
+		Type:    memo.OutputTypeMemoPollRankVote,
+		Data:    append(append([]byte{}, rankedTxHashes[0]...), ranking...),
+		RefData: []byte(message),
+	}}
+	if tip != 0 {
+		if tip < memo.DustMinimumOutput {
+			return nil, jerr.New("error tip not above dust limit")
+		}
+		if tip > 1e8 {
+			return nil, jerr.New("error trying to tip too much")
+		}
+		memoPollOption, err := db.GetMemoPollOption(rankedTxHashes[0])
+		if err != nil {
+			return nil, jerr.Get("error getting memo poll option", err)
+		}
+		transactions = append(transactions, memo.Output{
+			Type:    memo.OutputTypeP2PK,
+			Address: memoPollOption.GetAddress(),
+			Amount:  tip,
+		})
+	}
+	tx, err := Build(transactions, privateKeys)
+	if err != nil {
+		return nil, jerr.Get("error building rank vote tx", err)
+	}
+	return tx, nil
+}
@@ -11,6 +11,19 @@ import (
 )
 
 func Create(spendOuts []*db.TransactionOut, privateKey *wallet.PrivateKey, spendOutputs []memo.Output) (*wire.MsgTx, error) {
+	tx, err := CreateUnsigned(spendOuts, spendOutputs)
+	if err != nil {
+		return nil, jerr.Get("error creating unsigned tx", err)
+	}
+	err = Sign(tx, spendOuts, privateKey)
+	if err != nil {
+		return nil, jerr.Get("error signing tx", err)
+	}
+	return tx, nil
+}
+
+// Inputs are left without signature scripts so the tx can be signed elsewhere.
+func CreateUnsigned(spendOuts []*db.TransactionOut, spendOutputs []memo.Output) (*wire.MsgTx, error) {
 	var txOuts []*wire.TxOut
 	for _, spendOutput := range spendOutputs {
 		switch spendOutput.Type {
@@ -194,7 +207,7 @@ func Create(spendOuts []*db.TransactionOut, privateKey *wallet.PrivateKey, spend
 				return nil, jerr.Get("error creating memo topic unfollow output", err)
 			}
 			txOuts = append(txOuts, wire.NewTxOut(spendOutput.Amount, pkScript))
-		case memo.OutputTypeMemoPollQuestionSingle, memo.OutputTypeMemoPollQuestionMulti:
+		case memo.OutputTypeMemoPollQuestionSingle, memo.OutputTypeMemoPollQuestionMulti, memo.OutputTypeMemoPollQuestionRank:
 			var question = spendOutput.Data
 			var optionCount = spendOutput.RefData
 			if len(question) > memo.MaxPollQuestionSize {
@@ -212,6 +225,8 @@ func Create(spendOuts []*db.TransactionOut, privateKey *wallet.PrivateKey, spend
 				pollType = memo.CodePollTypeSingle
 			case memo.OutputTypeMemoPollQuestionMulti:
 				pollType = memo.CodePollTypeMulti
+			case memo.OutputTypeMemoPollQuestionRank:
+				pollType = memo.CodePollTypeRank
 			default:
 				return nil, jerr.New("invalid poll type")
 			}
@@ -265,6 +280,29 @@ func Create(spendOuts []*db.TransactionOut, privateKey *wallet.PrivateKey, spend
 				return nil, jerr.Get("error creating memo poll vote output", err)
 			}
 			txOuts = append(txOuts, wire.NewTxOut(spendOutput.Amount, pkScript))
+		case memo.OutputTypeMemoPollRankVote:
+			// Data is the first choice option tx hash followed by the ranking, RefData is the message.
+			if len(spendOutput.Data) <= 32 {
+				return nil, jerr.New("invalid ranked vote data")
+			}
+			var ranking = spendOutput.Data[32:]
+			if len(ranking) > memo.MaxPollRankSize {
+				return nil, jerr.New("ranking too large")
+			}
+			if len(spendOutput.RefData)+len(ranking) > memo.MaxVoteCommentSize {
+				return nil, jerr.New("comment data too large")
+			}
+			pkScript, err := txscript.NewScriptBuilder().
+				AddOp(txscript.OP_RETURN).
+				AddData([]byte{memo.CodePrefix, memo.CodePollVote}).
+				AddData(spendOutput.Data[:32]).
+				AddData(spendOutput.RefData).
+				AddData(ranking).
+				Script()
+			if err != nil {
+				return nil, jerr.Get("error creating memo poll rank vote output", err)
+			}
+			txOuts = append(txOuts, wire.NewTxOut(spendOutput.Amount, pkScript))
 		case memo.OutputTypeMemoSetProfilePic:
 			if len(spendOutput.Data) > memo.MaxPostSize {
 				return nil, jerr.New("url too large")
@@ -281,6 +319,81 @@ func Create(spendOuts []*db.TransactionOut, privateKey *wallet.PrivateKey, spend
 				return nil, jerr.Get("error creating memo set pic output", err)
 			}
 			txOuts = append(txOuts, wire.NewTxOut(spendOutput.Amount, pkScript))
+		case memo.OutputTypeMemoRepost:
+			if len(spendOutput.RefData) != 32 {
+				return nil, jerr.New("invalid txn hash")
+			}
+			if len(spendOutput.Data) > memo.MaxReplySize {
+				return nil, jerr.New("data too large")
+			}
+			builder := txscript.NewScriptBuilder().
+				AddOp(txscript.OP_RETURN).
+				AddData([]byte{memo.CodePrefix, memo.CodeRepost}).
+				AddData(spendOutput.RefData)
+			if len(spendOutput.Data) > 0 {
+				builder = builder.AddData(spendOutput.Data)
+			}
+			pkScript, err := builder.Script()
+			if err != nil {
+				return nil, jerr.Get("error creating memo repost output", err)
+			}
+			txOuts = append(txOuts, wire.NewTxOut(spendOutput.Amount, pkScript))
+		case memo.OutputTypeMemoSetImageBaseUrl:
+			if len(spendOutput.Data) > memo.MaxImageBaseUrlSize {
+				return nil, jerr.New("url too large")
+			}
+			if len(spendOutput.Data) == 0 {
+				return nil, jerr.New("empty url")
+			}
+			pkScript, err := txscript.NewScriptBuilder().
+				AddOp(txscript.OP_RETURN).
+				AddData([]byte{memo.CodePrefix, memo.CodeSetImageBaseUrl}).
+				AddData(spendOutput.Data).
+				Script()
+			if err != nil {
+				return nil, jerr.Get("error creating memo set image base url output", err)
+			}
+			txOuts = append(txOuts, wire.NewTxOut(spendOutput.Amount, pkScript))
+		case memo.OutputTypeMemoAttachPicture:
+			if len(spendOutput.RefData) != 32 {
+				return nil, jerr.New("invalid txn hash")
+			}
+			if len(spendOutput.Data) > memo.MaxPictureUrlSize {
+				return nil, jerr.New("url too large")
+			}
+			if len(spendOutput.Data) == 0 {
+				return nil, jerr.New("empty url")
+			}
+			pkScript, err := txscript.NewScriptBuilder().
+				AddOp(txscript.OP_RETURN).
+				AddData([]byte{memo.CodePrefix, memo.CodeAttachPicture}).
+				AddData(spendOutput.RefData).
+				AddData(spendOutput.Data).
+				Script()
+			if err != nil {
+				return nil, jerr.Get("error creating memo attach picture output", err)
+			}
+			txOuts = append(txOuts, wire.NewTxOut(spendOutput.Amount, pkScript))
+		case memo.OutputTypeMemoDirectMessage:
+			if len(spendOutput.RefData) != 20 {
+				return nil, jerr.New("invalid recipient pk hash")
+			}
+			if len(spendOutput.Data) > memo.MaxDirectMessageCipherSize {
+				return nil, jerr.New("encrypted message too large")
+			}
+			if len(spendOutput.Data) == 0 {
+				return nil, jerr.New("empty encrypted message")
+			}
+			pkScript, err := txscript.NewScriptBuilder().
+				AddOp(txscript.OP_RETURN).
+				AddData([]byte{memo.CodePrefix, memo.CodeDirectMessage}).
+				AddData(spendOutput.RefData).
+				AddData(spendOutput.Data).
+				Script()
+			if err != nil {
+				return nil, jerr.Get("error creating memo direct message output", err)
+			}
+			txOuts = append(txOuts, wire.NewTxOut(spendOutput.Amount, pkScript))
 		}
 	}
 
@@ -305,7 +418,13 @@ func Create(spendOuts []*db.TransactionOut, privateKey *wallet.PrivateKey, spend
 		TxOut:    txOuts,
 		LockTime: 0,
 	}
+	return tx, nil
+}
 
+func Sign(tx *wire.MsgTx, spendOuts []*db.TransactionOut, privateKey *wallet.PrivateKey) error {
+	if len(tx.TxIn) != len(spendOuts) {
+		return jerr.New("spend outs do not match tx inputs")
+	}
 	for i := 0; i < len(spendOuts); i++ {
 		signature, err := txscript.SignatureScript(
 			tx,
@@ -317,9 +436,40 @@ func Create(spendOuts []*db.TransactionOut, privateKey *wallet.PrivateKey, spend
 			spendOuts[i].Value,
 		)
 		if err != nil {
-			return nil, jerr.Get("error signing transaction", err)
+			return jerr.Get("error signing transaction", err)
 		}
-		txIns[i].SignatureScript = signature
+		tx.TxIn[i].SignatureScript = signature
 	}
-	return tx, nil
+	return nil
+}
+
+// SignWithKeys signs each input with the key matching the spent output's address.
+func SignWithKeys(tx *wire.MsgTx, spendOuts []*db.TransactionOut, privateKeys []*wallet.PrivateKey) error {
+	if len(tx.TxIn) != len(spendOuts) {
+		return jerr.New("spend outs do not match tx inputs")
+	}
+	var keys = make(map[string]*wallet.PrivateKey)
+	for _, privateKey := range privateKeys {
+		keys[privateKey.GetPublicKey().GetAddress().GetEncoded()] = privateKey
+	}
+	for i := 0; i < len(spendOuts); i++ {
+		privateKey, ok := keys[wallet.GetAddressFromPkHash(spendOuts[i].KeyPkHash).GetEncoded()]
+		if ! ok {
+			return jerr.New("no private key for spend out address")
+		}
+		signature, err := txscript.SignatureScript(
+			tx,
+			i,
+			spendOuts[i].PkScript,
+			txscript.SigHashAll+wallet.SigHashForkID,
+			privateKey.GetBtcEcPrivateKey(),
+			true,
+			spendOuts[i].Value,
+		)
+		if err != nil {
+			return jerr.Get("error signing transaction", err)
+		}
+		tx.TxIn[i].SignatureScript = signature
+	}
+	return nil
 }
@@ -0,0 +1,89 @@
+package transaction
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"github.com/memocash/memo/app/bus"
+	"github.com/memocash/memo/app/db"
+)
+
+func publishMemoEvent(memoCode byte, txHash []byte, pkHash []byte) {
+	go func() {
+		var err error
+		switch memoCode {
+		case memo.CodePost, memo.CodeReply, memo.CodeTopicMessage, memo.CodePollCreate, memo.CodePollVote:
+			err = publishPostEvent(txHash)
+		case memo.CodeLike:
+			err = publishLikeEvent(txHash)
+		case memo.CodeFollow, memo.CodeUnfollow:
+			bus.Publish(bus.UserChannel(pkHash), bus.EventFollow, txHash)
+		}
+		if err != nil {
+			jerr.Get("error publishing memo event", err).Print()
+		}
+	}()
+}
+
+func publishPostEvent(txHash []byte) error {
+	memoPost, err := db.GetStore().GetMemoPost(txHash)
+	if err != nil {
+		return jerr.Get("error getting memo post", err)
+	}
+	bus.Publish(bus.UserChannel(memoPost.PkHash), bus.EventPost, txHash)
+	if memoPost.Topic != "" {
+		bus.Publish(bus.TopicChannel(memoPost.Topic), bus.EventPost, txHash)
+	}
+	rootTxHash, err := getThreadRootTxHash(memoPost)
+	if err != nil {
+		return jerr.Get("error getting thread root tx hash", err)
+	}
+	if len(rootTxHash) > 0 {
+		bus.Publish(bus.ThreadChannel(rootTxHash), bus.EventPost, txHash)
+	}
+	return nil
+}
+
+func publishLikeEvent(txHash []byte) error {
+	memoLike, err := db.GetStore().GetMemoLike(txHash)
+	if err != nil {
+		return jerr.Get("error getting memo like", err)
+	}
+	bus.Publish(bus.UserChannel(memoLike.PkHash), bus.EventLike, txHash)
+	memoPost, err := db.GetStore().GetMemoPost(memoLike.LikeTxHash)
+	if err != nil {
+		if db.IsRecordNotFoundError(err) {
+			return nil
+		}
+		return jerr.Get("error getting liked memo post", err)
+	}
+	if memoPost.Topic != "" {
+		bus.Publish(bus.TopicChannel(memoPost.Topic), bus.EventLike, txHash)
+	}
+	rootTxHash, err := getThreadRootTxHash(memoPost)
+	if err != nil {
+		return jerr.Get("error getting thread root tx hash", err)
+	}
+	if len(rootTxHash) == 0 {
+		rootTxHash = memoPost.TxHash
+	}
+	bus.Publish(bus.ThreadChannel(rootTxHash), bus.EventLike, txHash)
+	return nil
+}
+
+// Root tx hashes are set in batches by the action node so may not be set yet, fall back to the parent's root.
+func getThreadRootTxHash(memoPost *db.MemoPost) ([]byte, error) {
+	if len(memoPost.RootTxHash) > 0 || len(memoPost.ParentTxHash) == 0 {
+		return memoPost.RootTxHash, nil
+	}
+	parentPost, err := db.GetStore().GetMemoPost(memoPost.ParentTxHash)
+	if err != nil {
+		if db.IsRecordNotFoundError(err) {
+			return memoPost.ParentTxHash, nil
+		}
+		return nil, jerr.Get("error getting parent memo post", err)
+	}
+	if len(parentPost.RootTxHash) > 0 {
+		return parentPost.RootTxHash, nil
+	}
+	return parentPost.TxHash, nil
+}
@@ -77,3 +77,12 @@ func addMemoPollVoteFeedEvent(memoPollVote *db.MemoPollVote) {
 		}
 	}()
 }
+
+func addMemoRepostFeedEvent(memoRepost *db.MemoRepost) {
+	go func() {
+		err := feed_event.AddRepost(memoRepost)
+		if err != nil {
+			jerr.Get("error adding repost feed event", err).Print()
+		}
+	}()
+}
@@ -134,7 +134,7 @@ func GetTxInfo(tx *memo.Tx) TxInfo {
 		if err != nil {
 			return TxInfo{Error: jerr.Get("error disassembling lockScript", err)}
 		}
-		scriptClass, addresses, sigCount, err := txscript.ExtractPkScriptAddrs(out.PkScript, &wallet.MainNetParamsOld)
+		scriptClass, addresses, sigCount, err := txscript.ExtractPkScriptAddrs(out.PkScript, &wallet.NetParamsOld)
 		var txInfoAddress TxInfoAddress
 		if out.Value > 0 {
 			if len(addresses) != 1 {
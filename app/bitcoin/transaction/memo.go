@@ -14,6 +14,7 @@ import (
 	"github.com/memocash/memo/app/html-parser"
 	"github.com/memocash/memo/app/metric"
 	"github.com/memocash/memo/app/profile/pic"
+	"strings"
 )
 
 func GetMemoOutputIfExists(txn *db.Transaction) (*db.TransactionOut, error) {
@@ -124,8 +125,30 @@ func SaveMemo(txn *db.Transaction, out *db.TransactionOut, block *db.Block) erro
 		if err != nil {
 			return jerr.Get("error saving memo_set_pic", err)
 		}
+	case memo.CodeRepost:
+		err = saveMemoRepost(txn, out, block, inputAddress, parentHash)
+		if err != nil {
+			return jerr.Get("error saving memo_repost", err)
+		}
+	case memo.CodeSetImageBaseUrl:
+		err = saveMemoSetImageBaseUrl(txn, out, block, inputAddress, parentHash)
+		if err != nil {
+			return jerr.Get("error saving memo_set_image_base_url", err)
+		}
+	case memo.CodeAttachPicture:
+		err = saveMemoAttachPicture(txn, out, block, inputAddress, parentHash)
+		if err != nil {
+			return jerr.Get("error saving memo_attach_picture", err)
+		}
+	case memo.CodeDirectMessage:
+		err = saveMemoDirectMessage(txn, out, block, inputAddress, parentHash)
+		if err != nil {
+			return jerr.Get("error saving memo_direct_message", err)
+		}
 	}
+	updateSearchIndex(memoCode, txn.Hash, inputAddress.ScriptAddress())
 	if isNew {
+		publishMemoEvent(memoCode, txn.Hash, inputAddress.ScriptAddress())
 		go func() {
 			err := metric.AddMemoSave(memoCode)
 			if err != nil {
@@ -171,7 +194,7 @@ func saveMemoTest(txn *db.Transaction, out *db.TransactionOut, block *db.Block,
 }
 
 func saveMemoPost(txn *db.Transaction, out *db.TransactionOut, block *db.Block, inputAddress *btcutil.AddressPubKeyHash, parentHash []byte) error {
-	memoPost, err := db.GetMemoPost(txn.Hash)
+	memoPost, err := db.GetStore().GetMemoPost(txn.Hash)
 	if err != nil && ! db.IsRecordNotFoundError(err) {
 		return jerr.Get("error getting memo_post", err)
 	}
@@ -214,6 +237,8 @@ func saveMemoPost(txn *db.Transaction, out *db.TransactionOut, block *db.Block,
 	if err != nil {
 		return jerr.Get("error saving memo_post", err)
 	}
+	addPostWebhooks(memoPost)
+	federatePost(memoPost)
 	addMemoPostFeedEvent(memoPost)
 	return nil
 }
@@ -327,7 +352,7 @@ func saveMemoSetPic(txn *db.Transaction, out *db.TransactionOut, block *db.Block
 }
 
 func saveMemoFollow(txn *db.Transaction, out *db.TransactionOut, block *db.Block, inputAddress *btcutil.AddressPubKeyHash, parentHash []byte, unfollow bool) error {
-	memoFollow, err := db.GetMemoFollow(txn.Hash)
+	memoFollow, err := db.GetStore().GetMemoFollow(txn.Hash)
 	if err != nil && ! db.IsRecordNotFoundError(err) {
 		return jerr.Get("error getting memo_follow", err)
 	}
@@ -382,7 +407,7 @@ func saveMemoLike(txn *db.Transaction, out *db.TransactionOut, block *db.Block,
 	if len(out.PkScript) < 37 {
 		return jerr.New("script too short")
 	}
-	memoLike, err := db.GetMemoLike(txn.Hash)
+	memoLike, err := db.GetStore().GetMemoLike(txn.Hash)
 	if err != nil && ! db.IsRecordNotFoundError(err) {
 		return jerr.Get("error getting memo_like", err)
 	}
@@ -441,8 +466,236 @@ func saveMemoLike(txn *db.Transaction, out *db.TransactionOut, block *db.Block,
 	return nil
 }
 
+func saveMemoRepost(txn *db.Transaction, out *db.TransactionOut, block *db.Block, inputAddress *btcutil.AddressPubKeyHash, parentHash []byte) error {
+	memoRepost, err := db.GetMemoRepost(txn.Hash)
+	if err != nil && ! db.IsRecordNotFoundError(err) {
+		return jerr.Get("error getting memo_repost", err)
+	}
+	var blockId uint
+	if block != nil {
+		blockId = block.Id
+	}
+	if memoRepost != nil {
+		if memoRepost.BlockId != 0 || blockId == 0 {
+			return nil
+		}
+		memoRepost.BlockId = blockId
+		memoRepost.Block = block
+		err = memoRepost.Save()
+		if err != nil {
+			return jerr.Get("error saving memo_repost", err)
+		}
+		addMemoRepostFeedEvent(memoRepost)
+		return nil
+	}
+	pushData, err := txscript.PushedData(out.PkScript)
+	if err != nil {
+		return jerr.Get("error parsing push data from memo repost", err)
+	}
+	if len(pushData) != 2 && len(pushData) != 3 {
+		return jerr.Newf("invalid repost, incorrect push data (%d)", len(pushData))
+	}
+	txHash, err := chainhash.NewHash(pushData[1])
+	if err != nil {
+		return jerr.Get("error parsing transaction hash", err)
+	}
+	var message string
+	if len(pushData) == 3 {
+		message = string(pushData[2])
+	}
+	memoRepost = &db.MemoRepost{
+		TxHash:       txn.Hash,
+		PkHash:       inputAddress.ScriptAddress(),
+		PkScript:     out.PkScript,
+		ParentHash:   parentHash,
+		Address:      inputAddress.EncodeAddress(),
+		RepostTxHash: txHash.CloneBytes(),
+		Message:      html_parser.EscapeWithEmojis(message),
+		BlockId:      blockId,
+		Block:        block,
+	}
+	err = memoRepost.Save()
+	if err != nil {
+		return jerr.Get("error saving memo_repost", err)
+	}
+	addMemoRepostFeedEvent(memoRepost)
+	return nil
+}
+
+func saveMemoSetImageBaseUrl(txn *db.Transaction, out *db.TransactionOut, block *db.Block, inputAddress *btcutil.AddressPubKeyHash, parentHash []byte) error {
+	memoSetImageBaseUrl, err := db.GetMemoSetImageBaseUrl(txn.Hash)
+	if err != nil && ! db.IsRecordNotFoundError(err) {
+		return jerr.Get("error getting memo_set_image_base_url", err)
+	}
+	var blockId uint
+	if block != nil {
+		blockId = block.Id
+	}
+	if memoSetImageBaseUrl != nil {
+		if memoSetImageBaseUrl.BlockId != 0 || blockId == 0 {
+			return nil
+		}
+		memoSetImageBaseUrl.BlockId = blockId
+		memoSetImageBaseUrl.Block = block
+		err = memoSetImageBaseUrl.Save()
+		if err != nil {
+			return jerr.Get("error saving memo_set_image_base_url", err)
+		}
+		return nil
+	}
+	pushData, err := txscript.PushedData(out.PkScript)
+	if err != nil {
+		return jerr.Get("error parsing push data from set image base url", err)
+	}
+	if len(pushData) != 2 {
+		return jerr.Newf("invalid set image base url, incorrect push data (%d)", len(pushData))
+	}
+	memoSetImageBaseUrl = &db.MemoSetImageBaseUrl{
+		TxHash:     txn.Hash,
+		PkHash:     inputAddress.ScriptAddress(),
+		PkScript:   out.PkScript,
+		ParentHash: parentHash,
+		Address:    inputAddress.EncodeAddress(),
+		Url:        string(pushData[1]),
+		BlockId:    blockId,
+		Block:      block,
+	}
+	err = memoSetImageBaseUrl.Save()
+	if err != nil {
+		return jerr.Get("error saving memo_set_image_base_url", err)
+	}
+	return nil
+}
+
+func saveMemoAttachPicture(txn *db.Transaction, out *db.TransactionOut, block *db.Block, inputAddress *btcutil.AddressPubKeyHash, parentHash []byte) error {
+	memoAttachPicture, err := db.GetMemoAttachPicture(txn.Hash)
+	if err != nil && ! db.IsRecordNotFoundError(err) {
+		return jerr.Get("error getting memo_attach_picture", err)
+	}
+	var blockId uint
+	if block != nil {
+		blockId = block.Id
+	}
+	if memoAttachPicture != nil {
+		if memoAttachPicture.BlockId != 0 || blockId == 0 {
+			return nil
+		}
+		memoAttachPicture.BlockId = blockId
+		memoAttachPicture.Block = block
+		err = memoAttachPicture.Save()
+		if err != nil {
+			return jerr.Get("error saving memo_attach_picture", err)
+		}
+		return nil
+	}
+	pushData, err := txscript.PushedData(out.PkScript)
+	if err != nil {
+		return jerr.Get("error parsing push data from attach picture", err)
+	}
+	if len(pushData) != 3 {
+		return jerr.Newf("invalid attach picture, incorrect push data (%d)", len(pushData))
+	}
+	txHash, err := chainhash.NewHash(pushData[1])
+	if err != nil {
+		return jerr.Get("error parsing transaction hash", err)
+	}
+	var url = string(pushData[2])
+	// Relative urls are resolved against the image base url the same user had set at this point in the chain.
+	// Pictures that can't be resolved to an https url are skipped.
+	if ! strings.HasPrefix(url, "https://") {
+		if strings.HasPrefix(url, "http://") {
+			return nil
+		}
+		imageBaseUrl, err := db.GetImageBaseUrlForPkHashAtBlock(inputAddress.ScriptAddress(), block)
+		if err != nil {
+			return jerr.Get("error getting image base url", err)
+		}
+		if imageBaseUrl == nil {
+			return nil
+		}
+		url = strings.TrimRight(imageBaseUrl.Url, "/") + "/" + strings.TrimLeft(url, "/")
+		if ! strings.HasPrefix(url, "https://") {
+			return nil
+		}
+	}
+	memoAttachPicture = &db.MemoAttachPicture{
+		TxHash:     txn.Hash,
+		PkHash:     inputAddress.ScriptAddress(),
+		PkScript:   out.PkScript,
+		ParentHash: parentHash,
+		Address:    inputAddress.EncodeAddress(),
+		PostTxHash: txHash.CloneBytes(),
+		Url:        url,
+		BlockId:    blockId,
+		Block:      block,
+	}
+	err = memoAttachPicture.Save()
+	if err != nil {
+		return jerr.Get("error saving memo_attach_picture", err)
+	}
+	go func() {
+		err := pic.FetchPostPic(memoAttachPicture.Url, memoAttachPicture.GetTransactionHashString())
+		if err != nil {
+			jerr.Get("error generating post pic", err).Print()
+		} else {
+			fmt.Printf("Generated post pic (%s) for tx %s\n", memoAttachPicture.Url, memoAttachPicture.GetTransactionHashString())
+		}
+	}()
+	return nil
+}
+
+func saveMemoDirectMessage(txn *db.Transaction, out *db.TransactionOut, block *db.Block, inputAddress *btcutil.AddressPubKeyHash, parentHash []byte) error {
+	memoDirectMessage, err := db.GetMemoDirectMessage(txn.Hash)
+	if err != nil && ! db.IsRecordNotFoundError(err) {
+		return jerr.Get("error getting memo_direct_message", err)
+	}
+	var blockId uint
+	if block != nil {
+		blockId = block.Id
+	}
+	if memoDirectMessage != nil {
+		if memoDirectMessage.BlockId != 0 || blockId == 0 {
+			return nil
+		}
+		memoDirectMessage.BlockId = blockId
+		memoDirectMessage.Block = block
+		err = memoDirectMessage.Save()
+		if err != nil {
+			return jerr.Get("error saving memo_direct_message", err)
+		}
+		return nil
+	}
+	pushData, err := txscript.PushedData(out.PkScript)
+	if err != nil {
+		return jerr.Get("error parsing push data from memo direct message", err)
+	}
+	if len(pushData) != 3 {
+		return jerr.Newf("invalid direct message, incorrect push data (%d)", len(pushData))
+	}
+	if len(pushData[1]) != 20 {
+		return jerr.Newf("invalid direct message recipient pk hash (%x)", pushData[1])
+	}
+	memoDirectMessage = &db.MemoDirectMessage{
+		TxHash:          txn.Hash,
+		PkHash:          inputAddress.ScriptAddress(),
+		PkScript:        out.PkScript,
+		ParentHash:      parentHash,
+		Address:         inputAddress.EncodeAddress(),
+		RecipientPkHash: pushData[1],
+		Encrypted:       pushData[2],
+		BlockId:         blockId,
+		Block:           block,
+	}
+	err = memoDirectMessage.Save()
+	if err != nil {
+		return jerr.Get("error saving memo_direct_message", err)
+	}
+	addDirectMessageNotification(memoDirectMessage)
+	return nil
+}
+
 func saveMemoReply(txn *db.Transaction, out *db.TransactionOut, block *db.Block, inputAddress *btcutil.AddressPubKeyHash, parentHash []byte) error {
-	memoPost, err := db.GetMemoPost(txn.Hash)
+	memoPost, err := db.GetStore().GetMemoPost(txn.Hash)
 	if err != nil && ! db.IsRecordNotFoundError(err) {
 		return jerr.Get("error getting memo_reply", err)
 	}
@@ -497,12 +750,14 @@ func saveMemoReply(txn *db.Transaction, out *db.TransactionOut, block *db.Block,
 	}
 	addReplyNotification(memoPost)
 	updateRootTxHash(memoPost)
+	addPostWebhooks(memoPost)
+	federatePost(memoPost)
 	addMemoPostFeedEvent(memoPost)
 	return nil
 }
 
 func saveMemoTopicMessage(txn *db.Transaction, out *db.TransactionOut, block *db.Block, inputAddress *btcutil.AddressPubKeyHash, parentHash []byte) error {
-	memoPost, err := db.GetMemoPost(txn.Hash)
+	memoPost, err := db.GetStore().GetMemoPost(txn.Hash)
 	if err != nil && ! db.IsRecordNotFoundError(err) {
 		return jerr.Get("error getting memo topic message", err)
 	}
@@ -553,6 +808,8 @@ func saveMemoTopicMessage(txn *db.Transaction, out *db.TransactionOut, block *db
 	if err != nil {
 		return jerr.Get("error saving memo topic message", err)
 	}
+	addPostWebhooks(memoPost)
+	federatePost(memoPost)
 	addMemoPostFeedEvent(memoPost)
 	updateTopicInfo(topicName)
 	return nil
@@ -661,7 +918,7 @@ func saveMemoSetProfile(txn *db.Transaction, out *db.TransactionOut, block *db.B
 }
 
 func saveMemoPollQuestion(txn *db.Transaction, out *db.TransactionOut, block *db.Block, inputAddress *btcutil.AddressPubKeyHash, parentHash []byte) error {
-	memoPost, err := db.GetMemoPost(txn.Hash)
+	memoPost, err := db.GetStore().GetMemoPost(txn.Hash)
 	if err != nil && ! db.IsRecordNotFoundError(err) {
 		return jerr.Get("error getting memo_post", err)
 	}
@@ -825,9 +1082,14 @@ func saveMemoPollVote(txn *db.Transaction, out *db.TransactionOut, block *db.Blo
 		return jerr.Get("error parsing option transaction hash", err)
 	}
 	var message string
-	if len(pushData) == 3 {
+	if len(pushData) >= 3 {
 		message = string(pushData[2])
 	}
+	// Ranked votes include the full ranking as an additional push.
+	var ranking []byte
+	if len(pushData) == 4 {
+		ranking = pushData[3]
+	}
 	var tipPkHash []byte
 	var tipAmount int64
 	for _, txOut := range txn.TxOut {
@@ -846,6 +1108,7 @@ func saveMemoPollVote(txn *db.Transaction, out *db.TransactionOut, block *db.Blo
 		PkScript:     out.PkScript,
 		Message:      html_parser.EscapeWithEmojis(message),
 		OptionTxHash: optionTxHash.CloneBytes(),
+		Ranking:      ranking,
 		TipAmount:    tipAmount,
 		TipPkHash:    tipPkHash,
 		ParentHash:   parentHash,
@@ -861,7 +1124,7 @@ func saveMemoPollVote(txn *db.Transaction, out *db.TransactionOut, block *db.Blo
 }
 
 func saveMemoVotePost(txn *db.Transaction, out *db.TransactionOut, block *db.Block, inputAddress *btcutil.AddressPubKeyHash, parentHash []byte) error {
-	memoPost, err := db.GetMemoPost(txn.Hash)
+	memoPost, err := db.GetStore().GetMemoPost(txn.Hash)
 	if err != nil && ! db.IsRecordNotFoundError(err) {
 		return jerr.Get("error getting memo_post for poll vote", err)
 	}
@@ -890,7 +1153,7 @@ func saveMemoVotePost(txn *db.Transaction, out *db.TransactionOut, block *db.Blo
 		return jerr.Newf("invalid poll vote, incorrect push data (%d)", len(pushData))
 	}
 	var message string
-	if len(pushData) == 3 {
+	if len(pushData) >= 3 {
 		message = string(pushData[2])
 	}
 	if message == "" {
@@ -930,7 +1193,7 @@ func getInputPkHash(txn *db.Transaction) (*btcutil.AddressPubKeyHash, error) {
 		// Unknown script type
 		return nil, jerr.New("error no pk hash found")
 	}
-	addressPkHash, err := btcutil.NewAddressPubKeyHash(pkHash, &wallet.MainNetParamsOld)
+	addressPkHash, err := btcutil.NewAddressPubKeyHash(pkHash, &wallet.NetParamsOld)
 	if err != nil {
 		return nil, jerr.Get("error getting pubkeyhash from memo test", err)
 	}
@@ -0,0 +1,152 @@
+package transaction
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/jchavannes/btcd/wire"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"github.com/memocash/memo/app/bitcoin/queuer"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/metric"
+	"github.com/memocash/memo/app/mutex"
+	"time"
+)
+
+// Unconfirmed txs are sent to peers again every interval for up to a day.
+const (
+	rebroadcastInterval  = 10 * time.Minute
+	rebroadcastMaxAge    = 24 * time.Hour
+	rebroadcastBatchSize = 100
+)
+
+const txRejectedErrorText = "transaction rejected by peer"
+
+var txRejectedError = jerr.New(txRejectedErrorText)
+
+func IsTxRejectedError(err error) bool {
+	return jerr.HasError(err, txRejectedErrorText)
+}
+
+func init() {
+	queuer.Node.OnTxReject = onTxReject
+}
+
+func queueTx(tx *memo.Tx, rebuilds int) {
+	addRebuildTx(tx, rebuilds)
+	go func() {
+		err := metric.AddMemoBroadcast(tx.Type)
+		if err != nil {
+			jerr.Get("error adding memo broadcast metric", err).Print()
+		}
+	}()
+	outboundTx, err := addOutboundTx(tx)
+	if err != nil {
+		jerr.Get("error adding outbound tx", err).Print()
+	}
+	peers := queuer.Node.GetPeers()
+	addSentPeers(tx.MsgTx, peers)
+	err = Broadcast(tx.MsgTx, peers)
+	if err != nil {
+		jerr.Get("error broadcasting tx", err).Print()
+		return
+	}
+	if outboundTx != nil {
+		err = outboundTx.SetBroadcast()
+		if err != nil {
+			jerr.Get("error setting outbound tx broadcast", err).Print()
+		}
+	}
+}
+
+func addOutboundTx(tx *memo.Tx) (*db.OutboundTx, error) {
+	var raw bytes.Buffer
+	err := tx.MsgTx.Serialize(&raw)
+	if err != nil {
+		return nil, jerr.Get("error serializing tx", err)
+	}
+	txHash := tx.MsgTx.TxHash()
+	outboundTx, err := db.AddOutboundTx(txHash.CloneBytes(), tx.SelfPkHash, tx.Type.String(), raw.Bytes())
+	if err != nil {
+		return nil, jerr.Get("error saving outbound tx", err)
+	}
+	return outboundTx, nil
+}
+
+// Inputs of a rejected tx are no longer reserved. Txs rejected for an insufficient fee are rebuilt at a higher rate.
+// Otherwise the lock on the address is released so the inputs can be spent by the next action.
+func setTxRejected(msg *wire.MsgReject) {
+	outboundTx, err := db.GetOutboundTx(msg.Hash.CloneBytes())
+	if err != nil {
+		if ! db.IsRecordNotFoundError(err) {
+			jerr.Get("error getting rejected outbound tx", err).Print()
+		}
+		return
+	}
+	if outboundTx.IsRejected() {
+		return
+	}
+	err = db.RemoveUtxoReservations(msg.Hash.CloneBytes())
+	if err != nil {
+		jerr.Get("error removing utxo reservations", err).Print()
+	}
+	var replacementTx *memo.Tx
+	var rebuilds int
+	if msg.Code == wire.RejectInsufficientFee {
+		replacementTx, rebuilds = rebuildWithHigherFee(msg.Hash.String())
+	}
+	var replacedByTxHash []byte
+	if replacementTx != nil {
+		replacementTxHash := replacementTx.MsgTx.TxHash()
+		replacedByTxHash = replacementTxHash.CloneBytes()
+	}
+	err = outboundTx.SetRejected(fmt.Sprintf("%s: %s", msg.Code.String(), msg.Reason), replacedByTxHash)
+	if err != nil {
+		jerr.Get("error setting outbound tx rejected", err).Print()
+		return
+	}
+	if ! outboundTx.IsRejected() {
+		return
+	}
+	if replacementTx == nil {
+		mutex.Unlock(outboundTx.PkHash)
+		return
+	}
+	queueTx(replacementTx, rebuilds)
+}
+
+func RebroadcastPending() {
+	for {
+		time.Sleep(rebroadcastInterval)
+		err := rebroadcastPendingBatch()
+		if err != nil {
+			jerr.Get("error rebroadcasting pending txs", err).Print()
+		}
+	}
+}
+
+func rebroadcastPendingBatch() error {
+	now := time.Now()
+	outboundTxs, err := db.GetOutboundTxsToRebroadcast(now.Add(-rebroadcastMaxAge), now.Add(-rebroadcastInterval), rebroadcastBatchSize)
+	if err != nil {
+		return jerr.Get("error getting outbound txs to rebroadcast", err)
+	}
+	for _, outboundTx := range outboundTxs {
+		var msgTx wire.MsgTx
+		err = msgTx.Deserialize(bytes.NewReader(outboundTx.Raw))
+		if err != nil {
+			return jerr.Getf(err, "error deserializing outbound tx (%s)", outboundTx.GetTransactionHashString())
+		}
+		peers := queuer.Node.GetPeers()
+		addSentPeers(&msgTx, peers)
+		err = Broadcast(&msgTx, peers)
+		if err != nil {
+			return jerr.Get("error broadcasting tx", err)
+		}
+		err = outboundTx.SetBroadcast()
+		if err != nil {
+			return jerr.Get("error setting outbound tx broadcast", err)
+		}
+	}
+	return nil
+}
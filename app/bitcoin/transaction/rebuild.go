@@ -0,0 +1,69 @@
+package transaction
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/fee"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"sync"
+	"time"
+)
+
+// Broadcast txs are kept for a few minutes in case a peer rejects them for an insufficient fee.
+const (
+	rebuildTimeout = 5 * time.Minute
+	maxRebuilds    = 3
+)
+
+type rebuildTx struct {
+	Tx       *memo.Tx
+	Rebuilds int
+}
+
+var rebuildTxs = make(map[string]*rebuildTx)
+
+var rebuildMutex sync.Mutex
+
+func addRebuildTx(tx *memo.Tx, rebuilds int) {
+	if tx.Rebuild == nil {
+		return
+	}
+	txHash := tx.MsgTx.TxHash().String()
+	rebuildMutex.Lock()
+	rebuildTxs[txHash] = &rebuildTx{
+		Tx:       tx,
+		Rebuilds: rebuilds,
+	}
+	rebuildMutex.Unlock()
+	time.AfterFunc(rebuildTimeout, func() {
+		rebuildMutex.Lock()
+		delete(rebuildTxs, txHash)
+		rebuildMutex.Unlock()
+	})
+}
+
+// Returns nil if the tx can not be rebuilt. The lock on the address taken when the tx was built is held until the tx
+// is seen, so the rebuild runs under it.
+func rebuildWithHigherFee(txHash string) (*memo.Tx, int) {
+	rebuildMutex.Lock()
+	rejectedTx, ok := rebuildTxs[txHash]
+	delete(rebuildTxs, txHash)
+	rebuildMutex.Unlock()
+	if ! ok {
+		return nil, 0
+	}
+	if rejectedTx.Rebuilds >= maxRebuilds {
+		jerr.Newf("tx rejected for insufficient fee after %d rebuilds (%s)", rejectedTx.Rebuilds, txHash).Print()
+		return nil, 0
+	}
+	feeRate := fee.GetRebuildRate(rejectedTx.Tx.FeeRate)
+	if feeRate <= rejectedTx.Tx.FeeRate {
+		jerr.Newf("tx rejected for insufficient fee at max fee rate (%s)", txHash).Print()
+		return nil, 0
+	}
+	tx, err := rejectedTx.Tx.Rebuild(feeRate)
+	if err != nil {
+		jerr.Getf(err, "error rebuilding tx with higher fee (%s)", txHash).Print()
+		return nil, 0
+	}
+	return tx, rejectedTx.Rebuilds + 1
+}
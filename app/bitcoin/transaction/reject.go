@@ -0,0 +1,116 @@
+package transaction
+
+import (
+	"github.com/jchavannes/btcd/peer"
+	"github.com/jchavannes/btcd/wire"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/db"
+	"sync"
+	"time"
+)
+
+// A reject from one peer is not final since other peers may accept the tx. A tx is treated as rejected once every
+// peer it was sent to rejects it, or when it has not been seen a grace period after the first reject. Until then
+// reservations are left in place, they expire on their own if the tx is never seen.
+const (
+	rejectGracePeriod = time.Minute
+	sentPeersTimeout  = 5 * time.Minute
+)
+
+type txRejectState struct {
+	SentTo     map[string]bool
+	RejectedBy map[string]bool
+	LastReject *wire.MsgReject
+	Timer      *time.Timer
+}
+
+var rejects = make(map[string]*txRejectState)
+
+var rejectsMutex sync.Mutex
+
+func getTxRejectState(txHash string) *txRejectState {
+	rejectState, ok := rejects[txHash]
+	if ! ok {
+		rejectState = &txRejectState{
+			SentTo:     make(map[string]bool),
+			RejectedBy: make(map[string]bool),
+		}
+		rejects[txHash] = rejectState
+	}
+	return rejectState
+}
+
+// Records the peers a tx was broadcast to, rebroadcasts add to the set.
+func addSentPeers(tx *wire.MsgTx, peers []*peer.Peer) {
+	txHash := tx.TxHash().String()
+	rejectsMutex.Lock()
+	rejectState := getTxRejectState(txHash)
+	for _, p := range peers {
+		rejectState.SentTo[p.Addr()] = true
+	}
+	rejectsMutex.Unlock()
+	time.AfterFunc(sentPeersTimeout, func() {
+		rejectsMutex.Lock()
+		if rejectState, ok := rejects[txHash]; ok && rejectState.Timer == nil {
+			delete(rejects, txHash)
+		}
+		rejectsMutex.Unlock()
+	})
+}
+
+func (r *txRejectState) isRejectedByAll() bool {
+	if len(r.SentTo) == 0 {
+		return false
+	}
+	for address := range r.SentTo {
+		if ! r.RejectedBy[address] {
+			return false
+		}
+	}
+	return true
+}
+
+func onTxReject(p *peer.Peer, msg *wire.MsgReject) {
+	txHash := msg.Hash.String()
+	rejectsMutex.Lock()
+	rejectState := getTxRejectState(txHash)
+	rejectState.RejectedBy[p.Addr()] = true
+	rejectState.LastReject = msg
+	if rejectState.isRejectedByAll() {
+		if rejectState.Timer != nil {
+			rejectState.Timer.Stop()
+		}
+		delete(rejects, txHash)
+		rejectsMutex.Unlock()
+		setTxRejected(msg)
+		return
+	}
+	if rejectState.Timer == nil {
+		rejectState.Timer = time.AfterFunc(rejectGracePeriod, func() {
+			onRejectGracePeriod(txHash)
+		})
+	}
+	rejectsMutex.Unlock()
+}
+
+// The tx is only rejected if no peer relayed it back while waiting.
+func onRejectGracePeriod(txHash string) {
+	rejectsMutex.Lock()
+	rejectState, ok := rejects[txHash]
+	delete(rejects, txHash)
+	rejectsMutex.Unlock()
+	if ! ok {
+		return
+	}
+	outboundTx, err := db.GetOutboundTx(rejectState.LastReject.Hash.CloneBytes())
+	if err != nil {
+		if ! db.IsRecordNotFoundError(err) {
+			jerr.Get("error getting rejected outbound tx", err).Print()
+		}
+		return
+	}
+	if outboundTx.Status == db.OutboundTxStatusMempool || outboundTx.Status == db.OutboundTxStatusConfirmed {
+		return
+	}
+	setTxRejected(rejectState.LastReject)
+}
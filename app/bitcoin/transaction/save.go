@@ -33,6 +33,10 @@ func ConditionallySaveTransaction(msg *wire.MsgTx, dbBlock *db.Block, userNode b
 		if ! watched {
 			return false, false, nil
 		}
+		err = db.SetKeyAddressesUsed(pkHashes)
+		if err != nil {
+			return false, false, jerr.Get("error setting key addresses used", err)
+		}
 	} else {
 		savingMemo = true
 	}
@@ -70,6 +74,14 @@ func SaveTransaction(txn *db.Transaction, block *db.Block) error {
 			return jerr.Get("error saving new transaction", err)
 		}
 	}
+	err = db.SetOutboundTxSeen(hash.CloneBytes(), block != nil)
+	if err != nil {
+		return jerr.Get("error setting outbound tx seen", err)
+	}
+	err = db.ClearSpentCoinSelections(txn.TxIn)
+	if err != nil {
+		return jerr.Get("error clearing spent coin selections", err)
+	}
 	memoOutput, err := GetMemoOutputIfExists(txn)
 	if err != nil {
 		return jerr.Get("error getting memo output", err)
@@ -83,8 +95,13 @@ func SaveTransaction(txn *db.Transaction, block *db.Block) error {
 	return nil
 }
 
+// Balances of keys owning any HD change addresses are cleared too.
 func ClearCaches(pkHashes [][]byte) error {
-	for _, pkHash := range pkHashes {
+	keyPkHashes, err := db.GetKeyPkHashesForChangeAddresses(pkHashes)
+	if err != nil {
+		return jerr.Get("error getting key pk hashes for change addresses", err)
+	}
+	for _, pkHash := range append(pkHashes, keyPkHashes...) {
 		err := cache.ClearBalance(pkHash)
 		if err != nil && ! cache.IsMissError(err) {
 			return jerr.Get("error clearing balance cache", err)
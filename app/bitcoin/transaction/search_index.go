@@ -0,0 +1,24 @@
+package transaction
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"github.com/memocash/memo/app/obj/search"
+)
+
+func updateSearchIndex(memoCode byte, txHash []byte, pkHash []byte) {
+	go func() {
+		var err error
+		switch memoCode {
+		case memo.CodePost, memo.CodeReply, memo.CodeTopicMessage, memo.CodePollCreate, memo.CodePollVote:
+			err = search.UpdatePost(txHash)
+		case memo.CodeSetName:
+			err = search.UpdateName(pkHash)
+		case memo.CodeSetProfile:
+			err = search.UpdateProfile(pkHash)
+		}
+		if err != nil {
+			jerr.Get("error updating search index", err).Print()
+		}
+	}()
+}
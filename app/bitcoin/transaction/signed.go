@@ -0,0 +1,89 @@
+package transaction
+
+import (
+	"bytes"
+	"github.com/jchavannes/btcd/txscript"
+	"github.com/jchavannes/btcd/wire"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"github.com/memocash/memo/app/db"
+)
+
+// GetSignedMemoTx checks a tx signed outside of the server before it is queued for broadcast. Apart from its signatures
+// it must match the unsigned tx issued for the build id, and each input script must verify against the output it spends.
+func GetSignedMemoTx(raw []byte, buildId string) (*memo.Tx, error) {
+	var msg = wire.NewMsgTx(wire.TxVersion)
+	err := msg.Deserialize(bytes.NewReader(raw))
+	if err != nil {
+		return nil, jerr.Get("error deserializing tx", err)
+	}
+	if len(msg.TxIn) == 0 {
+		return nil, jerr.New("tx has no inputs")
+	}
+	for _, in := range msg.TxIn {
+		if len(in.SignatureScript) == 0 {
+			return nil, jerr.New("tx has unsigned input")
+		}
+	}
+	unsignedBuild, err := db.GetUnsignedBuild(buildId)
+	if err != nil {
+		return nil, jerr.Get("error getting unsigned build", err)
+	}
+	unsignedMsg := msg.Copy()
+	for _, in := range unsignedMsg.TxIn {
+		in.SignatureScript = nil
+	}
+	unsignedTxHash := unsignedMsg.TxHash()
+	if ! bytes.Equal(unsignedTxHash.CloneBytes(), unsignedBuild.TxHash) {
+		return nil, jerr.New("tx does not match unsigned build")
+	}
+	txn, err := db.ConvertMsgToTransaction(msg)
+	if err != nil {
+		return nil, jerr.Get("error converting msg to transaction", err)
+	}
+	out, err := GetMemoOutputIfExists(txn)
+	if err != nil {
+		return nil, jerr.Get("error getting memo output", err)
+	}
+	if out == nil {
+		return nil, jerr.New("tx does not contain a memo output")
+	}
+	outputType := memo.GetOutputTypeForCode(out.PkScript[3])
+	if outputType == memo.OutputTypeReturn {
+		return nil, jerr.New("unknown memo output type")
+	}
+	inputAddress, err := getInputPkHash(txn)
+	if err != nil {
+		return nil, jerr.Get("error getting pk hash from input", err)
+	}
+	var inputs []*memo.TxInput
+	for i, in := range txn.TxIn {
+		txOut, err := db.GetTransactionOutput(in.PreviousOutPointHash, in.PreviousOutPointIndex)
+		if err != nil {
+			return nil, jerr.Get("error getting input previous output", err)
+		}
+		if txOut.TxnInHashString != "" {
+			return nil, jerr.New("input previous output already spent")
+		}
+		engine, err := txscript.NewEngine(txOut.PkScript, msg, i, txscript.StandardVerifyFlags, nil, nil, txOut.Value)
+		if err != nil {
+			return nil, jerr.Get("error creating script engine", err)
+		}
+		err = engine.Execute()
+		if err != nil {
+			return nil, jerr.Getf(err, "error verifying input script %d", i)
+		}
+		inputs = append(inputs, &memo.TxInput{
+			PkHash:      txOut.KeyPkHash,
+			Value:       txOut.Value,
+			PrevOutHash: txOut.GetHashString(),
+			PkScript:    txOut.PkScript,
+		})
+	}
+	return &memo.Tx{
+		Type:       outputType,
+		SelfPkHash: inputAddress.ScriptAddress(),
+		MsgTx:      msg,
+		Inputs:     inputs,
+	}, nil
+}
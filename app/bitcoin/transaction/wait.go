@@ -4,39 +4,45 @@ import (
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/jchavannes/jgo/jerr"
 	"github.com/memocash/memo/app/bitcoin/memo"
-	"github.com/memocash/memo/app/bitcoin/queuer"
 	"github.com/memocash/memo/app/db"
-	"github.com/memocash/memo/app/metric"
 	"time"
 )
 
 const waitTime = 200 * time.Millisecond
 
 func QueueTx(tx *memo.Tx) {
-	go func() {
-		err := metric.AddMemoBroadcast(tx.Type)
-		if err != nil {
-			jerr.Get("error adding memo broadcast metric", err).Print()
-		}
-	}()
-	doneChan := make(chan struct{}, 1)
-	queuer.Node.Peer.QueueMessage(tx.MsgTx, doneChan)
-	<-doneChan
+	queueTx(tx, 0)
 }
 
-func WaitForTx(txHash *chainhash.Hash) error {
+// WaitForTx returns the hash of the tx found, which is a replacement if the tx was rebuilt with a higher fee. Txs
+// rejected by a peer return a tx rejected error with the reason.
+func WaitForTx(txHash *chainhash.Hash) (*chainhash.Hash, error) {
 	// wait up to 30 seconds
 	for i := 0; i < 150; i++ {
 		_, err := db.GetTransactionByHash(txHash.CloneBytes())
 		if err == nil {
-			return nil
+			return txHash, nil
 		}
 		if ! db.IsRecordNotFoundError(err) {
-			return jerr.Get("error looking for transaction", err)
+			return nil, jerr.Get("error looking for transaction", err)
+		}
+		outboundTx, err := db.GetOutboundTx(txHash.CloneBytes())
+		if err != nil && ! db.IsRecordNotFoundError(err) {
+			return nil, jerr.Get("error looking for outbound transaction", err)
+		}
+		if outboundTx != nil && outboundTx.IsRejected() {
+			if len(outboundTx.ReplacedByTxHash) == 0 {
+				return nil, jerr.Get(outboundTx.RejectReason, txRejectedError)
+			}
+			txHash, err = chainhash.NewHash(outboundTx.ReplacedByTxHash)
+			if err != nil {
+				return nil, jerr.Get("error parsing replacement tx hash", err)
+			}
+			continue
 		}
 		time.Sleep(waitTime)
 	}
-	return jerr.New("unable to find transaction")
+	return nil, jerr.New("unable to find transaction")
 }
 
 func WaitForPic(txHash *chainhash.Hash) error {
@@ -8,12 +8,12 @@ func GetAddress(pubKey []byte) Address {
 	if len(pubKey) == 0 {
 		return Address{}
 	}
-	addr, err := btcutil.NewAddressPubKey(pubKey, &MainNetParamsOld)
+	addr, err := btcutil.NewAddressPubKey(pubKey, &NetParamsOld)
 	if err != nil {
 		//fmt.Println(jerr.Get("error getting address", err))
 		return Address{}
 	}
-	address, err := btcutil.DecodeAddress(addr.EncodeAddress(), &MainNetParamsOld)
+	address, err := btcutil.DecodeAddress(addr.EncodeAddress(), &NetParamsOld)
 	if err != nil {
 		//fmt.Printf("error decoding address: %v\n", err)
 		return Address{}
@@ -24,7 +24,7 @@ func GetAddress(pubKey []byte) Address {
 }
 
 func GetAddressFromString(addressString string) Address {
-	address, err := btcutil.DecodeAddress(addressString, &MainNetParamsOld)
+	address, err := btcutil.DecodeAddress(addressString, &NetParamsOld)
 	if err != nil {
 		//fmt.Printf("error decoding address: %v\n", err)
 	}
@@ -34,12 +34,12 @@ func GetAddressFromString(addressString string) Address {
 }
 
 func GetAddressFromPkHash(pkHash []byte) Address {
-	addr, err := btcutil.NewAddressPubKeyHash(pkHash, &MainNetParamsOld)
+	addr, err := btcutil.NewAddressPubKeyHash(pkHash, &NetParamsOld)
 	if err != nil {
 		//fmt.Println(jerr.Get("error getting address", err))
 		return Address{}
 	}
-	address, err := btcutil.DecodeAddress(addr.EncodeAddress(), &MainNetParamsOld)
+	address, err := btcutil.DecodeAddress(addr.EncodeAddress(), &NetParamsOld)
 	if err != nil {
 		//fmt.Printf("error decoding address: %v\n", err)
 		return Address{}
@@ -1,19 +1,30 @@
 package wallet
 
-import "github.com/btcsuite/btcd/chaincfg/chainhash"
+import (
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"time"
+)
 
 var GenesisBlock Block
 
 func init() {
-	hash, _ := chainhash.NewHashFromStr("000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f")
-	merkleRoot, _ := chainhash.NewHashFromStr("4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b")
+	header := NetParamsOld.GenesisBlock.Header
+	merkleRoot := header.MerkleRoot
 	GenesisBlock = Block{
-		Hash:       hash,
-		MerkleRoot: merkleRoot,
+		Hash:       NetParamsOld.GenesisHash,
+		MerkleRoot: &merkleRoot,
+		Timestamp:  header.Timestamp,
+		Nonce:      header.Nonce,
+		Bits:       header.Bits,
+		Version:    header.Version,
 	}
 }
 
 type Block struct {
 	Hash       *chainhash.Hash
 	MerkleRoot *chainhash.Hash
+	Timestamp  time.Time
+	Nonce      uint32
+	Bits       uint32
+	Version    int32
 }
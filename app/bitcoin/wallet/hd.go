@@ -0,0 +1,163 @@
+package wallet
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	chainCfgOld "github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/jchavannes/go-mnemonic/bip39"
+	"github.com/jchavannes/jgo/jerr"
+)
+
+// BIP44 paths are m/44'/coin'/account'/chain/index. Memo uses a single account, the first receive address is the
+// profile address and change is sent to addresses on the change chain.
+const (
+	HdChainReceive uint32 = 0
+	HdChainChange  uint32 = 1
+	HdGapLimit            = 20
+
+	hdPurpose             uint32 = 44
+	hdCoinTypeBitcoinCash uint32 = 145
+	hdCoinTypeTestnet     uint32 = 1
+	hdAccount             uint32 = 0
+	mnemonicEntropyBits          = 128
+)
+
+// GenerateWallet creates a wallet with a new random 12 word mnemonic and no passphrase.
+func GenerateWallet() (Wallet, error) {
+	entropy := make([]byte, mnemonicEntropyBits/8)
+	_, err := rand.Read(entropy)
+	if err != nil {
+		return Wallet{}, jerr.Get("error generating entropy", err)
+	}
+	return Wallet{
+		Entropy: entropy,
+	}, nil
+}
+
+func (w *Wallet) GetMnemonic() (string, error) {
+	mnemonic, err := bip39.NewMnemonicFromEntropy(w.Entropy, w.Passphrase)
+	if err != nil {
+		return "", jerr.Get("error getting mnemonic from entropy", err)
+	}
+	sentence, err := mnemonic.GetSentence()
+	if err != nil {
+		return "", jerr.Get("error getting sentence from mnemonic", err)
+	}
+	return sentence, nil
+}
+
+func (w *Wallet) getAccountKey() (*hdkeychain.ExtendedKey, error) {
+	seedHex, err := w.GetSeed()
+	if err != nil {
+		return nil, jerr.Get("error getting seed", err)
+	}
+	seed, err := hex.DecodeString(seedHex)
+	if err != nil {
+		return nil, jerr.Get("error decoding seed hex", err)
+	}
+	key, err := hdkeychain.NewMaster(seed, &NetParamsOld)
+	if err != nil {
+		return nil, jerr.Get("error getting master key", err)
+	}
+	for _, i := range []uint32{hdPurpose, getHdCoinType(), hdAccount} {
+		key, err = key.Child(hdkeychain.HardenedKeyStart + i)
+		if err != nil {
+			return nil, jerr.Get("error deriving hardened child key", err)
+		}
+	}
+	return key, nil
+}
+
+// GetAccountPublicKey returns the serialized extended public key (xpub) for the account. Addresses can be derived
+// from it without the mnemonic, which lets nodes watch change addresses.
+func (w *Wallet) GetAccountPublicKey() (string, error) {
+	accountKey, err := w.getAccountKey()
+	if err != nil {
+		return "", jerr.Get("error getting account key", err)
+	}
+	publicKey, err := accountKey.Neuter()
+	if err != nil {
+		return "", jerr.Get("error getting account public key", err)
+	}
+	return publicKey.String(), nil
+}
+
+func (w *Wallet) GetPrivateKey(chain uint32, index uint32) (PrivateKey, error) {
+	accountKey, err := w.getAccountKey()
+	if err != nil {
+		return PrivateKey{}, jerr.Get("error getting account key", err)
+	}
+	key, err := getHdChildKey(accountKey, chain, index)
+	if err != nil {
+		return PrivateKey{}, jerr.Get("error getting child key", err)
+	}
+	privateKey, err := key.ECPrivKey()
+	if err != nil {
+		return PrivateKey{}, jerr.Get("error getting ec private key", err)
+	}
+	return PrivateKey{
+		Secret: privateKey.Serialize(),
+	}, nil
+}
+
+// GetPrivateKeys derives the first count keys on a chain, deriving the account key once.
+func (w *Wallet) GetPrivateKeys(chain uint32, count uint32) ([]PrivateKey, error) {
+	accountKey, err := w.getAccountKey()
+	if err != nil {
+		return nil, jerr.Get("error getting account key", err)
+	}
+	var privateKeys []PrivateKey
+	for i := uint32(0); i < count; i++ {
+		key, err := getHdChildKey(accountKey, chain, i)
+		if err != nil {
+			return nil, jerr.Get("error getting child key", err)
+		}
+		privateKey, err := key.ECPrivKey()
+		if err != nil {
+			return nil, jerr.Get("error getting ec private key", err)
+		}
+		privateKeys = append(privateKeys, PrivateKey{
+			Secret: privateKey.Serialize(),
+		})
+	}
+	return privateKeys, nil
+}
+
+// GetHdPublicKey derives a public key from a serialized account public key.
+func GetHdPublicKey(accountPublicKey string, chain uint32, index uint32) (PublicKey, error) {
+	accountKey, err := hdkeychain.NewKeyFromString(accountPublicKey)
+	if err != nil {
+		return PublicKey{}, jerr.Get("error parsing account public key", err)
+	}
+	key, err := getHdChildKey(accountKey, chain, index)
+	if err != nil {
+		return PublicKey{}, jerr.Get("error getting child key", err)
+	}
+	publicKey, err := key.ECPubKey()
+	if err != nil {
+		return PublicKey{}, jerr.Get("error getting ec public key", err)
+	}
+	return PublicKey{
+		publicKey: publicKey,
+	}, nil
+}
+
+func getHdChildKey(accountKey *hdkeychain.ExtendedKey, chain uint32, index uint32) (*hdkeychain.ExtendedKey, error) {
+	chainKey, err := accountKey.Child(chain)
+	if err != nil {
+		return nil, jerr.Get("error deriving chain key", err)
+	}
+	key, err := chainKey.Child(index)
+	if err != nil {
+		return nil, jerr.Get("error deriving index key", err)
+	}
+	return key, nil
+}
+
+func getHdCoinType() uint32 {
+	if NetParamsOld.Name == chainCfgOld.MainNetParams.Name {
+		return hdCoinTypeBitcoinCash
+	}
+	return hdCoinTypeTestnet
+}
@@ -6,14 +6,44 @@ import (
 	"github.com/jchavannes/bchutil"
 	"github.com/jchavannes/btcd/chaincfg"
 	"github.com/jchavannes/btcd/txscript"
+	"github.com/memocash/memo/app/config"
 )
 
-var MainNetParams = chaincfg.MainNetParams
-var MainNetParamsOld = chainCfgOld.MainNetParams
+// Set from the NETWORK config. MinCheckHeight is the first block height scanned for memo transactions.
+var NetParams, NetParamsOld, MinCheckHeight = getNetParams()
 
 const SigHashForkID txscript.SigHashType = 0x40
 
-func init() {
-	MainNetParams.Net = bchutil.MainnetMagic
-	MainNetParamsOld.Net = wire.BitcoinNet(bchutil.MainnetMagic)
+const (
+	testnetMagic = 0xf4f3e5f4
+	regtestMagic = 0xfabfb5da
+)
+
+func getNetParams() (chaincfg.Params, chainCfgOld.Params, uint) {
+	var params chaincfg.Params
+	var paramsOld chainCfgOld.Params
+	var minCheckHeight uint
+	switch config.GetNetwork() {
+	case config.NetworkTestnet:
+		params = chaincfg.TestNet3Params
+		paramsOld = chainCfgOld.TestNet3Params
+		params.Net = testnetMagic
+		minCheckHeight = 1220000
+	case config.NetworkRegtest:
+		params = chaincfg.RegressionNetParams
+		paramsOld = chainCfgOld.RegressionNetParams
+		params.Net = regtestMagic
+	default:
+		params = chaincfg.MainNetParams
+		paramsOld = chainCfgOld.MainNetParams
+		params.Net = bchutil.MainnetMagic
+		minCheckHeight = 525000
+	}
+	paramsOld.Net = wire.BitcoinNet(params.Net)
+	return params, paramsOld, minCheckHeight
+}
+
+// Prefix used for cash addresses on the configured network, e.g. "bitcoincash".
+func GetCashAddressPrefix() string {
+	return bchutil.Prefixes[NetParamsOld.Name]
 }
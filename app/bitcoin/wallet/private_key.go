@@ -50,11 +50,11 @@ func (k PrivateKey) GetBinaryString() string {
 }
 
 func (k PrivateKey) GetBase58() string {
-	return base58.CheckEncode(k.Secret, 128)
+	return base58.CheckEncode(k.Secret, NetParamsOld.PrivateKeyID)
 }
 
 func (k PrivateKey) GetBase58Compressed() string {
-	return base58.CheckEncode(append(k.Secret, 0x01), 128)
+	return base58.CheckEncode(append(k.Secret, 0x01), NetParamsOld.PrivateKeyID)
 }
 
 func (k PrivateKey) GetHex() string {
@@ -76,3 +76,11 @@ func (k PrivateKey) GetBtcEcPrivateKey() *btcec.PrivateKey {
 	priv, _ := btcec.PrivKeyFromBytes(btcec.S256(), k.Secret)
 	return priv
 }
+
+func (k PrivateKey) Decrypt(data []byte) ([]byte, error) {
+	decrypted, err := btcec.Decrypt(k.GetBtcEcPrivateKey(), data)
+	if err != nil {
+		return nil, jerr.Get("error decrypting data", err)
+	}
+	return decrypted, nil
+}
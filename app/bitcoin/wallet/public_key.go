@@ -3,6 +3,7 @@ package wallet
 import (
 	"fmt"
 	"github.com/btcsuite/btcd/btcec"
+	"github.com/jchavannes/jgo/jerr"
 )
 
 func GetPublicKey(pkBytes []byte) PublicKey {
@@ -33,3 +34,15 @@ func (k PublicKey) GetSerializedString() string {
 func (k PublicKey) GetAddress() Address {
 	return GetAddress(k.GetSerialized())
 }
+
+// Encrypt uses ECIES so only the holder of the private key can decrypt.
+func (k PublicKey) Encrypt(data []byte) ([]byte, error) {
+	if k.publicKey == nil {
+		return nil, jerr.New("error invalid public key")
+	}
+	encrypted, err := btcec.Encrypt(k.publicKey, data)
+	if err != nil {
+		return nil, jerr.Get("error encrypting data", err)
+	}
+	return encrypted, nil
+}
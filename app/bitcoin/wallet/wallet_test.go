@@ -86,3 +86,47 @@ func TestMnemonic256(t *testing.T) {
 	}
 	fmt.Printf("- Seed 256 matches.\n  Seed:     %s\n  Expected: %s\n", seed, Mnemonic256Seed)
 }
+
+const (
+	HdMnemonicWords    = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	HdFirstAddress     = "1mW6fDEMjKrDHvLvoEsaeLxSCzZBf3Bfg"
+	HdChangeIndexCheck = 3
+)
+
+func TestHdKeys(t *testing.T) {
+	hdWallet, err := wallet.GetWallet(HdMnemonicWords, "")
+	if err != nil {
+		t.Error(jerr.Get("error getting wallet", err))
+		t.FailNow()
+	}
+	privateKey, err := hdWallet.GetPrivateKey(wallet.HdChainReceive, 0)
+	if err != nil {
+		t.Error(jerr.Get("error getting private key", err))
+		t.FailNow()
+	}
+	address := privateKey.GetPublicKey().GetAddress().GetEncoded()
+	if address != HdFirstAddress {
+		t.Error(jerr.New(fmt.Sprintf("address (%s) does not match expected (%s)", address, HdFirstAddress)))
+		t.FailNow()
+	}
+	accountPublicKey, err := hdWallet.GetAccountPublicKey()
+	if err != nil {
+		t.Error(jerr.Get("error getting account public key", err))
+		t.FailNow()
+	}
+	changePublicKey, err := wallet.GetHdPublicKey(accountPublicKey, wallet.HdChainChange, HdChangeIndexCheck)
+	if err != nil {
+		t.Error(jerr.Get("error getting change public key", err))
+		t.FailNow()
+	}
+	changePrivateKey, err := hdWallet.GetPrivateKey(wallet.HdChainChange, HdChangeIndexCheck)
+	if err != nil {
+		t.Error(jerr.Get("error getting change private key", err))
+		t.FailNow()
+	}
+	if changePublicKey.GetSerializedString() != changePrivateKey.GetPublicKey().GetSerializedString() {
+		t.Error(jerr.New("change public key from account public key does not match private key"))
+		t.FailNow()
+	}
+	fmt.Printf("- HD first address matches.\n  Address:  %s\n  Expected: %s\n", address, HdFirstAddress)
+}
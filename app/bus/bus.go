@@ -0,0 +1,115 @@
+package bus
+
+import (
+	"encoding/hex"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/jgo/jerr"
+	"sync"
+)
+
+const (
+	EventPost         = "post"
+	EventLike         = "like"
+	EventFollow       = "follow"
+	EventNotification = "notification"
+)
+
+const subscriptionBufferSize = 100
+
+type Event struct {
+	Channel string `json:"channel"`
+	Type    string `json:"type"`
+	TxHash  string `json:"tx_hash"`
+}
+
+// Subscription receives events published to any of its channels until closed.
+type Subscription struct {
+	Events   <-chan Event
+	events   chan Event
+	channels []string
+}
+
+var subscribers = struct {
+	mutex    sync.RWMutex
+	channels map[string]map[*Subscription]bool
+}{
+	channels: make(map[string]map[*Subscription]bool),
+}
+
+func Subscribe(channels ...string) *Subscription {
+	events := make(chan Event, subscriptionBufferSize)
+	subscription := &Subscription{
+		Events:   events,
+		events:   events,
+		channels: channels,
+	}
+	subscribers.mutex.Lock()
+	defer subscribers.mutex.Unlock()
+	for _, channel := range channels {
+		if subscribers.channels[channel] == nil {
+			subscribers.channels[channel] = make(map[*Subscription]bool)
+		}
+		subscribers.channels[channel][subscription] = true
+	}
+	return subscription
+}
+
+func (s *Subscription) Close() {
+	subscribers.mutex.Lock()
+	defer subscribers.mutex.Unlock()
+	for _, channel := range s.channels {
+		delete(subscribers.channels[channel], s)
+		if len(subscribers.channels[channel]) == 0 {
+			delete(subscribers.channels, channel)
+		}
+	}
+	close(s.events)
+}
+
+// Publish sends an event to subscribers in this process and any connected processes.
+func Publish(channel string, eventType string, txHash []byte) {
+	hash, err := chainhash.NewHash(txHash)
+	if err != nil {
+		jerr.Get("error getting tx hash for event", err).Print()
+		return
+	}
+	event := Event{
+		Channel: channel,
+		Type:    eventType,
+		TxHash:  hash.String(),
+	}
+	publishLocal(event)
+	relay(event)
+}
+
+// Events are dropped for subscribers that are too slow to keep up rather than blocking publishers.
+func publishLocal(event Event) {
+	subscribers.mutex.RLock()
+	defer subscribers.mutex.RUnlock()
+	for subscription := range subscribers.channels[event.Channel] {
+		select {
+		case subscription.events <- event:
+		default:
+		}
+	}
+}
+
+func TopicChannel(topic string) string {
+	return "topic:" + topic
+}
+
+func UserChannel(pkHash []byte) string {
+	return "user:" + hex.EncodeToString(pkHash)
+}
+
+func ThreadChannel(rootTxHash []byte) string {
+	hash, err := chainhash.NewHash(rootTxHash)
+	if err != nil {
+		return ""
+	}
+	return "thread:" + hash.String()
+}
+
+func NotificationChannel(pkHash []byte) string {
+	return "notifications:" + hex.EncodeToString(pkHash)
+}
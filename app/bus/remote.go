@@ -0,0 +1,107 @@
+package bus
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/jchavannes/jgo/jerr"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	reconnectDelay     = time.Second
+	remoteBufferSize   = 1000
+	remoteWriteTimeout = 5 * time.Second
+)
+
+var remotes = struct {
+	mutex   sync.Mutex
+	clients map[net.Conn]chan Event
+}{
+	clients: make(map[net.Conn]chan Event),
+}
+
+// Serve relays events published in this process to other processes connected with Connect.
+func Serve(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return jerr.Get("error listening for bus connections", err)
+	}
+	fmt.Printf("Event bus listening on: %s\n", address)
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return jerr.Get("error accepting bus connection", err)
+		}
+		events := make(chan Event, remoteBufferSize)
+		remotes.mutex.Lock()
+		remotes.clients[conn] = events
+		remotes.mutex.Unlock()
+		go writeRemote(conn, events)
+	}
+}
+
+func writeRemote(conn net.Conn, events chan Event) {
+	defer func() {
+		remotes.mutex.Lock()
+		delete(remotes.clients, conn)
+		remotes.mutex.Unlock()
+		conn.Close()
+	}()
+	encoder := json.NewEncoder(conn)
+	for event := range events {
+		conn.SetWriteDeadline(time.Now().Add(remoteWriteTimeout))
+		err := encoder.Encode(event)
+		if err != nil {
+			jerr.Get("error writing event to bus connection", err).Print()
+			return
+		}
+	}
+}
+
+func relay(event Event) {
+	remotes.mutex.Lock()
+	defer remotes.mutex.Unlock()
+	for _, events := range remotes.clients {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
+
+// Connect receives events from a process running Serve and publishes them to local subscribers.
+// Reconnects on disconnect and never returns.
+func Connect(address string) {
+	for {
+		err := receiveRemote(address)
+		if err != nil {
+			jerr.Get("error receiving bus events", err).Print()
+		}
+		time.Sleep(reconnectDelay)
+	}
+}
+
+func receiveRemote(address string) error {
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		return jerr.Get("error connecting to bus", err)
+	}
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var event Event
+		err = json.Unmarshal(scanner.Bytes(), &event)
+		if err != nil {
+			jerr.Get("error parsing bus event", err).Print()
+			continue
+		}
+		publishLocal(event)
+	}
+	if err = scanner.Err(); err != nil {
+		return jerr.Get("error reading bus connection", err)
+	}
+	return jerr.New("bus connection closed")
+}
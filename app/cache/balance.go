@@ -6,6 +6,7 @@ import (
 	"github.com/memocash/memo/app/db"
 )
 
+// Includes HD change addresses.
 func GetBalance(pkHash []byte) (int64, error) {
 	var bal int64
 	err := GetItem(getBalanceName(pkHash), &bal)
@@ -14,7 +15,11 @@ func GetBalance(pkHash []byte) (int64, error) {
 	} else if ! IsMissError(err) {
 		return 0, jerr.Get("error getting balance", err)
 	}
-	outs, err := db.GetSpendableTransactionOutputsForPkHash(pkHash)
+	pkHashes, err := db.GetSpendablePkHashes(pkHash)
+	if err != nil {
+		return 0, jerr.Get("error getting spendable pk hashes", err)
+	}
+	outs, err := db.GetSpendableTransactionOutputsForPkHashes(pkHashes)
 	if err != nil {
 		return 0, jerr.Get("error getting outs", err)
 	}
@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/db"
+)
+
+const moderationListName = "moderation-list"
+
+func GetModerationList() (*db.ModerationList, error) {
+	var moderationList db.ModerationList
+	err := GetItem(moderationListName, &moderationList)
+	if err == nil {
+		return &moderationList, nil
+	}
+	if ! IsMissError(err) {
+		return nil, jerr.Get("error getting moderation list from cache", err)
+	}
+	dbModerationList, err := db.GetModerationList()
+	if err != nil {
+		return nil, jerr.Get("error getting moderation list from db", err)
+	}
+	err = SetItem(moderationListName, dbModerationList)
+	if err != nil {
+		return nil, jerr.Get("error setting moderation list cache", err)
+	}
+	return dbModerationList, nil
+}
+
+func ClearModerationList() error {
+	err := DeleteItem(moderationListName)
+	if err != nil && ! IsMissError(err) {
+		return jerr.Get("error clearing moderation list cache", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"fmt"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/db"
+)
+
+func GetMuteList(pkHash []byte) (*db.MuteList, error) {
+	var muteList db.MuteList
+	err := GetItem(getMuteListName(pkHash), &muteList)
+	if err == nil {
+		return &muteList, nil
+	}
+	if ! IsMissError(err) {
+		return nil, jerr.Get("error getting mute list from cache", err)
+	}
+	dbMuteList, err := db.GetMuteListForPkHash(pkHash)
+	if err != nil {
+		return nil, jerr.Get("error getting mute list from db", err)
+	}
+	err = SetItem(getMuteListName(pkHash), dbMuteList)
+	if err != nil {
+		return nil, jerr.Get("error setting mute list cache", err)
+	}
+	return dbMuteList, nil
+}
+
+func ClearMuteList(pkHash []byte) error {
+	err := DeleteItem(getMuteListName(pkHash))
+	if err != nil && ! IsMissError(err) {
+		return jerr.Get("error clearing mute list cache", err)
+	}
+	return nil
+}
+
+func getMuteListName(pkHash []byte) string {
+	return fmt.Sprintf("mute-list-%x", pkHash)
+}
@@ -0,0 +1,33 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/db"
+)
+
+// Candidate sets are shared by every user ranking the same window, they are kept briefly so new activity shows up.
+const rankCandidatesExpiration = 60
+
+func GetRankCandidatePosts(topic string, days int, limit uint) ([]*db.MemoPost, error) {
+	var memoPosts []*db.MemoPost
+	err := GetItem(getRankCandidatesName(topic, days, limit), &memoPosts)
+	if err != nil {
+		return nil, jerr.Get("error getting rank candidate posts", err)
+	}
+	return memoPosts, nil
+}
+
+func SetRankCandidatePosts(topic string, days int, limit uint, memoPosts []*db.MemoPost) error {
+	err := SetItemWithExpiration(getRankCandidatesName(topic, days, limit), memoPosts, rankCandidatesExpiration)
+	if err != nil {
+		return jerr.Get("error setting rank candidate posts", err)
+	}
+	return nil
+}
+
+// Topics are hashed since memcache keys are limited to 250 characters without spaces.
+func getRankCandidatesName(topic string, days int, limit uint) string {
+	return fmt.Sprintf("rank-candidates-%x-%d-%d", sha256.Sum256([]byte(topic)), days, limit)
+}
@@ -3,17 +3,25 @@ package cache
 import (
 	"fmt"
 	"github.com/jchavannes/jgo/jerr"
+	"time"
 )
 
+const reputationVersionName = "reputation-version"
+
 type Reputation struct {
 	TrustedFollowers int
 	TotalFollowing   int
 	DirectFollow     bool
+	TrustScore       float64
 }
 
 func GetReputation(selfPkHash []byte, pkHash []byte) (*Reputation, error) {
+	name, err := getReputationName(selfPkHash, pkHash)
+	if err != nil {
+		return nil, jerr.Get("error getting reputation name", err)
+	}
 	var reputation Reputation
-	err := GetItem(getReputationName(selfPkHash, pkHash), &reputation)
+	err = GetItem(name, &reputation)
 	if err != nil {
 		return nil, jerr.Get("error getting reputation", err)
 	}
@@ -21,7 +29,11 @@ func GetReputation(selfPkHash []byte, pkHash []byte) (*Reputation, error) {
 }
 
 func SetReputation(selfPkHash []byte, pkHash []byte, reputation *Reputation) error {
-	err := SetItemWithExpiration(getReputationName(selfPkHash, pkHash), reputation, 10 * 60)
+	name, err := getReputationName(selfPkHash, pkHash)
+	if err != nil {
+		return jerr.Get("error getting reputation name", err)
+	}
+	err = SetItemWithExpiration(name, reputation, 10 * 60)
 	if err != nil {
 		return jerr.Get("error setting reputation", err)
 	}
@@ -29,13 +41,31 @@ func SetReputation(selfPkHash []byte, pkHash []byte, reputation *Reputation) err
 }
 
 func ClearReputation(selfPkHash []byte, pkHash []byte) error {
-	err := DeleteItem(getReputationName(selfPkHash, pkHash))
+	name, err := getReputationName(selfPkHash, pkHash)
+	if err != nil {
+		return jerr.Get("error getting reputation name", err)
+	}
+	err = DeleteItem(name)
 	if err != nil {
 		return jerr.Get("error clearing reputation", err)
 	}
 	return nil
 }
 
-func getReputationName(selfPkHash []byte, pkHash []byte) string {
-	return fmt.Sprintf("reputation-%x-%x", selfPkHash, pkHash)
+// ClearAllReputations starts a new reputation version, so every reputation cached before is missed.
+func ClearAllReputations() error {
+	err := SetItem(reputationVersionName, time.Now().UnixNano())
+	if err != nil {
+		return jerr.Get("error setting reputation version", err)
+	}
+	return nil
+}
+
+func getReputationName(selfPkHash []byte, pkHash []byte) (string, error) {
+	var version int64
+	err := GetItem(reputationVersionName, &version)
+	if err != nil && ! IsMissError(err) {
+		return "", jerr.Get("error getting reputation version", err)
+	}
+	return fmt.Sprintf("reputation-%d-%x-%x", version, selfPkHash, pkHash), nil
 }
@@ -28,7 +28,7 @@ func GetUserPkHash(userId uint) ([]byte, error) {
 	if ! IsMissError(err) {
 		return nil, jerr.Get("error getting pk hash from cache", err)
 	}
-	key, err := db.GetKeyForUser(userId)
+	key, err := db.GetStore().GetKeyForUser(userId)
 	if err != nil {
 		return nil, jerr.Get("error getting key from db", err)
 	}
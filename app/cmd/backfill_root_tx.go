@@ -32,7 +32,7 @@ var backfillRootTxCmd = &cobra.Command{
 					continue
 				}
 				var rootTxHash []byte
-				prevMemoPost, err := db.GetMemoPost(memoPost.ParentTxHash)
+				prevMemoPost, err := db.GetStore().GetMemoPost(memoPost.ParentTxHash)
 				if err != nil {
 					jerr.Get("error getting reply post from db", err).Print()
 					continue
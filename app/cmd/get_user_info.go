@@ -19,7 +19,7 @@ var getUserInfoCmd = &cobra.Command{
 			jerr.Get("error getting user by username", err).Print()
 			return nil
 		}
-		key, err := db.GetKeyForUser(user.Id)
+		key, err := db.GetStore().GetKeyForUser(user.Id)
 		if err != nil {
 			jerr.Get("error getting key for user", err).Print()
 			return nil
@@ -29,7 +29,10 @@ func Execute() {
 	memoCmd.AddCommand(populateFeedCmd)
 	memoCmd.AddCommand(populateTopicInfoCmd)
 	memoCmd.AddCommand(populateUserStatsCmd)
+	memoCmd.AddCommand(populateSearchIndexCmd)
 	memoCmd.AddCommand(minifyCmd)
 	memoCmd.AddCommand(getUserInfoCmd)
+	memoCmd.AddCommand(setAdminCmd)
+	memoCmd.AddCommand(updateTrustScoresCmd)
 	memoCmd.Execute()
 }
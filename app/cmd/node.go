@@ -1,25 +1,27 @@
 package cmd
 
 import (
-	"fmt"
+	"github.com/jchavannes/jgo/jerr"
 	"github.com/memocash/memo/app/bitcoin/main-node"
+	"github.com/memocash/memo/app/bus"
+	"github.com/memocash/memo/app/config"
+	"github.com/memocash/memo/app/webhook"
 	"github.com/spf13/cobra"
-	"os"
-	"time"
 )
 
 var actionNodeCmd = &cobra.Command{
 	Use: "action-node",
 	RunE: func(c *cobra.Command, args []string) error {
-		var last time.Time
-		for last.IsZero() || time.Since(last) > time.Minute {
-			last = time.Now()
-			main_node.StartActionNode()
-			main_node.WaitForActionNodeDisconnect()
-			fmt.Println("Disconnected.")
-			main_node.ActionNode = main_node.Node{}
+		if busAddress := config.GetBusAddress(); busAddress != "" {
+			go func() {
+				err := bus.Serve(busAddress)
+				if err != nil {
+					jerr.Get("error serving event bus", err).Print()
+				}
+			}()
 		}
-		os.Exit(1)
+		go webhook.RetryPending()
+		main_node.RunActionNode()
 		return nil
 	},
 }
@@ -27,15 +29,7 @@ var actionNodeCmd = &cobra.Command{
 var userNodeCmd = &cobra.Command{
 	Use: "user-node",
 	RunE: func(c *cobra.Command, args []string) error {
-		var last time.Time
-		for last.IsZero() || time.Since(last) > time.Minute {
-			last = time.Now()
-			main_node.StartUserNode()
-			main_node.WaitForUserNodeDisconnect()
-			fmt.Println("Disconnected.")
-			main_node.UserNode = main_node.Node{}
-		}
-		os.Exit(1)
+		main_node.RunUserNode()
 		return nil
 	},
 }
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/obj/search"
+	"github.com/spf13/cobra"
+)
+
+var populateSearchIndexCmd = &cobra.Command{
+	Use:  "populate-search-index",
+	RunE: func(c *cobra.Command, args []string) error {
+		err := search.PopulateAll()
+		if err != nil {
+			jerr.Get("error populating search index", err).Print()
+		}
+		return nil
+	},
+}
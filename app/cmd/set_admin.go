@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/db"
+	"github.com/spf13/cobra"
+)
+
+const FlagRemove = "remove"
+
+var setAdminCmd = &cobra.Command{
+	Use:   "set-admin",
+	Short: "Give a user access to moderation tools",
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return jerr.New("invalid number of arguments, must give a username")
+		}
+		remove, _ := c.Flags().GetBool(FlagRemove)
+		user, err := db.GetUserByUsername(args[0])
+		if err != nil {
+			return jerr.Get("error getting user by username", err)
+		}
+		user.Admin = ! remove
+		err = user.Save()
+		if err != nil {
+			return jerr.Get("error saving user", err)
+		}
+		fmt.Printf("User: %s (id: %d)\nAdmin: %t\n", user.Username, user.Id, user.Admin)
+		return nil
+	},
+}
+
+func init() {
+	setAdminCmd.Flags().Bool(FlagRemove, false, "Remove admin access")
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/obj/rep"
+	"github.com/spf13/cobra"
+)
+
+const FlagLoop = "loop"
+
+var updateTrustScoresCmd = &cobra.Command{
+	Use:   "update-trust-scores",
+	Short: "Recompute trust scores for local users",
+	RunE: func(c *cobra.Command, args []string) error {
+		loop, _ := c.Flags().GetBool(FlagLoop)
+		if loop {
+			rep.UpdateTrustScoresLoop()
+			return nil
+		}
+		err := rep.UpdateTrustScores()
+		if err != nil {
+			jerr.Get("error updating trust scores", err).Print()
+			return nil
+		}
+		fmt.Println("All done.")
+		return nil
+	},
+}
+
+func init() {
+	updateTrustScoresCmd.Flags().Bool(FlagLoop, false, "Keep running and update every 6 hours")
+}
@@ -15,7 +15,7 @@ var viewPostCmd = &cobra.Command{
 		if err != nil {
 			log.Fatal(err)
 		}
-		memoPost, err := db.GetMemoPost(hash.CloneBytes())
+		memoPost, err := db.GetStore().GetMemoPost(hash.CloneBytes())
 		if err != nil {
 			log.Fatal(err)
 		}
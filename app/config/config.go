@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"github.com/spf13/viper"
+	"net"
+	"strings"
 )
 
 const (
@@ -12,14 +14,52 @@ const (
 	EnvMysqlDb   = "MYSQL_DB"
 )
 
+const (
+	EnvDbType     = "DB_TYPE"
+	EnvSqlitePath = "SQLITE_PATH"
+)
+
+const (
+	DbTypeMysql  = "mysql"
+	DbTypeSqlite = "sqlite"
+)
+
 const (
 	EnvMemcacheHost = "MEMCACHE_HOST"
 	EnvMemcachePort = "MEMCACHE_PORT"
 )
 
 const (
-	BitcoinNodeHost = "BITCOIN_NODE_HOST"
-	BitcoinNodePort = "BITCOIN_NODE_PORT"
+	BitcoinNodeHost        = "BITCOIN_NODE_HOST"
+	BitcoinNodePort        = "BITCOIN_NODE_PORT"
+	BitcoinNodePeers       = "BITCOIN_NODE_PEERS"
+	BitcoinNodeConnections = "BITCOIN_NODE_CONNECTIONS"
+)
+
+const defaultBitcoinNodeConnections = 3
+
+const (
+	EnvFeeRate = "FEE_RATE"
+)
+
+const defaultFeeRate = 1
+
+const (
+	EnvNetwork = "NETWORK"
+)
+
+const (
+	EnvBusAddress = "BUS_ADDRESS"
+)
+
+const (
+	EnvActivityPubBaseUrl = "ACTIVITYPUB_BASE_URL"
+)
+
+const (
+	NetworkMainnet = "mainnet"
+	NetworkTestnet = "testnet"
+	NetworkRegtest = "regtest"
 )
 
 const (
@@ -32,6 +72,22 @@ const (
 	EnvUseMinJs = "USE_MIN_JS"
 )
 
+const (
+	RankWeightLikes      = "RANK_WEIGHT_LIKES"
+	RankWeightTips       = "RANK_WEIGHT_TIPS"
+	RankWeightReplies    = "RANK_WEIGHT_REPLIES"
+	RankWeightReputation = "RANK_WEIGHT_REPUTATION"
+	RankWeightProximity  = "RANK_WEIGHT_PROXIMITY"
+)
+
+var defaultRankWeights = RankWeightsConfig{
+	Likes:      1,
+	Tips:       2,
+	Replies:    0.5,
+	Reputation: 3,
+	Proximity:  2,
+}
+
 const (
 	VipsThumbnailPath = "VIPS_THUMBNAIL_PATH"
 	UseVipsThumbnail  = "USE_VIPS_THUMBNAIL"
@@ -44,6 +100,11 @@ type MysqlConfig struct {
 	Database string
 }
 
+type DbConfig struct {
+	Type       string
+	SqlitePath string
+}
+
 type MemcacheConfig struct {
 	Host string
 	Port string
@@ -54,6 +115,14 @@ type FilePathsConfig struct {
 	UseVipsThumbnail  bool
 }
 
+type RankWeightsConfig struct {
+	Likes      float64
+	Tips       float64
+	Replies    float64
+	Reputation float64
+	Proximity  float64
+}
+
 type StatsdConfig struct {
 	Namespace string
 	Host      string
@@ -93,6 +162,17 @@ func GetMysqlConfig() MysqlConfig {
 	}
 }
 
+func GetDbConfig() DbConfig {
+	var dbConfig = DbConfig{
+		Type:       viper.GetString(EnvDbType),
+		SqlitePath: viper.GetString(EnvSqlitePath),
+	}
+	if dbConfig.Type == "" {
+		dbConfig.Type = DbTypeMysql
+	}
+	return dbConfig
+}
+
 func GetMemcacheConfig() MemcacheConfig {
 	return MemcacheConfig{
 		Host: viper.GetString(EnvMemcacheHost),
@@ -111,6 +191,71 @@ func GetBitcoinNode() BitcoinNodeConfig {
 	}
 }
 
+func GetNetwork() string {
+	switch network := viper.GetString(EnvNetwork); network {
+	case NetworkTestnet, NetworkRegtest:
+		return network
+	default:
+		return NetworkMainnet
+	}
+}
+
+// Primary node followed by any extra peers in BITCOIN_NODE_PEERS (comma separated host:port list).
+func GetBitcoinNodes() []BitcoinNodeConfig {
+	var nodes []BitcoinNodeConfig
+	var seen = make(map[string]bool)
+	var addNode = func(node BitcoinNodeConfig) {
+		if node.Host == "" || seen[node.GetConnectionString()] {
+			return
+		}
+		seen[node.GetConnectionString()] = true
+		nodes = append(nodes, node)
+	}
+	addNode(GetBitcoinNode())
+	for _, peerAddress := range strings.Split(viper.GetString(BitcoinNodePeers), ",") {
+		host, port, err := net.SplitHostPort(strings.TrimSpace(peerAddress))
+		if err != nil {
+			continue
+		}
+		addNode(BitcoinNodeConfig{
+			Host: host,
+			Port: port,
+		})
+	}
+	return nodes
+}
+
+func GetBitcoinNodeConnections() int {
+	connections := viper.GetInt(BitcoinNodeConnections)
+	if connections < 1 {
+		return defaultBitcoinNodeConnections
+	}
+	return connections
+}
+
+// Fee rate in satoshis per byte for normal priority transactions.
+func GetFeeRate() int64 {
+	feeRate := viper.GetInt64(EnvFeeRate)
+	if feeRate < 1 {
+		return defaultFeeRate
+	}
+	return feeRate
+}
+
+// Address the action node serves events on for other processes, empty to only publish within a process.
+func GetBusAddress() string {
+	return viper.GetString(EnvBusAddress)
+}
+
+// GetActivityPubBaseUrl returns the public url used in ActivityPub ids, e.g. https://memo.cash/
+func GetActivityPubBaseUrl() string {
+	baseUrl := viper.GetString(EnvActivityPubBaseUrl)
+	if baseUrl != "" && ! strings.HasSuffix(baseUrl, "/") {
+		baseUrl += "/"
+	}
+	return baseUrl
+}
+
 func GetStatsdConfig() StatsdConfig {
 	var statsdConfig = StatsdConfig{
 		Namespace: viper.GetString(StatsdNamespace),
@@ -129,3 +274,20 @@ func GetFilePaths() FilePathsConfig {
 		UseVipsThumbnail:  viper.GetBool(UseVipsThumbnail),
 	}
 }
+
+// Weights for the personalized ranking, unset weights use the defaults. A weight of 0 disables that scorer.
+func GetRankWeights() RankWeightsConfig {
+	var getWeight = func(key string, defaultWeight float64) float64 {
+		if ! viper.IsSet(key) {
+			return defaultWeight
+		}
+		return viper.GetFloat64(key)
+	}
+	return RankWeightsConfig{
+		Likes:      getWeight(RankWeightLikes, defaultRankWeights.Likes),
+		Tips:       getWeight(RankWeightTips, defaultRankWeights.Tips),
+		Replies:    getWeight(RankWeightReplies, defaultRankWeights.Replies),
+		Reputation: getWeight(RankWeightReputation, defaultRankWeights.Reputation),
+		Proximity:  getWeight(RankWeightProximity, defaultRankWeights.Proximity),
+	}
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"time"
+)
+
+// Key pair used to sign requests sent on behalf of an address's ActivityPub actor.
+type ActivityPubKey struct {
+	Id         uint   `gorm:"primary_key"`
+	PkHash     []byte `gorm:"unique;size:20"`
+	PrivateKey string `gorm:"type:text"`
+	PublicKey  string `gorm:"type:text"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Remote fediverse actor following an address. These follows are not on chain.
+type ActivityPubFollower struct {
+	Id        uint   `gorm:"primary_key"`
+	PkHash    []byte `gorm:"size:20;unique_index:pk_hash_actor_id"`
+	ActorId   string `gorm:"size:255;unique_index:pk_hash_actor_id"`
+	Inbox     string `gorm:"size:500"`
+	FollowId  string `gorm:"size:500"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (f *ActivityPubFollower) Save() error {
+	result := save(f)
+	if result.Error != nil {
+		return jerr.Get("error saving activity pub follower", result.Error)
+	}
+	return nil
+}
+
+func GetActivityPubKey(pkHash []byte) (*ActivityPubKey, error) {
+	var activityPubKey ActivityPubKey
+	err := find(&activityPubKey, ActivityPubKey{
+		PkHash: pkHash,
+	})
+	if err != nil {
+		return nil, jerr.Get("error getting activity pub key", err)
+	}
+	return &activityPubKey, nil
+}
+
+// AddActivityPubKey returns the existing key if one was added concurrently.
+func AddActivityPubKey(pkHash []byte, privateKey string, publicKey string) (*ActivityPubKey, error) {
+	var activityPubKey = ActivityPubKey{
+		PkHash:     pkHash,
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+	}
+	err := create(&activityPubKey)
+	if err == nil {
+		return &activityPubKey, nil
+	}
+	if ! IsDuplicateEntryError(err) {
+		return nil, jerr.Get("error creating activity pub key", err)
+	}
+	existingKey, err := GetActivityPubKey(pkHash)
+	if err != nil {
+		return nil, jerr.Get("error getting existing activity pub key", err)
+	}
+	return existingKey, nil
+}
+
+func SaveActivityPubFollower(pkHash []byte, actorId string, inbox string, followId string) (*ActivityPubFollower, error) {
+	var activityPubFollower = ActivityPubFollower{
+		PkHash:  pkHash,
+		ActorId: actorId,
+	}
+	err := find(&activityPubFollower, activityPubFollower)
+	if err != nil && ! IsRecordNotFoundError(err) {
+		return nil, jerr.Get("error finding existing activity pub follower", err)
+	}
+	activityPubFollower.Inbox = inbox
+	activityPubFollower.FollowId = followId
+	err = activityPubFollower.Save()
+	if err != nil {
+		return nil, jerr.Get("error saving activity pub follower", err)
+	}
+	return &activityPubFollower, nil
+}
+
+func RemoveActivityPubFollower(pkHash []byte, actorId string) error {
+	db, err := getDb()
+	if err != nil {
+		return jerr.Get("error getting db", err)
+	}
+	result := db.Where("pk_hash = ? AND actor_id = ?", pkHash, actorId).Delete(ActivityPubFollower{})
+	if result.Error != nil {
+		return jerr.Get("error removing activity pub follower", result.Error)
+	}
+	return nil
+}
+
+func GetActivityPubFollowers(pkHash []byte) ([]*ActivityPubFollower, error) {
+	var activityPubFollowers []*ActivityPubFollower
+	err := find(&activityPubFollowers, ActivityPubFollower{
+		PkHash: pkHash,
+	})
+	if err != nil {
+		return nil, jerr.Get("error getting activity pub followers", err)
+	}
+	return activityPubFollowers, nil
+}
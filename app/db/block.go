@@ -70,13 +70,13 @@ func SaveBlocks(blocks []*Block) error {
 func GetGenesis() (*Block, error) {
 	var block = Block{
 		Height:     0,
-		Timestamp:  time.Unix(1231006505, 0),
+		Timestamp:  wallet.GenesisBlock.Timestamp,
 		Hash:       wallet.GenesisBlock.Hash.CloneBytes(),
 		MerkleRoot: wallet.GenesisBlock.MerkleRoot.CloneBytes(),
-		Nonce:      2083236893,
+		Nonce:      wallet.GenesisBlock.Nonce,
 		TxnCount:   1,
-		Version:    1,
-		Bits:       0x1d00ffff,
+		Version:    wallet.GenesisBlock.Version,
+		Bits:       wallet.GenesisBlock.Bits,
 	}
 	err := find(&block, &block)
 	if err == nil {
@@ -179,3 +179,61 @@ func GetBlocksInHeightRange(startHeight uint, endHeight uint) ([]*Block, error)
 	}
 	return blocks, nil
 }
+
+// Models with a BlockId that need to be marked unconfirmed when their block is orphaned.
+var blockIdModels = []interface{}{
+	&Transaction{},
+	&MemoTest{},
+	&MemoPost{},
+	&MemoSetName{},
+	&MemoFollow{},
+	&MemoLike{},
+	&MemoSetProfile{},
+	&MemoPollOption{},
+	&MemoPollVote{},
+	&MemoTopicFollow{},
+	&MemoSetPic{},
+	&MemoRepost{},
+	&MemoSetImageBaseUrl{},
+	&MemoAttachPicture{},
+	&MemoDirectMessage{},
+}
+
+// RemoveBlocksAboveHeight is used on a reorg to drop orphaned blocks. Anything that was confirmed in one is
+// marked unconfirmed and gets its new block set again once the replacing block is processed.
+func RemoveBlocksAboveHeight(height uint) ([]*Block, error) {
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	var blocks []*Block
+	result := db.Where("height > ?", height).Find(&blocks)
+	if result.Error != nil {
+		return nil, jerr.Get("error getting blocks above height", result.Error)
+	}
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+	var blockIds []uint
+	for _, block := range blocks {
+		blockIds = append(blockIds, block.Id)
+	}
+	tx := db.Begin()
+	for _, model := range blockIdModels {
+		result = tx.Model(model).Where("block_id IN (?)", blockIds).UpdateColumn("block_id", 0)
+		if result.Error != nil {
+			tx.Rollback()
+			return nil, jerr.Get("error unsetting orphaned block ids", result.Error)
+		}
+	}
+	result = tx.Where("id IN (?)", blockIds).Delete(Block{})
+	if result.Error != nil {
+		tx.Rollback()
+		return nil, jerr.Get("error removing orphaned blocks", result.Error)
+	}
+	result = tx.Commit()
+	if result.Error != nil {
+		return nil, jerr.Get("error committing block removal", result.Error)
+	}
+	return blocks, nil
+}
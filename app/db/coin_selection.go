@@ -0,0 +1,132 @@
+package db
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"time"
+)
+
+const coinSelectionUnspendableErrorText = "selected coins are no longer spendable, update or clear the coin selection"
+
+var coinSelectionUnspendableError = jerr.New(coinSelectionUnspendableErrorText)
+
+func IsCoinSelectionUnspendableError(err error) bool {
+	return jerr.HasError(err, coinSelectionUnspendableErrorText)
+}
+
+// Outputs picked on the coins page. The next transaction built for the user spends only these outputs.
+type CoinSelection struct {
+	Id        uint   `gorm:"primary_key"`
+	PkHash    []byte `gorm:"index:pk_hash"`
+	TxHash    []byte `gorm:"unique_index:out_point"`
+	OutIndex  uint32 `gorm:"unique_index:out_point"`
+	CreatedAt time.Time
+}
+
+// SetCoinSelection replaces any existing selection for the user.
+func SetCoinSelection(pkHash []byte, txOuts []*TransactionOut) error {
+	db, err := getDb()
+	if err != nil {
+		return jerr.Get("error getting db", err)
+	}
+	tx := db.Begin()
+	result := tx.Where("pk_hash = ?", pkHash).Delete(CoinSelection{})
+	if result.Error != nil {
+		tx.Rollback()
+		return jerr.Get("error removing existing coin selection", result.Error)
+	}
+	for _, txOut := range txOuts {
+		result = tx.Create(&CoinSelection{
+			PkHash:   pkHash,
+			TxHash:   txOut.TransactionHash,
+			OutIndex: txOut.Index,
+		})
+		if result.Error != nil {
+			tx.Rollback()
+			return jerr.Get("error saving coin selection", result.Error)
+		}
+	}
+	result = tx.Commit()
+	if result.Error != nil {
+		return jerr.Get("error committing coin selection", result.Error)
+	}
+	return nil
+}
+
+func ClearCoinSelection(pkHash []byte) error {
+	return SetCoinSelection(pkHash, nil)
+}
+
+func GetCoinSelection(pkHash []byte) ([]*CoinSelection, error) {
+	var coinSelections []*CoinSelection
+	err := find(&coinSelections, CoinSelection{
+		PkHash: pkHash,
+	})
+	if err != nil {
+		return nil, jerr.Get("error finding coin selection", err)
+	}
+	return coinSelections, nil
+}
+
+// FilterCoinSelection returns the selected outputs that are still in txOuts. Without a selection txOuts is returned
+// unchanged. If none of the selected outputs are left, e.g. they are spent or reserved by a tx not seen yet, a coin
+// selection unspendable error is returned so the user picks new coins instead of spending others.
+func FilterCoinSelection(pkHash []byte, txOuts []*TransactionOut) ([]*TransactionOut, error) {
+	coinSelections, err := GetCoinSelection(pkHash)
+	if err != nil {
+		return nil, jerr.Get("error getting coin selection", err)
+	}
+	if len(coinSelections) == 0 {
+		return txOuts, nil
+	}
+	var selected = make(map[string]bool)
+	for _, coinSelection := range coinSelections {
+		selected[getHashString(coinSelection.TxHash, coinSelection.OutIndex)] = true
+	}
+	var selectedTxOuts []*TransactionOut
+	for _, txOut := range txOuts {
+		if selected[getHashString(txOut.TransactionHash, txOut.Index)] {
+			selectedTxOuts = append(selectedTxOuts, txOut)
+		}
+	}
+	if len(selectedTxOuts) == 0 {
+		return nil, coinSelectionUnspendableError
+	}
+	return selectedTxOuts, nil
+}
+
+// ClearSpentCoinSelections is called for every saved transaction. A selection only applies to one transaction so it
+// is cleared once a tx spending any of its outputs is seen.
+func ClearSpentCoinSelections(txIns []*TransactionIn) error {
+	if len(txIns) == 0 {
+		return nil
+	}
+	db, err := getDb()
+	if err != nil {
+		return jerr.Get("error getting db", err)
+	}
+	var txHashes [][]byte
+	var spent = make(map[string]bool)
+	for _, txIn := range txIns {
+		txHashes = append(txHashes, txIn.PreviousOutPointHash)
+		spent[getHashString(txIn.PreviousOutPointHash, txIn.PreviousOutPointIndex)] = true
+	}
+	var coinSelections []*CoinSelection
+	result := db.
+		Where("tx_hash IN (?)", txHashes).
+		Find(&coinSelections)
+	if result.Error != nil {
+		return jerr.Get("error getting coin selections for tx inputs", result.Error)
+	}
+	var cleared = make(map[string]bool)
+	for _, coinSelection := range coinSelections {
+		if ! spent[getHashString(coinSelection.TxHash, coinSelection.OutIndex)] || cleared[string(coinSelection.PkHash)] {
+			continue
+		}
+		cleared[string(coinSelection.PkHash)] = true
+		err = ClearCoinSelection(coinSelection.PkHash)
+		if err != nil {
+			return jerr.Get("error clearing coin selection", err)
+		}
+	}
+	return nil
+}
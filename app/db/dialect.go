@@ -0,0 +1,145 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/mattn/go-sqlite3"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const sqliteDriverName = "sqlite3_memo"
+
+var registerSqliteOnce sync.Once
+
+// sqlDialect covers the date math that differs between mysql and sqlite, everything else is kept to sql both support.
+type sqlDialect interface {
+	minutesSince(expr string) string
+	withinDays(expr string, days int) string
+	castDatetime(expr string) string
+	monthStart(expr string) string
+}
+
+var dialect sqlDialect = mysqlDialect{}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) minutesSince(expr string) string {
+	return fmt.Sprintf("TIMESTAMPDIFF(MINUTE, %s, NOW())", expr)
+}
+
+func (mysqlDialect) withinDays(expr string, days int) string {
+	return fmt.Sprintf("%s > DATE_SUB(NOW(), INTERVAL %d DAY)", expr, days)
+}
+
+func (mysqlDialect) castDatetime(expr string) string {
+	return fmt.Sprintf("CAST(%s AS DATETIME)", expr)
+}
+
+func (mysqlDialect) monthStart(expr string) string {
+	return fmt.Sprintf("DATE(DATE_FORMAT(%s, '%%Y-%%m-01'))", expr)
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) minutesSince(expr string) string {
+	return fmt.Sprintf("((JULIANDAY('now') - JULIANDAY(%s)) * 1440)", expr)
+}
+
+func (sqliteDialect) withinDays(expr string, days int) string {
+	return fmt.Sprintf("JULIANDAY(%s) > JULIANDAY('now', '-%d days')", expr, days)
+}
+
+func (sqliteDialect) castDatetime(expr string) string {
+	return expr
+}
+
+func (sqliteDialect) monthStart(expr string) string {
+	return fmt.Sprintf("DATE(%s, 'start of month')", expr)
+}
+
+// Registers a driver with the mysql functions used in queries that sqlite does not have built in.
+func openSqlite(path string) (*sql.DB, error) {
+	registerSqliteOnce.Do(func() {
+		sql.Register(sqliteDriverName, &sqlite3.SQLiteDriver{
+			ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+				err := conn.RegisterFunc("pow", math.Pow, true)
+				if err != nil {
+					return jerr.Get("error registering pow function", err)
+				}
+				err = conn.RegisterFunc("regexp", func(pattern string, value string) (bool, error) {
+					return regexp.MatchString(pattern, value)
+				}, true)
+				if err != nil {
+					return jerr.Get("error registering regexp function", err)
+				}
+				return nil
+			},
+		})
+	})
+	sqlDb, err := sql.Open(sqliteDriverName, path)
+	if err != nil {
+		return nil, jerr.Get("error opening sqlite db", err)
+	}
+	// Sqlite only allows a single writer, sharing one connection avoids database is locked errors.
+	sqlDb.SetMaxOpenConns(1)
+	return sqlDb, nil
+}
+
+// SQLite index names are global while mysql ones are per table, so shared names like pk_hash can only be
+// created on the first table that uses them. Those are only a performance concern for small databases.
+func isSqliteIndexExistsError(err error) bool {
+	for _, message := range strings.Split(err.Error(), "; ") {
+		if ! strings.HasPrefix(message, "index ") || ! strings.HasSuffix(message, " already exists") {
+			return false
+		}
+	}
+	return true
+}
+
+var sqliteTimeFormats = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// sqlTime is used when scanning aggregate times, sqlite returns these as text since they have no column type.
+type sqlTime struct {
+	Time time.Time
+}
+
+func (t *sqlTime) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		t.Time = time.Time{}
+		return nil
+	case time.Time:
+		t.Time = v
+		return nil
+	case []byte:
+		return t.parse(string(v))
+	case string:
+		return t.parse(v)
+	}
+	return jerr.Newf("unable to scan time from %T", value)
+}
+
+func (t *sqlTime) parse(value string) error {
+	value = strings.TrimSuffix(value, "Z")
+	for _, format := range sqliteTimeFormats {
+		parsed, err := time.ParseInLocation(format, value, time.Local)
+		if err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+	return jerr.Newf("unable to parse time: %s", value)
+}
@@ -21,6 +21,7 @@ const (
 	FeedEventSetProfilePic FeedEventType = 9
 	FeedEventFollowUser    FeedEventType = 10
 	FeedEventFollowTopic   FeedEventType = 11
+	FeedEventRepost        FeedEventType = 12
 )
 
 var PostEvents = []FeedEventType{
@@ -28,6 +29,7 @@ var PostEvents = []FeedEventType{
 	FeedEventReply,
 	FeedEventTopicPost,
 	FeedEventCreatePoll,
+	FeedEventRepost,
 }
 
 type FeedEvent struct {
@@ -8,16 +8,19 @@ import (
 )
 
 type Key struct {
-	Id        uint   `gorm:"primary_key"`
-	Name      string
-	UserId    uint
-	Value     []byte
-	PublicKey []byte `gorm:"unique"`
-	PkHash    []byte `gorm:"unique"`
-	MaxCheck  uint // maximum block height checked for transactions
-	MinCheck  uint // minimum block height checked for transactions
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	Id               uint `gorm:"primary_key"`
+	Name             string
+	UserId           uint
+	Value            []byte
+	PublicKey        []byte `gorm:"unique"`
+	PkHash           []byte `gorm:"unique"`
+	MaxCheck         uint   // maximum block height checked for transactions
+	MinCheck         uint   // minimum block height checked for transactions
+	Seed             []byte // encrypted mnemonic entropy for HD keys, empty for imported single keys
+	AccountPublicKey string // BIP44 account xpub, used to derive change addresses without the password
+	ChangeIndex      uint   // next unused change address index
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
 }
 
 func (k *Key) Save() error {
@@ -47,6 +50,52 @@ func (k Key) GetPrivateKey(password string) (*wallet.PrivateKey, error) {
 	return &privateKey, nil
 }
 
+func (k Key) IsHd() bool {
+	return len(k.Seed) > 0
+}
+
+// GetWallet returns the HD wallet, used to show the mnemonic and sign for change addresses.
+func (k Key) GetWallet(password string) (*wallet.Wallet, error) {
+	if ! k.IsHd() {
+		return nil, jerr.New("key is not an hd key")
+	}
+	key, err := crypto.GenerateEncryptionKeyFromPassword(password)
+	if err != nil {
+		return nil, jerr.Get("error generating key from password", err)
+	}
+	entropy, err := crypto.Decrypt(k.Seed, key)
+	if err != nil {
+		return nil, jerr.Get("failed to decrypt", err)
+	}
+	return &wallet.Wallet{
+		Entropy: entropy,
+	}, nil
+}
+
+// GetSpendingKeys returns the profile key followed by keys for any used HD change addresses.
+func (k Key) GetSpendingKeys(password string) ([]*wallet.PrivateKey, error) {
+	privateKey, err := k.GetPrivateKey(password)
+	if err != nil {
+		return nil, jerr.Get("error getting private key", err)
+	}
+	var privateKeys = []*wallet.PrivateKey{privateKey}
+	if ! k.IsHd() || k.ChangeIndex == 0 {
+		return privateKeys, nil
+	}
+	hdWallet, err := k.GetWallet(password)
+	if err != nil {
+		return nil, jerr.Get("error getting wallet", err)
+	}
+	changeKeys, err := hdWallet.GetPrivateKeys(wallet.HdChainChange, uint32(k.ChangeIndex))
+	if err != nil {
+		return nil, jerr.Get("error getting change keys", err)
+	}
+	for i := range changeKeys {
+		privateKeys = append(privateKeys, &changeKeys[i])
+	}
+	return privateKeys, nil
+}
+
 func (k *Key) UpdatePassword(oldPassword string, newPassword string) error {
 	privateKey, err := k.GetPrivateKey(oldPassword)
 	if err != nil {
@@ -61,6 +110,17 @@ func (k *Key) UpdatePassword(oldPassword string, newPassword string) error {
 		return jerr.Get("failed to encrypt", err)
 	}
 	k.Value = encryptedSecret
+	if k.IsHd() {
+		hdWallet, err := k.GetWallet(oldPassword)
+		if err != nil {
+			return jerr.Get("error getting wallet from password", err)
+		}
+		encryptedSeed, err := crypto.Encrypt(hdWallet.Entropy, encryptionKey)
+		if err != nil {
+			return jerr.Get("failed to encrypt seed", err)
+		}
+		k.Seed = encryptedSeed
+	}
 	err = k.Save()
 	if err != nil {
 		return jerr.Get("error saving key", err)
@@ -77,6 +137,10 @@ func (k Key) GetAddress() wallet.Address {
 }
 
 func (k Key) Delete() error {
+	err := removeKeyAddresses(k.Id)
+	if err != nil {
+		return jerr.Get("error removing key addresses", err)
+	}
 	result := remove(&k)
 	if result.Error != nil {
 		return jerr.Get("error deleting key", result.Error)
@@ -85,12 +149,20 @@ func (k Key) Delete() error {
 }
 
 func GenerateKey(name string, password string, userId uint) (*Key, error) {
-	key, err := crypto.GenerateEncryptionKeyFromPassword(password)
+	hdWallet, err := wallet.GenerateWallet()
 	if err != nil {
-		return nil, jerr.Get("error generating key from password", err)
+		return nil, jerr.Get("error generating wallet", err)
 	}
-	privateKey := wallet.GeneratePrivateKey()
-	return createKey(name, privateKey, key, userId)
+	return createHdKey(name, password, hdWallet, userId)
+}
+
+// RestoreKey creates an HD key from an existing BIP39 mnemonic.
+func RestoreKey(name string, password string, mnemonic string, userId uint) (*Key, error) {
+	hdWallet, err := wallet.GetWallet(mnemonic, "")
+	if err != nil {
+		return nil, jerr.Get("error getting wallet from mnemonic", err)
+	}
+	return createHdKey(name, password, hdWallet, userId)
 }
 
 func ImportKey(name string, password string, wif string, userId uint) (*Key, error) {
@@ -105,23 +177,67 @@ func ImportKey(name string, password string, wif string, userId uint) (*Key, err
 	return createKey(name, privateKey, key, userId)
 }
 
+// The first receive address is the profile address, its private key is stored the same as single keys.
+func createHdKey(name string, password string, hdWallet wallet.Wallet, userId uint) (*Key, error) {
+	key, err := crypto.GenerateEncryptionKeyFromPassword(password)
+	if err != nil {
+		return nil, jerr.Get("error generating key from password", err)
+	}
+	privateKey, err := hdWallet.GetPrivateKey(wallet.HdChainReceive, 0)
+	if err != nil {
+		return nil, jerr.Get("error getting profile private key", err)
+	}
+	accountPublicKey, err := hdWallet.GetAccountPublicKey()
+	if err != nil {
+		return nil, jerr.Get("error getting account public key", err)
+	}
+	encryptedSeed, err := crypto.Encrypt(hdWallet.Entropy, key)
+	if err != nil {
+		return nil, jerr.Get("failed to encrypt seed", err)
+	}
+	dbPrivateKey := newKey(name, privateKey, userId)
+	dbPrivateKey.Seed = encryptedSeed
+	dbPrivateKey.AccountPublicKey = accountPublicKey
+	err = saveNewKey(dbPrivateKey, privateKey, key)
+	if err != nil {
+		return nil, jerr.Get("error saving hd key", err)
+	}
+	err = dbPrivateKey.AddChangeAddresses()
+	if err != nil {
+		return nil, jerr.Get("error adding change addresses", err)
+	}
+	return dbPrivateKey, nil
+}
+
 func createKey(name string, privateKey wallet.PrivateKey, key []byte, userId uint) (*Key, error) {
-	encryptedSecret, err := crypto.Encrypt(privateKey.Secret, key)
+	dbPrivateKey := newKey(name, privateKey, userId)
+	err := saveNewKey(dbPrivateKey, privateKey, key)
 	if err != nil {
-		return nil, jerr.Get("failed to encrypt", err)
+		return nil, jerr.Get("error saving new key", err)
 	}
-	var dbPrivateKey = &Key{
+	return dbPrivateKey, nil
+}
+
+func newKey(name string, privateKey wallet.PrivateKey, userId uint) *Key {
+	return &Key{
 		Name:      name,
 		UserId:    userId,
-		Value:     encryptedSecret,
 		PublicKey: privateKey.GetPublicKey().GetSerialized(),
 		PkHash:    privateKey.GetPublicKey().GetAddress().GetScriptAddress(),
 	}
+}
+
+func saveNewKey(dbPrivateKey *Key, privateKey wallet.PrivateKey, key []byte) error {
+	encryptedSecret, err := crypto.Encrypt(privateKey.Secret, key)
+	if err != nil {
+		return jerr.Get("failed to encrypt", err)
+	}
+	dbPrivateKey.Value = encryptedSecret
 	result := save(dbPrivateKey)
 	if result.Error != nil {
-		return nil, jerr.Get("error saving key", result.Error)
+		return jerr.Get("error saving key", result.Error)
 	}
-	return dbPrivateKey, nil
+	return nil
 }
 
 func GetKey(id uint, userId uint) (*Key, error) {
@@ -190,7 +306,17 @@ func ContainsWatchedPkHash(pkHashes [][]byte) (bool, error) {
 	if result.Error != nil {
 		return false, jerr.Get("error running query", result.Error)
 	}
-	return len(keys) != 0, nil
+	if len(keys) != 0 {
+		return true, nil
+	}
+	var keyAddresses []*KeyAddress
+	result = db.
+		Where("pk_hash in (?)", pkHashes).
+		Find(&keyAddresses)
+	if result.Error != nil {
+		return false, jerr.Get("error running key address query", result.Error)
+	}
+	return len(keyAddresses) != 0, nil
 }
 
 func GetUserIdFromPkHash(pkHash []byte) (uint, error) {
@@ -0,0 +1,202 @@
+package db
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"time"
+)
+
+// Change addresses derived for HD keys. Addresses are kept up to the gap limit past the next unused change address
+// so the user node watches them before they are used.
+type KeyAddress struct {
+	Id           uint   `gorm:"primary_key"`
+	KeyId        uint   `gorm:"unique_index:key_address"`
+	AddressIndex uint   `gorm:"unique_index:key_address"`
+	PublicKey    []byte `gorm:"unique"`
+	PkHash       []byte `gorm:"unique"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (a KeyAddress) GetAddress() wallet.Address {
+	return wallet.GetAddressFromPkHash(a.PkHash)
+}
+
+// AddChangeAddresses derives any change addresses missing up to the gap limit.
+func (k *Key) AddChangeAddresses() error {
+	keyAddresses, err := GetKeyAddresses(k.Id)
+	if err != nil {
+		return jerr.Get("error getting key addresses", err)
+	}
+	for i := uint(len(keyAddresses)); i < k.ChangeIndex+wallet.HdGapLimit; i++ {
+		publicKey, err := wallet.GetHdPublicKey(k.AccountPublicKey, wallet.HdChainChange, uint32(i))
+		if err != nil {
+			return jerr.Get("error deriving change public key", err)
+		}
+		err = create(&KeyAddress{
+			KeyId:        k.Id,
+			AddressIndex: i,
+			PublicKey:    publicKey.GetSerialized(),
+			PkHash:       publicKey.GetAddress().GetScriptAddress(),
+		})
+		if err != nil && ! IsDuplicateEntryError(err) {
+			return jerr.Get("error creating key address", err)
+		}
+	}
+	return nil
+}
+
+// GetChangeAddress returns the next unused change address without using it.
+func (k Key) GetChangeAddress() (wallet.Address, error) {
+	publicKey, err := wallet.GetHdPublicKey(k.AccountPublicKey, wallet.HdChainChange, uint32(k.ChangeIndex))
+	if err != nil {
+		return wallet.Address{}, jerr.Get("error deriving change public key", err)
+	}
+	return publicKey.GetAddress(), nil
+}
+
+// SetChangeAddressUsed moves the next change address past index and extends watched addresses to the gap limit.
+func (k *Key) SetChangeAddressUsed(index uint) error {
+	if index < k.ChangeIndex {
+		return nil
+	}
+	k.ChangeIndex = index + 1
+	err := k.Save()
+	if err != nil {
+		return jerr.Get("error saving key change index", err)
+	}
+	err = k.AddChangeAddresses()
+	if err != nil {
+		return jerr.Get("error adding change addresses", err)
+	}
+	return nil
+}
+
+func GetKeyAddresses(keyId uint) ([]*KeyAddress, error) {
+	var keyAddresses []*KeyAddress
+	err := find(&keyAddresses, KeyAddress{
+		KeyId: keyId,
+	})
+	if err != nil {
+		return nil, jerr.Get("error finding key addresses", err)
+	}
+	return keyAddresses, nil
+}
+
+func GetAllKeyAddresses() ([]*KeyAddress, error) {
+	var keyAddresses []*KeyAddress
+	err := find(&keyAddresses, KeyAddress{})
+	if err != nil {
+		return nil, jerr.Get("error finding key addresses", err)
+	}
+	return keyAddresses, nil
+}
+
+// GetSpendablePkHashes returns the pk hash followed by the change addresses of its key when it is an HD key, so
+// balances include change.
+func GetSpendablePkHashes(pkHash []byte) ([][]byte, error) {
+	var pkHashes = [][]byte{pkHash}
+	var key Key
+	err := find(&key, Key{
+		PkHash: pkHash,
+	})
+	if err != nil {
+		if IsRecordNotFoundError(err) {
+			return pkHashes, nil
+		}
+		return nil, jerr.Get("error finding key", err)
+	}
+	if ! key.IsHd() {
+		return pkHashes, nil
+	}
+	keyAddresses, err := GetKeyAddresses(key.Id)
+	if err != nil {
+		return nil, jerr.Get("error getting key addresses", err)
+	}
+	for _, keyAddress := range keyAddresses {
+		pkHashes = append(pkHashes, keyAddress.PkHash)
+	}
+	return pkHashes, nil
+}
+
+// GetKeyPkHashesForChangeAddresses returns the profile pk hashes of keys owning any of the change addresses.
+func GetKeyPkHashesForChangeAddresses(pkHashes [][]byte) ([][]byte, error) {
+	if len(pkHashes) == 0 {
+		return nil, nil
+	}
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	var keyAddresses []*KeyAddress
+	result := db.
+		Where("pk_hash in (?)", pkHashes).
+		Find(&keyAddresses)
+	if result.Error != nil {
+		return nil, jerr.Get("error running key address query", result.Error)
+	}
+	if len(keyAddresses) == 0 {
+		return nil, nil
+	}
+	var keyIds []uint
+	for _, keyAddress := range keyAddresses {
+		keyIds = append(keyIds, keyAddress.KeyId)
+	}
+	var keys []*Key
+	result = db.
+		Where("id in (?)", keyIds).
+		Find(&keys)
+	if result.Error != nil {
+		return nil, jerr.Get("error running key query", result.Error)
+	}
+	var keyPkHashes [][]byte
+	for _, key := range keys {
+		keyPkHashes = append(keyPkHashes, key.PkHash)
+	}
+	return keyPkHashes, nil
+}
+
+// SetKeyAddressesUsed is called for transactions seen by the user node, so change addresses used by another wallet
+// restored from the same mnemonic are found.
+func SetKeyAddressesUsed(pkHashes [][]byte) error {
+	if len(pkHashes) == 0 {
+		return nil
+	}
+	db, err := getDb()
+	if err != nil {
+		return jerr.Get("error getting db", err)
+	}
+	var keyAddresses []*KeyAddress
+	result := db.
+		Where("pk_hash in (?)", pkHashes).
+		Find(&keyAddresses)
+	if result.Error != nil {
+		return jerr.Get("error running query", result.Error)
+	}
+	for _, keyAddress := range keyAddresses {
+		var key Key
+		err = find(&key, Key{
+			Id: keyAddress.KeyId,
+		})
+		if err != nil {
+			return jerr.Get("error finding key for address", err)
+		}
+		err = key.SetChangeAddressUsed(keyAddress.AddressIndex)
+		if err != nil {
+			return jerr.Get("error setting change address used", err)
+		}
+	}
+	return nil
+}
+
+func removeKeyAddresses(keyId uint) error {
+	db, err := getDb()
+	if err != nil {
+		return jerr.Get("error getting db", err)
+	}
+	result := db.Where("key_id = ?", keyId).Delete(KeyAddress{})
+	if result.Error != nil {
+		return jerr.Get("error deleting key addresses", result.Error)
+	}
+	return nil
+}
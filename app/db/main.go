@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/jchavannes/gorm"
 	_ "github.com/jchavannes/gorm/dialects/mysql"
+	_ "github.com/jchavannes/gorm/dialects/sqlite"
 	"github.com/jchavannes/jgo/jerr"
 	"github.com/memocash/memo/app/config"
 	"reflect"
@@ -55,28 +56,97 @@ var dbInterfaces = []interface{}{
 	FeedEvent{},
 	TopicInfo{},
 	UserStat{},
+	MemoRepost{},
+	MemoSetImageBaseUrl{},
+	MemoAttachPicture{},
+	SearchWord{},
+	Webhook{},
+	WebhookDelivery{},
+	ActivityPubKey{},
+	ActivityPubFollower{},
+	KeyAddress{},
+	CoinSelection{},
+	OutboundTx{},
+	UtxoReservation{},
+	MemoDirectMessage{},
+	UserMute{},
+	Moderation{},
+	PostReport{},
+	TrustScore{},
+	UnsignedBuild{},
 }
 
 func getDb() (*gorm.DB, error) {
 	if conn == nil {
-		conf := config.GetMysqlConfig()
+		dbConfig := config.GetDbConfig()
 		var err error
-		connectionString := conf.Username + ":" + conf.Password + "@tcp(" + conf.Host + ")/" + conf.Database + "?parseTime=true&loc=Local"
-		conn, err = gorm.Open("mysql", connectionString)
-		conn.LogMode(false)
-		if err != nil {
-			return conn, jerr.Get(fmt.Sprintf("failed to connect to database (host: %s)", conf.Host), err)
+		switch dbConfig.Type {
+		case config.DbTypeSqlite:
+			conn, err = getSqliteDb(dbConfig.SqlitePath)
+		default:
+			conn, err = getMysqlDb(config.GetMysqlConfig())
 		}
-		for _, dbInterface := range dbInterfaces {
-			result := conn.AutoMigrate(dbInterface)
-			if result.Error != nil {
-				return result, result.Error
-			}
+		if err != nil {
+			conn = nil
+			return nil, jerr.Get("error connecting to database", err)
 		}
 	}
 	return conn, nil
 }
 
+// Close closes the database connection, the next query connects again using the current config.
+func Close() error {
+	if conn == nil {
+		return nil
+	}
+	err := conn.Close()
+	conn = nil
+	if err != nil {
+		return jerr.Get("error closing database connection", err)
+	}
+	return nil
+}
+
+func getMysqlDb(conf config.MysqlConfig) (*gorm.DB, error) {
+	connectionString := conf.Username + ":" + conf.Password + "@tcp(" + conf.Host + ")/" + conf.Database + "?parseTime=true&loc=Local"
+	mysqlConn, err := gorm.Open("mysql", connectionString)
+	if err != nil {
+		return nil, jerr.Get(fmt.Sprintf("failed to connect to database (host: %s)", conf.Host), err)
+	}
+	mysqlConn.LogMode(false)
+	for _, dbInterface := range dbInterfaces {
+		result := mysqlConn.AutoMigrate(dbInterface)
+		if result.Error != nil {
+			return nil, jerr.Get("error migrating mysql table", result.Error)
+		}
+	}
+	dialect = mysqlDialect{}
+	return mysqlConn, nil
+}
+
+func getSqliteDb(path string) (*gorm.DB, error) {
+	if path == "" {
+		return nil, jerr.New("sqlite path not set")
+	}
+	sqlDb, err := openSqlite(path)
+	if err != nil {
+		return nil, jerr.Get("error opening sqlite db", err)
+	}
+	sqliteConn, err := gorm.Open("sqlite3", sqlDb)
+	if err != nil {
+		return nil, jerr.Get(fmt.Sprintf("failed to connect to database (path: %s)", path), err)
+	}
+	sqliteConn.LogMode(false)
+	for _, dbInterface := range dbInterfaces {
+		result := sqliteConn.AutoMigrate(dbInterface)
+		if result.Error != nil && ! isSqliteIndexExistsError(result.Error) {
+			return nil, jerr.Get("error migrating sqlite table", result.Error)
+		}
+	}
+	dialect = sqliteDialect{}
+	return sqliteConn, nil
+}
+
 func IsRecordNotFoundError(e error) bool {
 	return hasError(e, "record not found")
 }
@@ -90,7 +160,7 @@ func IsAlreadyExistsError(e error) bool {
 }
 
 func IsDuplicateEntryError(e error) bool {
-	return jerr.HasErrorPart(e, "Duplicate entry")
+	return jerr.HasErrorPart(e, "Duplicate entry") || jerr.HasErrorPart(e, "UNIQUE constraint failed")
 }
 
 func hasError(e error, s string) bool {
@@ -169,9 +239,9 @@ func findPreloadColumns(columns []string, out interface{}, where ...interface{})
 }
 
 func Save(value interface{}) error {
-	db, _ := getDb()
-	if db.Error != nil {
-		return jerr.Get("error getting db", db.Error)
+	db, err := getDb()
+	if err != nil {
+		return jerr.Get("error getting db", err)
 	}
 	result := db.Save(value)
 	if result.Error != nil {
@@ -181,18 +251,18 @@ func Save(value interface{}) error {
 }
 
 func save(value interface{}) *gorm.DB {
-	db, _ := getDb()
-	if db.Error != nil {
-		return db
+	db, err := getDb()
+	if err != nil {
+		return &gorm.DB{Error: err}
 	}
 	result := db.Save(value)
 	return result
 }
 
 func remove(value interface{}) *gorm.DB {
-	db, _ := getDb()
-	if db.Error != nil {
-		return db
+	db, err := getDb()
+	if err != nil {
+		return &gorm.DB{Error: err}
 	}
 	result := db.Delete(value)
 	return result
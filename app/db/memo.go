@@ -42,7 +42,7 @@ func (m MemoTest) GetTransactionHashString() string {
 }
 
 func (m MemoTest) GetAddressString() string {
-	pkHash, err := btcutil.NewAddressPubKeyHash(m.PkHash, &wallet.MainNetParamsOld)
+	pkHash, err := btcutil.NewAddressPubKeyHash(m.PkHash, &wallet.NetParamsOld)
 	if err != nil {
 		jerr.Get("error getting pubkeyhash from memo test", err).Print()
 		return ""
@@ -81,18 +81,32 @@ func GetMemoCohortStats() ([]view.MemoCohortStat, error) {
 		Table("memo_tests").
 		Select("COUNT(*) AS num_posts, " +
 		"COUNT(DISTINCT memo_tests.pk_hash) AS num_users," +
-		"DATE(DATE_FORMAT(user_stats.first_post, '%Y-%m-01')) AS cohort," +
-		"DATE(DATE_FORMAT(`timestamp`, '%Y-%m-%d')) AS date").
+		dialect.monthStart("user_stats.first_post") + " AS cohort," +
+		"DATE(`timestamp`) AS date").
 		Joins("JOIN blocks ON (memo_tests.block_id = blocks.id)").
 		Joins("JOIN user_stats ON (memo_tests.pk_hash = user_stats.pk_hash)").
 		Where("HEX(memo_tests.pk_script) REGEXP '6A026D(0|1).*'").
 		Group("date, cohort").
 		Order("date ASC")
-	var memoCohortStats []view.MemoCohortStat
-	result := query.Find(&memoCohortStats)
+	var memoCohortRows []struct {
+		NumPosts int
+		NumUsers int
+		Cohort   sqlTime
+		Date     sqlTime
+	}
+	result := query.Scan(&memoCohortRows)
 	if result.Error != nil {
 		return nil, jerr.Get("error getting memo stats", result.Error)
 	}
+	var memoCohortStats []view.MemoCohortStat
+	for _, memoCohortRow := range memoCohortRows {
+		memoCohortStats = append(memoCohortStats, view.MemoCohortStat{
+			Date:     memoCohortRow.Date.Time,
+			Cohort:   memoCohortRow.Cohort.Time,
+			NumPosts: memoCohortRow.NumPosts,
+			NumUsers: memoCohortRow.NumUsers,
+		})
+	}
 	return memoCohortStats, nil
 }
 
@@ -105,7 +119,7 @@ func GetUserStats() ([]view.UserStat, error) {
 		"LEFT JOIN (" +
 		"	SELECT" +
 		"		follow_pk_hash," +
-		"		COALESCE(SUM(IF(unfollow=0, 1, 0)), 0) AS num_followers" +
+		"		COALESCE(SUM(CASE WHEN unfollow=0 THEN 1 ELSE 0 END), 0) AS num_followers" +
 		"	FROM memo_follows" +
 		"	JOIN (" +
 		"		SELECT MAX(id) AS id" +
@@ -125,10 +139,26 @@ func GetUserStats() ([]view.UserStat, error) {
 		Joins(joinSql).
 		Where("HEX(memo_tests.pk_script) REGEXP '6A026D(0|1).*'").
 		Group("memo_tests.pk_hash")
-	var userStats []view.UserStat
-	result := query.Find(&userStats)
+	var userStatRows []struct {
+		PkHash       []byte
+		NumPosts     int
+		NumFollowers int
+		FirstPost    sqlTime
+		LastPost     sqlTime
+	}
+	result := query.Scan(&userStatRows)
 	if result.Error != nil {
 		return nil, jerr.Get("error getting user stats", result.Error)
 	}
+	var userStats []view.UserStat
+	for _, userStatRow := range userStatRows {
+		userStats = append(userStats, view.UserStat{
+			PkHash:       userStatRow.PkHash,
+			NumPosts:     userStatRow.NumPosts,
+			NumFollowers: userStatRow.NumFollowers,
+			FirstPost:    userStatRow.FirstPost.Time,
+			LastPost:     userStatRow.LastPost.Time,
+		})
+	}
 	return userStats, nil
 }
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"fmt"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/script"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"html"
+	"strings"
+	"time"
+)
+
+type MemoAttachPicture struct {
+	Id         uint   `gorm:"primary_key"`
+	TxHash     []byte `gorm:"unique;size:50"`
+	ParentHash []byte
+	PkHash     []byte `gorm:"index:pk_hash"`
+	PkScript   []byte `gorm:"size:500"`
+	Address    string
+	PostTxHash []byte `gorm:"index:post_tx_hash"`
+	Url        string `gorm:"size:500"`
+	BlockId    uint
+	Block      *Block
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (m MemoAttachPicture) Save() error {
+	result := save(&m)
+	if result.Error != nil {
+		return jerr.Get("error saving memo attach picture", result.Error)
+	}
+	return nil
+}
+
+func (m MemoAttachPicture) GetTransactionHashString() string {
+	hash, err := chainhash.NewHash(m.TxHash)
+	if err != nil {
+		jerr.Get("error getting chainhash from memo attach picture", err).Print()
+		return ""
+	}
+	return hash.String()
+}
+
+func (m MemoAttachPicture) GetPostTransactionHashString() string {
+	hash, err := chainhash.NewHash(m.PostTxHash)
+	if err != nil {
+		jerr.Get("error getting chainhash from memo attach picture post_tx_hash", err).Print()
+		return ""
+	}
+	return hash.String()
+}
+
+func (m MemoAttachPicture) GetAddressString() string {
+	return wallet.GetAddressFromPkHash(m.PkHash).GetEncoded()
+}
+
+func (m MemoAttachPicture) GetScriptString() string {
+	return html.EscapeString(script.GetScriptString(m.PkScript))
+}
+
+func (m MemoAttachPicture) GetExtension() string {
+	if strings.HasSuffix(m.Url, "jpg") {
+		return "jpg"
+	} else {
+		return "png"
+	}
+}
+
+func (m MemoAttachPicture) GetFileName(width int) string {
+	return fmt.Sprintf("%s-%d.%s", m.GetTransactionHashString(), width, m.GetExtension())
+}
+
+func GetMemoAttachPicture(txHash []byte) (*MemoAttachPicture, error) {
+	var memoAttachPicture MemoAttachPicture
+	err := find(&memoAttachPicture, MemoAttachPicture{
+		TxHash: txHash,
+	})
+	if err != nil {
+		return nil, jerr.Get("error getting memo attach picture", err)
+	}
+	return &memoAttachPicture, nil
+}
+
+func GetAttachedPicturesForPostTxHashes(postTxHashes [][]byte) ([]*MemoAttachPicture, error) {
+	if len(postTxHashes) == 0 {
+		return nil, nil
+	}
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	var memoAttachPictures []*MemoAttachPicture
+	result := db.
+		Where("post_tx_hash IN (?)", postTxHashes).
+		Order("id ASC").
+		Find(&memoAttachPictures)
+	if result.Error != nil {
+		return nil, jerr.Get("error getting attached pictures", result.Error)
+	}
+	return memoAttachPictures, nil
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"time"
+)
+
+const directMessagesPageSize = 25
+
+// Messages are stored encrypted, they can only be read with the recipient's private key.
+type MemoDirectMessage struct {
+	Id              uint   `gorm:"primary_key"`
+	TxHash          []byte `gorm:"unique;size:50"`
+	ParentHash      []byte
+	PkHash          []byte `gorm:"index:pk_hash"`
+	PkScript        []byte `gorm:"size:500"`
+	Address         string
+	RecipientPkHash []byte `gorm:"index:recipient_pk_hash"`
+	Encrypted       []byte `gorm:"size:500"`
+	BlockId         uint
+	Block           *Block
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (m *MemoDirectMessage) Save() error {
+	result := save(m)
+	if result.Error != nil {
+		return jerr.Get("error saving memo direct message", result.Error)
+	}
+	return nil
+}
+
+func (m MemoDirectMessage) GetTransactionHashString() string {
+	hash, err := chainhash.NewHash(m.TxHash)
+	if err != nil {
+		jerr.Get("error getting chainhash from memo direct message", err).Print()
+		return ""
+	}
+	return hash.String()
+}
+
+func (m MemoDirectMessage) GetAddressString() string {
+	return wallet.GetAddressFromPkHash(m.PkHash).GetEncoded()
+}
+
+func (m MemoDirectMessage) GetRecipientAddressString() string {
+	return wallet.GetAddressFromPkHash(m.RecipientPkHash).GetEncoded()
+}
+
+func (m MemoDirectMessage) GetTimeString() string {
+	if m.BlockId != 0 {
+		return m.Block.Timestamp.Format("2006-01-02 15:04:05")
+	}
+	return "Unconfirmed"
+}
+
+func GetMemoDirectMessage(txHash []byte) (*MemoDirectMessage, error) {
+	var memoDirectMessage MemoDirectMessage
+	err := findPreloadColumns([]string{BlockTable}, &memoDirectMessage, MemoDirectMessage{
+		TxHash: txHash,
+	})
+	if err != nil {
+		return nil, jerr.Get("error getting memo direct message", err)
+	}
+	return &memoDirectMessage, nil
+}
+
+func GetDirectMessagesForRecipient(recipientPkHash []byte, offset uint) ([]*MemoDirectMessage, error) {
+	return getDirectMessages("recipient_pk_hash = ?", recipientPkHash, offset)
+}
+
+func GetDirectMessagesFromSender(pkHash []byte, offset uint) ([]*MemoDirectMessage, error) {
+	return getDirectMessages("pk_hash = ?", pkHash, offset)
+}
+
+func getDirectMessages(where string, pkHash []byte, offset uint) ([]*MemoDirectMessage, error) {
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	var memoDirectMessages []*MemoDirectMessage
+	result := db.
+		Preload(BlockTable).
+		Where(where, pkHash).
+		Order("id DESC").
+		Limit(directMessagesPageSize).
+		Offset(offset).
+		Find(&memoDirectMessages)
+	if result.Error != nil {
+		return nil, jerr.Get("error getting memo direct messages", result.Error)
+	}
+	return memoDirectMessages, nil
+}
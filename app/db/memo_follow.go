@@ -45,7 +45,7 @@ func (m MemoFollow) GetTransactionHashString() string {
 }
 
 func (m MemoFollow) GetAddressString() string {
-	pkHash, err := btcutil.NewAddressPubKeyHash(m.PkHash, &wallet.MainNetParamsOld)
+	pkHash, err := btcutil.NewAddressPubKeyHash(m.PkHash, &wallet.NetParamsOld)
 	if err != nil {
 		jerr.Get("error getting pubkeyhash from memo follow", err).Print()
 		return ""
@@ -54,7 +54,7 @@ func (m MemoFollow) GetAddressString() string {
 }
 
 func (m MemoFollow) GetFollowAddressString() string {
-	pkHash, err := btcutil.NewAddressPubKeyHash(m.FollowPkHash, &wallet.MainNetParamsOld)
+	pkHash, err := btcutil.NewAddressPubKeyHash(m.FollowPkHash, &wallet.NetParamsOld)
 	if err != nil {
 		jerr.Get("error getting pubkeyhash from memo follow", err).Print()
 		return ""
@@ -191,7 +191,7 @@ func GetFollowingCountForPkHash(pkHash []byte) (uint, error) {
 	}
 	sql := "" +
 		"SELECT " +
-		"	COALESCE(SUM(IF(unfollow=0, 1, 0)), 0) AS following " +
+		"	COALESCE(SUM(CASE WHEN unfollow=0 THEN 1 ELSE 0 END), 0) AS following " +
 		"FROM memo_follows " +
 		"JOIN (" +
 		"	SELECT MAX(id) AS id" +
@@ -216,7 +216,7 @@ func GetFollowerCountForPkHash(followPkHash []byte) (uint, error) {
 	}
 	sql := "" +
 		"SELECT " +
-		"	COALESCE(SUM(IF(unfollow=0, 1, 0)), 0) AS followers " +
+		"	COALESCE(SUM(CASE WHEN unfollow=0 THEN 1 ELSE 0 END), 0) AS followers " +
 		"FROM memo_follows " +
 		"JOIN (" +
 		"	SELECT MAX(id) AS id" +
@@ -289,3 +289,57 @@ func GetAllFollows(offset uint) ([]*MemoFollow, error) {
 	}
 	return memoFollows, nil
 }
+
+// GetAllCurrentFollows returns the latest follow for every pair of addresses, excluding unfollows.
+func GetAllCurrentFollows() ([]*MemoFollow, error) {
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	sql := "" +
+		"SELECT " +
+		"	memo_follows.pk_hash, " +
+		"	memo_follows.follow_pk_hash " +
+		"FROM memo_follows " +
+		"JOIN (" +
+		"	SELECT MAX(id) AS id" +
+		"	FROM memo_follows" +
+		"	GROUP BY pk_hash, follow_pk_hash" +
+		") sq ON (sq.id = memo_follows.id) " +
+		"WHERE unfollow = 0"
+	var memoFollows []*MemoFollow
+	result := db.Raw(sql).Scan(&memoFollows)
+	if result.Error != nil {
+		return nil, jerr.Get("error running all follows query", result.Error)
+	}
+	return memoFollows, nil
+}
+
+// GetCurrentFollowsBetween returns current follows from any of the pk hashes to any of the follow pk hashes.
+func GetCurrentFollowsBetween(pkHashes [][]byte, followPkHashes [][]byte) ([]*MemoFollow, error) {
+	if len(pkHashes) == 0 || len(followPkHashes) == 0 {
+		return nil, nil
+	}
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	sql := "" +
+		"SELECT " +
+		"	memo_follows.pk_hash, " +
+		"	memo_follows.follow_pk_hash " +
+		"FROM memo_follows " +
+		"JOIN (" +
+		"	SELECT MAX(id) AS id" +
+		"	FROM memo_follows" +
+		"	WHERE pk_hash IN (?) AND follow_pk_hash IN (?)" +
+		"	GROUP BY pk_hash, follow_pk_hash" +
+		") sq ON (sq.id = memo_follows.id) " +
+		"WHERE unfollow = 0"
+	var memoFollows []*MemoFollow
+	result := db.Raw(sql, pkHashes, followPkHashes).Scan(&memoFollows)
+	if result.Error != nil {
+		return nil, jerr.Get("error running follows between query", result.Error)
+	}
+	return memoFollows, nil
+}
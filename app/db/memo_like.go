@@ -55,7 +55,7 @@ func (m MemoLike) GetLikeTransactionHashString() string {
 }
 
 func (m MemoLike) GetAddressString() string {
-	pkHash, err := btcutil.NewAddressPubKeyHash(m.PkHash, &wallet.MainNetParamsOld)
+	pkHash, err := btcutil.NewAddressPubKeyHash(m.PkHash, &wallet.NetParamsOld)
 	if err != nil {
 		jerr.Get("error getting pubkeyhash from memo post", err).Print()
 		return ""
@@ -137,6 +137,25 @@ func GetMemoLikesForTxnHash(txHash []byte) ([]*MemoLike, error) {
 	return memoLikes, nil
 }
 
+// GetMemoLikesForTxnHashes returns likes of any of the posts.
+func GetMemoLikesForTxnHashes(txHashes [][]byte) ([]*MemoLike, error) {
+	if len(txHashes) == 0 {
+		return nil, nil
+	}
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	var memoLikes []*MemoLike
+	result := db.
+		Where("like_tx_hash IN (?)", txHashes).
+		Find(&memoLikes)
+	if result.Error != nil {
+		return nil, jerr.Get("error getting memo likes", result.Error)
+	}
+	return memoLikes, nil
+}
+
 func GetMemoLikesForPkHash(pkHash []byte) ([]*MemoLike, error) {
 	if len(pkHash) == 0 {
 		return nil, nil
@@ -154,6 +173,25 @@ func GetMemoLikesForPkHash(pkHash []byte) ([]*MemoLike, error) {
 	return memoLikes, nil
 }
 
+func GetRecentMemoLikesForPkHash(pkHash []byte, offset uint) ([]*MemoLike, error) {
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	var memoLikes []*MemoLike
+	result := db.
+		Preload(BlockTable).
+		Where("pk_hash = ?", pkHash).
+		Order("id DESC").
+		Limit(25).
+		Offset(offset).
+		Find(&memoLikes)
+	if result.Error != nil {
+		return nil, jerr.Get("error getting recent memo likes", result.Error)
+	}
+	return memoLikes, nil
+}
+
 func sortReverse(memoLikes []*MemoLike) {
 	sort.Sort(memoLikeSortByDate(memoLikes))
 	for i, j := 0, len(memoLikes)-1; i < j; i, j = i+1, j-1 {
@@ -16,6 +16,7 @@ type MemoPollVote struct {
 	BlockId      uint
 	Block        *Block
 	OptionTxHash []byte `gorm:"index:option_tx_hash"`
+	Ranking      []byte
 	TipAmount    int64
 	TipPkHash    []byte
 	Message      string
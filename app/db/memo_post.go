@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/gorm"
 	"github.com/jchavannes/jgo/jerr"
 	"github.com/memocash/memo/app/bitcoin/script"
 	"github.com/memocash/memo/app/bitcoin/wallet"
@@ -272,7 +273,8 @@ func GetRecentPosts(offset uint, searchString string) ([]*MemoPost, error) {
 	}
 	db = db.Preload(BlockTable)
 	if searchString != "" {
-		db = db.Where("message LIKE ?", fmt.Sprintf("%%%s%%", searchString))
+		searchQuery, searchArgs := getPostSearchQuery(searchString)
+		db = db.Where(searchQuery, searchArgs...)
 	}
 	var memoPosts []*MemoPost
 	result := db.
@@ -366,32 +368,52 @@ func GetTopPosts(offset uint, timeStart time.Time, timeEnd time.Time) ([]*MemoPo
 	return sortedPosts, nil
 }
 
+// Uses the block time when the post was seen after it was mined, e.g. during a resync.
+const postTimestamp = "CASE WHEN COALESCE(blocks.timestamp, memo_posts.created_at) < memo_posts.created_at THEN blocks.timestamp ELSE memo_posts.created_at END"
+
 const (
 	RankCountBoost int     = 60
 	RankGravity    float32 = 2
 )
 
-func GetRankedPosts(offset uint, searchString string) ([]*MemoPost, error) {
-	db, err := getDb()
+// Likes and replies, with tips counted twice, used to pick rank candidates. Newer posts break ties.
+const rankCandidateActivity = "COUNT(DISTINCT memo_likes.tx_hash) + " +
+	"COUNT(DISTINCT CASE WHEN memo_likes.tip_amount > 0 THEN memo_likes.tx_hash END) + " +
+	"COUNT(DISTINCT replies.tx_hash)"
+
+// GetRankCandidatePosts returns the most active posts from the last number of days, or matching a search, for ranking
+// outside of SQL. An empty topic includes all posts.
+func GetRankCandidatePosts(topic string, searchString string, days int, limit uint) ([]*MemoPost, error) {
+	query, err := getRankCandidateQuery(topic, searchString, days)
 	if err != nil {
-		return nil, jerr.Get("error getting db", err)
+		return nil, jerr.Get("error getting rank candidate query", err)
+	}
+	var memoPosts []*MemoPost
+	result := query.
+		Joins("LEFT OUTER JOIN memo_likes ON (memo_posts.tx_hash = memo_likes.like_tx_hash)").
+		Joins("LEFT OUTER JOIN memo_posts replies ON (memo_posts.tx_hash = replies.parent_tx_hash)").
+		Group("memo_posts.id").
+		Order(rankCandidateActivity + " DESC, memo_posts.id DESC").
+		Limit(limit).
+		Preload(BlockTable).
+		Find(&memoPosts)
+	if result.Error != nil {
+		return nil, jerr.Get("error running query", result.Error)
 	}
-	var coalescedTimestamp = "IF(COALESCE(blocks.timestamp, memo_posts.created_at) < memo_posts.created_at, blocks.timestamp, memo_posts.created_at)"
-	var scoreQuery = fmt.Sprintf("((COUNT(DISTINCT memo_likes.pk_hash)-1)*%d)/POW(TIMESTAMPDIFF(MINUTE, "+coalescedTimestamp+", NOW())+2,%0.2f)", RankCountBoost, RankGravity)
+	return memoPosts, nil
+}
 
-	if searchString != "" {
-		db = db.Where("message LIKE ?", fmt.Sprintf("%%%s%%", searchString))
-	} else {
-		db = db.Where(coalescedTimestamp + " > DATE_SUB(NOW(), INTERVAL 7 DAY)")
+// GetNewestRankCandidatePosts returns the newest posts from the same window as GetRankCandidatePosts, so posts without
+// any activity yet can still be ranked.
+func GetNewestRankCandidatePosts(topic string, searchString string, days int, limit uint) ([]*MemoPost, error) {
+	query, err := getRankCandidateQuery(topic, searchString, days)
+	if err != nil {
+		return nil, jerr.Get("error getting rank candidate query", err)
 	}
 	var memoPosts []*MemoPost
-	result := db.
-		Joins("LEFT OUTER JOIN memo_likes ON (memo_posts.tx_hash = memo_likes.like_tx_hash)").
-		Joins("LEFT OUTER JOIN blocks ON (memo_posts.block_id = blocks.id)").
-		Group("memo_posts.tx_hash").
-		Order(scoreQuery + " DESC").
-		Limit(25).
-		Offset(offset).
+	result := query.
+		Order("memo_posts.id DESC").
+		Limit(limit).
 		Preload(BlockTable).
 		Find(&memoPosts)
 	if result.Error != nil {
@@ -400,13 +422,29 @@ func GetRankedPosts(offset uint, searchString string) ([]*MemoPost, error) {
 	return memoPosts, nil
 }
 
+func getRankCandidateQuery(topic string, searchString string, days int) (*gorm.DB, error) {
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	if searchString != "" {
+		searchQuery, searchArgs := getPostSearchQuery(searchString)
+		db = db.Where(searchQuery, searchArgs...)
+	} else {
+		db = db.Where(dialect.withinDays(postTimestamp, days))
+	}
+	if topic != "" {
+		db = db.Where("memo_posts.topic = ?", topic)
+	}
+	return db.Joins("LEFT OUTER JOIN blocks ON (memo_posts.block_id = blocks.id)"), nil
+}
+
 func GetPollsPosts(offset uint) ([]*MemoPost, error) {
 	db, err := getDb()
 	if err != nil {
 		return nil, jerr.Get("error getting db", err)
 	}
-	var coalescedTimestamp = "IF(COALESCE(blocks.timestamp, memo_posts.created_at) < memo_posts.created_at, blocks.timestamp, memo_posts.created_at)"
-	var scoreQuery = fmt.Sprintf("((COUNT(DISTINCT memo_poll_votes.tx_hash)-1)*%d)/POW(TIMESTAMPDIFF(MINUTE, "+coalescedTimestamp+", NOW())+2,%0.2f)", RankCountBoost, RankGravity)
+	var scoreQuery = fmt.Sprintf("((COUNT(DISTINCT memo_poll_votes.tx_hash)-1)*%d)/POW("+dialect.minutesSince(postTimestamp)+"+2,%0.2f)", RankCountBoost, RankGravity)
 
 	var memoPosts []*MemoPost
 	result := db.
@@ -458,10 +496,10 @@ func GetCountMemoPosts() (uint, uint, uint, uint, error) {
 		return 0, 0, 0, 0, jerr.Get("error getting db", err)
 	}
 	selectStmt := "" +
-		"SUM(IF(IFNULL(topic, '') = '' AND IFNULL(parent_tx_hash, b'') = b'', 1, 0)) AS non_topic_posts, " +
-		"SUM(IF(IFNULL(is_vote, 0) = 1, 1, 0)) AS vote_posts, " +
-		"SUM(IF(IFNULL(topic, '') = '', 0, 1)) AS topic_posts, " +
-		"SUM(IF(IFNULL(parent_tx_hash, b'') = b'', 0, 1)) AS reply_posts"
+		"SUM(CASE WHEN IFNULL(topic, '') = '' AND IFNULL(parent_tx_hash, X'') = X'' THEN 1 ELSE 0 END) AS non_topic_posts, " +
+		"SUM(CASE WHEN IFNULL(is_vote, 0) = 1 THEN 1 ELSE 0 END) AS vote_posts, " +
+		"SUM(CASE WHEN IFNULL(topic, '') = '' THEN 0 ELSE 1 END) AS topic_posts, " +
+		"SUM(CASE WHEN IFNULL(parent_tx_hash, X'') = X'' THEN 0 ELSE 1 END) AS reply_posts"
 	query := db.
 		Model(&MemoPost{}).
 		Where("IFNULL(is_poll, 0) = 0").
@@ -497,7 +535,7 @@ func GetTopicInfoFromPosts(topicNames ...string) ([]*view.Topic, error) {
 		Table("memo_posts").
 		Select("" +
 		"memo_posts.topic, " +
-		"CAST(MAX(IF(COALESCE(blocks.timestamp, memo_posts.created_at) < memo_posts.created_at, blocks.timestamp, memo_posts.created_at)) AS DATETIME) AS max_time, " +
+		dialect.castDatetime("MAX("+postTimestamp+")") + " AS max_time, " +
 		"COUNT(DISTINCT memo_posts.id) AS post_count, " +
 		"COUNT(DISTINCT case memo_topic_follows.unfollow when 0 then memo_topic_follows.id else null end) AS follower_count").
 		Joins("LEFT JOIN memo_topic_follows ON (memo_posts.topic = memo_topic_follows.topic)").
@@ -517,10 +555,12 @@ func GetTopicInfoFromPosts(topicNames ...string) ([]*view.Topic, error) {
 	var topics []*view.Topic
 	for rows.Next() {
 		var topic view.Topic
-		err := rows.Scan(&topic.Name, &topic.RecentTime, &topic.CountPosts, &topic.CountFollows)
+		var recentTime sqlTime
+		err := rows.Scan(&topic.Name, &recentTime, &topic.CountPosts, &topic.CountFollows)
 		if err != nil {
 			return nil, jerr.Get("error scanning row with topic", err)
 		}
+		topic.RecentTime = recentTime.Time
 		topics = append(topics, &topic)
 	}
 	return topics, nil
@@ -635,7 +675,7 @@ func GetThreads(offset uint, topic string) ([]*view.Thread, error) {
 		"topic_posts.message AS message, " +
 		"root_tx_hash, " +
 		"COUNT(memo_posts.`id`) AS num_replies, " +
-		"CAST(MAX(IF(COALESCE(blocks.timestamp, memo_posts.created_at) < memo_posts.created_at, blocks.timestamp, memo_posts.created_at)) AS DATETIME) AS recent_reply").
+		dialect.castDatetime("MAX("+postTimestamp+")") + " AS recent_reply").
 		Joins("LEFT JOIN blocks ON (memo_posts.block_id = blocks.id)").
 		Group("memo_posts.root_tx_hash").
 		Order("recent_reply DESC").
@@ -655,10 +695,26 @@ func GetThreads(offset uint, topic string) ([]*view.Thread, error) {
 			") topic_posts ON (memo_posts.root_tx_hash = topic_posts.tx_hash)"
 		query = query.Joins(joinSelect)
 	}
-	var threads []*view.Thread
-	result := query.Scan(&threads)
+	var threadRows []struct {
+		Topic       string
+		Message     string
+		RootTxHash  []byte
+		NumReplies  int
+		RecentReply sqlTime
+	}
+	result := query.Scan(&threadRows)
 	if result.Error != nil {
 		return nil, jerr.Get("error getting threads", result.Error)
 	}
+	var threads []*view.Thread
+	for _, threadRow := range threadRows {
+		threads = append(threads, &view.Thread{
+			Topic:       threadRow.Topic,
+			Message:     threadRow.Message,
+			RootTxHash:  threadRow.RootTxHash,
+			NumReplies:  threadRow.NumReplies,
+			RecentReply: threadRow.RecentReply.Time,
+		})
+	}
 	return threads, nil
 }
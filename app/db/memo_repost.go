@@ -0,0 +1,121 @@
+package db
+
+import (
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/script"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"html"
+	"time"
+)
+
+type MemoRepost struct {
+	Id           uint   `gorm:"primary_key"`
+	TxHash       []byte `gorm:"unique;size:50"`
+	ParentHash   []byte
+	PkHash       []byte `gorm:"index:pk_hash"`
+	PkScript     []byte `gorm:"size:500"`
+	Address      string
+	RepostTxHash []byte `gorm:"index:repost_tx_hash"`
+	Message      string `gorm:"size:500"`
+	BlockId      uint
+	Block        *Block
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (m MemoRepost) Save() error {
+	result := save(&m)
+	if result.Error != nil {
+		return jerr.Get("error saving memo repost", result.Error)
+	}
+	return nil
+}
+
+func (m MemoRepost) GetTransactionHashString() string {
+	hash, err := chainhash.NewHash(m.TxHash)
+	if err != nil {
+		jerr.Get("error getting chainhash from memo repost", err).Print()
+		return ""
+	}
+	return hash.String()
+}
+
+func (m MemoRepost) GetRepostTransactionHashString() string {
+	hash, err := chainhash.NewHash(m.RepostTxHash)
+	if err != nil {
+		jerr.Get("error getting chainhash from memo repost repost_tx_hash", err).Print()
+		return ""
+	}
+	return hash.String()
+}
+
+func (m MemoRepost) GetAddressString() string {
+	return m.GetAddress().GetEncoded()
+}
+
+func (m MemoRepost) GetAddress() wallet.Address {
+	return wallet.GetAddressFromPkHash(m.PkHash)
+}
+
+func (m MemoRepost) GetScriptString() string {
+	return html.EscapeString(script.GetScriptString(m.PkScript))
+}
+
+func (m MemoRepost) GetTimeString() string {
+	if m.BlockId != 0 {
+		return m.Block.Timestamp.Format("2006-01-02 15:04:05")
+	}
+	return "Unconfirmed"
+}
+
+func GetMemoRepost(txHash []byte) (*MemoRepost, error) {
+	var memoRepost MemoRepost
+	err := findPreloadColumns([]string{BlockTable}, &memoRepost, MemoRepost{
+		TxHash: txHash,
+	})
+	if err != nil {
+		return nil, jerr.Get("error getting memo repost", err)
+	}
+	return &memoRepost, nil
+}
+
+func GetMemoRepostsByTxHashes(txHashes [][]byte) ([]*MemoRepost, error) {
+	var memoReposts []*MemoRepost
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	result := db.
+		Where("tx_hash IN (?)", txHashes).
+		Find(&memoReposts)
+	if result.Error != nil {
+		return nil, jerr.Get("error getting memo reposts", result.Error)
+	}
+	return memoReposts, nil
+}
+
+func GetMemoRepostsForTxnHash(txHash []byte) ([]*MemoRepost, error) {
+	var memoReposts []*MemoRepost
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	result := db.
+		Preload(BlockTable).
+		Where("repost_tx_hash = ?", txHash).
+		Order("id DESC").
+		Find(&memoReposts)
+	if result.Error != nil {
+		return nil, jerr.Get("error getting memo reposts", result.Error)
+	}
+	return memoReposts, nil
+}
+
+func GetCountMemoReposts() (uint, error) {
+	cnt, err := count(&MemoRepost{})
+	if err != nil {
+		return 0, jerr.Get("error getting total count", err)
+	}
+	return cnt, nil
+}
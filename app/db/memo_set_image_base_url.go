@@ -0,0 +1,110 @@
+package db
+
+import (
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/script"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"html"
+	"time"
+)
+
+type MemoSetImageBaseUrl struct {
+	Id         uint   `gorm:"primary_key"`
+	TxHash     []byte `gorm:"unique;size:50"`
+	ParentHash []byte
+	PkHash     []byte `gorm:"index:pk_hash"`
+	PkScript   []byte `gorm:"size:500"`
+	Address    string
+	Url        string `gorm:"size:500"`
+	BlockId    uint
+	Block      *Block
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (m MemoSetImageBaseUrl) Save() error {
+	result := save(&m)
+	if result.Error != nil {
+		return jerr.Get("error saving memo set image base url", result.Error)
+	}
+	return nil
+}
+
+func (m MemoSetImageBaseUrl) GetTransactionHashString() string {
+	hash, err := chainhash.NewHash(m.TxHash)
+	if err != nil {
+		jerr.Get("error getting chainhash from memo set image base url", err).Print()
+		return ""
+	}
+	return hash.String()
+}
+
+func (m MemoSetImageBaseUrl) GetAddressString() string {
+	return wallet.GetAddressFromPkHash(m.PkHash).GetEncoded()
+}
+
+func (m MemoSetImageBaseUrl) GetScriptString() string {
+	return html.EscapeString(script.GetScriptString(m.PkScript))
+}
+
+func GetMemoSetImageBaseUrl(txHash []byte) (*MemoSetImageBaseUrl, error) {
+	var memoSetImageBaseUrl MemoSetImageBaseUrl
+	err := find(&memoSetImageBaseUrl, MemoSetImageBaseUrl{
+		TxHash: txHash,
+	})
+	if err != nil {
+		return nil, jerr.Get("error getting memo set image base url", err)
+	}
+	return &memoSetImageBaseUrl, nil
+}
+
+func GetImageBaseUrlForPkHash(pkHash []byte) (*MemoSetImageBaseUrl, error) {
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	var memoSetImageBaseUrls []*MemoSetImageBaseUrl
+	result := db.
+		Where("pk_hash = ?", pkHash).
+		Order("id DESC").
+		Limit(1).
+		Find(&memoSetImageBaseUrls)
+	if result.Error != nil {
+		return nil, jerr.Get("error getting image base url", result.Error)
+	}
+	if len(memoSetImageBaseUrls) == 0 {
+		return nil, nil
+	}
+	return memoSetImageBaseUrls[0], nil
+}
+
+// GetImageBaseUrlForPkHashAtBlock returns the latest image base url set at or before the block, so a resync resolves
+// urls the same way as when they were first seen. Unconfirmed base urls are only used for unconfirmed txs.
+func GetImageBaseUrlForPkHashAtBlock(pkHash []byte, block *Block) (*MemoSetImageBaseUrl, error) {
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	query := db.
+		Joins("LEFT OUTER JOIN blocks ON (memo_set_image_base_urls.block_id = blocks.id)").
+		Where("memo_set_image_base_urls.pk_hash = ?", pkHash)
+	if block != nil {
+		query = query.Where("blocks.height <= ?", block.Height)
+	} else {
+		query = query.Order("CASE WHEN blocks.id IS NULL THEN 1 ELSE 0 END DESC")
+	}
+	var memoSetImageBaseUrls []*MemoSetImageBaseUrl
+	result := query.
+		Order("blocks.height DESC").
+		Order("memo_set_image_base_urls.id DESC").
+		Limit(1).
+		Find(&memoSetImageBaseUrls)
+	if result.Error != nil {
+		return nil, jerr.Get("error getting image base url", result.Error)
+	}
+	if len(memoSetImageBaseUrls) == 0 {
+		return nil, nil
+	}
+	return memoSetImageBaseUrls[0], nil
+}
@@ -43,7 +43,7 @@ func (m MemoSetName) GetTransactionHashString() string {
 }
 
 func (m MemoSetName) GetAddressString() string {
-	pkHash, err := btcutil.NewAddressPubKeyHash(m.PkHash, &wallet.MainNetParamsOld)
+	pkHash, err := btcutil.NewAddressPubKeyHash(m.PkHash, &wallet.NetParamsOld)
 	if err != nil {
 		jerr.Get("error getting pubkeyhash from memo post", err).Print()
 		return ""
@@ -44,7 +44,7 @@ func (m MemoSetPic) GetTransactionHashString() string {
 }
 
 func (m MemoSetPic) GetAddressString() string {
-	pkHash, err := btcutil.NewAddressPubKeyHash(m.PkHash, &wallet.MainNetParamsOld)
+	pkHash, err := btcutil.NewAddressPubKeyHash(m.PkHash, &wallet.NetParamsOld)
 	if err != nil {
 		jerr.Get("error getting pubkeyhash from memo post", err).Print()
 		return ""
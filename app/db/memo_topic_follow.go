@@ -85,7 +85,7 @@ func GetMemoTopicFollowCountForUser(pkHash []byte) (uint, error) {
 		return 0, jerr.Get("error getting db", err)
 	}
 	sql := "" +
-		"SELECT COALESCE(SUM(IF(unfollow, 0, 1)), 0) AS following_count " +
+		"SELECT COALESCE(SUM(CASE WHEN unfollow THEN 0 ELSE 1 END), 0) AS following_count " +
 		"FROM memo_topic_follows " +
 		"JOIN (" +
 		"	SELECT MAX(id) AS id" +
@@ -0,0 +1,144 @@
+package db
+
+import (
+	"bytes"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"net/url"
+	"time"
+)
+
+// Post, address or topic hidden by an admin. Chain data is kept, hidden items are only excluded from rendering.
+type Moderation struct {
+	Id        uint   `gorm:"primary_key"`
+	TxHash    []byte `gorm:"size:32;index:tx_hash"`
+	PkHash    []byte `gorm:"size:20;index:pk_hash"`
+	Topic     string `gorm:"size:500"`
+	Reason    string `gorm:"size:255"`
+	UserId    uint
+	CreatedAt time.Time
+}
+
+func (m Moderation) IsPost() bool {
+	return len(m.TxHash) > 0
+}
+
+func (m Moderation) IsAddress() bool {
+	return len(m.PkHash) > 0
+}
+
+func (m Moderation) GetTransactionHashString() string {
+	hash, err := chainhash.NewHash(m.TxHash)
+	if err != nil {
+		jerr.Get("error getting chainhash from moderation", err).Print()
+		return ""
+	}
+	return hash.String()
+}
+
+func (m Moderation) GetAddressString() string {
+	return wallet.GetAddressFromPkHash(m.PkHash).GetEncoded()
+}
+
+func (m Moderation) GetUrlEncodedTopic() string {
+	return url.QueryEscape(m.Topic)
+}
+
+type ModerationList struct {
+	Hides []*Moderation
+}
+
+func (m ModerationList) IsTxHashHidden(txHash []byte) bool {
+	for _, hide := range m.Hides {
+		if hide.IsPost() && bytes.Equal(hide.TxHash, txHash) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m ModerationList) IsPkHashHidden(pkHash []byte) bool {
+	for _, hide := range m.Hides {
+		if hide.IsAddress() && bytes.Equal(hide.PkHash, pkHash) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m ModerationList) IsTopicHidden(topic string) bool {
+	if topic == "" {
+		return false
+	}
+	for _, hide := range m.Hides {
+		if ! hide.IsPost() && ! hide.IsAddress() && hide.Topic == topic {
+			return true
+		}
+	}
+	return false
+}
+
+func (m ModerationList) IsPostHidden(memoPost *MemoPost) bool {
+	return m.IsTxHashHidden(memoPost.TxHash) || m.IsPkHashHidden(memoPost.PkHash) || m.IsTopicHidden(memoPost.Topic)
+}
+
+func (m ModerationList) FilterPosts(memoPosts []*MemoPost) []*MemoPost {
+	if len(m.Hides) == 0 {
+		return memoPosts
+	}
+	var filteredPosts []*MemoPost
+	for _, memoPost := range memoPosts {
+		if ! m.IsPostHidden(memoPost) {
+			filteredPosts = append(filteredPosts, memoPost)
+		}
+	}
+	return filteredPosts
+}
+
+func AddModeration(userId uint, txHash []byte, pkHash []byte, topic string, reason string) (*Moderation, error) {
+	var moderation = Moderation{
+		TxHash: txHash,
+		PkHash: pkHash,
+		Topic:  topic,
+		Reason: reason,
+		UserId: userId,
+	}
+	err := create(&moderation)
+	if err != nil {
+		return nil, jerr.Get("error creating moderation", err)
+	}
+	return &moderation, nil
+}
+
+func GetModerationList() (*ModerationList, error) {
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	var moderations []*Moderation
+	result := db.
+		Order("id DESC").
+		Find(&moderations)
+	if result.Error != nil {
+		return nil, jerr.Get("error getting moderations", result.Error)
+	}
+	return &ModerationList{
+		Hides: moderations,
+	}, nil
+}
+
+func DeleteModeration(id uint) error {
+	db, err := getDb()
+	if err != nil {
+		return jerr.Get("error getting db", err)
+	}
+	result := db.Where("id = ?", id).Delete(Moderation{})
+	if result.Error != nil {
+		return jerr.Get("error deleting moderation", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return jerr.New("moderation not found")
+	}
+	return nil
+}
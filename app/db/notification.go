@@ -6,10 +6,11 @@ import (
 )
 
 const (
-	NotificationTypeLike        = 1
-	NotificationTypeReply       = 2
-	NotificationTypeThreadReply = 3
-	NotificationTypeNewFollower = 4
+	NotificationTypeLike          = 1
+	NotificationTypeReply         = 2
+	NotificationTypeThreadReply   = 3
+	NotificationTypeNewFollower   = 4
+	NotificationTypeDirectMessage = 5
 )
 
 type Notification struct {
@@ -37,6 +38,18 @@ func AddNotification(pkHash []byte, txHash []byte, notificationType uint) (*Noti
 	return nil, nil
 }
 
+func GetNotification(pkHash []byte, txHash []byte) (*Notification, error) {
+	var notification Notification
+	err := find(&notification, Notification{
+		PkHash: pkHash,
+		TxHash: txHash,
+	})
+	if err != nil {
+		return nil, jerr.Get("error getting notification", err)
+	}
+	return &notification, nil
+}
+
 func GetRecentNotificationsForUser(pkHash []byte, offset uint) ([]*Notification, error) {
 	db, err := getDb()
 	if err != nil {
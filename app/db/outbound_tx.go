@@ -0,0 +1,184 @@
+package db
+
+import (
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/jgo/jerr"
+	"time"
+)
+
+const (
+	OutboundTxStatusQueued    = "queued"
+	OutboundTxStatusBroadcast = "broadcast"
+	OutboundTxStatusMempool   = "mempool"
+	OutboundTxStatusConfirmed = "confirmed"
+	OutboundTxStatusRejected  = "rejected"
+)
+
+const outboundTxsPageSize = 25
+
+// Transactions built by the server and sent to peers. Unconfirmed transactions are rebroadcast until they confirm,
+// are rejected or expire.
+type OutboundTx struct {
+	Id               uint   `gorm:"primary_key"`
+	TxHash           []byte `gorm:"size:32;unique"`
+	PkHash           []byte `gorm:"index:pk_hash"`
+	Type             string `gorm:"size:25"`
+	Raw              []byte `gorm:"type:blob"`
+	Status           string `gorm:"size:25;index:status"`
+	RejectReason     string `gorm:"size:255"`
+	ReplacedByTxHash []byte `gorm:"size:32"`
+	Broadcasts       uint
+	BroadcastAt      *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+func (o *OutboundTx) Save() error {
+	result := save(o)
+	if result.Error != nil {
+		return jerr.Get("error saving outbound tx", result.Error)
+	}
+	return nil
+}
+
+func (o OutboundTx) GetTransactionHashString() string {
+	hash, err := chainhash.NewHash(o.TxHash)
+	if err != nil {
+		jerr.Get("error getting chainhash from outbound tx", err).Print()
+		return ""
+	}
+	return hash.String()
+}
+
+func (o OutboundTx) GetReplacedByTxHashString() string {
+	if len(o.ReplacedByTxHash) == 0 {
+		return ""
+	}
+	hash, err := chainhash.NewHash(o.ReplacedByTxHash)
+	if err != nil {
+		jerr.Get("error getting chainhash from outbound tx replacement", err).Print()
+		return ""
+	}
+	return hash.String()
+}
+
+func (o OutboundTx) IsRejected() bool {
+	return o.Status == OutboundTxStatusRejected
+}
+
+// SetBroadcast records a send to peers, a tx already seen stays in its current state.
+func (o *OutboundTx) SetBroadcast() error {
+	now := time.Now()
+	o.Broadcasts++
+	o.BroadcastAt = &now
+	if o.Status == OutboundTxStatusQueued {
+		o.Status = OutboundTxStatusBroadcast
+	}
+	err := o.Save()
+	if err != nil {
+		return jerr.Get("error saving outbound tx broadcast", err)
+	}
+	return nil
+}
+
+// SetRejected is ignored once the tx has been seen, since other peers accepted it.
+func (o *OutboundTx) SetRejected(reason string, replacedByTxHash []byte) error {
+	if o.Status == OutboundTxStatusMempool || o.Status == OutboundTxStatusConfirmed {
+		return nil
+	}
+	o.Status = OutboundTxStatusRejected
+	o.RejectReason = reason
+	o.ReplacedByTxHash = replacedByTxHash
+	err := o.Save()
+	if err != nil {
+		return jerr.Get("error saving outbound tx reject", err)
+	}
+	return nil
+}
+
+func AddOutboundTx(txHash []byte, pkHash []byte, txType string, raw []byte) (*OutboundTx, error) {
+	var outboundTx = OutboundTx{
+		TxHash: txHash,
+		PkHash: pkHash,
+		Type:   txType,
+		Raw:    raw,
+		Status: OutboundTxStatusQueued,
+	}
+	err := create(&outboundTx)
+	if err != nil {
+		return nil, jerr.Get("error creating outbound tx", err)
+	}
+	return &outboundTx, nil
+}
+
+func GetOutboundTx(txHash []byte) (*OutboundTx, error) {
+	var outboundTx OutboundTx
+	err := find(&outboundTx, OutboundTx{
+		TxHash: txHash,
+	})
+	if err != nil {
+		return nil, jerr.Get("error finding outbound tx", err)
+	}
+	return &outboundTx, nil
+}
+
+// SetOutboundTxSeen is called for every saved transaction, most of which are not outbound.
+func SetOutboundTxSeen(txHash []byte, confirmed bool) error {
+	db, err := getDb()
+	if err != nil {
+		return jerr.Get("error getting db", err)
+	}
+	var status = OutboundTxStatusMempool
+	var fromStatuses = []string{OutboundTxStatusQueued, OutboundTxStatusBroadcast, OutboundTxStatusRejected}
+	if confirmed {
+		status = OutboundTxStatusConfirmed
+		fromStatuses = append(fromStatuses, OutboundTxStatusMempool)
+	}
+	result := db.
+		Model(&OutboundTx{}).
+		Where("tx_hash = ? AND status IN (?)", txHash, fromStatuses).
+		Updates(map[string]interface{}{
+			"status":        status,
+			"reject_reason": "",
+		})
+	if result.Error != nil {
+		return jerr.Get("error updating outbound tx status", result.Error)
+	}
+	return nil
+}
+
+func GetRecentOutboundTxs(pkHash []byte) ([]*OutboundTx, error) {
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	var outboundTxs []*OutboundTx
+	result := db.
+		Where("pk_hash = ?", pkHash).
+		Order("id DESC").
+		Limit(outboundTxsPageSize).
+		Find(&outboundTxs)
+	if result.Error != nil {
+		return nil, jerr.Get("error getting recent outbound txs", result.Error)
+	}
+	return outboundTxs, nil
+}
+
+// GetOutboundTxsToRebroadcast returns unconfirmed txs created since createdAfter and not sent since broadcastBefore.
+func GetOutboundTxsToRebroadcast(createdAfter time.Time, broadcastBefore time.Time, limit uint) ([]*OutboundTx, error) {
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	var outboundTxs []*OutboundTx
+	result := db.
+		Where("status IN (?)", []string{OutboundTxStatusQueued, OutboundTxStatusBroadcast, OutboundTxStatusMempool}).
+		Where("created_at > ? AND (broadcast_at IS NULL OR broadcast_at < ?)", createdAfter, broadcastBefore).
+		Order("broadcast_at ASC").
+		Limit(limit).
+		Find(&outboundTxs)
+	if result.Error != nil {
+		return nil, jerr.Get("error getting outbound txs to rebroadcast", result.Error)
+	}
+	return outboundTxs, nil
+}
@@ -3,18 +3,40 @@ package db
 import (
 	"fmt"
 	"github.com/jchavannes/btcd/wire"
+	"github.com/jchavannes/jgo/jerr"
 	"net"
 	"time"
 )
 
+const (
+	PeerMaxFailures    = 5
+	PeerMaxMisbehavior = 3
+	PeerBanDuration    = time.Hour
+	PeerMinUptime      = time.Minute
+)
+
 type Peer struct {
-	Id        uint `gorm:"primary_key"`
-	IP        []byte `gorm:"unique_index:ip_port"`
-	Port      uint16 `gorm:"unique_index:ip_port"`
-	Services  uint64
-	Address   string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	Id            uint   `gorm:"primary_key"`
+	IP            []byte `gorm:"unique_index:ip_port"`
+	Port          uint16 `gorm:"unique_index:ip_port"`
+	Services      uint64
+	Address       string
+	Successes     uint
+	Failures      uint
+	Misbehavior   uint
+	LastConnectAt *time.Time
+	LastFailAt    *time.Time
+	BannedUntil   *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (p *Peer) Save() error {
+	result := save(p)
+	if result.Error != nil {
+		return jerr.Get("error saving peer", result.Error)
+	}
+	return nil
 }
 
 func (p *Peer) GetAddress() string {
@@ -24,3 +46,87 @@ func (p *Peer) GetAddress() string {
 func (p *Peer) GetServices() string {
 	return wire.ServiceFlag(p.Services).String()
 }
+
+func (p *Peer) IsBanned() bool {
+	return p.BannedUntil != nil && p.BannedUntil.After(time.Now())
+}
+
+func (p *Peer) RecordConnect() error {
+	now := time.Now()
+	p.Successes++
+	p.LastConnectAt = &now
+	return p.Save()
+}
+
+// Connections that drop shortly after connecting count as failures, otherwise the failure count is reset.
+func (p *Peer) RecordDisconnect() error {
+	if p.LastConnectAt != nil && time.Since(*p.LastConnectAt) > PeerMinUptime {
+		p.Failures = 0
+		return p.Save()
+	}
+	return p.RecordFailure()
+}
+
+func (p *Peer) RecordFailure() error {
+	now := time.Now()
+	p.Failures++
+	p.LastFailAt = &now
+	if p.Failures >= PeerMaxFailures {
+		p.ban()
+	}
+	return p.Save()
+}
+
+func (p *Peer) RecordMisbehavior() error {
+	p.Misbehavior++
+	if p.Misbehavior >= PeerMaxMisbehavior {
+		p.ban()
+	}
+	return p.Save()
+}
+
+func (p *Peer) ban() {
+	bannedUntil := time.Now().Add(PeerBanDuration)
+	p.BannedUntil = &bannedUntil
+	p.Failures = 0
+	p.Misbehavior = 0
+}
+
+func GetOrCreatePeer(ip []byte, port uint16, address string) (*Peer, error) {
+	var peer = &Peer{
+		IP:   ip,
+		Port: port,
+	}
+	err := find(peer, peer)
+	if err == nil {
+		return peer, nil
+	}
+	if ! IsRecordNotFoundError(err) {
+		return nil, jerr.Get("error getting peer", err)
+	}
+	peer.Address = address
+	err = create(peer)
+	if err != nil {
+		return nil, jerr.Get("error creating peer", err)
+	}
+	return peer, nil
+}
+
+type PeerSortByHealth []*Peer
+
+func (peers PeerSortByHealth) Len() int      { return len(peers) }
+func (peers PeerSortByHealth) Swap(i, j int) { peers[i], peers[j] = peers[j], peers[i] }
+func (peers PeerSortByHealth) Less(i, j int) bool {
+	if peers[i].Failures+peers[i].Misbehavior != peers[j].Failures+peers[j].Misbehavior {
+		return peers[i].Failures+peers[i].Misbehavior < peers[j].Failures+peers[j].Misbehavior
+	}
+	return peers[i].Successes > peers[j].Successes
+}
+
+type PeerSortByBan []*Peer
+
+func (peers PeerSortByBan) Len() int      { return len(peers) }
+func (peers PeerSortByBan) Swap(i, j int) { peers[i], peers[j] = peers[j], peers[i] }
+func (peers PeerSortByBan) Less(i, j int) bool {
+	return peers[i].BannedUntil.Before(*peers[j].BannedUntil)
+}
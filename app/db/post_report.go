@@ -0,0 +1,81 @@
+package db
+
+import (
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/jgo/jerr"
+	"time"
+)
+
+const postReportsPageSize = 25
+
+// Report of a post by a user, shown in the admin queue until resolved.
+type PostReport struct {
+	Id        uint   `gorm:"primary_key"`
+	TxHash    []byte `gorm:"size:32;unique_index:tx_hash_user"`
+	UserId    uint   `gorm:"unique_index:tx_hash_user"`
+	Reason    string `gorm:"size:255"`
+	Resolved  bool   `gorm:"index:resolved"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (p PostReport) GetTransactionHashString() string {
+	hash, err := chainhash.NewHash(p.TxHash)
+	if err != nil {
+		jerr.Get("error getting chainhash from post report", err).Print()
+		return ""
+	}
+	return hash.String()
+}
+
+// AddPostReport updates the reason and reopens a user's existing report for the post.
+func AddPostReport(txHash []byte, userId uint, reason string) (*PostReport, error) {
+	var postReport = PostReport{
+		TxHash: txHash,
+		UserId: userId,
+	}
+	err := find(&postReport, postReport)
+	if err != nil && ! IsRecordNotFoundError(err) {
+		return nil, jerr.Get("error finding existing post report", err)
+	}
+	postReport.Reason = reason
+	postReport.Resolved = false
+	result := save(&postReport)
+	if result.Error != nil {
+		return nil, jerr.Get("error saving post report", result.Error)
+	}
+	return &postReport, nil
+}
+
+func GetOpenPostReports(offset uint) ([]*PostReport, error) {
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	var postReports []*PostReport
+	result := db.
+		Where("resolved = ?", false).
+		Order("id ASC").
+		Limit(postReportsPageSize).
+		Offset(offset).
+		Find(&postReports)
+	if result.Error != nil {
+		return nil, jerr.Get("error getting open post reports", result.Error)
+	}
+	return postReports, nil
+}
+
+func ResolvePostReports(txHash []byte) error {
+	db, err := getDb()
+	if err != nil {
+		return jerr.Get("error getting db", err)
+	}
+	result := db.
+		Model(&PostReport{}).
+		Where("tx_hash = ?", txHash).
+		Update("resolved", true)
+	if result.Error != nil {
+		return jerr.Get("error resolving post reports", result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package db
+
+import (
+	"fmt"
+	"github.com/jchavannes/jgo/jerr"
+	"html"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	SearchTypePost    = "post"
+	SearchTypeName    = "name"
+	SearchTypeProfile = "profile"
+	SearchTypeTopic   = "topic"
+)
+
+const (
+	searchWordMinLength = 2
+	searchWordMaxLength = 100
+	searchMaxQueryWords = 10
+)
+
+// Inverted index of words used by search. Item key is the tx hash for posts, pk hash for names and profiles,
+// and the topic name for topics.
+type SearchWord struct {
+	Id        uint   `gorm:"primary_key"`
+	Word      string `gorm:"size:100;unique_index:word_item"`
+	ItemType  string `gorm:"size:20;unique_index:word_item;index:item"`
+	ItemKey   []byte `gorm:"size:220;unique_index:word_item;index:item"`
+	Weight    uint
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type SearchResult struct {
+	ItemKey []byte
+	Matches uint
+	Score   uint
+}
+
+// GetSearchWords splits text into lower case words with the number of times each appears.
+func GetSearchWords(text string) map[string]uint {
+	var words = make(map[string]uint)
+	fields := strings.FieldsFunc(strings.ToLower(html.UnescapeString(text)), func(r rune) bool {
+		return ! unicode.IsLetter(r) && ! unicode.IsNumber(r)
+	})
+	for _, field := range fields {
+		if len(field) < searchWordMinLength || len(field) > searchWordMaxLength {
+			continue
+		}
+		words[field]++
+	}
+	return words
+}
+
+// Longer words are kept when a search has too many, they match fewer items. Sorting also keeps the query the same
+// for the same search string.
+func getSearchQueryWords(searchString string) []string {
+	var queryWords []string
+	for word := range GetSearchWords(searchString) {
+		queryWords = append(queryWords, word)
+	}
+	sort.Slice(queryWords, func(i, j int) bool {
+		if len(queryWords[i]) != len(queryWords[j]) {
+			return len(queryWords[i]) > len(queryWords[j])
+		}
+		return queryWords[i] < queryWords[j]
+	})
+	if len(queryWords) > searchMaxQueryWords {
+		queryWords = queryWords[:searchMaxQueryWords]
+	}
+	return queryWords
+}
+
+// SetSearchIndex replaces any indexed words for the item with the words in text.
+func SetSearchIndex(itemType string, itemKey []byte, text string) error {
+	db, err := getDb()
+	if err != nil {
+		return jerr.Get("error getting db", err)
+	}
+	tx := db.Begin()
+	result := tx.Where("item_type = ? AND item_key = ?", itemType, itemKey).Delete(SearchWord{})
+	if result.Error != nil {
+		tx.Rollback()
+		return jerr.Get("error removing existing search words", result.Error)
+	}
+	for word, weight := range GetSearchWords(text) {
+		result = tx.Create(&SearchWord{
+			Word:     word,
+			ItemType: itemType,
+			ItemKey:  itemKey,
+			Weight:   weight,
+		})
+		if result.Error != nil {
+			tx.Rollback()
+			return jerr.Get("error saving search word", result.Error)
+		}
+	}
+	result = tx.Commit()
+	if result.Error != nil {
+		return jerr.Get("error committing search words", result.Error)
+	}
+	return nil
+}
+
+func HasSearchIndex(itemType string, itemKey []byte) (bool, error) {
+	var searchWord SearchWord
+	err := find(&searchWord, SearchWord{
+		ItemType: itemType,
+		ItemKey:  itemKey,
+	})
+	if err != nil {
+		if IsRecordNotFoundError(err) {
+			return false, nil
+		}
+		return false, jerr.Get("error finding search word", err)
+	}
+	return true, nil
+}
+
+// Search returns keys for items of the given types ordered by the number of query words matched, then weight.
+// Items of different types with the same key (e.g. a name and profile for the same pk hash) are combined.
+func Search(searchString string, itemTypes []string, offset uint, limit uint) ([]*SearchResult, error) {
+	queryWords := getSearchQueryWords(searchString)
+	if len(queryWords) == 0 {
+		return nil, nil
+	}
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	rows, err := db.
+		Table("search_words").
+		Select("item_key, COUNT(DISTINCT word) AS matches, SUM(weight) AS score").
+		Where("word IN (?) AND item_type IN (?)", queryWords, itemTypes).
+		Group("item_key").
+		Order("matches DESC, score DESC, MAX(id) DESC").
+		Limit(limit).
+		Offset(offset).
+		Rows()
+	if err != nil {
+		return nil, jerr.Get("error running search query", err)
+	}
+	defer rows.Close()
+	var searchResults []*SearchResult
+	for rows.Next() {
+		var searchResult SearchResult
+		err = rows.Scan(&searchResult.ItemKey, &searchResult.Matches, &searchResult.Score)
+		if err != nil {
+			return nil, jerr.Get("error scanning search result", err)
+		}
+		searchResults = append(searchResults, &searchResult)
+	}
+	return searchResults, nil
+}
+
+// Query limiting posts to those containing every word in the search string. Messages containing the search string
+// also match, for substrings of words, text without spaces such as CJK, and posts not indexed yet.
+func getPostSearchQuery(searchString string) (string, []interface{}) {
+	likeArg := fmt.Sprintf("%%%s%%", searchString)
+	queryWords := getSearchQueryWords(searchString)
+	if len(queryWords) == 0 {
+		return "memo_posts.message LIKE ?", []interface{}{likeArg}
+	}
+	query := "(memo_posts.tx_hash IN (" +
+		"SELECT item_key FROM search_words " +
+		"WHERE item_type = ? AND word IN (?) " +
+		"GROUP BY item_key " +
+		"HAVING COUNT(DISTINCT word) = ?) " +
+		"OR memo_posts.message LIKE ?)"
+	return query, []interface{}{SearchTypePost, queryWords, len(queryWords), likeArg}
+}
@@ -0,0 +1,123 @@
+package db_test
+
+import (
+	"bytes"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/testutil"
+	"testing"
+)
+
+// Runs the gorm store against a temp file SQLite database, the migrations run on the first query.
+func TestSqliteStore(t *testing.T) {
+	defer testutil.UseSqlite(t)()
+	store := db.GetStore()
+
+	key, err := store.GenerateKey("test", "password", 1)
+	if err != nil {
+		t.Fatal(jerr.Get("error generating key", err))
+	}
+	userKey, err := store.GetKeyForUser(1)
+	if err != nil {
+		t.Fatal(jerr.Get("error getting key for user", err))
+	}
+	if ! bytes.Equal(userKey.PkHash, key.PkHash) {
+		t.Fatal(jerr.New("key for user does not match generated key"))
+	}
+
+	followPkHash := bytes.Repeat([]byte{0x02}, 20)
+	memoPost := &db.MemoPost{
+		TxHash:  bytes.Repeat([]byte{0x01}, 32),
+		PkHash:  key.PkHash,
+		Topic:   "test",
+		Message: "hello sqlite",
+	}
+	err = memoPost.Save()
+	if err != nil {
+		t.Fatal(jerr.Get("error saving memo post", err))
+	}
+	err = db.MemoFollow{
+		TxHash:       bytes.Repeat([]byte{0x03}, 32),
+		PkHash:       key.PkHash,
+		FollowPkHash: followPkHash,
+	}.Save()
+	if err != nil {
+		t.Fatal(jerr.Get("error saving memo follow", err))
+	}
+
+	savedPost, err := store.GetMemoPost(memoPost.TxHash)
+	if err != nil {
+		t.Fatal(jerr.Get("error getting memo post", err))
+	}
+	if savedPost.Message != memoPost.Message {
+		t.Fatal(jerr.Newf("unexpected memo post message: %s", savedPost.Message))
+	}
+	recentPosts, err := store.GetRecentPosts(0, "")
+	if err != nil {
+		t.Fatal(jerr.Get("error getting recent posts", err))
+	}
+	if len(recentPosts) != 1 {
+		t.Fatal(jerr.Newf("expected 1 recent post, got %d", len(recentPosts)))
+	}
+	topicPosts, err := store.GetPostsForTopic("test", 0)
+	if err != nil {
+		t.Fatal(jerr.Get("error getting posts for topic", err))
+	}
+	if len(topicPosts) != 1 {
+		t.Fatal(jerr.Newf("expected 1 topic post, got %d", len(topicPosts)))
+	}
+	// Liked posts are picked as rank candidates ahead of newer posts without likes, which are picked as newest.
+	newerPost := &db.MemoPost{
+		TxHash:  bytes.Repeat([]byte{0x04}, 32),
+		PkHash:  key.PkHash,
+		Message: "newer",
+	}
+	err = newerPost.Save()
+	if err != nil {
+		t.Fatal(jerr.Get("error saving newer memo post", err))
+	}
+	err = db.MemoLike{
+		TxHash:     bytes.Repeat([]byte{0x05}, 32),
+		PkHash:     followPkHash,
+		LikeTxHash: memoPost.TxHash,
+	}.Save()
+	if err != nil {
+		t.Fatal(jerr.Get("error saving memo like", err))
+	}
+	candidatePosts, err := store.GetRankCandidatePosts("", "", 7, 1)
+	if err != nil {
+		t.Fatal(jerr.Get("error getting rank candidate posts", err))
+	}
+	if len(candidatePosts) != 1 || ! bytes.Equal(candidatePosts[0].TxHash, memoPost.TxHash) {
+		t.Fatal(jerr.New("expected liked post to be the rank candidate"))
+	}
+	newestPosts, err := store.GetNewestRankCandidatePosts("", "", 7, 1)
+	if err != nil {
+		t.Fatal(jerr.Get("error getting newest rank candidate posts", err))
+	}
+	if len(newestPosts) != 1 || ! bytes.Equal(newestPosts[0].TxHash, newerPost.TxHash) {
+		t.Fatal(jerr.New("expected newer post to be the newest rank candidate"))
+	}
+	isFollowing, err := store.IsFollowing(key.PkHash, followPkHash)
+	if err != nil {
+		t.Fatal(jerr.Get("error checking following", err))
+	}
+	if ! isFollowing {
+		t.Fatal(jerr.New("expected follow to be found"))
+	}
+	followers, err := store.GetFollowersForPkHash(key.PkHash, -1)
+	if err != nil {
+		t.Fatal(jerr.Get("error getting followers", err))
+	}
+	if len(followers) != 1 || ! bytes.Equal(followers[0].FollowPkHash, followPkHash) {
+		t.Fatal(jerr.New("unexpected follows for pk hash"))
+	}
+	// Falls back to the genesis block when no blocks are saved.
+	recentBlock, err := store.GetRecentBlock()
+	if err != nil {
+		t.Fatal(jerr.Get("error getting recent block", err))
+	}
+	if recentBlock.Height != 0 {
+		t.Fatal(jerr.Newf("expected genesis block, got height %d", recentBlock.Height))
+	}
+}
@@ -0,0 +1,263 @@
+package db
+
+import (
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/memocash/memo/app/db/view"
+	"time"
+)
+
+// Store covers the queries used outside of this package. The default implementation runs them through
+// gorm against whichever database is configured, tests and other tools can swap it out with SetStore.
+type Store interface {
+	PostStore
+	FollowStore
+	LikeStore
+	KeyStore
+	SessionStore
+	BlockStore
+}
+
+type PostStore interface {
+	GetMemoPost(txHash []byte) (*MemoPost, error)
+	GetPostsByTxHashes(txHashes [][]byte) ([]*MemoPost, error)
+	GetPostReplyCount(txHash []byte) (uint, error)
+	GetPostReplyCounts(txHashes [][]byte) ([]TxHashCount, error)
+	GetPostReplies(txHash []byte, offset uint) ([]*MemoPost, error)
+	GetRecentPosts(offset uint, searchString string) ([]*MemoPost, error)
+	GetRankCandidatePosts(topic string, searchString string, days int, limit uint) ([]*MemoPost, error)
+	GetNewestRankCandidatePosts(topic string, searchString string, days int, limit uint) ([]*MemoPost, error)
+	GetTopPosts(offset uint, timeStart time.Time, timeEnd time.Time) ([]*MemoPost, error)
+	GetPersonalizedTopPosts(selfPkHash []byte, offset uint, timeStart time.Time, timeEnd time.Time) ([]*MemoPost, error)
+	GetPollsPosts(offset uint) ([]*MemoPost, error)
+	GetPostsForPkHash(pkHash []byte, offset uint) ([]*MemoPost, error)
+	GetPostsFeedForPkHash(pkHash []byte, offset uint) ([]*MemoPost, error)
+	GetPostsForTopic(topic string, offset uint) ([]*MemoPost, error)
+	GetOlderPostsForTopic(topic string, firstPostId uint) ([]*MemoPost, error)
+	GetThreads(offset uint, topic string) ([]*view.Thread, error)
+	GetCountMemoPosts() (uint, uint, uint, uint, error)
+}
+
+type FollowStore interface {
+	GetMemoFollow(txHash []byte) (*MemoFollow, error)
+	IsFollowing(followerPkHash []byte, followingPkHash []byte) (bool, error)
+	GetFollowersForPkHash(pkHash []byte, offset int) ([]*MemoFollow, error)
+	GetFollowingForPkHash(followPkHash []byte, offset int) ([]*MemoFollow, error)
+	GetFollowerCountForPkHash(followPkHash []byte) (uint, error)
+	GetFollowingCountForPkHash(pkHash []byte) (uint, error)
+	GetCountMemoFollows() (uint, error)
+}
+
+type LikeStore interface {
+	GetMemoLike(txHash []byte) (*MemoLike, error)
+	GetMemoLikesForTxnHash(txHash []byte) ([]*MemoLike, error)
+	GetMemoLikesForTxnHashes(txHashes [][]byte) ([]*MemoLike, error)
+	GetMemoLikesForPkHash(pkHash []byte) ([]*MemoLike, error)
+	GetCountMemoLikes() (uint, error)
+}
+
+type KeyStore interface {
+	GetKey(id uint, userId uint) (*Key, error)
+	GetKeyForUser(userId uint) (*Key, error)
+	GenerateKey(name string, password string, userId uint) (*Key, error)
+	ImportKey(name string, password string, wif string, userId uint) (*Key, error)
+	RestoreKey(name string, password string, mnemonic string, userId uint) (*Key, error)
+	GetAllKeys() ([]*Key, error)
+}
+
+type SessionStore interface {
+	GetSession(cookieId string) (*Session, error)
+	GetCsrfTokenString(cookieId string) (string, error)
+	UpdateCsrfTokenSession(oldCookieId string, newCookieId string) error
+}
+
+type BlockStore interface {
+	GetRecentBlock() (*Block, error)
+	GetBlockByHash(hash chainhash.Hash) (*Block, error)
+	GetBlocksInHeightRange(startHeight uint, endHeight uint) ([]*Block, error)
+	GetBlockByHeight(height uint) (*Block, error)
+	RemoveBlocksAboveHeight(height uint) ([]*Block, error)
+}
+
+var store Store = gormStore{}
+
+func GetStore() Store {
+	return store
+}
+
+func SetStore(s Store) {
+	store = s
+}
+
+type gormStore struct{}
+
+func (gormStore) GetMemoPost(txHash []byte) (*MemoPost, error) {
+	return GetMemoPost(txHash)
+}
+
+func (gormStore) GetPostsByTxHashes(txHashes [][]byte) ([]*MemoPost, error) {
+	return GetPostsByTxHashes(txHashes)
+}
+
+func (gormStore) GetPostReplyCount(txHash []byte) (uint, error) {
+	return GetPostReplyCount(txHash)
+}
+
+func (gormStore) GetPostReplyCounts(txHashes [][]byte) ([]TxHashCount, error) {
+	return GetPostReplyCounts(txHashes)
+}
+
+func (gormStore) GetPostReplies(txHash []byte, offset uint) ([]*MemoPost, error) {
+	return GetPostReplies(txHash, offset)
+}
+
+func (gormStore) GetRecentPosts(offset uint, searchString string) ([]*MemoPost, error) {
+	return GetRecentPosts(offset, searchString)
+}
+
+func (gormStore) GetRankCandidatePosts(topic string, searchString string, days int, limit uint) ([]*MemoPost, error) {
+	return GetRankCandidatePosts(topic, searchString, days, limit)
+}
+
+func (gormStore) GetNewestRankCandidatePosts(topic string, searchString string, days int, limit uint) ([]*MemoPost, error) {
+	return GetNewestRankCandidatePosts(topic, searchString, days, limit)
+}
+
+func (gormStore) GetTopPosts(offset uint, timeStart time.Time, timeEnd time.Time) ([]*MemoPost, error) {
+	return GetTopPosts(offset, timeStart, timeEnd)
+}
+
+func (gormStore) GetPersonalizedTopPosts(selfPkHash []byte, offset uint, timeStart time.Time, timeEnd time.Time) ([]*MemoPost, error) {
+	return GetPersonalizedTopPosts(selfPkHash, offset, timeStart, timeEnd)
+}
+
+func (gormStore) GetPollsPosts(offset uint) ([]*MemoPost, error) {
+	return GetPollsPosts(offset)
+}
+
+func (gormStore) GetPostsForPkHash(pkHash []byte, offset uint) ([]*MemoPost, error) {
+	return GetPostsForPkHash(pkHash, offset)
+}
+
+func (gormStore) GetPostsFeedForPkHash(pkHash []byte, offset uint) ([]*MemoPost, error) {
+	return GetPostsFeedForPkHash(pkHash, offset)
+}
+
+func (gormStore) GetPostsForTopic(topic string, offset uint) ([]*MemoPost, error) {
+	return GetPostsForTopic(topic, offset)
+}
+
+func (gormStore) GetOlderPostsForTopic(topic string, firstPostId uint) ([]*MemoPost, error) {
+	return GetOlderPostsForTopic(topic, firstPostId)
+}
+
+func (gormStore) GetThreads(offset uint, topic string) ([]*view.Thread, error) {
+	return GetThreads(offset, topic)
+}
+
+func (gormStore) GetCountMemoPosts() (uint, uint, uint, uint, error) {
+	return GetCountMemoPosts()
+}
+
+func (gormStore) GetMemoFollow(txHash []byte) (*MemoFollow, error) {
+	return GetMemoFollow(txHash)
+}
+
+func (gormStore) IsFollowing(followerPkHash []byte, followingPkHash []byte) (bool, error) {
+	return IsFollowing(followerPkHash, followingPkHash)
+}
+
+func (gormStore) GetFollowersForPkHash(pkHash []byte, offset int) ([]*MemoFollow, error) {
+	return GetFollowersForPkHash(pkHash, offset)
+}
+
+func (gormStore) GetFollowingForPkHash(followPkHash []byte, offset int) ([]*MemoFollow, error) {
+	return GetFollowingForPkHash(followPkHash, offset)
+}
+
+func (gormStore) GetFollowerCountForPkHash(followPkHash []byte) (uint, error) {
+	return GetFollowerCountForPkHash(followPkHash)
+}
+
+func (gormStore) GetFollowingCountForPkHash(pkHash []byte) (uint, error) {
+	return GetFollowingCountForPkHash(pkHash)
+}
+
+func (gormStore) GetCountMemoFollows() (uint, error) {
+	return GetCountMemoFollows()
+}
+
+func (gormStore) GetMemoLike(txHash []byte) (*MemoLike, error) {
+	return GetMemoLike(txHash)
+}
+
+func (gormStore) GetMemoLikesForTxnHash(txHash []byte) ([]*MemoLike, error) {
+	return GetMemoLikesForTxnHash(txHash)
+}
+
+func (gormStore) GetMemoLikesForTxnHashes(txHashes [][]byte) ([]*MemoLike, error) {
+	return GetMemoLikesForTxnHashes(txHashes)
+}
+
+func (gormStore) GetMemoLikesForPkHash(pkHash []byte) ([]*MemoLike, error) {
+	return GetMemoLikesForPkHash(pkHash)
+}
+
+func (gormStore) GetCountMemoLikes() (uint, error) {
+	return GetCountMemoLikes()
+}
+
+func (gormStore) GetKey(id uint, userId uint) (*Key, error) {
+	return GetKey(id, userId)
+}
+
+func (gormStore) GetKeyForUser(userId uint) (*Key, error) {
+	return GetKeyForUser(userId)
+}
+
+func (gormStore) GenerateKey(name string, password string, userId uint) (*Key, error) {
+	return GenerateKey(name, password, userId)
+}
+
+func (gormStore) ImportKey(name string, password string, wif string, userId uint) (*Key, error) {
+	return ImportKey(name, password, wif, userId)
+}
+
+func (gormStore) RestoreKey(name string, password string, mnemonic string, userId uint) (*Key, error) {
+	return RestoreKey(name, password, mnemonic, userId)
+}
+
+func (gormStore) GetAllKeys() ([]*Key, error) {
+	return GetAllKeys()
+}
+
+func (gormStore) GetSession(cookieId string) (*Session, error) {
+	return GetSession(cookieId)
+}
+
+func (gormStore) GetCsrfTokenString(cookieId string) (string, error) {
+	return GetCsrfTokenString(cookieId)
+}
+
+func (gormStore) UpdateCsrfTokenSession(oldCookieId string, newCookieId string) error {
+	return UpdateCsrfTokenSession(oldCookieId, newCookieId)
+}
+
+func (gormStore) GetRecentBlock() (*Block, error) {
+	return GetRecentBlock()
+}
+
+func (gormStore) GetBlockByHash(hash chainhash.Hash) (*Block, error) {
+	return GetBlockByHash(hash)
+}
+
+func (gormStore) GetBlocksInHeightRange(startHeight uint, endHeight uint) ([]*Block, error) {
+	return GetBlocksInHeightRange(startHeight, endHeight)
+}
+
+func (gormStore) GetBlockByHeight(height uint) (*Block, error) {
+	return GetBlockByHeight(height)
+}
+
+func (gormStore) RemoveBlocksAboveHeight(height uint) ([]*Block, error) {
+	return RemoveBlocksAboveHeight(height)
+}
@@ -46,6 +46,14 @@ func GetTopicInfo(offset uint, searchString string, pkHash []byte, orderType vie
 	if searchString != "" {
 		query = query.Where("topic_name LIKE ?", fmt.Sprintf("%%%s%%", searchString))
 	}
+	// Topics hidden by moderation are excluded here rather than after the limit so pages stay full.
+	query = query.Where("topic_name NOT IN (" +
+		"SELECT topic " +
+		"FROM moderations " +
+		"WHERE topic != '' " +
+		"AND (tx_hash IS NULL OR LENGTH(tx_hash) = 0) " +
+		"AND (pk_hash IS NULL OR LENGTH(pk_hash) = 0)" +
+		")")
 	if len(pkHash) > 0 {
 		joinQuery := "JOIN (" +
 			"SELECT MAX(id) AS id " +
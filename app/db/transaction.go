@@ -216,7 +216,7 @@ func ConvertMsgToTransaction(msg *wire.MsgTx) (*Transaction, error) {
 		if err != nil {
 			return nil, jerr.Get("error disassembling lockScript: %s\n", err)
 		}
-		scriptClass, _, sigCount, err := txscript.ExtractPkScriptAddrs(out.PkScript, &wallet.MainNetParamsOld)
+		scriptClass, _, sigCount, err := txscript.ExtractPkScriptAddrs(out.PkScript, &wallet.NetParamsOld)
 		var transactionOut = TransactionOut{
 			Index:           uint32(index),
 			Value:           out.Value,
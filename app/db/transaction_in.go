@@ -141,3 +141,17 @@ func GetTransactionInput(txHash []byte, index uint32) (*TransactionIn, error) {
 	}
 	return &transactionIn, nil
 }
+
+// GetPublicKeyForPkHash finds the public key for an address from an input it has signed.
+func GetPublicKeyForPkHash(pkHash []byte) ([]byte, error) {
+	var transactionIn TransactionIn
+	err := find(&transactionIn, TransactionIn{KeyPkHash: pkHash})
+	if err != nil {
+		return nil, jerr.Get("error finding transaction input for pk hash", err)
+	}
+	publicKey := transactionIn.GetPublicKey()
+	if len(publicKey) == 0 {
+		return nil, jerr.New("error parsing public key from transaction input")
+	}
+	return publicKey, nil
+}
@@ -62,7 +62,7 @@ func (t TransactionOut) GetPkHash() []byte {
 }
 
 func (t TransactionOut) GetAddressString() string {
-	addressPkHash, err := btcutil.NewAddressPubKeyHash(t.KeyPkHash, &wallet.MainNetParamsOld)
+	addressPkHash, err := btcutil.NewAddressPubKeyHash(t.KeyPkHash, &wallet.NetParamsOld)
 	if err != nil {
 		jerr.Get("error parsing address", err).Print()
 		return ""
@@ -142,6 +142,24 @@ func GetSpendableTransactionOutputsForPkHash(pkHash []byte) ([]*TransactionOut,
 	return transactionOuts, nil
 }
 
+// Used for HD keys where change is spread across several addresses.
+func GetSpendableTransactionOutputsForPkHashes(pkHashes [][]byte) ([]*TransactionOut, error) {
+	var transactionOuts []*TransactionOut
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	result := db.
+		Where("txn_in_hash_string = ''").
+		Where("value > 0").
+		Where("key_pk_hash IN (?)", pkHashes).
+		Find(&transactionOuts)
+	if result.Error != nil {
+		return nil, jerr.Get("error getting transaction outputs", result.Error)
+	}
+	return transactionOuts, nil
+}
+
 type TxOutSortByValue []*TransactionOut
 
 func (txOuts TxOutSortByValue) Len() int      { return len(txOuts) }
@@ -150,6 +168,7 @@ func (txOuts TxOutSortByValue) Less(i, j int) bool {
 	return txOuts[i].Value > txOuts[j].Value
 }
 
+// Only the profile address is checked, memo actions don't spend from HD change addresses.
 func HasSpendable(pkHash []byte) (bool, error) {
 	transactionOutputs, err := GetSpendableTransactionOutputsForPkHash(pkHash)
 	if err != nil {
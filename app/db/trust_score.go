@@ -0,0 +1,74 @@
+package db
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"time"
+)
+
+// Multi-hop trust of an address as seen from a seed address (a local user). Scores are relative to the most
+// trusted address for the seed, from 0 to 1.
+type TrustScore struct {
+	Id         uint   `gorm:"primary_key"`
+	SeedPkHash []byte `gorm:"size:20;unique_index:seed_pk_hash_trust"`
+	PkHash     []byte `gorm:"size:20;unique_index:seed_pk_hash_trust"`
+	Score      float64
+	CreatedAt  time.Time
+}
+
+func GetTrustScore(seedPkHash []byte, pkHash []byte) (*TrustScore, error) {
+	var trustScore TrustScore
+	err := find(&trustScore, TrustScore{
+		SeedPkHash: seedPkHash,
+		PkHash:     pkHash,
+	})
+	if err != nil {
+		return nil, jerr.Get("error getting trust score", err)
+	}
+	return &trustScore, nil
+}
+
+func GetTrustScores(seedPkHash []byte, pkHashes [][]byte) ([]*TrustScore, error) {
+	if len(pkHashes) == 0 {
+		return nil, nil
+	}
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	var trustScores []*TrustScore
+	result := db.
+		Where("seed_pk_hash = ?", seedPkHash).
+		Where("pk_hash IN (?)", pkHashes).
+		Find(&trustScores)
+	if result.Error != nil {
+		return nil, jerr.Get("error getting trust scores", result.Error)
+	}
+	return trustScores, nil
+}
+
+// SetTrustScores replaces all scores for a seed.
+func SetTrustScores(seedPkHash []byte, trustScores []*TrustScore) error {
+	db, err := getDb()
+	if err != nil {
+		return jerr.Get("error getting db", err)
+	}
+	tx := db.Begin()
+	result := tx.Where("seed_pk_hash = ?", seedPkHash).Delete(TrustScore{})
+	if result.Error != nil {
+		tx.Rollback()
+		return jerr.Get("error removing old trust scores", result.Error)
+	}
+	for _, trustScore := range trustScores {
+		trustScore.SeedPkHash = seedPkHash
+		result = tx.Create(trustScore)
+		if result.Error != nil {
+			tx.Rollback()
+			return jerr.Get("error saving trust score", result.Error)
+		}
+	}
+	result = tx.Commit()
+	if result.Error != nil {
+		return jerr.Get("error committing trust scores", result.Error)
+	}
+	return nil
+}
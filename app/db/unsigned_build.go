@@ -0,0 +1,79 @@
+package db
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"time"
+)
+
+const unsignedBuildNotFoundErrorText = "unsigned build not found or expired"
+
+var unsignedBuildNotFoundError = jerr.New(unsignedBuildNotFoundErrorText)
+
+func IsUnsignedBuildNotFoundError(err error) bool {
+	return jerr.HasError(err, unsignedBuildNotFoundErrorText)
+}
+
+// An unsigned tx built for a client to sign. Signed txs are only accepted for a build id the server issued, and only
+// if they spend the same inputs and create the same outputs.
+type UnsignedBuild struct {
+	Id        uint      `gorm:"primary_key"`
+	BuildId   string    `gorm:"unique;size:140"`
+	TxHash    []byte    `gorm:"size:32"`
+	ExpiresAt time.Time `gorm:"index:expires_at"`
+	CreatedAt time.Time
+}
+
+// SaveUnsignedBuild stores the hash of an unsigned tx and returns a new build id for it.
+func SaveUnsignedBuild(txHash []byte, expiresAt time.Time) (string, error) {
+	db, err := getDb()
+	if err != nil {
+		return "", jerr.Get("error getting db", err)
+	}
+	result := db.Where("expires_at < ?", time.Now()).Delete(UnsignedBuild{})
+	if result.Error != nil {
+		return "", jerr.Get("error removing expired unsigned builds", result.Error)
+	}
+	var unsignedBuild = &UnsignedBuild{
+		BuildId:   web.CreateToken(),
+		TxHash:    txHash,
+		ExpiresAt: expiresAt,
+	}
+	err = create(unsignedBuild)
+	if err != nil {
+		return "", jerr.Get("error saving unsigned build", err)
+	}
+	return unsignedBuild.BuildId, nil
+}
+
+func GetUnsignedBuild(buildId string) (*UnsignedBuild, error) {
+	if buildId == "" {
+		return nil, jerr.Get("error build id not set", unsignedBuildNotFoundError)
+	}
+	var unsignedBuild = &UnsignedBuild{
+		BuildId: buildId,
+	}
+	err := find(unsignedBuild, unsignedBuild)
+	if err != nil {
+		if IsRecordNotFoundError(err) {
+			return nil, jerr.Get("error getting unsigned build", unsignedBuildNotFoundError)
+		}
+		return nil, jerr.Get("error getting unsigned build", err)
+	}
+	if unsignedBuild.ExpiresAt.Before(time.Now()) {
+		return nil, jerr.Get("error unsigned build expired", unsignedBuildNotFoundError)
+	}
+	return unsignedBuild, nil
+}
+
+func RemoveUnsignedBuild(buildId string) error {
+	db, err := getDb()
+	if err != nil {
+		return jerr.Get("error getting db", err)
+	}
+	result := db.Where("build_id = ?", buildId).Delete(UnsignedBuild{})
+	if result.Error != nil {
+		return jerr.Get("error removing unsigned build", result.Error)
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ type User struct {
 	Id           uint   `gorm:"primary_key"`
 	Username     string `gorm:"unique;size:50"`
 	PasswordHash string
+	Admin        bool
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 }
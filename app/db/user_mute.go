@@ -0,0 +1,153 @@
+package db
+
+import (
+	"bytes"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"net/url"
+	"time"
+)
+
+const MaxMutesPerUser = 500
+
+// Address or topic hidden from a user's feeds. Blocked addresses are also hidden from direct messages.
+type UserMute struct {
+	Id         uint   `gorm:"primary_key"`
+	UserId     uint   `gorm:"index:user_id"`
+	PkHash     []byte `gorm:"size:20;unique_index:pk_hash_mute"`
+	MutePkHash []byte `gorm:"size:20;unique_index:pk_hash_mute"`
+	Topic      string `gorm:"size:500;unique_index:pk_hash_mute"`
+	Block      bool
+	CreatedAt  time.Time
+}
+
+func (u UserMute) IsTopic() bool {
+	return len(u.MutePkHash) == 0
+}
+
+func (u UserMute) GetUrlEncodedTopic() string {
+	return url.QueryEscape(u.Topic)
+}
+
+func (u UserMute) GetAddressString() string {
+	return wallet.GetAddressFromPkHash(u.MutePkHash).GetEncoded()
+}
+
+type MuteList struct {
+	Mutes []*UserMute
+}
+
+func (m MuteList) IsPkHashMuted(pkHash []byte) bool {
+	for _, mute := range m.Mutes {
+		if ! mute.IsTopic() && bytes.Equal(mute.MutePkHash, pkHash) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m MuteList) IsPkHashBlocked(pkHash []byte) bool {
+	for _, mute := range m.Mutes {
+		if mute.Block && bytes.Equal(mute.MutePkHash, pkHash) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m MuteList) IsTopicMuted(topic string) bool {
+	if topic == "" {
+		return false
+	}
+	for _, mute := range m.Mutes {
+		if mute.IsTopic() && mute.Topic == topic {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPostMuted checks both the author and topic of the post.
+func (m MuteList) IsPostMuted(memoPost *MemoPost) bool {
+	return m.IsPkHashMuted(memoPost.PkHash) || m.IsTopicMuted(memoPost.Topic)
+}
+
+// FilterPosts removes posts from muted addresses or topics.
+func (m MuteList) FilterPosts(memoPosts []*MemoPost) []*MemoPost {
+	if len(m.Mutes) == 0 {
+		return memoPosts
+	}
+	var filteredPosts []*MemoPost
+	for _, memoPost := range memoPosts {
+		if ! m.IsPostMuted(memoPost) {
+			filteredPosts = append(filteredPosts, memoPost)
+		}
+	}
+	return filteredPosts
+}
+
+// FilterPostsByAuthor ignores muted topics, used when a topic is being viewed directly.
+func (m MuteList) FilterPostsByAuthor(memoPosts []*MemoPost) []*MemoPost {
+	if len(m.Mutes) == 0 {
+		return memoPosts
+	}
+	var filteredPosts []*MemoPost
+	for _, memoPost := range memoPosts {
+		if ! m.IsPkHashMuted(memoPost.PkHash) {
+			filteredPosts = append(filteredPosts, memoPost)
+		}
+	}
+	return filteredPosts
+}
+
+func AddUserMute(userId uint, pkHash []byte, mutePkHash []byte, topic string, block bool) (*UserMute, error) {
+	var userMute = UserMute{
+		PkHash:     pkHash,
+		MutePkHash: mutePkHash,
+		Topic:      topic,
+	}
+	err := find(&userMute, userMute)
+	if err != nil && ! IsRecordNotFoundError(err) {
+		return nil, jerr.Get("error finding existing user mute", err)
+	}
+	userMute.UserId = userId
+	userMute.Block = block
+	result := save(&userMute)
+	if result.Error != nil {
+		return nil, jerr.Get("error saving user mute", result.Error)
+	}
+	return &userMute, nil
+}
+
+func GetMuteListForPkHash(pkHash []byte) (*MuteList, error) {
+	var userMutes []*UserMute
+	err := find(&userMutes, UserMute{
+		PkHash: pkHash,
+	})
+	if err != nil {
+		return nil, jerr.Get("error getting user mutes", err)
+	}
+	return &MuteList{
+		Mutes: userMutes,
+	}, nil
+}
+
+func DeleteUserMute(id uint, userId uint) (*UserMute, error) {
+	var userMute UserMute
+	err := find(&userMute, UserMute{
+		Id:     id,
+		UserId: userId,
+	})
+	if err != nil {
+		return nil, jerr.Get("error finding user mute", err)
+	}
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	result := db.Delete(&userMute)
+	if result.Error != nil {
+		return nil, jerr.Get("error deleting user mute", result.Error)
+	}
+	return &userMute, nil
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"fmt"
 	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/fee"
 	"time"
 )
 
@@ -18,13 +19,15 @@ const (
 )
 
 type UserSettings struct {
-	Id           uint   `gorm:"primary_key"`
-	UserId       uint   `gorm:"unique"`
-	DefaultTip   uint
-	Integrations string `gorm:"size:25"`
-	Theme        string `gorm:"size:25"`
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	Id            uint   `gorm:"primary_key"`
+	UserId        uint   `gorm:"unique"`
+	DefaultTip    uint
+	Integrations  string `gorm:"size:25"`
+	Theme         string `gorm:"size:25"`
+	FeePriority   string `gorm:"size:25"`
+	HideUntrusted bool
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
 }
 
 func (u *UserSettings) Save() error {
@@ -42,7 +45,7 @@ func (u UserSettings) GetDefaultTipString() string {
 	return fmt.Sprintf("%d", u.DefaultTip)
 }
 
-func SaveSettingsForUser(userId uint, defaultTip uint, integrations string, theme string) (*UserSettings, error) {
+func SaveSettingsForUser(userId uint, defaultTip uint, integrations string, theme string, feePriority string, hideUntrusted bool) (*UserSettings, error) {
 	var userSettings = UserSettings{
 		UserId: userId,
 	}
@@ -53,6 +56,8 @@ func SaveSettingsForUser(userId uint, defaultTip uint, integrations string, them
 	userSettings.DefaultTip = defaultTip
 	userSettings.Integrations = integrations
 	userSettings.Theme = theme
+	userSettings.FeePriority = feePriority
+	userSettings.HideUntrusted = hideUntrusted
 	err = userSettings.Save()
 	if err != nil {
 		return nil, jerr.Get("error saving settings", err)
@@ -85,6 +90,7 @@ func GetDefaultUserSettings() UserSettings {
 	return UserSettings{
 		Integrations: SettingIntegrationsAll,
 		Theme:        SettingThemeDefault,
+		FeePriority:  fee.PriorityNormal,
 	}
 }
 
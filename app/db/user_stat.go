@@ -68,11 +68,21 @@ func GetUserFirstPostStats() ([]view.UserDateStat, error) {
 		Where("num_posts > 1").
 		Group("date").
 		Order("date ASC")
-	var userDataStats []view.UserDateStat
-	result := query.Find(&userDataStats)
+	var userDateRows []struct {
+		Date     sqlTime
+		NumUsers int
+	}
+	result := query.Scan(&userDateRows)
 	if result.Error != nil {
 		return nil, jerr.Get("error getting user first post stats", result.Error)
 	}
+	var userDataStats []view.UserDateStat
+	for _, userDateRow := range userDateRows {
+		userDataStats = append(userDataStats, view.UserDateStat{
+			Date:     userDateRow.Date.Time,
+			NumUsers: userDateRow.NumUsers,
+		})
+	}
 	return userDataStats, nil
 }
 
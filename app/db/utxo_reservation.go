@@ -0,0 +1,107 @@
+package db
+
+import (
+	"github.com/jchavannes/btcd/wire"
+	"github.com/jchavannes/jgo/jerr"
+	"time"
+)
+
+const outputReservedErrorText = "output reserved by another transaction"
+
+var outputReservedError = jerr.New(outputReservedErrorText)
+
+func IsOutputReservedError(err error) bool {
+	return jerr.HasError(err, outputReservedErrorText)
+}
+
+// Outputs spent by a built transaction that has not been seen yet. Reservations are shared between web instances so
+// concurrent builds do not select the same outputs. They are removed when the spending tx is rejected and ignored
+// once they expire.
+type UtxoReservation struct {
+	Id          uint      `gorm:"primary_key"`
+	TxHash      []byte    `gorm:"size:32;unique_index:out_point"`
+	OutIndex    uint32    `gorm:"unique_index:out_point"`
+	SpendTxHash []byte    `gorm:"size:32;index:spend_tx_hash"`
+	ExpiresAt   time.Time `gorm:"index:expires_at"`
+	CreatedAt   time.Time
+}
+
+// ReserveTxInputs reserves every output spent by the tx. If any is already reserved none are and an output reserved
+// error is returned.
+func ReserveTxInputs(msgTx *wire.MsgTx, expiresAt time.Time) error {
+	db, err := getDb()
+	if err != nil {
+		return jerr.Get("error getting db", err)
+	}
+	result := db.Where("expires_at < ?", time.Now()).Delete(UtxoReservation{})
+	if result.Error != nil {
+		return jerr.Get("error removing expired utxo reservations", result.Error)
+	}
+	spendTxHash := msgTx.TxHash()
+	tx := db.Begin()
+	for _, txIn := range msgTx.TxIn {
+		result = tx.Create(&UtxoReservation{
+			TxHash:      txIn.PreviousOutPoint.Hash.CloneBytes(),
+			OutIndex:    txIn.PreviousOutPoint.Index,
+			SpendTxHash: spendTxHash.CloneBytes(),
+			ExpiresAt:   expiresAt,
+		})
+		if result.Error != nil {
+			tx.Rollback()
+			if IsDuplicateEntryError(result.Error) {
+				return jerr.Get("error reserving tx input", outputReservedError)
+			}
+			return jerr.Get("error saving utxo reservation", result.Error)
+		}
+	}
+	result = tx.Commit()
+	if result.Error != nil {
+		return jerr.Get("error committing utxo reservations", result.Error)
+	}
+	return nil
+}
+
+func RemoveUtxoReservations(spendTxHash []byte) error {
+	db, err := getDb()
+	if err != nil {
+		return jerr.Get("error getting db", err)
+	}
+	result := db.Where("spend_tx_hash = ?", spendTxHash).Delete(UtxoReservation{})
+	if result.Error != nil {
+		return jerr.Get("error removing utxo reservations", result.Error)
+	}
+	return nil
+}
+
+// FilterReservedTransactionOutputs removes outputs with an unexpired reservation.
+func FilterReservedTransactionOutputs(txOuts []*TransactionOut) ([]*TransactionOut, error) {
+	if len(txOuts) == 0 {
+		return txOuts, nil
+	}
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	var txHashes [][]byte
+	for _, txOut := range txOuts {
+		txHashes = append(txHashes, txOut.TransactionHash)
+	}
+	var utxoReservations []*UtxoReservation
+	result := db.
+		Where("tx_hash IN (?) AND expires_at > ?", txHashes, time.Now()).
+		Find(&utxoReservations)
+	if result.Error != nil {
+		return nil, jerr.Get("error getting utxo reservations", result.Error)
+	}
+	var reserved = make(map[string]bool)
+	for _, utxoReservation := range utxoReservations {
+		reserved[getHashString(utxoReservation.TxHash, utxoReservation.OutIndex)] = true
+	}
+	var unreservedTxOuts []*TransactionOut
+	for _, txOut := range txOuts {
+		if ! reserved[txOut.GetHashString()] {
+			unreservedTxOuts = append(unreservedTxOuts, txOut)
+		}
+	}
+	return unreservedTxOuts, nil
+}
@@ -0,0 +1,141 @@
+package db
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"strings"
+	"time"
+)
+
+const (
+	WebhookEventPost         = "post"
+	WebhookEventReply        = "reply"
+	WebhookEventLike         = "like"
+	WebhookEventFollow       = "follow"
+	WebhookEventTopicMessage = "topic_message"
+
+	MaxWebhooksPerUser = 5
+)
+
+var WebhookEvents = []string{
+	WebhookEventPost,
+	WebhookEventReply,
+	WebhookEventLike,
+	WebhookEventFollow,
+	WebhookEventTopicMessage,
+}
+
+// Webhook URL registered by a user to receive events for their address. Events are stored comma separated.
+type Webhook struct {
+	Id        uint   `gorm:"primary_key"`
+	UserId    uint   `gorm:"index:user_id"`
+	PkHash    []byte `gorm:"index:pk_hash;size:20"`
+	Url       string `gorm:"size:500"`
+	Secret    string `gorm:"size:64"`
+	Events    string `gorm:"size:100"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (w Webhook) GetAddressString() string {
+	return wallet.GetAddressFromPkHash(w.PkHash).GetEncoded()
+}
+
+func (w Webhook) GetEvents() []string {
+	return strings.Split(w.Events, ",")
+}
+
+func (w Webhook) HasEvent(event string) bool {
+	for _, webhookEvent := range w.GetEvents() {
+		if webhookEvent == event {
+			return true
+		}
+	}
+	return false
+}
+
+func IsValidWebhookEvent(event string) bool {
+	for _, validEvent := range WebhookEvents {
+		if event == validEvent {
+			return true
+		}
+	}
+	return false
+}
+
+func AddWebhook(userId uint, pkHash []byte, url string, secret string, events []string) (*Webhook, error) {
+	var webhook = Webhook{
+		UserId: userId,
+		PkHash: pkHash,
+		Url:    url,
+		Secret: secret,
+		Events: strings.Join(events, ","),
+	}
+	err := create(&webhook)
+	if err != nil {
+		return nil, jerr.Get("error creating webhook", err)
+	}
+	return &webhook, nil
+}
+
+func GetWebhook(id uint) (*Webhook, error) {
+	var webhook Webhook
+	err := find(&webhook, Webhook{
+		Id: id,
+	})
+	if err != nil {
+		return nil, jerr.Get("error getting webhook", err)
+	}
+	return &webhook, nil
+}
+
+func GetWebhooksForUser(userId uint) ([]*Webhook, error) {
+	var webhooks []*Webhook
+	err := find(&webhooks, Webhook{
+		UserId: userId,
+	})
+	if err != nil {
+		return nil, jerr.Get("error getting webhooks for user", err)
+	}
+	return webhooks, nil
+}
+
+// GetWebhooksForPkHashes returns webhooks for any of the addresses that are subscribed to the event.
+func GetWebhooksForPkHashes(pkHashes [][]byte, event string) ([]*Webhook, error) {
+	if len(pkHashes) == 0 {
+		return nil, nil
+	}
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	var webhooks []*Webhook
+	result := db.
+		Where("pk_hash IN (?)", pkHashes).
+		Find(&webhooks)
+	if result.Error != nil {
+		return nil, jerr.Get("error getting webhooks for pk hashes", result.Error)
+	}
+	var subscribedWebhooks []*Webhook
+	for _, webhook := range webhooks {
+		if webhook.HasEvent(event) {
+			subscribedWebhooks = append(subscribedWebhooks, webhook)
+		}
+	}
+	return subscribedWebhooks, nil
+}
+
+func DeleteWebhook(id uint, userId uint) error {
+	db, err := getDb()
+	if err != nil {
+		return jerr.Get("error getting db", err)
+	}
+	result := db.Where("id = ? AND user_id = ?", id, userId).Delete(Webhook{})
+	if result.Error != nil {
+		return jerr.Get("error deleting webhook", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return jerr.New("webhook not found")
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/jgo/jerr"
+	"time"
+)
+
+const webhookDeliveriesPageSize = 25
+
+// Log of each event sent to a webhook. Deliveries that fail are retried until NextAttemptAt is cleared.
+type WebhookDelivery struct {
+	Id            uint   `gorm:"primary_key"`
+	WebhookId     uint   `gorm:"unique_index:webhook_event_tx_hash"`
+	Event         string `gorm:"size:25;unique_index:webhook_event_tx_hash"`
+	TxHash        []byte `gorm:"size:32;unique_index:webhook_event_tx_hash"`
+	Payload       string `gorm:"type:text"`
+	Attempts      uint
+	ResponseCode  int
+	Error         string `gorm:"size:255"`
+	DeliveredAt   *time.Time
+	NextAttemptAt *time.Time `gorm:"index:next_attempt_at"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (d *WebhookDelivery) Save() error {
+	result := save(d)
+	if result.Error != nil {
+		return jerr.Get("error saving webhook delivery", result.Error)
+	}
+	return nil
+}
+
+func (d WebhookDelivery) GetTransactionHashString() string {
+	hash, err := chainhash.NewHash(d.TxHash)
+	if err != nil {
+		jerr.Get("error getting chainhash from webhook delivery", err).Print()
+		return ""
+	}
+	return hash.String()
+}
+
+func (d WebhookDelivery) IsDelivered() bool {
+	return d.DeliveredAt != nil
+}
+
+func (d WebhookDelivery) IsPending() bool {
+	return d.DeliveredAt == nil && d.NextAttemptAt != nil
+}
+
+// AddWebhookDelivery returns nil if the event has already been queued for the webhook.
+func AddWebhookDelivery(webhookId uint, event string, txHash []byte, payload string, nextAttemptAt time.Time) (*WebhookDelivery, error) {
+	var webhookDelivery = WebhookDelivery{
+		WebhookId:     webhookId,
+		Event:         event,
+		TxHash:        txHash,
+		Payload:       payload,
+		NextAttemptAt: &nextAttemptAt,
+	}
+	err := create(&webhookDelivery)
+	if err == nil {
+		return &webhookDelivery, nil
+	}
+	if ! IsDuplicateEntryError(err) {
+		return nil, jerr.Get("error creating webhook delivery", err)
+	}
+	return nil, nil
+}
+
+func GetRecentWebhookDeliveries(webhookId uint) ([]*WebhookDelivery, error) {
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	var webhookDeliveries []*WebhookDelivery
+	result := db.
+		Where("webhook_id = ?", webhookId).
+		Order("id DESC").
+		Limit(webhookDeliveriesPageSize).
+		Find(&webhookDeliveries)
+	if result.Error != nil {
+		return nil, jerr.Get("error getting recent webhook deliveries", result.Error)
+	}
+	return webhookDeliveries, nil
+}
+
+func GetPendingWebhookDeliveries(limit uint) ([]*WebhookDelivery, error) {
+	db, err := getDb()
+	if err != nil {
+		return nil, jerr.Get("error getting db", err)
+	}
+	var webhookDeliveries []*WebhookDelivery
+	result := db.
+		Where("delivered_at IS NULL AND next_attempt_at <= ?", time.Now()).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&webhookDeliveries)
+	if result.Error != nil {
+		return nil, jerr.Get("error getting pending webhook deliveries", result.Error)
+	}
+	return webhookDeliveries, nil
+}
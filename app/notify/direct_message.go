@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bus"
+	"github.com/memocash/memo/app/cache"
+	"github.com/memocash/memo/app/db"
+	"time"
+)
+
+// The message is encrypted so only the sender is shown, it is read from the inbox.
+type DirectMessageNotification struct {
+	DirectMessage *db.MemoDirectMessage
+	Notification  *db.Notification
+	Name          string
+}
+
+func (n DirectMessageNotification) GetNotification() *Notification {
+	return &Notification{
+		Type:           TypeDirectMessage,
+		DbNotification: n.Notification,
+		PkHash:         n.DirectMessage.PkHash,
+		Time:           n.GetTime(),
+		AddressString:  n.DirectMessage.GetAddressString(),
+		PostHashString: n.DirectMessage.GetTransactionHashString(),
+	}
+}
+
+func (n DirectMessageNotification) GetName() string {
+	return n.Name
+}
+
+func (n DirectMessageNotification) GetPostHashString() string {
+	return n.DirectMessage.GetTransactionHashString()
+}
+
+func (n DirectMessageNotification) GetAddressString() string {
+	return n.DirectMessage.GetAddressString()
+}
+
+func (n DirectMessageNotification) GetMessage() string {
+	return ""
+}
+
+func (n DirectMessageNotification) GetTime() time.Time {
+	if n.DirectMessage.Block != nil && n.DirectMessage.Block.Timestamp.Before(n.DirectMessage.CreatedAt) {
+		return n.DirectMessage.Block.Timestamp
+	} else {
+		return n.DirectMessage.CreatedAt
+	}
+}
+
+func AddDirectMessageNotification(directMessage *db.MemoDirectMessage, updateCache bool) error {
+	userId, err := db.GetUserIdFromPkHash(directMessage.RecipientPkHash)
+	if err != nil {
+		if db.IsRecordNotFoundError(err) {
+			// Don't add notifications for external users, not an error though
+			return nil
+		}
+		return jerr.Get("error getting user id from pk hash", err)
+	}
+	muted, err := isMuted(directMessage.RecipientPkHash, directMessage.PkHash)
+	if err != nil {
+		return jerr.Get("error checking if muted", err)
+	}
+	if muted {
+		return nil
+	}
+	notification, err := db.AddNotification(directMessage.RecipientPkHash, directMessage.TxHash, db.NotificationTypeDirectMessage)
+	if err != nil {
+		return jerr.Get("error adding notification", err)
+	}
+	if updateCache {
+		_, err = cache.GetAndSetUnreadNotificationCount(userId)
+		if err != nil {
+			return jerr.Get("error setting notification unread count", err)
+		}
+		if notification != nil {
+			bus.Publish(bus.NotificationChannel(notification.PkHash), bus.EventNotification, notification.TxHash)
+		}
+	}
+	return nil
+}
@@ -2,68 +2,161 @@ package notify
 
 import (
 	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/cache"
 	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/obj/rep"
 )
 
-func GetNotificationsFeed(pkHash []byte, offset uint) ([]*Notification, error) {
+// GetNotificationsFeed also returns the number of notifications read from the db, muted and untrusted notifications
+// are removed after reading so pages advance by it.
+func GetNotificationsFeed(pkHash []byte, offset uint) ([]*Notification, int, error) {
 	dbNotifications, err := db.GetRecentNotificationsForUser(pkHash, offset)
 	if err != nil {
-		return nil, jerr.Get("error getting notifications from db", err)
+		return nil, 0, jerr.Get("error getting notifications from db", err)
+	}
+	muteList, err := cache.GetMuteList(pkHash)
+	if err != nil {
+		return nil, 0, jerr.Get("error getting mute list", err)
+	}
+	hideUntrusted, err := isHideUntrusted(pkHash)
+	if err != nil {
+		return nil, 0, jerr.Get("error getting hide untrusted setting", err)
 	}
 	var notifications []*Notification
 	for _, dbNotification := range dbNotifications {
-		switch dbNotification.Type {
-		case db.NotificationTypeLike:
-			like, err := db.GetMemoLike(dbNotification.TxHash)
-			if err != nil {
-				jerr.Get("error getting notification like", err).Print()
-				continue
-			}
-			post, err := db.GetMemoPost(like.LikeTxHash)
-			if err != nil {
-				jerr.Get("error getting like post for notification", err).Print()
-				continue
-			}
-			notifications = append(notifications, LikeNotification{
-				Notification: dbNotification,
-				Like:         like,
-				Post:         post,
-			}.GetNotification())
-		case db.NotificationTypeReply:
-			post, err := db.GetMemoPost(dbNotification.TxHash)
-			if err != nil {
-				jerr.Get("error getting notification post", err).Print()
-				continue
-			}
-			parent, err := db.GetMemoPost(post.ParentTxHash)
-			if err != nil {
-				jerr.Get("error getting notification post parent", err).Print()
-				continue
-			}
-			notifications = append(notifications, ReplyNotification{
-				Notification: dbNotification,
-				Post:         post,
-				Parent:       parent,
-			}.GetNotification())
-		case db.NotificationTypeNewFollower:
-			follow, err := db.GetMemoFollow(dbNotification.TxHash)
-			if err != nil {
-				jerr.Get("error getting notification new follower", err).Print()
-				continue
-			}
-			notifications = append(notifications, NewFollowerNotification{
-				Notification: dbNotification,
-				Follow:       follow,
-			}.GetNotification())
+		notification, err := getNotification(dbNotification)
+		if err != nil {
+			jerr.Get("error getting notification", err).Print()
+			continue
+		}
+		if notification == nil || muteList.IsPkHashMuted(notification.PkHash) {
+			continue
+		}
+		notifications = append(notifications, notification)
+	}
+	if hideUntrusted {
+		notifications, err = filterUntrusted(pkHash, notifications)
+		if err != nil {
+			return nil, 0, jerr.Get("error filtering untrusted notifications", err)
 		}
 	}
 	err = AttachNamesToNotifications(notifications)
 	if err != nil {
-		return nil, jerr.Get("error attaching names to notifications", err)
+		return nil, 0, jerr.Get("error attaching names to notifications", err)
 	}
 	err = AttachProfilePicsToNotifications(notifications)
 	if err != nil {
-		return nil, jerr.Get("error attaching profile pics to notifications", err)
+		return nil, 0, jerr.Get("error attaching profile pics to notifications", err)
+	}
+	return notifications, len(dbNotifications), nil
+}
+
+func filterUntrusted(pkHash []byte, notifications []*Notification) ([]*Notification, error) {
+	var pkHashes [][]byte
+	for _, notification := range notifications {
+		pkHashes = append(pkHashes, notification.PkHash)
+	}
+	trusted, err := rep.GetTrustedPkHashes(pkHash, pkHashes)
+	if err != nil {
+		return nil, jerr.Get("error getting trusted pk hashes", err)
+	}
+	var trustedNotifications []*Notification
+	for _, notification := range notifications {
+		if trusted[string(notification.PkHash)] {
+			trustedNotifications = append(trustedNotifications, notification)
+		}
+	}
+	return trustedNotifications, nil
+}
+
+func isHideUntrusted(pkHash []byte) (bool, error) {
+	userId, err := db.GetUserIdFromPkHash(pkHash)
+	if err != nil {
+		if db.IsRecordNotFoundError(err) {
+			return false, nil
+		}
+		return false, jerr.Get("error getting user id", err)
+	}
+	userSettings, err := cache.GetUserSettings(userId)
+	if err != nil {
+		return false, jerr.Get("error getting user settings", err)
+	}
+	return userSettings.HideUntrusted, nil
+}
+
+// GetNotification returns a single notification with name and profile pic attached.
+func GetNotification(pkHash []byte, txHash []byte) (*Notification, error) {
+	dbNotification, err := db.GetNotification(pkHash, txHash)
+	if err != nil {
+		return nil, jerr.Get("error getting notification from db", err)
+	}
+	notification, err := getNotification(dbNotification)
+	if err != nil {
+		return nil, jerr.Get("error getting notification", err)
+	}
+	if notification == nil {
+		return nil, jerr.New("unknown notification type")
+	}
+	notifications := []*Notification{notification}
+	err = AttachNamesToNotifications(notifications)
+	if err != nil {
+		return nil, jerr.Get("error attaching names to notification", err)
+	}
+	err = AttachProfilePicsToNotifications(notifications)
+	if err != nil {
+		return nil, jerr.Get("error attaching profile pic to notification", err)
+	}
+	return notification, nil
+}
+
+func getNotification(dbNotification *db.Notification) (*Notification, error) {
+	switch dbNotification.Type {
+	case db.NotificationTypeLike:
+		like, err := db.GetStore().GetMemoLike(dbNotification.TxHash)
+		if err != nil {
+			return nil, jerr.Get("error getting notification like", err)
+		}
+		post, err := db.GetStore().GetMemoPost(like.LikeTxHash)
+		if err != nil {
+			return nil, jerr.Get("error getting like post for notification", err)
+		}
+		return LikeNotification{
+			Notification: dbNotification,
+			Like:         like,
+			Post:         post,
+		}.GetNotification(), nil
+	case db.NotificationTypeReply:
+		post, err := db.GetStore().GetMemoPost(dbNotification.TxHash)
+		if err != nil {
+			return nil, jerr.Get("error getting notification post", err)
+		}
+		parent, err := db.GetStore().GetMemoPost(post.ParentTxHash)
+		if err != nil {
+			return nil, jerr.Get("error getting notification post parent", err)
+		}
+		return ReplyNotification{
+			Notification: dbNotification,
+			Post:         post,
+			Parent:       parent,
+		}.GetNotification(), nil
+	case db.NotificationTypeNewFollower:
+		follow, err := db.GetStore().GetMemoFollow(dbNotification.TxHash)
+		if err != nil {
+			return nil, jerr.Get("error getting notification new follower", err)
+		}
+		return NewFollowerNotification{
+			Notification: dbNotification,
+			Follow:       follow,
+		}.GetNotification(), nil
+	case db.NotificationTypeDirectMessage:
+		directMessage, err := db.GetMemoDirectMessage(dbNotification.TxHash)
+		if err != nil {
+			return nil, jerr.Get("error getting notification direct message", err)
+		}
+		return DirectMessageNotification{
+			Notification:  dbNotification,
+			DirectMessage: directMessage,
+		}.GetNotification(), nil
 	}
-	return notifications, nil
+	return nil, nil
 }
@@ -2,8 +2,10 @@ package notify
 
 import (
 	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bus"
 	"github.com/memocash/memo/app/cache"
 	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/webhook"
 	"time"
 )
 
@@ -56,7 +58,14 @@ func AddNewFollowerNotification(follow *db.MemoFollow, updateCache bool) error {
 		}
 		return jerr.Get("error getting user id from pk hash", err)
 	}
-	_, err = db.AddNotification(follow.FollowPkHash, follow.TxHash, db.NotificationTypeNewFollower)
+	muted, err := isMuted(follow.FollowPkHash, follow.PkHash)
+	if err != nil {
+		return jerr.Get("error checking if muted", err)
+	}
+	if muted {
+		return nil
+	}
+	notification, err := db.AddNotification(follow.FollowPkHash, follow.TxHash, db.NotificationTypeNewFollower)
 	if err != nil {
 		return jerr.Get("error adding notification", err)
 	}
@@ -65,6 +74,13 @@ func AddNewFollowerNotification(follow *db.MemoFollow, updateCache bool) error {
 		if err != nil {
 			return jerr.Get("error setting notification unread count", err)
 		}
+		if notification != nil {
+			bus.Publish(bus.NotificationChannel(notification.PkHash), bus.EventNotification, notification.TxHash)
+			err = webhook.AddFollow(follow)
+			if err != nil {
+				return jerr.Get("error adding webhook deliveries", err)
+			}
+		}
 	}
 	return nil
 }
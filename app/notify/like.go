@@ -2,8 +2,10 @@ package notify
 
 import (
 	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bus"
 	"github.com/memocash/memo/app/cache"
 	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/webhook"
 	"time"
 )
 
@@ -52,7 +54,7 @@ func (n LikeNotification) GetTime() time.Time {
 }
 
 func AddLikeNotification(like *db.MemoLike, updateCache bool) error {
-	post, err := db.GetMemoPost(like.LikeTxHash)
+	post, err := db.GetStore().GetMemoPost(like.LikeTxHash)
 	if err != nil {
 		return jerr.Get("error getting memo post", err)
 	}
@@ -64,7 +66,14 @@ func AddLikeNotification(like *db.MemoLike, updateCache bool) error {
 		}
 		return jerr.Get("error getting user id from pk hash", err)
 	}
-	_, err = db.AddNotification(post.PkHash, like.TxHash, db.NotificationTypeLike)
+	muted, err := isMuted(post.PkHash, like.PkHash)
+	if err != nil {
+		return jerr.Get("error checking if muted", err)
+	}
+	if muted {
+		return nil
+	}
+	notification, err := db.AddNotification(post.PkHash, like.TxHash, db.NotificationTypeLike)
 	if err != nil {
 		return jerr.Get("error adding notification", err)
 	}
@@ -73,6 +82,13 @@ func AddLikeNotification(like *db.MemoLike, updateCache bool) error {
 		if err != nil {
 			return jerr.Get("error setting notification unread count", err)
 		}
+		if notification != nil {
+			bus.Publish(bus.NotificationChannel(notification.PkHash), bus.EventNotification, notification.TxHash)
+			err = webhook.AddLike(like, post)
+			if err != nil {
+				return jerr.Get("error adding webhook deliveries", err)
+			}
+		}
 	}
 	return nil
 }
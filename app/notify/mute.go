@@ -0,0 +1,15 @@
+package notify
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/cache"
+)
+
+// Notifications are not added for actions by addresses the recipient has muted.
+func isMuted(pkHash []byte, actorPkHash []byte) (bool, error) {
+	muteList, err := cache.GetMuteList(pkHash)
+	if err != nil {
+		return false, jerr.Get("error getting mute list", err)
+	}
+	return muteList.IsPkHashMuted(actorPkHash), nil
+}
@@ -11,9 +11,10 @@ import (
 type NotificationType string
 
 const (
-	TypeLike   = "like"
-	TypeFollow = "follow"
-	TypeReply  = "reply"
+	TypeLike          = "like"
+	TypeFollow        = "follow"
+	TypeReply         = "reply"
+	TypeDirectMessage = "direct-message"
 )
 
 type Notification struct {
@@ -46,6 +47,10 @@ func (n Notification) IsNewFollower() bool {
 	return n.Type == TypeFollow
 }
 
+func (n Notification) IsDirectMessage() bool {
+	return n.Type == TypeDirectMessage
+}
+
 func (n Notification) GetTimeAgo() string {
 	return util.GetTimeAgo(n.Time)
 }
@@ -2,8 +2,10 @@ package notify
 
 import (
 	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bus"
 	"github.com/memocash/memo/app/cache"
 	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/webhook"
 	"time"
 )
 
@@ -53,7 +55,7 @@ func (n ReplyNotification) GetTime() time.Time {
 }
 
 func AddReplyNotification(reply *db.MemoPost, updateCache bool) error {
-	parent, err := db.GetMemoPost(reply.ParentTxHash)
+	parent, err := db.GetStore().GetMemoPost(reply.ParentTxHash)
 	if err != nil {
 		return jerr.Get("error getting parent post", err)
 	}
@@ -65,7 +67,14 @@ func AddReplyNotification(reply *db.MemoPost, updateCache bool) error {
 		}
 		return jerr.Get("error getting user id from pk hash", err)
 	}
-	_, err = db.AddNotification(parent.PkHash, reply.TxHash, db.NotificationTypeReply)
+	muted, err := isMuted(parent.PkHash, reply.PkHash)
+	if err != nil {
+		return jerr.Get("error checking if muted", err)
+	}
+	if muted {
+		return nil
+	}
+	notification, err := db.AddNotification(parent.PkHash, reply.TxHash, db.NotificationTypeReply)
 	if err != nil {
 		return jerr.Get("error adding notification", err)
 	}
@@ -74,6 +83,13 @@ func AddReplyNotification(reply *db.MemoPost, updateCache bool) error {
 		if err != nil {
 			return jerr.Get("error setting notification unread count", err)
 		}
+		if notification != nil {
+			bus.Publish(bus.NotificationChannel(notification.PkHash), bus.EventNotification, notification.TxHash)
+			err = webhook.AddReply(reply, parent)
+			if err != nil {
+				return jerr.Get("error adding webhook deliveries", err)
+			}
+		}
 	}
 	return nil
 }
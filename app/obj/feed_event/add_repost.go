@@ -0,0 +1,22 @@
+package feed_event
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/db"
+)
+
+func AddRepost(repost *db.MemoRepost) error {
+	var feed = db.FeedEvent{
+		PkHash:    repost.PkHash,
+		TxHash:    repost.TxHash,
+		EventType: db.FeedEventRepost,
+	}
+	if repost.Block != nil {
+		feed.BlockHeight = repost.Block.Height
+	}
+	err := feed.Save()
+	if err != nil {
+		return jerr.Get("error saving feed repost", err)
+	}
+	return nil
+}
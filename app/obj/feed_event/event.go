@@ -18,6 +18,7 @@ type Event struct {
 	ProfilePic       *db.MemoSetPic
 	Post             *profile.Post
 	MemoLike         *db.MemoLike
+	Repost           *db.MemoRepost
 	PollOption       *db.MemoPollOption
 	PollVote         *db.MemoPollVote
 	SetName          *db.MemoSetName
@@ -68,6 +69,16 @@ func (e *Event) GetProfileText() string {
 	return profileText
 }
 
+func (e *Event) GetRepostMessage() string {
+	if e.Repost == nil {
+		return ""
+	}
+	var msg = e.Repost.Message
+	msg = strings.TrimSpace(msg)
+	msg = format.AddLinks(msg)
+	return msg
+}
+
 func (e *Event) GetSetName() string {
 	if e.SetName == nil {
 		return ""
@@ -95,6 +106,10 @@ func (e *Event) IsLike() bool {
 	return e.FeedEvent.EventType == db.FeedEventLike
 }
 
+func (e *Event) IsRepost() bool {
+	return e.FeedEvent.EventType == db.FeedEventRepost
+}
+
 func (e *Event) IsPost() bool {
 	return e.FeedEvent.EventType == db.FeedEventPost
 }
@@ -159,6 +174,8 @@ func (e *Event) GetType() string {
 		return "Follow User"
 	case db.FeedEventFollowTopic:
 		return "Follow Topic"
+	case db.FeedEventRepost:
+		return "Repost"
 	}
 	return ""
 }
@@ -18,6 +18,14 @@ func GetEventsForUser(userId uint, pkHash []byte, offset uint) ([]*Event, error)
 	if err != nil {
 		return nil, jerr.Get("error getting events from feed events", err)
 	}
+	events, err = filterHiddenEvents(events)
+	if err != nil {
+		return nil, jerr.Get("error filtering hidden events", err)
+	}
+	events, err = filterMutedEvents(events, pkHash)
+	if err != nil {
+		return nil, jerr.Get("error filtering muted events", err)
+	}
 	return events, nil
 }
 
@@ -30,6 +38,10 @@ func GetUserEvents(userId uint, userPkHash []byte, pkHash []byte, offset uint, e
 	if err != nil {
 		return nil, jerr.Get("error getting events from feed events", err)
 	}
+	events, err = filterHiddenEvents(events)
+	if err != nil {
+		return nil, jerr.Get("error filtering hidden events", err)
+	}
 	return events, nil
 }
 
@@ -42,6 +54,14 @@ func GetAllEvents(userId uint, pkHash []byte, offset uint) ([]*Event, error) {
 	if err != nil {
 		return nil, jerr.Get("error getting events from feed events", err)
 	}
+	events, err = filterHiddenEvents(events)
+	if err != nil {
+		return nil, jerr.Get("error filtering hidden events", err)
+	}
+	events, err = filterMutedEvents(events, pkHash)
+	if err != nil {
+		return nil, jerr.Get("error filtering muted events", err)
+	}
 	return events, nil
 }
 
@@ -49,6 +69,7 @@ func getEvents(feedEvents []*db.FeedEvent, userId uint, pkHash []byte) ([]*Event
 	var (
 		postTxHashes          [][]byte
 		likeTxHashes          [][]byte
+		repostTxHashes        [][]byte
 		postVoteTxHashes      [][]byte
 		setNameTxHashes       [][]byte
 		profileSetTxHashes    [][]byte
@@ -63,6 +84,8 @@ func getEvents(feedEvents []*db.FeedEvent, userId uint, pkHash []byte) ([]*Event
 			postTxHashes = append(postTxHashes, feedEvent.TxHash)
 		case db.FeedEventLike:
 			likeTxHashes = append(likeTxHashes, feedEvent.TxHash)
+		case db.FeedEventRepost:
+			repostTxHashes = append(repostTxHashes, feedEvent.TxHash)
 		case db.FeedEventPollVote:
 			postVoteTxHashes = append(postVoteTxHashes, feedEvent.TxHash)
 		case db.FeedEventSetName:
@@ -126,6 +149,14 @@ func getEvents(feedEvents []*db.FeedEvent, userId uint, pkHash []byte) ([]*Event
 		postTxHashes = append(postTxHashes, memoLike.LikeTxHash)
 	}
 
+	memoReposts, err := db.GetMemoRepostsByTxHashes(repostTxHashes)
+	if err != nil {
+		return nil, jerr.Get("error getting memo reposts by tx hashes", err)
+	}
+	for _, memoRepost := range memoReposts {
+		postTxHashes = append(postTxHashes, memoRepost.RepostTxHash)
+	}
+
 	memoPollVotes, err := db.GetMemoPollVotesByTxHashes(postVoteTxHashes)
 	if err != nil {
 		return nil, jerr.Get("error getting memo poll votes by tx hashes", err)
@@ -219,6 +250,17 @@ func getEvents(feedEvents []*db.FeedEvent, userId uint, pkHash []byte) ([]*Event
 					}
 				}
 			}
+		case db.FeedEventRepost:
+			for _, memoRepost := range memoReposts {
+				if bytes.Equal(memoRepost.TxHash, feedEvent.TxHash) {
+					event.Repost = memoRepost
+					for _, post := range posts {
+						if bytes.Equal(post.Memo.TxHash, memoRepost.RepostTxHash) {
+							event.Post = post
+						}
+					}
+				}
+			}
 		case db.FeedEventPollVote:
 			for _, memoPollVote := range memoPollVotes {
 				if bytes.Equal(memoPollVote.TxHash, feedEvent.TxHash) {
@@ -297,6 +339,65 @@ func getEvents(feedEvents []*db.FeedEvent, userId uint, pkHash []byte) ([]*Event
 	return events, nil
 }
 
+// Events by banned addresses are removed, as are post events whose post was hidden by moderation.
+func filterHiddenEvents(events []*Event) ([]*Event, error) {
+	moderationList, err := cache.GetModerationList()
+	if err != nil {
+		return nil, jerr.Get("error getting moderation list", err)
+	}
+	if len(moderationList.Hides) == 0 {
+		return events, nil
+	}
+	var filteredEvents []*Event
+	for _, event := range events {
+		if moderationList.IsPkHashHidden(event.FeedEvent.PkHash) {
+			continue
+		}
+		if event.Post == nil && hasPost(event.FeedEvent.EventType) {
+			continue
+		}
+		if event.TopicFollow != nil && moderationList.IsTopicHidden(event.TopicFollow.Topic) {
+			continue
+		}
+		filteredEvents = append(filteredEvents, event)
+	}
+	return filteredEvents, nil
+}
+
+func hasPost(eventType db.FeedEventType) bool {
+	switch eventType {
+	case db.FeedEventPost, db.FeedEventTopicPost, db.FeedEventReply, db.FeedEventCreatePoll, db.FeedEventLike,
+		db.FeedEventRepost, db.FeedEventPollVote:
+		return true
+	}
+	return false
+}
+
+// Events are hidden if the actor, post author or post topic is muted.
+func filterMutedEvents(events []*Event, pkHash []byte) ([]*Event, error) {
+	muteList, err := profile.GetMuteList(pkHash)
+	if err != nil {
+		return nil, jerr.Get("error getting mute list", err)
+	}
+	if len(muteList.Mutes) == 0 {
+		return events, nil
+	}
+	var filteredEvents []*Event
+	for _, event := range events {
+		if muteList.IsPkHashMuted(event.FeedEvent.PkHash) {
+			continue
+		}
+		if event.Post != nil && muteList.IsPostMuted(event.Post.Memo) {
+			continue
+		}
+		if event.TopicFollow != nil && muteList.IsTopicMuted(event.TopicFollow.Topic) {
+			continue
+		}
+		filteredEvents = append(filteredEvents, event)
+	}
+	return filteredEvents, nil
+}
+
 func AttachReputationToEvents(events []*Event) error {
 	for _, event := range events {
 		reputation, err := rep.GetReputation(event.SelfPkHash, event.FeedEvent.PkHash)
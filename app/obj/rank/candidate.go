@@ -0,0 +1,98 @@
+package rank
+
+import (
+	"bytes"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/obj/rep"
+	"time"
+)
+
+// Candidate is a post with everything scorers need. Author trust and follows are from the perspective of self, and
+// are empty when logged out.
+type Candidate struct {
+	Post          *db.MemoPost
+	Likes         []*db.MemoLike
+	ReplyCount    uint
+	AuthorTrust   float64
+	FollowsAuthor bool
+	FollowedLikes int
+	Score         float64
+}
+
+// GetTimestamp uses the block time when the post was seen after it was mined, e.g. during a resync.
+func (c Candidate) GetTimestamp() time.Time {
+	if c.Post.Block != nil && c.Post.Block.Timestamp.Before(c.Post.CreatedAt) {
+		return c.Post.Block.Timestamp
+	}
+	return c.Post.CreatedAt
+}
+
+func GetCandidates(selfPkHash []byte, memoPosts []*db.MemoPost) ([]*Candidate, error) {
+	if len(memoPosts) == 0 {
+		return nil, nil
+	}
+	var txHashes [][]byte
+	var authorPkHashes [][]byte
+	var candidates []*Candidate
+	var candidateMap = make(map[string]*Candidate)
+	for _, memoPost := range memoPosts {
+		var candidate = &Candidate{
+			Post: memoPost,
+		}
+		candidates = append(candidates, candidate)
+		candidateMap[string(memoPost.TxHash)] = candidate
+		txHashes = append(txHashes, memoPost.TxHash)
+		authorPkHashes = append(authorPkHashes, memoPost.PkHash)
+	}
+	memoLikes, err := db.GetStore().GetMemoLikesForTxnHashes(txHashes)
+	if err != nil {
+		return nil, jerr.Get("error getting likes for candidates", err)
+	}
+	for _, memoLike := range memoLikes {
+		if candidate, ok := candidateMap[string(memoLike.LikeTxHash)]; ok {
+			candidate.Likes = append(candidate.Likes, memoLike)
+		}
+	}
+	replyCounts, err := db.GetStore().GetPostReplyCounts(txHashes)
+	if err != nil {
+		return nil, jerr.Get("error getting reply counts for candidates", err)
+	}
+	for _, replyCount := range replyCounts {
+		if candidate, ok := candidateMap[string(replyCount.TxHash)]; ok {
+			candidate.ReplyCount = replyCount.Count
+		}
+	}
+	if len(selfPkHash) == 0 {
+		return candidates, nil
+	}
+	trustScores, err := rep.GetTrustScores(selfPkHash, authorPkHashes)
+	if err != nil {
+		return nil, jerr.Get("error getting trust scores for candidates", err)
+	}
+	memoFollows, err := db.GetStore().GetFollowersForPkHash(selfPkHash, -1)
+	if err != nil {
+		return nil, jerr.Get("error getting follows for self", err)
+	}
+	var follows = make(map[string]bool)
+	for _, memoFollow := range memoFollows {
+		follows[string(memoFollow.FollowPkHash)] = true
+	}
+	for _, candidate := range candidates {
+		if bytes.Equal(candidate.Post.PkHash, selfPkHash) {
+			continue
+		}
+		candidate.AuthorTrust = trustScores[string(candidate.Post.PkHash)]
+		candidate.FollowsAuthor = follows[string(candidate.Post.PkHash)]
+		var likers = make(map[string]bool)
+		for _, memoLike := range candidate.Likes {
+			likers[string(memoLike.PkHash)] = true
+		}
+		for liker := range likers {
+			if follows[liker] {
+				candidate.FollowedLikes++
+			}
+		}
+	}
+	return candidates, nil
+}
@@ -0,0 +1,171 @@
+package rank
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/cache"
+	"github.com/memocash/memo/app/config"
+	"github.com/memocash/memo/app/db"
+	"math"
+	"sort"
+	"time"
+)
+
+// Posts are ranked from the most active candidates in the window plus the newest ones, more active candidates are
+// loaded for later pages.
+const (
+	CandidateDays      = 7
+	TopicCandidateDays = 30
+	MaxCandidates      = 500
+	NewestCandidates   = 100
+	PageSize           = 25
+)
+
+const (
+	NameLikes      = "likes"
+	NameTips       = "tips"
+	NameDiscussion = "discussion"
+	NamePersonal   = "personal"
+)
+
+type Scorer interface {
+	GetScore(candidate *Candidate) float64
+}
+
+type WeightedScorer struct {
+	Scorer Scorer
+	Weight float64
+}
+
+// Ranker sums weighted scores and divides by age, same as the old SQL formula:
+// score / (minutes since post + 2) ^ gravity
+type Ranker struct {
+	Name    string
+	Scorers []WeightedScorer
+	Gravity float64
+}
+
+func (r Ranker) GetScore(candidate *Candidate, now time.Time) float64 {
+	var score float64
+	for _, weightedScorer := range r.Scorers {
+		if weightedScorer.Weight == 0 {
+			continue
+		}
+		score += weightedScorer.Weight * weightedScorer.Scorer.GetScore(candidate)
+	}
+	minutes := math.Max(now.Sub(candidate.GetTimestamp()).Minutes(), 0)
+	return score / math.Pow(minutes+2, r.Gravity)
+}
+
+// Rank sorts candidates highest score first. Ties keep their existing order.
+func (r Ranker) Rank(candidates []*Candidate) {
+	now := time.Now()
+	for _, candidate := range candidates {
+		candidate.Score = r.GetScore(candidate, now)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+}
+
+// GetPage ranks posts for self and returns a page of them.
+func (r Ranker) GetPage(selfPkHash []byte, memoPosts []*db.MemoPost, offset uint) ([]*db.MemoPost, error) {
+	candidates, err := GetCandidates(selfPkHash, memoPosts)
+	if err != nil {
+		return nil, err
+	}
+	r.Rank(candidates)
+	var page []*db.MemoPost
+	for i := int(offset); i < len(candidates) && i < int(offset)+PageSize; i++ {
+		page = append(page, candidates[i].Post)
+	}
+	return page, nil
+}
+
+// GetCandidateLimit returns how many candidates to load so the page at offset has posts to rank.
+func GetCandidateLimit(offset uint) uint {
+	return MaxCandidates + offset
+}
+
+// GetCandidatePosts returns the most active posts in the window along with the newest ones, so fresh posts can be
+// ranked before they get any likes. Candidates for a window are cached, searches are not.
+func GetCandidatePosts(topic string, searchString string, days int, offset uint) ([]*db.MemoPost, error) {
+	limit := GetCandidateLimit(offset)
+	if searchString == "" {
+		memoPosts, err := cache.GetRankCandidatePosts(topic, days, limit)
+		if err == nil {
+			return memoPosts, nil
+		}
+		if ! cache.IsMissError(err) {
+			return nil, jerr.Get("error getting rank candidate posts from cache", err)
+		}
+	}
+	memoPosts, err := db.GetStore().GetRankCandidatePosts(topic, searchString, days, limit)
+	if err != nil {
+		return nil, jerr.Get("error getting rank candidate posts", err)
+	}
+	newestPosts, err := db.GetStore().GetNewestRankCandidatePosts(topic, searchString, days, NewestCandidates)
+	if err != nil {
+		return nil, jerr.Get("error getting newest rank candidate posts", err)
+	}
+	var found = make(map[string]bool)
+	for _, memoPost := range memoPosts {
+		found[string(memoPost.TxHash)] = true
+	}
+	for _, newestPost := range newestPosts {
+		if ! found[string(newestPost.TxHash)] {
+			memoPosts = append(memoPosts, newestPost)
+		}
+	}
+	if searchString == "" && len(memoPosts) > 0 {
+		err = cache.SetRankCandidatePosts(topic, days, limit, memoPosts)
+		if err != nil {
+			return nil, jerr.Get("error setting rank candidate posts cache", err)
+		}
+	}
+	return memoPosts, nil
+}
+
+// GetRanker returns the named ranker, unknown names get the likes ranker.
+func GetRanker(name string) Ranker {
+	weights := config.GetRankWeights()
+	switch name {
+	case NameTips:
+		return Ranker{
+			Name: NameTips,
+			Scorers: []WeightedScorer{
+				{Scorer: LikesScorer{}, Weight: float64(db.RankCountBoost)},
+				{Scorer: TipsScorer{}, Weight: float64(db.RankCountBoost) * 2},
+			},
+			Gravity: float64(db.RankGravity),
+		}
+	case NameDiscussion:
+		return Ranker{
+			Name: NameDiscussion,
+			Scorers: []WeightedScorer{
+				{Scorer: LikesScorer{}, Weight: float64(db.RankCountBoost)},
+				{Scorer: RepliesScorer{}, Weight: float64(db.RankCountBoost)},
+			},
+			Gravity: float64(db.RankGravity),
+		}
+	case NamePersonal:
+		return Ranker{
+			Name: NamePersonal,
+			Scorers: []WeightedScorer{
+				{Scorer: LikesScorer{}, Weight: float64(db.RankCountBoost) * weights.Likes},
+				{Scorer: TipsScorer{}, Weight: float64(db.RankCountBoost) * weights.Tips},
+				{Scorer: RepliesScorer{}, Weight: float64(db.RankCountBoost) * weights.Replies},
+				{Scorer: ReputationScorer{}, Weight: float64(db.RankCountBoost) * weights.Reputation},
+				{Scorer: ProximityScorer{}, Weight: float64(db.RankCountBoost) * weights.Proximity},
+			},
+			Gravity: float64(db.RankGravity),
+		}
+	default:
+		return Ranker{
+			Name: NameLikes,
+			Scorers: []WeightedScorer{
+				{Scorer: LikesScorer{}, Weight: float64(db.RankCountBoost)},
+			},
+			Gravity: float64(db.RankGravity),
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package rank_test
+
+import (
+	"bytes"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/obj/rank"
+	"testing"
+	"time"
+)
+
+var (
+	authorPkHash = bytes.Repeat([]byte{0x01}, 20)
+	likerPkHash  = bytes.Repeat([]byte{0x02}, 20)
+)
+
+func getCandidate(id byte, age time.Duration, likes []*db.MemoLike) *rank.Candidate {
+	return &rank.Candidate{
+		Post: &db.MemoPost{
+			TxHash:    bytes.Repeat([]byte{id}, 32),
+			PkHash:    authorPkHash,
+			CreatedAt: time.Now().Add(-age),
+		},
+		Likes: likes,
+	}
+}
+
+func TestRanker(t *testing.T) {
+	ranker := rank.Ranker{
+		Scorers: []rank.WeightedScorer{
+			{Scorer: rank.LikesScorer{}, Weight: 1},
+			{Scorer: rank.TipsScorer{}, Weight: 1},
+		},
+		Gravity: 2,
+	}
+	selfLike := &db.MemoLike{PkHash: authorPkHash}
+	like := &db.MemoLike{PkHash: likerPkHash}
+	tip := &db.MemoLike{PkHash: likerPkHash, TipPkHash: authorPkHash, TipAmount: 546}
+	candidates := []*rank.Candidate{
+		getCandidate(1, time.Minute, []*db.MemoLike{selfLike}),
+		getCandidate(2, time.Hour, []*db.MemoLike{like}),
+		getCandidate(3, time.Hour, []*db.MemoLike{tip}),
+		getCandidate(4, time.Minute, nil),
+	}
+	ranker.Rank(candidates)
+	var order []byte
+	for _, candidate := range candidates {
+		order = append(order, candidate.Post.TxHash[0])
+	}
+	// Author likes do not count and ties keep their order.
+	if ! bytes.Equal(order, []byte{3, 2, 1, 4}) {
+		t.Fatal(jerr.Newf("unexpected order: %v", order))
+	}
+}
@@ -0,0 +1,62 @@
+package rank
+
+import (
+	"bytes"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"math"
+)
+
+// Distinct addresses liking the post, not counting the author.
+type LikesScorer struct{}
+
+func (LikesScorer) GetScore(candidate *Candidate) float64 {
+	var likers = make(map[string]bool)
+	for _, memoLike := range candidate.Likes {
+		if bytes.Equal(memoLike.PkHash, candidate.Post.PkHash) {
+			continue
+		}
+		likers[string(memoLike.PkHash)] = true
+	}
+	return float64(len(likers))
+}
+
+// Total satoshis tipped to the author on likes of the post. Log scale so one large tip does not outweigh everything
+// else, a dust sized tip counts as 1.
+type TipsScorer struct{}
+
+func (TipsScorer) GetScore(candidate *Candidate) float64 {
+	var satoshis int64
+	for _, memoLike := range candidate.Likes {
+		if bytes.Equal(memoLike.TipPkHash, candidate.Post.PkHash) {
+			satoshis += memoLike.TipAmount
+		}
+	}
+	if satoshis <= 0 {
+		return 0
+	}
+	return math.Log2(1 + float64(satoshis)/float64(memo.DustMinimumOutput))
+}
+
+type RepliesScorer struct{}
+
+func (RepliesScorer) GetScore(candidate *Candidate) float64 {
+	return float64(candidate.ReplyCount)
+}
+
+// Trust of the author from self, from 0 to 1.
+type ReputationScorer struct{}
+
+func (ReputationScorer) GetScore(candidate *Candidate) float64 {
+	return candidate.AuthorTrust
+}
+
+// 1 for authors self follows plus 1 for each address self follows that liked the post.
+type ProximityScorer struct{}
+
+func (ProximityScorer) GetScore(candidate *Candidate) float64 {
+	var score = float64(candidate.FollowedLikes)
+	if candidate.FollowsAuthor {
+		score++
+	}
+	return score
+}
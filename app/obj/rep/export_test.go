@@ -0,0 +1,8 @@
+package rep
+
+// FollowGraph and its methods let tests compute trust for a graph without a db.
+type FollowGraph = followGraph
+
+var PersonalizedPageRank = followGraph.personalizedPageRank
+
+var GetGraphTrustScores = followGraph.getTrustScores
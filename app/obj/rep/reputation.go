@@ -29,6 +29,23 @@ func (r Reputation) GetTotalFollowing() int {
 	return r.rep.TotalFollowing
 }
 
+// Multi-hop trust from the trust graph, relative to the most trusted address.
+func (r Reputation) GetTrustScore() float64 {
+	return r.rep.TrustScore
+}
+
+func (r Reputation) GetTrustScoreString() string {
+	if r.rep.TrustScore == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.1f%%", r.rep.TrustScore*100)
+}
+
+// IsTrusted is false for addresses with no path from self in the follow graph.
+func (r Reputation) IsTrusted() bool {
+	return r.IsSelf || r.rep.DirectFollow || r.rep.TrustedFollowers > 0 || r.rep.TrustScore >= TrustScoreTrusted
+}
+
 func (r Reputation) GetPercentString() string {
 	if r.rep.TotalFollowing == 0 {
 		return "n/a"
@@ -46,9 +63,9 @@ func (r Reputation) GetClass() string {
 	}
 	percentage := r.GetPercentage()
 	switch {
-	case percentage > 0.20:
+	case percentage > 0.20 || r.rep.TrustScore >= TrustScoreHigh:
 		return "green"
-	case percentage > 0.05:
+	case percentage > 0.05 || r.rep.TrustScore >= TrustScoreMedium:
 		return "yellow"
 	default:
 		return "red"
@@ -73,11 +90,11 @@ func GetReputation(selfPkHash []byte, pkHash []byte) (*Reputation, error) {
 		return nil, jerr.Get("error getting reputation from cache", err)
 	}
 
-	trustedUsers, err := db.GetFollowersForPkHash(selfPkHash, -1)
+	trustedUsers, err := db.GetStore().GetFollowersForPkHash(selfPkHash, -1)
 	if err != nil {
 		return nil, jerr.Get("error getting trustedUsers", err)
 	}
-	followersToCheck, err := db.GetFollowingForPkHash(pkHash, -1)
+	followersToCheck, err := db.GetStore().GetFollowingForPkHash(pkHash, -1)
 	if err != nil {
 		return nil, jerr.Get("error getting followersToCheck", err)
 	}
@@ -105,10 +122,18 @@ TrustedFollowersLoop:
 			}
 		}
 	}
+	var trustScore float64
+	dbTrustScore, err := db.GetTrustScore(selfPkHash, pkHash)
+	if err == nil {
+		trustScore = dbTrustScore.Score
+	} else if ! db.IsRecordNotFoundError(err) {
+		return nil, jerr.Get("error getting trust score", err)
+	}
 	var rep = &cache.Reputation{
 		TrustedFollowers: len(trustedFollowers),
 		TotalFollowing:   len(deDupedTrustedUsers),
 		DirectFollow:     directFollow,
+		TrustScore:       trustScore,
 	}
 	err = cache.SetReputation(selfPkHash, pkHash, rep)
 	if err != nil {
@@ -119,3 +144,39 @@ TrustedFollowersLoop:
 		IsSelf: bytes.Equal(selfPkHash, pkHash),
 	}, nil
 }
+
+// GetTrustedPkHashes checks IsTrusted for many addresses at once, keyed by pk hash. It uses a fixed number of
+// queries instead of a reputation lookup per address.
+func GetTrustedPkHashes(selfPkHash []byte, pkHashes [][]byte) (map[string]bool, error) {
+	var trusted = make(map[string]bool)
+	if len(selfPkHash) == 0 || len(pkHashes) == 0 {
+		return trusted, nil
+	}
+	trusted[string(selfPkHash)] = true
+	trustedUsers, err := db.GetStore().GetFollowersForPkHash(selfPkHash, -1)
+	if err != nil {
+		return nil, jerr.Get("error getting trusted users", err)
+	}
+	var trustedUserPkHashes [][]byte
+	for _, trustedUser := range trustedUsers {
+		trusted[string(trustedUser.FollowPkHash)] = true
+		trustedUserPkHashes = append(trustedUserPkHashes, trustedUser.FollowPkHash)
+	}
+	trustedFollows, err := db.GetCurrentFollowsBetween(trustedUserPkHashes, pkHashes)
+	if err != nil {
+		return nil, jerr.Get("error getting trusted follows", err)
+	}
+	for _, trustedFollow := range trustedFollows {
+		trusted[string(trustedFollow.FollowPkHash)] = true
+	}
+	trustScores, err := GetTrustScores(selfPkHash, pkHashes)
+	if err != nil {
+		return nil, jerr.Get("error getting trust scores", err)
+	}
+	for pkHash, trustScore := range trustScores {
+		if trustScore >= TrustScoreTrusted {
+			trusted[pkHash] = true
+		}
+	}
+	return trusted, nil
+}
@@ -0,0 +1,148 @@
+package rep
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/cache"
+	"github.com/memocash/memo/app/db"
+	"sort"
+	"time"
+)
+
+// Trust is personalized PageRank over the follow graph, computed for each local user as the seed. Scores are
+// approximated with local pushes so only the part of the graph near the seed is visited.
+const (
+	trustInterval  = 6 * time.Hour
+	trustRestart   = 0.15
+	trustEpsilon   = 1e-5
+	trustMinScore  = 0.001
+	trustMaxScores = 2000
+)
+
+// Minimum scores for reputation classes and for an address to count as trusted.
+const (
+	TrustScoreHigh    = 0.10
+	TrustScoreMedium  = 0.01
+	TrustScoreTrusted = trustMinScore
+)
+
+type followGraph map[string][]string
+
+func UpdateTrustScoresLoop() {
+	for {
+		err := UpdateTrustScores()
+		if err != nil {
+			jerr.Get("error updating trust scores", err).Print()
+		}
+		time.Sleep(trustInterval)
+	}
+}
+
+func UpdateTrustScores() error {
+	memoFollows, err := db.GetAllCurrentFollows()
+	if err != nil {
+		return jerr.Get("error getting follows", err)
+	}
+	var graph = make(followGraph)
+	for _, memoFollow := range memoFollows {
+		pkHash := string(memoFollow.PkHash)
+		graph[pkHash] = append(graph[pkHash], string(memoFollow.FollowPkHash))
+	}
+	keys, err := db.GetStore().GetAllKeys()
+	if err != nil {
+		return jerr.Get("error getting keys", err)
+	}
+	var seen = make(map[string]bool)
+	for _, key := range keys {
+		seed := string(key.PkHash)
+		if len(seed) == 0 || seen[seed] {
+			continue
+		}
+		seen[seed] = true
+		err = db.SetTrustScores(key.PkHash, graph.getTrustScores(seed))
+		if err != nil {
+			jerr.Get("error setting trust scores", err).Print()
+			continue
+		}
+	}
+	err = cache.ClearAllReputations()
+	if err != nil {
+		return jerr.Get("error clearing reputations", err)
+	}
+	return nil
+}
+
+func (g followGraph) getTrustScores(seed string) []*db.TrustScore {
+	ranks := g.personalizedPageRank(seed)
+	delete(ranks, seed)
+	var maxRank float64
+	for _, rank := range ranks {
+		if rank > maxRank {
+			maxRank = rank
+		}
+	}
+	var trustScores []*db.TrustScore
+	for pkHash, rank := range ranks {
+		score := rank / maxRank
+		if score < trustMinScore {
+			continue
+		}
+		trustScores = append(trustScores, &db.TrustScore{
+			PkHash: []byte(pkHash),
+			Score:  score,
+		})
+	}
+	sort.Slice(trustScores, func(i, j int) bool {
+		return trustScores[i].Score > trustScores[j].Score
+	})
+	if len(trustScores) > trustMaxScores {
+		trustScores = trustScores[:trustMaxScores]
+	}
+	return trustScores
+}
+
+// Forward push approximation (Andersen, Chung, Lang). Residual mass on addresses without follows goes back to the
+// seed, same as a random walk restarting there.
+func (g followGraph) personalizedPageRank(seed string) map[string]float64 {
+	var ranks = make(map[string]float64)
+	var residuals = map[string]float64{seed: 1}
+	var queue = []string{seed}
+	var queued = map[string]bool{seed: true}
+	for len(queue) > 0 {
+		pkHash := queue[0]
+		queue = queue[1:]
+		queued[pkHash] = false
+		residual := residuals[pkHash]
+		residuals[pkHash] = 0
+		ranks[pkHash] += trustRestart * residual
+		follows := g[pkHash]
+		var targets = follows
+		if len(follows) == 0 {
+			targets = []string{seed}
+		}
+		share := (1 - trustRestart) * residual / float64(len(targets))
+		for _, target := range targets {
+			residuals[target] += share
+			if ! queued[target] && residuals[target] >= trustEpsilon*float64(len(g[target])+1) {
+				queue = append(queue, target)
+				queued[target] = true
+			}
+		}
+	}
+	return ranks
+}
+
+// GetTrustScores returns trust from self for each address with a score, keyed by pk hash.
+func GetTrustScores(selfPkHash []byte, pkHashes [][]byte) (map[string]float64, error) {
+	var trustScores = make(map[string]float64)
+	if len(selfPkHash) == 0 {
+		return trustScores, nil
+	}
+	dbTrustScores, err := db.GetTrustScores(selfPkHash, pkHashes)
+	if err != nil {
+		return nil, jerr.Get("error getting trust scores", err)
+	}
+	for _, dbTrustScore := range dbTrustScores {
+		trustScores[string(dbTrustScore.PkHash)] = dbTrustScore.Score
+	}
+	return trustScores, nil
+}
@@ -0,0 +1,64 @@
+package rep_test
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/obj/rep"
+	"math"
+	"testing"
+)
+
+// The seed follows b and c, b follows d and d follows back. Nobody reachable from the seed follows e.
+var testFollowGraph = rep.FollowGraph{
+	"a": {"b", "c"},
+	"b": {"d"},
+	"d": {"a"},
+	"e": {"b"},
+}
+
+func TestPersonalizedPageRank(t *testing.T) {
+	ranks := rep.PersonalizedPageRank(testFollowGraph, "a")
+	var total float64
+	for _, rank := range ranks {
+		total += rank
+	}
+	if math.Abs(total-1) > 0.01 {
+		t.Fatal(jerr.Newf("expected ranks to sum to 1, got %f", total))
+	}
+	if math.Abs(ranks["b"]-ranks["c"]) > 0.001 {
+		t.Fatal(jerr.Newf("expected b and c to have the same rank, got %f and %f", ranks["b"], ranks["c"]))
+	}
+	if ranks["a"] <= ranks["b"] || ranks["b"] <= ranks["d"] || ranks["d"] <= 0 {
+		t.Fatal(jerr.Newf("expected rank to fall with distance from seed, got a %f b %f d %f", ranks["a"],
+			ranks["b"], ranks["d"]))
+	}
+	if _, ok := ranks["e"]; ok {
+		t.Fatal(jerr.New("expected no rank for address not reachable from seed"))
+	}
+}
+
+func TestGetTrustScores(t *testing.T) {
+	trustScores := rep.GetGraphTrustScores(testFollowGraph, "a")
+	if len(trustScores) != 3 {
+		t.Fatal(jerr.Newf("expected 3 trust scores, got %d", len(trustScores)))
+	}
+	if trustScores[0].Score != 1 {
+		t.Fatal(jerr.Newf("expected top score to be normalized to 1, got %f", trustScores[0].Score))
+	}
+	var prevScore = math.Inf(1)
+	for _, trustScore := range trustScores {
+		pkHash := string(trustScore.PkHash)
+		if pkHash == "a" || pkHash == "e" {
+			t.Fatal(jerr.Newf("unexpected trust score for %s", pkHash))
+		}
+		if trustScore.Score > prevScore {
+			t.Fatal(jerr.New("expected trust scores sorted by score"))
+		}
+		prevScore = trustScore.Score
+	}
+	if string(trustScores[2].PkHash) != "d" {
+		t.Fatal(jerr.Newf("expected d to have the lowest score, got %s", trustScores[2].PkHash))
+	}
+	if len(rep.GetGraphTrustScores(rep.FollowGraph{}, "a")) != 0 {
+		t.Fatal(jerr.New("expected no trust scores for seed without follows"))
+	}
+}
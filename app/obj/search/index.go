@@ -0,0 +1,77 @@
+package search
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/db"
+)
+
+// UpdatePost indexes a post and its topic if they have not been indexed yet.
+func UpdatePost(txHash []byte) error {
+	memoPost, err := db.GetStore().GetMemoPost(txHash)
+	if err != nil {
+		if db.IsRecordNotFoundError(err) {
+			return nil
+		}
+		return jerr.Get("error getting memo post", err)
+	}
+	return updateMemoPost(memoPost)
+}
+
+func updateMemoPost(memoPost *db.MemoPost) error {
+	hasIndex, err := db.HasSearchIndex(db.SearchTypePost, memoPost.TxHash)
+	if err != nil {
+		return jerr.Get("error checking post search index", err)
+	}
+	if ! hasIndex {
+		err = db.SetSearchIndex(db.SearchTypePost, memoPost.TxHash, memoPost.Message)
+		if err != nil {
+			return jerr.Get("error setting post search index", err)
+		}
+	}
+	if memoPost.Topic == "" {
+		return nil
+	}
+	hasIndex, err = db.HasSearchIndex(db.SearchTypeTopic, []byte(memoPost.Topic))
+	if err != nil {
+		return jerr.Get("error checking topic search index", err)
+	}
+	if ! hasIndex {
+		err = db.SetSearchIndex(db.SearchTypeTopic, []byte(memoPost.Topic), memoPost.Topic)
+		if err != nil {
+			return jerr.Get("error setting topic search index", err)
+		}
+	}
+	return nil
+}
+
+// UpdateName indexes the current name for a user, replacing any previous name.
+func UpdateName(pkHash []byte) error {
+	memoSetName, err := db.GetNameForPkHash(pkHash)
+	if err != nil {
+		return jerr.Get("error getting name for pk hash", err)
+	}
+	if memoSetName == nil {
+		return nil
+	}
+	err = db.SetSearchIndex(db.SearchTypeName, pkHash, memoSetName.Name)
+	if err != nil {
+		return jerr.Get("error setting name search index", err)
+	}
+	return nil
+}
+
+// UpdateProfile indexes the current profile text for a user, replacing any previous profile.
+func UpdateProfile(pkHash []byte) error {
+	memoSetProfile, err := db.GetProfileForPkHash(pkHash)
+	if err != nil {
+		return jerr.Get("error getting profile for pk hash", err)
+	}
+	if memoSetProfile == nil {
+		return nil
+	}
+	err = db.SetSearchIndex(db.SearchTypeProfile, pkHash, memoSetProfile.Profile)
+	if err != nil {
+		return jerr.Get("error setting profile search index", err)
+	}
+	return nil
+}
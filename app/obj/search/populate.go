@@ -0,0 +1,79 @@
+package search
+
+import (
+	"fmt"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/db"
+)
+
+func PopulateAll() error {
+	var offset uint
+	for {
+		memoPosts, err := db.GetPosts(offset)
+		if err != nil {
+			return jerr.Get("error getting posts", err)
+		}
+		for _, memoPost := range memoPosts {
+			err = updateMemoPost(memoPost)
+			if err != nil {
+				return jerr.Get("error updating post", err)
+			}
+		}
+		offset += uint(len(memoPosts))
+		if len(memoPosts) < 25 {
+			break
+		}
+		if offset%10000 == 0 {
+			fmt.Printf("Posts indexed: %d\n", offset)
+		}
+	}
+	fmt.Printf("Posts indexed: %d\n", offset)
+	var pkHashesDone = make(map[string]bool)
+	offset = 0
+	for {
+		memoSetNames, err := db.GetSetNames(offset)
+		if err != nil {
+			return jerr.Get("error getting set names", err)
+		}
+		for _, memoSetName := range memoSetNames {
+			if pkHashesDone[string(memoSetName.PkHash)] {
+				continue
+			}
+			pkHashesDone[string(memoSetName.PkHash)] = true
+			err = UpdateName(memoSetName.PkHash)
+			if err != nil {
+				return jerr.Get("error updating name", err)
+			}
+		}
+		offset += uint(len(memoSetNames))
+		if len(memoSetNames) < 25 {
+			break
+		}
+	}
+	fmt.Printf("Names indexed: %d\n", len(pkHashesDone))
+	pkHashesDone = make(map[string]bool)
+	offset = 0
+	for {
+		memoSetProfiles, err := db.GetSetProfiles(offset)
+		if err != nil {
+			return jerr.Get("error getting set profiles", err)
+		}
+		for _, memoSetProfile := range memoSetProfiles {
+			if pkHashesDone[string(memoSetProfile.PkHash)] {
+				continue
+			}
+			pkHashesDone[string(memoSetProfile.PkHash)] = true
+			err = UpdateProfile(memoSetProfile.PkHash)
+			if err != nil {
+				return jerr.Get("error updating profile", err)
+			}
+		}
+		offset += uint(len(memoSetProfiles))
+		if len(memoSetProfiles) < 25 {
+			break
+		}
+	}
+	fmt.Printf("Profiles indexed: %d\n", len(pkHashesDone))
+	fmt.Println("All done.")
+	return nil
+}
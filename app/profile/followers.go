@@ -17,17 +17,19 @@ type Follower struct {
 }
 
 func (f *Follower) GetAddressString() string {
-	address, err := btcutil.NewAddressPubKeyHash(f.PkHash, &wallet.MainNetParamsOld)
+	address, err := btcutil.NewAddressPubKeyHash(f.PkHash, &wallet.NetParamsOld)
 	if err != nil {
 		return ""
 	}
 	return address.String()
 }
 
-func GetFollowing(selfPkHash []byte, pkHash []byte, offset int) ([]*Follower, error) {
-	memoFollows, err := db.GetFollowersForPkHash(pkHash, offset)
+// GetFollowing collapses follows and unfollows, the returned count is the number of follow rows read so pages can
+// advance past collapsed rows.
+func GetFollowing(selfPkHash []byte, pkHash []byte, offset int) ([]*Follower, int, error) {
+	memoFollows, err := db.GetStore().GetFollowersForPkHash(pkHash, offset)
 	if err != nil && ! db.IsRecordNotFoundError(err) {
-		return nil, jerr.Get("error getting memo follows for hash", err)
+		return nil, 0, jerr.Get("error getting memo follows for hash", err)
 	}
 
 	var following []*Follower
@@ -36,7 +38,7 @@ MemoFollow:
 		var name = "Unknown"
 		memoSetName, err := db.GetNameForPkHash(memoFollow.FollowPkHash)
 		if err != nil && ! db.IsRecordNotFoundError(err) {
-			return nil, jerr.Get("error getting name for pk hash", err)
+			return nil, 0, jerr.Get("error getting name for pk hash", err)
 		}
 		if memoSetName != nil {
 			name = memoSetName.Name
@@ -58,20 +60,20 @@ MemoFollow:
 			})
 		}
 	}
-	return following, nil
+	return following, len(memoFollows), nil
 }
 
-func GetFollowers(selfPkHash []byte, pkHash []byte, offset int) ([]*Follower, error) {
-	memoFollows, err := db.GetFollowingForPkHash(pkHash, offset)
+func GetFollowers(selfPkHash []byte, pkHash []byte, offset int) ([]*Follower, int, error) {
+	memoFollows, err := db.GetStore().GetFollowingForPkHash(pkHash, offset)
 	if err != nil && ! db.IsRecordNotFoundError(err) {
-		return nil, jerr.Get("error getting memo follows for hash", err)
+		return nil, 0, jerr.Get("error getting memo follows for hash", err)
 	}
 	var followers []*Follower
 	for _, memoFollow := range memoFollows {
 		var name = "Unknown"
 		memoSetName, err := db.GetNameForPkHash(memoFollow.PkHash)
 		if err != nil && ! db.IsRecordNotFoundError(err) {
-			return nil, jerr.Get("error getting name for pk hash", err)
+			return nil, 0, jerr.Get("error getting name for pk hash", err)
 		}
 		if memoSetName != nil {
 			name = memoSetName.Name
@@ -82,7 +84,7 @@ func GetFollowers(selfPkHash []byte, pkHash []byte, offset int) ([]*Follower, er
 			SelfPkHash: selfPkHash,
 		})
 	}
-	return followers, nil
+	return followers, len(memoFollows), nil
 }
 
 func AttachReputationToFollowers(followers []*Follower) error {
@@ -59,7 +59,7 @@ func (l *Like) GetTimeString(timezone string) string {
 
 func AttachLikesToPosts(posts []*Post) error {
 	for _, post := range posts {
-		memoLikes, err := db.GetMemoLikesForTxnHash(post.Memo.TxHash)
+		memoLikes, err := db.GetStore().GetMemoLikesForTxnHash(post.Memo.TxHash)
 		if err != nil {
 			return jerr.Get("error getting likes for post", err)
 		}
@@ -112,7 +112,7 @@ func AttachReputationToPosts(posts []*Post) error {
 }
 
 func GetLikesForPkHash(pkHash []byte) ([]*Like, error) {
-	memoLikes, err := db.GetMemoLikesForPkHash(pkHash)
+	memoLikes, err := db.GetStore().GetMemoLikesForPkHash(pkHash)
 	if err != nil {
 		return nil, jerr.Get("error getting memo likes from db", err)
 	}
@@ -123,7 +123,7 @@ func GetLikesForPkHash(pkHash []byte) ([]*Like, error) {
 			TxnHash:    memoLike.TxHash,
 			PostTxHash: memoLike.LikeTxHash,
 		}
-		memoPost, err := db.GetMemoPost(memoLike.LikeTxHash)
+		memoPost, err := db.GetStore().GetMemoPost(memoLike.LikeTxHash)
 		if err != nil && ! db.IsRecordNotFoundError(err) {
 			return nil, jerr.Get("error getting transaction from db", err)
 		}
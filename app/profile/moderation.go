@@ -0,0 +1,64 @@
+package profile
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/cache"
+	"github.com/memocash/memo/app/db"
+)
+
+const postHiddenErrorText = "post hidden by moderation"
+
+var postHiddenError = jerr.New(postHiddenErrorText)
+
+func IsPostHiddenError(err error) bool {
+	return jerr.HasError(err, postHiddenErrorText)
+}
+
+const topicHiddenErrorText = "topic hidden by moderation"
+
+var topicHiddenError = jerr.New(topicHiddenErrorText)
+
+func IsTopicHiddenError(err error) bool {
+	return jerr.HasError(err, topicHiddenErrorText)
+}
+
+func IsPostHidden(memoPost *db.MemoPost) (bool, error) {
+	moderationList, err := cache.GetModerationList()
+	if err != nil {
+		return false, jerr.Get("error getting moderation list", err)
+	}
+	return moderationList.IsPostHidden(memoPost), nil
+}
+
+// Single post lookups return an error for hidden posts so they can't be served by tx hash.
+func checkPostHidden(memoPost *db.MemoPost) error {
+	hidden, err := IsPostHidden(memoPost)
+	if err != nil {
+		return jerr.Get("error checking if post hidden", err)
+	}
+	if hidden {
+		return postHiddenError
+	}
+	return nil
+}
+
+// CheckTopicHidden returns a topic hidden error so hidden topics can't be viewed by name.
+func CheckTopicHidden(topic string) error {
+	moderationList, err := cache.GetModerationList()
+	if err != nil {
+		return jerr.Get("error getting moderation list", err)
+	}
+	if moderationList.IsTopicHidden(topic) {
+		return topicHiddenError
+	}
+	return nil
+}
+
+// Posts hidden by moderation are removed for every user.
+func filterHiddenPosts(dbPosts []*db.MemoPost) ([]*db.MemoPost, error) {
+	moderationList, err := cache.GetModerationList()
+	if err != nil {
+		return nil, jerr.Get("error getting moderation list", err)
+	}
+	return moderationList.FilterPosts(dbPosts), nil
+}
@@ -0,0 +1,45 @@
+package profile
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/cache"
+	"github.com/memocash/memo/app/db"
+)
+
+// GetMuteList returns an empty list for logged out users.
+func GetMuteList(selfPkHash []byte) (*db.MuteList, error) {
+	if len(selfPkHash) == 0 {
+		return &db.MuteList{}, nil
+	}
+	muteList, err := cache.GetMuteList(selfPkHash)
+	if err != nil {
+		return nil, jerr.Get("error getting mute list", err)
+	}
+	return muteList, nil
+}
+
+// filterPosts removes posts hidden by moderation or muted by the user.
+func filterPosts(selfPkHash []byte, dbPosts []*db.MemoPost) ([]*db.MemoPost, error) {
+	dbPosts, err := filterHiddenPosts(dbPosts)
+	if err != nil {
+		return nil, jerr.Get("error filtering hidden posts", err)
+	}
+	muteList, err := GetMuteList(selfPkHash)
+	if err != nil {
+		return nil, jerr.Get("error getting mute list", err)
+	}
+	return muteList.FilterPosts(dbPosts), nil
+}
+
+// filterTopicPosts ignores muted topics since the topic is being viewed directly.
+func filterTopicPosts(selfPkHash []byte, dbPosts []*db.MemoPost) ([]*db.MemoPost, error) {
+	dbPosts, err := filterHiddenPosts(dbPosts)
+	if err != nil {
+		return nil, jerr.Get("error filtering hidden posts", err)
+	}
+	muteList, err := GetMuteList(selfPkHash)
+	if err != nil {
+		return nil, jerr.Get("error getting mute list", err)
+	}
+	return muteList.FilterPostsByAuthor(dbPosts), nil
+}
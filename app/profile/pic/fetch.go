@@ -15,6 +15,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -23,19 +24,61 @@ const (
 	ResizeSm  = 24
 )
 
+// Remote images larger than this are rejected. Dimensions are checked before decoding since a small compressed
+// image can decode to a very large one.
+const (
+	MaxFetchSize = 10 * 1024 * 1024
+	MaxPixels    = 40 * 1000 * 1000
+)
+
+const fetchTimeout = 30 * time.Second
+
+var client = util.GetPublicHttpClient(fetchTimeout)
+
 // Call when a profile pic doesn't exist on the file system.
 func FetchProfilePic(url string, address string) error {
 	if ! util.ValidateImgurDirectLink(url) {
 		return jerr.New("invalid imgur link")
 	}
-	response, err := http.Get(url)
+	err := fetchAndResize(url, res.PicPath, address, []int{ResizeSm, ResizeMed, ResizeLg}, true)
+	if err != nil {
+		return jerr.Get("error fetching profile pic", err)
+	}
+	return nil
+}
+
+// Call when a picture attached to a post doesn't exist on the file system.
+func FetchPostPic(url string, txHash string) error {
+	if ! util.ValidateImageLink(url) {
+		return jerr.New("invalid image link")
+	}
+	err := fetchAndResize(url, res.PostPicPath, txHash, []int{ResizeMed, ResizeLg}, false)
+	if err != nil {
+		return jerr.Get("error fetching post pic", err)
+	}
+	return nil
+}
+
+// Square crops are saved as name-WxW.ext, otherwise aspect ratio is preserved and files are saved as name-W.ext.
+func GetResizedName(name string, width int, crop bool) string {
+	if crop {
+		return name + "-" + strconv.Itoa(width) + "x" + strconv.Itoa(width)
+	}
+	return name + "-" + strconv.Itoa(width)
+}
+
+func fetchAndResize(url string, dir string, name string, widths []int, crop bool) error {
+	response, err := client.Get(url)
 	if err != nil {
 		return jerr.Get("couldn't fetch remote image", err)
 	}
 	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return jerr.Newf("unexpected status fetching remote image (%d)", response.StatusCode)
+	}
 
-	if _, err := os.Stat(res.PicPath); os.IsNotExist(err) {
-		err = os.Mkdir(res.PicPath, 0755)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		err = os.Mkdir(dir, 0755)
 		if err != nil {
 			return jerr.Get("unable to create pic path", err)
 		}
@@ -44,105 +87,127 @@ func FetchProfilePic(url string, address string) error {
 	if strings.HasSuffix(url, "png") {
 		fileEnding = "png"
 	}
-	profilePicName := res.PicPath + address
-	file, err := os.Create(profilePicName + "." + fileEnding)
+	picName := dir + name
+	file, err := os.Create(picName + "." + fileEnding)
 	if err != nil {
 		return jerr.Get("couldn't create image file", err)
 	}
 
-	_, err = io.Copy(file, response.Body)
+	written, err := io.Copy(file, io.LimitReader(response.Body, MaxFetchSize+1))
 	if err != nil {
+		file.Close()
 		return jerr.Get("couldn't save image file", err)
 	}
 	err = file.Close()
 	if err != nil {
 		return jerr.Get("error closing file", err)
 	}
+	defer os.Remove(picName + "." + fileEnding)
+	if written > MaxFetchSize {
+		return jerr.New("remote image too large")
+	}
+	err = checkDimensions(picName + "." + fileEnding)
+	if err != nil {
+		return jerr.Get("error checking image dimensions", err)
+	}
 
 	// Resize. vipsthumbnail (super fast) integration is off by default.
 	if !config.GetFilePaths().UseVipsThumbnail {
-		file, err := os.Open(profilePicName + "." + fileEnding)
+		file, err := os.Open(picName + "." + fileEnding)
 		if err != nil {
-			return jerr.Get("couldn't open fetched profile pic", err)
+			return jerr.Get("couldn't open fetched pic", err)
 		}
+		defer file.Close()
 
 		// Decode jpeg into image.Image.
 		var img image.Image
 		if fileEnding == "jpg" {
 			img, err = jpeg.Decode(file)
 			if err != nil {
-				return jerr.Get("couldn't decode jpg profile pic", err)
+				return jerr.Get("couldn't decode jpg pic", err)
 			}
 		} else {
 			img, err = png.Decode(file)
 			if err != nil {
-				return jerr.Get("couldn't decode png profile pic", err)
+				return jerr.Get("couldn't decode png pic", err)
 			}
 		}
 
-		widths := []int{ResizeSm, ResizeMed, ResizeLg}
 		for _, width := range widths {
+			var resizedImg image.Image
+			if crop {
+				// Some square crop handling.
+				ratio := float32(img.Bounds().Max.X) / float32(img.Bounds().Max.Y)
+				ratioY := float32(img.Bounds().Max.Y) / float32(img.Bounds().Max.X)
+				if ratioY > ratio {
+					ratio = ratioY
+				}
+				resizeWidth := uint(float32(width) * ratio)
 
-			// Some square crop handling.
-			ratio := float32(img.Bounds().Max.X) / float32(img.Bounds().Max.Y)
-			ratioY := float32(img.Bounds().Max.Y) / float32(img.Bounds().Max.X)
-			if ratioY > ratio {
-				ratio = ratioY
-			}
-			resizeWidth := uint(float32(width) * ratio)
-
-			// Resize to resizeWidth using Lanczos resampling and preserve aspect ratio.
-			resizedImg := resize.Resize(resizeWidth, 0, img, resize.Lanczos3)
+				// Resize to resizeWidth using Lanczos resampling and preserve aspect ratio.
+				resizedImg = resize.Resize(resizeWidth, 0, img, resize.Lanczos3)
 
-			croppedImg, err := cutter.Crop(resizedImg, cutter.Config{
-				Width:  width,
-				Height: width,
-				Mode:   cutter.Centered,
-			})
-			if err != nil {
-				return jerr.Get("error cropping image", err)
+				resizedImg, err = cutter.Crop(resizedImg, cutter.Config{
+					Width:  width,
+					Height: width,
+					Mode:   cutter.Centered,
+				})
+				if err != nil {
+					return jerr.Get("error cropping image", err)
+				}
+			} else {
+				// Fit within width x width, never upscaling.
+				resizedImg = resize.Thumbnail(uint(width), uint(width), img, resize.Lanczos3)
 			}
 
-			out, err := os.Create(profilePicName + "-" + strconv.Itoa(width) + "x" + strconv.Itoa(width) + "." + fileEnding)
+			out, err := os.Create(GetResizedName(picName, width, crop) + "." + fileEnding)
 			if err != nil {
-				return jerr.Get("couldn't create profile pic file", err)
+				return jerr.Get("couldn't create resized pic file", err)
 			}
 
 			// Write new image to file.
 			if fileEnding == "jpg" {
-				err = jpeg.Encode(out, croppedImg, nil)
+				err = jpeg.Encode(out, resizedImg, nil)
 				if err != nil {
-					return jerr.Get("error encoding cropped image", err)
+					return jerr.Get("error encoding resized image", err)
 				}
 			} else {
-				err = png.Encode(out, croppedImg)
+				err = png.Encode(out, resizedImg)
 				if err != nil {
-					return jerr.Get("error encoding cropped image", err)
+					return jerr.Get("error encoding resized image", err)
 				}
 			}
 			err = out.Close()
 			if err != nil {
-				return jerr.Get("error saving cropped image", err)
+				return jerr.Get("error saving resized image", err)
 			}
 		}
 	} else {
-		err = ResizeExternally(profilePicName+"."+fileEnding, profilePicName+"-"+strconv.Itoa(ResizeSm)+"x"+strconv.Itoa(ResizeSm)+"."+fileEnding, ResizeSm, ResizeSm)
-		if err != nil {
-			return jerr.Get("couldn't resize image file", err)
-		}
-		err = ResizeExternally(profilePicName+"."+fileEnding, profilePicName+"-"+strconv.Itoa(ResizeMed)+"x"+strconv.Itoa(ResizeMed)+"."+fileEnding, ResizeMed, ResizeMed)
-		if err != nil {
-			return jerr.Get("couldn't resize image file", err)
-		}
-		err = ResizeExternally(profilePicName+"."+fileEnding, profilePicName+"-"+strconv.Itoa(ResizeLg)+"x"+strconv.Itoa(ResizeLg)+"."+fileEnding, ResizeLg, ResizeLg)
-		if err != nil {
-			return jerr.Get("couldn't resize image file", err)
+		for _, width := range widths {
+			err = ResizeExternally(picName+"."+fileEnding, GetResizedName(picName, width, crop)+"."+fileEnding, uint(width), uint(width), crop)
+			if err != nil {
+				return jerr.Get("couldn't resize image file", err)
+			}
 		}
 	}
-	err = os.Remove(profilePicName + "." + fileEnding)
+	return nil
+}
+
+func checkDimensions(fileName string) error {
+	file, err := os.Open(fileName)
 	if err != nil {
-		return jerr.Get("error removing profile pic", err)
+		return jerr.Get("couldn't open fetched pic", err)
+	}
+	defer file.Close()
+	imageConfig, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return jerr.Get("couldn't decode image config", err)
+	}
+	if imageConfig.Width <= 0 || imageConfig.Height <= 0 {
+		return jerr.New("invalid image dimensions")
+	}
+	if int64(imageConfig.Width)*int64(imageConfig.Height) > MaxPixels {
+		return jerr.Newf("image dimensions too large (%dx%d)", imageConfig.Width, imageConfig.Height)
 	}
-
 	return nil
 }
@@ -6,14 +6,16 @@ import (
 	"strconv"
 )
 
-func ResizeExternally(from string, to string, width uint, height uint) error {
+func ResizeExternally(from string, to string, width uint, height uint, crop bool) error {
 	var args = []string{
 		"--size", strconv.FormatUint(uint64(width), 10) + "x" +
 			strconv.FormatUint(uint64(height), 10),
 		"--output", to,
-		"--crop",
-		from,
 	}
+	if crop {
+		args = append(args, "--crop")
+	}
+	args = append(args, from)
 	path, err := exec.LookPath(config.GetFilePaths().VipsThumbnailPath)
 	if err != nil {
 		return err
@@ -0,0 +1,29 @@
+package profile
+
+import (
+	"bytes"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/db"
+)
+
+// Only pictures attached by the post's author are shown.
+func AttachPicturesToPosts(posts []*Post) error {
+	var txHashes [][]byte
+	for _, post := range posts {
+		txHashes = append(txHashes, post.Memo.TxHash)
+	}
+	attachedPictures, err := db.GetAttachedPicturesForPostTxHashes(txHashes)
+	if err != nil {
+		return jerr.Get("error getting attached pictures for posts", err)
+	}
+	for _, post := range posts {
+		post.Pictures = nil
+		for _, attachedPicture := range attachedPictures {
+			if bytes.Equal(attachedPicture.PostTxHash, post.Memo.TxHash) &&
+				bytes.Equal(attachedPicture.PkHash, post.Memo.PkHash) {
+				post.Pictures = append(post.Pictures, attachedPicture)
+			}
+		}
+	}
+	return nil
+}
@@ -2,8 +2,10 @@ package profile
 
 import (
 	"bytes"
+	"github.com/jchavannes/jgo/jerr"
 	"github.com/memocash/memo/app/bitcoin/memo"
 	"github.com/memocash/memo/app/db"
+	"sort"
 )
 
 type Poll struct {
@@ -18,8 +20,26 @@ type Option struct {
 	UniqueVotes int
 	Satoshis    int64
 }
+
+type RankRound struct {
+	Number     int
+	Options    []RankRoundOption
+	Exhausted  int
+	Eliminated string
+	Winner     string
+}
+
+type RankRoundOption struct {
+	Name  string
+	Votes int
+}
+
 func (p *Poll) IsMulti() bool {
-	return p.Question.PollType != memo.CodePollTypeSingle
+	return p.Question.PollType == memo.CodePollTypeMulti
+}
+
+func (p *Poll) IsRank() bool {
+	return p.Question.PollType == memo.CodePollTypeRank
 }
 
 func (p *Poll) CanVote() bool {
@@ -69,3 +89,147 @@ func (p *Poll) GetOptions() []Option {
 	}
 	return options
 }
+
+// Ballots are ordered lists of option tx hashes, votes without a valid ranking count as a single choice.
+func (p *Poll) getBallots() [][][]byte {
+	var optionTxHashes [][]byte
+	for _, option := range p.Question.Options {
+		optionTxHashes = append(optionTxHashes, option.TxHash)
+	}
+	var ballots [][][]byte
+	for _, vote := range p.Votes {
+		ballot := getVoteRanking(optionTxHashes, vote)
+		if len(ballot) == 0 {
+			ballot = [][]byte{vote.OptionTxHash}
+		}
+		ballots = append(ballots, ballot)
+	}
+	return ballots
+}
+
+// The option tx hash is what option totals count and tips go to, so a ranking whose first choice is a different
+// option is ignored.
+func getVoteRanking(optionTxHashes [][]byte, vote *db.MemoPollVote) [][]byte {
+	if len(vote.Ranking) == 0 {
+		return nil
+	}
+	rankedTxHashes := memo.DecodeRanking(optionTxHashes, vote.Ranking)
+	if len(rankedTxHashes) == 0 || ! bytes.Equal(rankedTxHashes[0], vote.OptionTxHash) {
+		return nil
+	}
+	return rankedTxHashes
+}
+
+// Instant-runoff tally. Each round counts every ballot for its highest ranked remaining option. If no option has a
+// majority of the non-exhausted ballots, the option(s) with the fewest votes are eliminated and the next round begins.
+func (p *Poll) GetRankRounds() []RankRound {
+	var options = p.Question.Options
+	var remaining = make(map[string]bool)
+	for _, option := range options {
+		remaining[string(option.TxHash)] = true
+	}
+	ballots := p.getBallots()
+	var history []map[string]int
+	var rounds []RankRound
+	for number := 1; len(remaining) > 0; number++ {
+		var counts = make(map[string]int)
+		var round = RankRound{Number: number}
+		var active int
+		for _, ballot := range ballots {
+			var counted bool
+			for _, txHash := range ballot {
+				if remaining[string(txHash)] {
+					counts[string(txHash)]++
+					counted = true
+					break
+				}
+			}
+			if counted {
+				active++
+			} else {
+				round.Exhausted++
+			}
+		}
+		var lowest = -1
+		for _, option := range options {
+			if ! remaining[string(option.TxHash)] {
+				continue
+			}
+			votes := counts[string(option.TxHash)]
+			round.Options = append(round.Options, RankRoundOption{
+				Name:  option.Option,
+				Votes: votes,
+			})
+			if votes*2 > active {
+				round.Winner = option.Option
+			}
+			if lowest == -1 || votes < lowest {
+				lowest = votes
+			}
+		}
+		sort.SliceStable(round.Options, func(i, j int) bool {
+			return round.Options[i].Votes > round.Options[j].Votes
+		})
+		if round.Winner != "" || active == 0 {
+			rounds = append(rounds, round)
+			break
+		}
+		var tied []*db.MemoPollOption
+		for _, option := range options {
+			if remaining[string(option.TxHash)] && counts[string(option.TxHash)] == lowest {
+				tied = append(tied, option)
+			}
+		}
+		if len(tied) == len(remaining) {
+			// Remaining options are tied.
+			rounds = append(rounds, round)
+			break
+		}
+		// Ties for last place are broken by fewest votes in earlier rounds, then by highest option tx hash.
+		for i := len(history) - 1; i >= 0 && len(tied) > 1; i-- {
+			var fewest = -1
+			for _, option := range tied {
+				if fewest == -1 || history[i][string(option.TxHash)] < fewest {
+					fewest = history[i][string(option.TxHash)]
+				}
+			}
+			var stillTied []*db.MemoPollOption
+			for _, option := range tied {
+				if history[i][string(option.TxHash)] == fewest {
+					stillTied = append(stillTied, option)
+				}
+			}
+			tied = stillTied
+		}
+		eliminated := tied[0]
+		for _, option := range tied[1:] {
+			if bytes.Compare(option.TxHash, eliminated.TxHash) > 0 {
+				eliminated = option
+			}
+		}
+		delete(remaining, string(eliminated.TxHash))
+		round.Eliminated = eliminated.Option
+		history = append(history, counts)
+		rounds = append(rounds, round)
+	}
+	return rounds
+}
+
+func GetRankRoundsForTxHash(txHash []byte) ([]RankRound, error) {
+	question, err := db.GetMemoPollQuestion(txHash)
+	if err != nil {
+		return nil, jerr.Get("error getting memo poll question", err)
+	}
+	if question.PollType != memo.CodePollTypeRank {
+		return nil, nil
+	}
+	votes, err := db.GetVotesForOptions(question.TxHash, true)
+	if err != nil && ! db.IsRecordNotFoundError(err) {
+		return nil, jerr.Get("error getting votes for options", err)
+	}
+	var poll = Poll{
+		Question: question,
+		Votes:    votes,
+	}
+	return poll.GetRankRounds(), nil
+}
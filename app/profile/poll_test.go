@@ -0,0 +1,130 @@
+package profile_test
+
+import (
+	"bytes"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/profile"
+	"testing"
+)
+
+// Options are named by letter, option A has the lowest tx hash.
+var rankOptionNames = []string{"A", "B", "C", "D"}
+
+type rankRoundTest struct {
+	Name    string
+	Options int
+	Ballots []string
+	// Option voted for by each ballot, the first ranked option if not set.
+	Choices []string
+	Rounds  []profile.RankRound
+}
+
+var rankRoundTests = []rankRoundTest{{
+	Name:    "majority in round 1",
+	Options: 2,
+	Ballots: []string{"AB", "A", "BA"},
+	Rounds: []profile.RankRound{
+		{Winner: "A"},
+	},
+}, {
+	Name:    "elimination with transfers",
+	Options: 3,
+	Ballots: []string{"A", "A", "B", "B", "CB"},
+	Rounds: []profile.RankRound{
+		{Eliminated: "C"},
+		{Winner: "B"},
+	},
+}, {
+	Name:    "exhausted ballot",
+	Options: 3,
+	Ballots: []string{"A", "A", "B", "C"},
+	Rounds: []profile.RankRound{
+		{Eliminated: "C"},
+		{Winner: "A", Exhausted: 1},
+	},
+}, {
+	// B and D tie in round 1, D has the higher tx hash. B and C tie in round 2, B had fewer votes in round 1.
+	Name:    "tie for last place",
+	Options: 4,
+	Ballots: []string{"A", "A", "A", "A", "B", "C", "C", "DB"},
+	Rounds: []profile.RankRound{
+		{Eliminated: "D"},
+		{Eliminated: "B"},
+		{Winner: "A", Exhausted: 2},
+	},
+}, {
+	Name:    "all tied in final round",
+	Options: 3,
+	Ballots: []string{"A", "A", "B", "B", "C"},
+	Rounds: []profile.RankRound{
+		{Eliminated: "C"},
+		{Exhausted: 1},
+	},
+}, {
+	// The second ballot votes for A but ranks B first, its ranking is ignored and it counts for A.
+	Name:    "ranking not matching vote",
+	Options: 2,
+	Ballots: []string{"A", "BA", "B"},
+	Choices: []string{"A", "A", "B"},
+	Rounds: []profile.RankRound{
+		{Winner: "A"},
+	},
+}}
+
+func TestGetRankRounds(t *testing.T) {
+	for _, test := range rankRoundTests {
+		var options []*db.MemoPollOption
+		var optionTxHashes = make(map[rune][]byte)
+		var allTxHashes [][]byte
+		for i, name := range rankOptionNames[:test.Options] {
+			txHash := bytes.Repeat([]byte{byte(i + 1)}, 32)
+			options = append(options, &db.MemoPollOption{
+				TxHash: txHash,
+				Option: name,
+			})
+			optionTxHashes[rune(name[0])] = txHash
+			allTxHashes = append(allTxHashes, txHash)
+		}
+		var votes []*db.MemoPollVote
+		for i, ballot := range test.Ballots {
+			var rankedTxHashes [][]byte
+			for _, name := range ballot {
+				rankedTxHashes = append(rankedTxHashes, optionTxHashes[name])
+			}
+			ranking, err := memo.EncodeRanking(allTxHashes, rankedTxHashes)
+			if err != nil {
+				t.Fatal(jerr.Getf(err, "%s: error encoding ranking", test.Name))
+			}
+			var optionTxHash = rankedTxHashes[0]
+			if len(test.Choices) > 0 {
+				optionTxHash = optionTxHashes[rune(test.Choices[i][0])]
+			}
+			votes = append(votes, &db.MemoPollVote{
+				OptionTxHash: optionTxHash,
+				Ranking:      ranking,
+			})
+		}
+		poll := profile.Poll{
+			Question: &db.MemoPollQuestion{
+				Options:  options,
+				PollType: memo.CodePollTypeRank,
+			},
+			Votes: votes,
+		}
+		rounds := poll.GetRankRounds()
+		if len(rounds) != len(test.Rounds) {
+			t.Fatal(jerr.Newf("%s: expected %d rounds, got %d", test.Name, len(test.Rounds), len(rounds)))
+		}
+		for i, round := range rounds {
+			expected := test.Rounds[i]
+			if round.Number != i+1 || round.Winner != expected.Winner || round.Eliminated != expected.Eliminated ||
+				round.Exhausted != expected.Exhausted {
+				t.Fatal(jerr.Newf("%s: round %d expected winner %q eliminated %q exhausted %d, got %q %q %d",
+					test.Name, i+1, expected.Winner, expected.Eliminated, expected.Exhausted, round.Winner,
+					round.Eliminated, round.Exhausted))
+			}
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/memocash/memo/app/bitcoin/memo"
 	"github.com/memocash/memo/app/cache"
 	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/obj/rank"
 	"github.com/memocash/memo/app/obj/rep"
 	"github.com/memocash/memo/app/util"
 	"github.com/memocash/memo/app/util/format"
@@ -28,6 +29,7 @@ type Post struct {
 	VoteQuestion *db.MemoPost
 	VoteOption   *db.MemoPollOption
 	ProfilePic   *db.MemoSetPic
+	Pictures     []*db.MemoAttachPicture
 }
 
 func (p Post) IsSelf() bool {
@@ -105,10 +107,14 @@ func (p Post) GetLastLikeId() uint {
 }
 
 func GetPostsFeed(selfPkHash []byte, offset uint) ([]*Post, error) {
-	dbPosts, err := db.GetPostsFeedForPkHash(selfPkHash, offset)
+	dbPosts, err := db.GetStore().GetPostsFeedForPkHash(selfPkHash, offset)
 	if err != nil {
 		return nil, jerr.Get("error getting posts for hash", err)
 	}
+	dbPosts, err = filterPosts(selfPkHash, dbPosts)
+	if err != nil {
+		return nil, jerr.Get("error filtering posts", err)
+	}
 	var foundPkHashes [][]byte
 	for _, dbPost := range dbPosts {
 		for _, foundPkHash := range foundPkHashes {
@@ -131,7 +137,7 @@ func GetPostsFeed(selfPkHash []byte, offset uint) ([]*Post, error) {
 	}
 	var posts []*Post
 	for _, dbPost := range dbPosts {
-		cnt, err := db.GetPostReplyCount(dbPost.TxHash)
+		cnt, err := db.GetStore().GetPostReplyCount(dbPost.TxHash)
 		if err != nil {
 			return nil, jerr.Get("error getting post reply count", err)
 		}
@@ -146,31 +152,41 @@ func GetPostsFeed(selfPkHash []byte, offset uint) ([]*Post, error) {
 		}
 		posts = append(posts, post)
 	}
+	err = AttachPicturesToPosts(posts)
+	if err != nil {
+		return nil, jerr.Get("error attaching pictures to posts", err)
+	}
 	return posts, nil
 }
 
-func GetPostsForHash(pkHash []byte, selfPkHash []byte, offset uint) ([]*Post, error) {
+// Returns the scanned count like GetRecentPosts.
+func GetPostsForHash(pkHash []byte, selfPkHash []byte, offset uint) ([]*Post, int, error) {
 	var name = ""
 	setName, err := db.GetNameForPkHash(pkHash)
 	if err != nil {
-		return nil, jerr.Get("error getting name for hash", err)
+		return nil, 0, jerr.Get("error getting name for hash", err)
 	}
 	if setName != nil {
 		name = setName.Name
 	}
 	setPic, err := db.GetPicForPkHash(pkHash)
 	if err != nil {
-		return nil, jerr.Get("error getting profile pic for hash", err)
+		return nil, 0, jerr.Get("error getting profile pic for hash", err)
 	}
-	dbPosts, err := db.GetPostsForPkHash(pkHash, offset)
+	dbPosts, err := db.GetStore().GetPostsForPkHash(pkHash, offset)
 	if err != nil {
-		return nil, jerr.Get("error getting posts for hash", err)
+		return nil, 0, jerr.Get("error getting posts for hash", err)
+	}
+	scanned := len(dbPosts)
+	dbPosts, err = filterHiddenPosts(dbPosts)
+	if err != nil {
+		return nil, 0, jerr.Get("error filtering hidden posts", err)
 	}
 	var posts []*Post
 	for _, dbPost := range dbPosts {
-		cnt, err := db.GetPostReplyCount(dbPost.TxHash)
+		cnt, err := db.GetStore().GetPostReplyCount(dbPost.TxHash)
 		if err != nil {
-			return nil, jerr.Get("error getting post reply count", err)
+			return nil, 0, jerr.Get("error getting post reply count", err)
 		}
 		post := &Post{
 			Name:       name,
@@ -183,14 +199,22 @@ func GetPostsForHash(pkHash []byte, selfPkHash []byte, offset uint) ([]*Post, er
 		}
 		posts = append(posts, post)
 	}
-	return posts, nil
+	err = AttachPicturesToPosts(posts)
+	if err != nil {
+		return nil, 0, jerr.Get("error attaching pictures to posts", err)
+	}
+	return posts, scanned, nil
 }
 
 func GetPostByTxHashWithReplies(txHash []byte, selfPkHash []byte, offset uint) (*Post, error) {
-	memoPost, err := db.GetMemoPost(txHash)
+	memoPost, err := db.GetStore().GetMemoPost(txHash)
 	if err != nil {
 		return nil, jerr.Get("error getting memo post", err)
 	}
+	err = checkPostHidden(memoPost)
+	if err != nil {
+		return nil, jerr.Get("error checking post moderation", err)
+	}
 	setName, err := db.GetNameForPkHash(memoPost.PkHash)
 	if err != nil {
 		return nil, jerr.Get("error getting name for hash", err)
@@ -203,7 +227,7 @@ func GetPostByTxHashWithReplies(txHash []byte, selfPkHash []byte, offset uint) (
 	if err != nil {
 		return nil, jerr.Get("error getting profile pic for hash", err)
 	}
-	cnt, err := db.GetPostReplyCount(txHash)
+	cnt, err := db.GetStore().GetPostReplyCount(txHash)
 	if err != nil {
 		return nil, jerr.Get("error getting post reply count", err)
 	}
@@ -216,6 +240,10 @@ func GetPostByTxHashWithReplies(txHash []byte, selfPkHash []byte, offset uint) (
 	if setPic != nil {
 		post.ProfilePic = setPic
 	}
+	err = AttachPicturesToPosts([]*Post{post})
+	if err != nil {
+		return nil, jerr.Get("error attaching pictures to post", err)
+	}
 	err = AttachRepliesToPost(post, offset)
 	if err != nil {
 		return nil, jerr.Get("error attaching replies to post", err)
@@ -224,10 +252,14 @@ func GetPostByTxHashWithReplies(txHash []byte, selfPkHash []byte, offset uint) (
 }
 
 func GetPostByTxHash(txHash []byte, selfPkHash []byte) (*Post, error) {
-	memoPost, err := db.GetMemoPost(txHash)
+	memoPost, err := db.GetStore().GetMemoPost(txHash)
 	if err != nil {
 		return nil, jerr.Get("error getting memo post", err)
 	}
+	err = checkPostHidden(memoPost)
+	if err != nil {
+		return nil, jerr.Get("error checking post moderation", err)
+	}
 	setName, err := db.GetNameForPkHash(memoPost.PkHash)
 	if err != nil {
 		return nil, jerr.Get("error getting name for hash", err)
@@ -236,7 +268,7 @@ func GetPostByTxHash(txHash []byte, selfPkHash []byte) (*Post, error) {
 	if setName != nil {
 		name = setName.Name
 	}
-	cnt, err := db.GetPostReplyCount(txHash)
+	cnt, err := db.GetStore().GetPostReplyCount(txHash)
 	if err != nil {
 		return nil, jerr.Get("error getting post reply count", err)
 	}
@@ -246,14 +278,22 @@ func GetPostByTxHash(txHash []byte, selfPkHash []byte) (*Post, error) {
 		SelfPkHash: selfPkHash,
 		ReplyCount: cnt,
 	}
+	err = AttachPicturesToPosts([]*Post{post})
+	if err != nil {
+		return nil, jerr.Get("error attaching pictures to post", err)
+	}
 	return post, nil
 }
 
 func GetPostsByTxHashes(txHashes [][]byte, selfPkHash []byte) ([]*Post, error) {
-	memoPosts, err := db.GetPostsByTxHashes(txHashes)
+	memoPosts, err := db.GetStore().GetPostsByTxHashes(txHashes)
 	if err != nil {
 		return nil, jerr.Get("error getting memo posts", err)
 	}
+	memoPosts, err = filterHiddenPosts(memoPosts)
+	if err != nil {
+		return nil, jerr.Get("error filtering hidden posts", err)
+	}
 	var namePkHashes [][]byte
 	var pollVoteTxHashes [][]byte
 	for _, memoPost := range memoPosts {
@@ -278,7 +318,7 @@ func GetPostsByTxHashes(txHashes [][]byte, selfPkHash []byte) ([]*Post, error) {
 	for _, memoPollOption := range memoPollOptions {
 		pollTxHashes = append(pollTxHashes, memoPollOption.PollTxHash)
 	}
-	memoPollQuestions, err := db.GetPostsByTxHashes(pollTxHashes)
+	memoPollQuestions, err := db.GetStore().GetPostsByTxHashes(pollTxHashes)
 	if err != nil {
 		return nil, jerr.Get("error getting poll questions for tx hashes", err)
 	}
@@ -286,7 +326,7 @@ func GetPostsByTxHashes(txHashes [][]byte, selfPkHash []byte) ([]*Post, error) {
 	if err != nil {
 		return nil, jerr.Get("error getting names for hashes", err)
 	}
-	txHashCounts, err := db.GetPostReplyCounts(txHashes)
+	txHashCounts, err := db.GetStore().GetPostReplyCounts(txHashes)
 	if err != nil {
 		return nil, jerr.Get("error getting post reply counts", err)
 	}
@@ -331,14 +371,22 @@ func GetPostsByTxHashes(txHashes [][]byte, selfPkHash []byte) ([]*Post, error) {
 			VoteOption:   voteOption,
 		})
 	}
+	err = AttachPicturesToPosts(posts)
+	if err != nil {
+		return nil, jerr.Get("error attaching pictures to posts", err)
+	}
 	return posts, nil
 }
 
 func AttachRepliesToPost(post *Post, offset uint) error {
-	replyMemoPosts, err := db.GetPostReplies(post.Memo.TxHash, offset)
+	replyMemoPosts, err := db.GetStore().GetPostReplies(post.Memo.TxHash, offset)
 	if err != nil {
 		return jerr.Get("error getting post replies", err)
 	}
+	replyMemoPosts, err = filterPosts(post.SelfPkHash, replyMemoPosts)
+	if err != nil {
+		return jerr.Get("error filtering replies", err)
+	}
 	var replies []*Post
 	for _, reply := range replyMemoPosts {
 		setName, err := db.GetNameForPkHash(reply.PkHash)
@@ -353,7 +401,7 @@ func AttachRepliesToPost(post *Post, offset uint) error {
 		if err != nil {
 			return jerr.Get("error getting profile pic for hash", err)
 		}
-		cnt, err := db.GetPostReplyCount(reply.TxHash)
+		cnt, err := db.GetStore().GetPostReplyCount(reply.TxHash)
 		if err != nil {
 			return jerr.Get("error getting post reply count", err)
 		}
@@ -368,31 +416,42 @@ func AttachRepliesToPost(post *Post, offset uint) error {
 		}
 		replies = append(replies, post)
 	}
+	err = AttachPicturesToPosts(replies)
+	if err != nil {
+		return jerr.Get("error attaching pictures to replies", err)
+	}
 	post.Replies = replies
 	return nil
 }
 
-func GetRecentPosts(selfPkHash []byte, offset uint, searchString string) ([]*Post, error) {
-	dbPosts, err := db.GetRecentPosts(offset, searchString)
+// GetRecentPosts also returns the number of posts read from the db before hidden and muted posts are removed, so
+// pages can advance past removed posts.
+func GetRecentPosts(selfPkHash []byte, offset uint, searchString string) ([]*Post, int, error) {
+	dbPosts, err := db.GetStore().GetRecentPosts(offset, searchString)
 	if err != nil {
-		return nil, jerr.Get("error getting posts for hash", err)
+		return nil, 0, jerr.Get("error getting posts for hash", err)
+	}
+	scanned := len(dbPosts)
+	dbPosts, err = filterPosts(selfPkHash, dbPosts)
+	if err != nil {
+		return nil, 0, jerr.Get("error filtering posts", err)
 	}
 	posts, err := CreatePostsFromDbPosts(selfPkHash, dbPosts)
 	if err != nil {
-		return nil, jerr.Get("error creating posts from db posts", err)
+		return nil, 0, jerr.Get("error creating posts from db posts", err)
 	}
 	err = AttachNamesToPosts(posts)
 	if err != nil {
-		return nil, jerr.Get("error attaching names to posts", err)
+		return nil, 0, jerr.Get("error attaching names to posts", err)
 	}
 	err = AttachProfilePicsToPosts(posts)
 	if err != nil {
-		return nil, jerr.Get("error attaching profile pics to posts", err)
+		return nil, 0, jerr.Get("error attaching profile pics to posts", err)
 	}
-	return posts, nil
+	return posts, scanned, nil
 }
 
-func GetTopPostsNamedRange(selfPkHash []byte, offset uint, timeRange string, personalized bool) ([]*Post, error) {
+func GetTopPostsNamedRange(selfPkHash []byte, offset uint, timeRange string, personalized bool) ([]*Post, int, error) {
 	var timeStart time.Time
 	switch timeRange {
 	case TimeRange1Hour:
@@ -407,10 +466,18 @@ func GetTopPostsNamedRange(selfPkHash []byte, offset uint, timeRange string, per
 	return GetTopPosts(selfPkHash, offset, timeStart, time.Time{}, personalized)
 }
 
-func GetRankedPosts(selfPkHash []byte, offset uint, searchString string) ([]*Post, error) {
-	memoPosts, err := db.GetRankedPosts(offset, searchString)
+func GetRankedPosts(selfPkHash []byte, offset uint, searchString string, ranker rank.Ranker) ([]*Post, error) {
+	memoPosts, err := rank.GetCandidatePosts("", searchString, rank.CandidateDays, offset)
 	if err != nil {
-		return nil, jerr.Get("error getting posts for hash", err)
+		return nil, jerr.Get("error getting rank candidate posts", err)
+	}
+	memoPosts, err = filterPosts(selfPkHash, memoPosts)
+	if err != nil {
+		return nil, jerr.Get("error filtering posts", err)
+	}
+	memoPosts, err = ranker.GetPage(selfPkHash, memoPosts, offset)
+	if err != nil {
+		return nil, jerr.Get("error ranking posts", err)
 	}
 	posts, err := CreatePostsFromDbPosts(selfPkHash, memoPosts)
 	if err != nil {
@@ -428,10 +495,14 @@ func GetRankedPosts(selfPkHash []byte, offset uint, searchString string) ([]*Pos
 }
 
 func GetPollsPosts(selfPkHash []byte, offset uint) ([]*Post, error) {
-	memoPosts, err := db.GetPollsPosts(offset)
+	memoPosts, err := db.GetStore().GetPollsPosts(offset)
 	if err != nil {
 		return nil, jerr.Get("error getting posts for hash", err)
 	}
+	memoPosts, err = filterPosts(selfPkHash, memoPosts)
+	if err != nil {
+		return nil, jerr.Get("error filtering posts", err)
+	}
 	posts, err := CreatePostsFromDbPosts(selfPkHash, memoPosts)
 	if err != nil {
 		return nil, jerr.Get("error creating posts from db posts", err)
@@ -450,7 +521,7 @@ func GetPollsPosts(selfPkHash []byte, offset uint) ([]*Post, error) {
 func CreatePostsFromDbPosts(selfPkHash []byte, dbPosts []*db.MemoPost) ([]*Post, error) {
 	var posts []*Post
 	for _, dbPost := range dbPosts {
-		cnt, err := db.GetPostReplyCount(dbPost.TxHash)
+		cnt, err := db.GetStore().GetPostReplyCount(dbPost.TxHash)
 		if err != nil {
 			return nil, jerr.Get("error getting post reply count", err)
 		}
@@ -461,6 +532,10 @@ func CreatePostsFromDbPosts(selfPkHash []byte, dbPosts []*db.MemoPost) ([]*Post,
 		}
 		posts = append(posts, post)
 	}
+	err := AttachPicturesToPosts(posts)
+	if err != nil {
+		return nil, jerr.Get("error attaching pictures to posts", err)
+	}
 	return posts, nil
 }
 
@@ -512,39 +587,79 @@ func AttachProfilePicsToPosts(posts []*Post) error {
 	return nil
 }
 
-func GetTopPosts(selfPkHash []byte, offset uint, timeStart time.Time, timeEnd time.Time, personalized bool) ([]*Post, error) {
+// Returns the scanned count like GetRecentPosts.
+func GetTopPosts(selfPkHash []byte, offset uint, timeStart time.Time, timeEnd time.Time, personalized bool) ([]*Post, int, error) {
 	var dbPosts []*db.MemoPost
 	var err error
 	if personalized {
-		dbPosts, err = db.GetPersonalizedTopPosts(selfPkHash, offset, timeStart, timeEnd)
+		dbPosts, err = db.GetStore().GetPersonalizedTopPosts(selfPkHash, offset, timeStart, timeEnd)
 		if err != nil {
-			return nil, jerr.Get("error getting posts for hash", err)
+			return nil, 0, jerr.Get("error getting posts for hash", err)
 		}
 	} else {
-		dbPosts, err = db.GetTopPosts(offset, timeStart, timeEnd)
+		dbPosts, err = db.GetStore().GetTopPosts(offset, timeStart, timeEnd)
 		if err != nil {
-			return nil, jerr.Get("error getting posts for hash", err)
+			return nil, 0, jerr.Get("error getting posts for hash", err)
 		}
 	}
+	scanned := len(dbPosts)
+	dbPosts, err = filterPosts(selfPkHash, dbPosts)
+	if err != nil {
+		return nil, 0, jerr.Get("error filtering posts", err)
+	}
 	posts, err := CreatePostsFromDbPosts(selfPkHash, dbPosts)
 	if err != nil {
-		return nil, jerr.Get("error creating posts from db posts", err)
+		return nil, 0, jerr.Get("error creating posts from db posts", err)
 	}
 	err = AttachNamesToPosts(posts)
 	if err != nil {
-		return nil, jerr.Get("error attaching names to posts", err)
+		return nil, 0, jerr.Get("error attaching names to posts", err)
 	}
 	err = AttachProfilePicsToPosts(posts)
 	if err != nil {
-		return nil, jerr.Get("error attaching profile pics to posts", err)
+		return nil, 0, jerr.Get("error attaching profile pics to posts", err)
 	}
-	return posts, nil
+	return posts, scanned, nil
 }
 
-func GetPostsForTopic(tag string, selfPkHash []byte, offset uint) ([]*Post, error) {
-	dbPosts, err := db.GetPostsForTopic(tag, offset)
+// Returns the scanned count like GetRecentPosts.
+func GetPostsForTopic(tag string, selfPkHash []byte, offset uint) ([]*Post, int, error) {
+	dbPosts, err := db.GetStore().GetPostsForTopic(tag, offset)
 	if err != nil {
-		return nil, jerr.Get("error getting posts for hash", err)
+		return nil, 0, jerr.Get("error getting posts for hash", err)
+	}
+	scanned := len(dbPosts)
+	dbPosts, err = filterTopicPosts(selfPkHash, dbPosts)
+	if err != nil {
+		return nil, 0, jerr.Get("error filtering posts", err)
+	}
+	posts, err := CreatePostsFromDbPosts(selfPkHash, dbPosts)
+	if err != nil {
+		return nil, 0, jerr.Get("error creating posts from db posts", err)
+	}
+	err = AttachNamesToPosts(posts)
+	if err != nil {
+		return nil, 0, jerr.Get("error attaching names to posts", err)
+	}
+	err = AttachProfilePicsToPosts(posts)
+	if err != nil {
+		return nil, 0, jerr.Get("error attaching profile pics to posts", err)
+	}
+	return posts, scanned, nil
+}
+
+func GetRankedPostsForTopic(tag string, selfPkHash []byte, offset uint, ranker rank.Ranker) ([]*Post, error) {
+	dbPosts, err := rank.GetCandidatePosts(tag, "", rank.TopicCandidateDays, offset)
+	if err != nil {
+		return nil, jerr.Get("error getting rank candidate posts", err)
+	}
+	dbPosts, err = filterTopicPosts(selfPkHash, dbPosts)
+	if err != nil {
+		return nil, jerr.Get("error filtering posts", err)
+	}
+	dbPosts, err = ranker.GetPage(selfPkHash, dbPosts, offset)
+	if err != nil {
+		return nil, jerr.Get("error ranking posts", err)
 	}
 	posts, err := CreatePostsFromDbPosts(selfPkHash, dbPosts)
 	if err != nil {
@@ -562,10 +677,14 @@ func GetPostsForTopic(tag string, selfPkHash []byte, offset uint) ([]*Post, erro
 }
 
 func GetOlderPostsForTopic(tag string, selfPkHash []byte, firstPostId uint) ([]*Post, error) {
-	dbPosts, err := db.GetOlderPostsForTopic(tag, firstPostId)
+	dbPosts, err := db.GetStore().GetOlderPostsForTopic(tag, firstPostId)
 	if err != nil {
 		return nil, jerr.Get("error getting posts", err)
 	}
+	dbPosts, err = filterTopicPosts(selfPkHash, dbPosts)
+	if err != nil {
+		return nil, jerr.Get("error filtering posts", err)
+	}
 	posts, err := CreatePostsFromDbPosts(selfPkHash, dbPosts)
 	if err != nil {
 		return nil, jerr.Get("error creating posts from db posts", err)
@@ -586,7 +705,7 @@ func AttachReplyCountToPosts(posts []*Post) error {
 	for _, post := range posts {
 		txHashes = append(txHashes, post.Memo.TxHash)
 	}
-	txHashCounts, err := db.GetPostReplyCounts(txHashes)
+	txHashCounts, err := db.GetStore().GetPostReplyCounts(txHashes)
 	if err != nil {
 		return jerr.Get("error getting post reply counts", err)
 	}
@@ -605,11 +724,18 @@ func AttachParentToPosts(posts []*Post) error {
 		if len(post.Memo.ParentTxHash) == 0 {
 			continue
 		}
-		parentPost, err := db.GetMemoPost(post.Memo.ParentTxHash)
+		parentPost, err := db.GetStore().GetMemoPost(post.Memo.ParentTxHash)
 		if err != nil {
 			jerr.Get("error getting memo post parent", err).Print()
 			continue
 		}
+		hidden, err := IsPostHidden(parentPost)
+		if err != nil {
+			return jerr.Get("error checking if parent post hidden", err)
+		}
+		if hidden {
+			continue
+		}
 		setName, err := db.GetNameForPkHash(parentPost.PkHash)
 		if err != nil {
 			return jerr.Get("error getting name for reply hash", err)
@@ -674,7 +800,7 @@ func AttachPollsToPosts(posts []*Post) error {
 				Question:   question,
 				SelfPkHash: post.SelfPkHash,
 			}
-			single := question.PollType == memo.CodePollTypeSingle
+			single := question.PollType != memo.CodePollTypeMulti
 			votes, err := db.GetVotesForOptions(question.TxHash, single)
 			if err != nil {
 				if db.IsRecordNotFoundError(err) {
@@ -694,7 +820,7 @@ func AttachPollsToPosts(posts []*Post) error {
 				return jerr.Get("error getting memo poll option", err)
 			}
 			post.VoteOption = memoPollOption
-			memoPost, err := db.GetMemoPost(memoPollOption.PollTxHash)
+			memoPost, err := db.GetStore().GetMemoPost(memoPollOption.PollTxHash)
 			if err != nil {
 				return jerr.Get("error getting memo poll question post", err)
 			}
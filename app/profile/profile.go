@@ -78,7 +78,7 @@ func (p Profile) GetNameTx() string {
 }
 
 func (p Profile) GetAddressString() string {
-	addr, err := btcutil.NewAddressPubKeyHash(p.PkHash, &wallet.MainNetParamsOld)
+	addr, err := btcutil.NewAddressPubKeyHash(p.PkHash, &wallet.NetParamsOld)
 	if err != nil {
 		return ""
 	}
@@ -86,11 +86,11 @@ func (p Profile) GetAddressString() string {
 }
 
 func (p Profile) GetCashAddressString() string {
-	addr, err := btcutil.NewAddressPubKeyHash(p.PkHash, &wallet.MainNetParamsOld)
+	addr, err := btcutil.NewAddressPubKeyHash(p.PkHash, &wallet.NetParamsOld)
 	if err != nil {
 		return ""
 	}
-	cashAddr, err := bchutil.NewCashAddressPubKeyHash(addr.ScriptAddress(), &wallet.MainNetParamsOld)
+	cashAddr, err := bchutil.NewCashAddressPubKeyHash(addr.ScriptAddress(), &wallet.NetParamsOld)
 	if err != nil {
 		return ""
 	}
@@ -99,7 +99,7 @@ func (p Profile) GetCashAddressString() string {
 
 func (p Profile) GetCashAddressOnlyString() string {
 	cashAddr := p.GetCashAddressString()
-	return strings.TrimPrefix(cashAddr, "bitcoincash:")
+	return strings.TrimPrefix(cashAddr, wallet.GetCashAddressPrefix()+":")
 }
 
 func (p *Profile) SetBalances() error {
@@ -114,7 +114,7 @@ func (p *Profile) SetBalances() error {
 }
 
 func (p *Profile) SetFollowerCount() error {
-	cnt, err := db.GetFollowerCountForPkHash(p.PkHash)
+	cnt, err := db.GetStore().GetFollowerCountForPkHash(p.PkHash)
 	if err != nil {
 		return jerr.Get("error getting follower count for hash", err)
 	}
@@ -123,7 +123,7 @@ func (p *Profile) SetFollowerCount() error {
 }
 
 func (p *Profile) SetFollowingCount() error {
-	cnt, err := db.GetFollowingCountForPkHash(p.PkHash)
+	cnt, err := db.GetStore().GetFollowingCountForPkHash(p.PkHash)
 	if err != nil {
 		return jerr.Get("error getting following count for hash", err)
 	}
@@ -202,8 +202,15 @@ func GetProfiles(selfPkHash []byte, searchString string, offset int, orderType d
 	if err != nil {
 		return nil, jerr.Get("error getting profiles from db", err)
 	}
+	moderationList, err := cache.GetModerationList()
+	if err != nil {
+		return nil, jerr.Get("error getting moderation list", err)
+	}
 	var profiles []*Profile
 	for _, objProfile := range objProfiles {
+		if moderationList.IsPkHashHidden(objProfile.PkHash) {
+			continue
+		}
 		profile, err := GetProfile(objProfile.PkHash, selfPkHash)
 		if err != nil {
 			return nil, jerr.Get("error getting profile for hash", err)
@@ -312,7 +319,7 @@ func GetProfileAndSetBalances(pkHash []byte, selfPkHash []byte) (*Profile, error
 }
 
 func CanFollow(pkHash []byte, selfPkHash []byte) (bool, error) {
-	isFollowing, err := db.IsFollowing(selfPkHash, pkHash)
+	isFollowing, err := db.GetStore().IsFollowing(selfPkHash, pkHash)
 	if err != nil {
 		return false, jerr.Get("error determining is follower from db", err)
 	}
@@ -7,6 +7,7 @@ import (
 	"github.com/memocash/memo/app/bitcoin/memo"
 	"github.com/memocash/memo/app/db"
 	"github.com/memocash/memo/app/util"
+	"strings"
 )
 
 type Vote struct {
@@ -43,7 +44,7 @@ func GetVotesForTxHash(txHash []byte) ([]*Vote, error) {
 	if numOptions < 2 || int(question.NumOptions) != numOptions {
 		return nil, jerr.Get("invalid question", err)
 	}
-	single := question.PollType == memo.CodePollTypeSingle
+	single := question.PollType != memo.CodePollTypeMulti
 	dbVotes, err := db.GetVotesForOptions(question.TxHash, single)
 	if err != nil {
 		if db.IsRecordNotFoundError(err) {
@@ -59,6 +60,10 @@ func GetVotesForTxHash(txHash []byte) ([]*Vote, error) {
 	if err != nil {
 		return nil, jerr.Get("error getting set names for pk hashes", err)
 	}
+	var optionTxHashes [][]byte
+	for _, option := range question.Options {
+		optionTxHashes = append(optionTxHashes, option.TxHash)
+	}
 	var votes []*Vote
 	for _, dbVote := range dbVotes {
 		var name string
@@ -76,6 +81,19 @@ func GetVotesForTxHash(txHash []byte) ([]*Vote, error) {
 				optionString = option.Option
 			}
 		}
+		if question.PollType == memo.CodePollTypeRank && len(dbVote.Ranking) > 0 {
+			var rankedOptions []string
+			for _, rankedTxHash := range getVoteRanking(optionTxHashes, dbVote) {
+				for _, option := range question.Options {
+					if bytes.Equal(option.TxHash, rankedTxHash) {
+						rankedOptions = append(rankedOptions, option.Option)
+					}
+				}
+			}
+			if len(rankedOptions) > 0 {
+				optionString = strings.Join(rankedOptions, " > ")
+			}
+		}
 		votes = append(votes, &Vote{
 			Name:    name,
 			Message: dbVote.Message,
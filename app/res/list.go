@@ -23,6 +23,8 @@ var JsFiles = []string{
 	"js/vote.js",
 	"js/modal.js",
 	"js/mini-profile.js",
+	"js/messages.js",
+	"js/admin.js",
 }
 
 var CssFiles = []string{
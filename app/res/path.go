@@ -1,5 +1,6 @@
 package res
 
 const (
-	PicPath = "web/public/img/profilepics/"
+	PicPath     = "web/public/img/profilepics/"
+	PostPicPath = "web/public/img/postpics/"
 )
@@ -18,6 +18,7 @@ const (
 	UrlNotFound        = "/404"
 	UrlMemoSetLanguage = "/set-language"
 	UrlAll             = "/all"
+	UrlSocket          = "/socket"
 
 	TmplAll              = "/index/all"
 	TmplAbout            = "/index/about"
@@ -34,6 +35,10 @@ const (
 	TmplCharts           = "/index/charts"
 )
 
+const (
+	UrlSearch = "/search"
+)
+
 const (
 	UrlSignup       = "/signup"
 	UrlSignupSubmit = "/signup-submit"
@@ -41,8 +46,9 @@ const (
 	UrlLoginSubmit  = "/login-submit"
 	UrlLogout       = "/logout"
 
-	TmplSignup = "/auth/signup"
-	TmplLogin  = "/auth/login"
+	TmplSignup       = "/auth/signup"
+	TmplSignupSubmit = "/auth/signup-submit"
+	TmplLogin        = "/auth/login"
 )
 
 const (
@@ -72,41 +78,90 @@ const (
 	UrlMemoLikeSubmit           = "/memo/like-submit"
 	UrlMemoReply                = "/memo/reply"
 	UrlMemoReplySubmit          = "/memo/reply-submit"
+	UrlMemoRepost               = "/memo/repost"
+	UrlMemoRepostSubmit         = "/memo/repost-submit"
 	UrlMemoWait                 = "/memo/wait"
 	UrlMemoWaitSubmit           = "/memo/wait-submit"
 	UrlMemoSetProfile           = "/memo/set-profile"
 	UrlMemoSetProfileSubmit     = "/memo/set-profile-submit"
 	UrlMemoSetProfilePic        = "/memo/set-profile-pic"
 	UrlMemoSetProfilePicSubmit  = "/memo/set-profile-pic-submit"
+	UrlMemoImageBaseUrl         = "/memo/set-image-base-url"
+	UrlMemoImageBaseUrlSubmit   = "/memo/set-image-base-url-submit"
+	UrlMemoAttachPicture        = "/memo/attach-picture"
+	UrlMemoAttachPictureSubmit  = "/memo/attach-picture-submit"
+	UrlMemoReport               = "/memo/report"
+	UrlMemoReportSubmit         = "/memo/report-submit"
 
 	TmplMemoPost         = "/memo/post"
 	TmplMemoPostThreaded = "/memo/post-threaded"
+	TmplMemoPostHidden   = "/memo/post-hidden"
+	TmplMemoReport       = "/memo/report"
+)
+
+const (
+	UrlProfiles                      = "/profiles"
+	UrlProfilesNew                   = "/profiles/new"
+	UrlProfilesMostActions           = "/profiles/most-actions"
+	UrlProfilesMostFollowers         = "/profiles/most-followers"
+	UrlProfileView                   = "/profile"
+	UrlProfileFollowers              = "/profile/followers"
+	UrlProfileFollowing              = "/profile/following"
+	UrlProfileSettings               = "/settings"
+	UrlProfileAccount                = "/account"
+	UrlProfileCoins                  = "/coins"
+	UrlProfileCoinsSelectSubmit      = "/coins/select-submit"
+	UrlProfileCoinsConsolidateSubmit = "/coins/consolidate-submit"
+	UrlProfileCoinsSendSubmit        = "/coins/send-submit"
+	UrlProfileCoinsTransactions      = "/coins/transactions"
+	UrlProfileSettingsSubmit         = "/settings-submit"
+	UrlProfileNotifications          = "/notifications"
+	UrlProfileTopicsFollowing        = "/profile/topics-following"
+	UrlProfileMini                   = "/profile/mini"
+	UrlProfileWebhooks               = "/settings/webhooks"
+	UrlProfileWebhookDeliveries      = "/settings/webhook-deliveries"
+	UrlProfileWebhookAddSubmit       = "/settings/webhook-add-submit"
+	UrlProfileWebhookDeleteSubmit    = "/settings/webhook-delete-submit"
+	UrlProfileMutes                  = "/settings/mutes"
+	UrlProfileMuteAddSubmit          = "/settings/mute-add-submit"
+	UrlProfileMuteDeleteSubmit       = "/settings/mute-delete-submit"
+
+	TmplProfiles                 = "/profile/all"
+	TmplProfilesNew              = "/profile/new"
+	TmplProfileSettings          = "/profile/settings"
+	TmplProfileAccount           = "/profile/account"
+	TmplProfileCoins             = "/profile/coins"
+	TmplProfileTransactions      = "/profile/transactions"
+	TmplProfileNotifications     = "/profile/notifications"
+	TmplProfilesMostActions      = "/profile/most-actions"
+	TmplProfilesMostFollowers    = "/profile/most-followers"
+	TmplProfileWebhooks          = "/profile/webhooks"
+	TmplProfileWebhookDeliveries = "/profile/webhook-deliveries"
+	TmplProfileMutes             = "/profile/mutes"
+)
+
+const (
+	UrlAdminReports                = "/admin/reports"
+	UrlAdminReportDismissSubmit    = "/admin/report-dismiss-submit"
+	UrlAdminModeration             = "/admin/moderation"
+	UrlAdminModerationAddSubmit    = "/admin/moderation-add-submit"
+	UrlAdminModerationDeleteSubmit = "/admin/moderation-delete-submit"
+
+	TmplAdminReports    = "/admin/reports"
+	TmplAdminModeration = "/admin/moderation"
 )
 
 const (
-	UrlProfiles               = "/profiles"
-	UrlProfilesNew            = "/profiles/new"
-	UrlProfilesMostActions    = "/profiles/most-actions"
-	UrlProfilesMostFollowers  = "/profiles/most-followers"
-	UrlProfileView            = "/profile"
-	UrlProfileFollowers       = "/profile/followers"
-	UrlProfileFollowing       = "/profile/following"
-	UrlProfileSettings        = "/settings"
-	UrlProfileAccount         = "/account"
-	UrlProfileCoins           = "/coins"
-	UrlProfileSettingsSubmit  = "/settings-submit"
-	UrlProfileNotifications   = "/notifications"
-	UrlProfileTopicsFollowing = "/profile/topics-following"
-	UrlProfileMini            = "/profile/mini"
-
-	TmplProfiles              = "/profile/all"
-	TmplProfilesNew           = "/profile/new"
-	TmplProfileSettings       = "/profile/settings"
-	TmplProfileAccount        = "/profile/account"
-	TmplProfileCoins          = "/profile/coins"
-	TmplProfileNotifications  = "/profile/notifications"
-	TmplProfilesMostActions   = "/profile/most-actions"
-	TmplProfilesMostFollowers = "/profile/most-followers"
+	UrlMessages          = "/messages"
+	UrlMessagesSent      = "/messages/sent"
+	UrlMessagesDecrypt   = "/messages/decrypt"
+	UrlMessagesNew       = "/messages/new"
+	UrlMessagesNewSubmit = "/messages/new-submit"
+
+	TmplMessagesInbox   = "/messages/inbox"
+	TmplMessagesSent    = "/messages/sent"
+	TmplMessagesDecrypt = "/messages/decrypt"
+	TmplMessagesNew     = "/messages/new"
 )
 
 const (
@@ -131,14 +186,16 @@ const (
 	UrlTopicsCreateSubmit = "/topics/create-submit"
 	UrlTopicView          = "/topic"
 	UrlTopicThreads       = "/topics/threads"
+	UrlTopicRanked        = "/topics/ranked"
 	UrlTopicsSocket       = "/topics/socket"
 	UrlTopicsMorePosts    = "/topics/more-posts"
 	UrlTopicsPostAjax     = "/topics/post-ajax"
 	UrlTopicsFollowSubmit = "/topics/follow-submit"
 	UrlTopicsFollowers    = "/topics/followers"
 
-	TmplTopicView = "/topics/view"
-	TmplTopicPost = "/topics/post"
+	TmplTopicView   = "/topics/view"
+	TmplTopicPost   = "/topics/post"
+	TmplTopicHidden = "/topics/hidden"
 )
 
 const (
@@ -148,6 +205,49 @@ const (
 	UrlPollVotesAjax    = "/poll/votes-ajax"
 )
 
+const (
+	UrlApiPost                = "/api/v1/post"
+	UrlApiPostsNew            = "/api/v1/posts/new"
+	UrlApiPostsRanked         = "/api/v1/posts/ranked"
+	UrlApiPostsTop            = "/api/v1/posts/top"
+	UrlApiProfile             = "/api/v1/profile"
+	UrlApiProfileFollowers    = "/api/v1/profile/followers"
+	UrlApiProfileFollowing    = "/api/v1/profile/following"
+	UrlApiProfilePosts        = "/api/v1/profile/posts"
+	UrlApiTopics              = "/api/v1/topics"
+	UrlApiTopic               = "/api/v1/topic"
+	UrlApiTopicThreads        = "/api/v1/topics/threads"
+	UrlApiPoll                = "/api/v1/poll"
+	UrlApiNotifications       = "/api/v1/notifications"
+	UrlApiNotificationsStream = "/api/v1/notifications/stream"
+	UrlApiTxUnsigned          = "/api/v1/tx/unsigned"
+	UrlApiTxSubmit            = "/api/v1/tx/submit"
+)
+
+const (
+	UrlFeedAtomProfile      = "/feeds/atom/profile"
+	UrlFeedJsonProfile      = "/feeds/json/profile"
+	UrlFeedAtomTopic        = "/feeds/atom/topic"
+	UrlFeedJsonTopic        = "/feeds/json/topic"
+	UrlFeedAtomPersonalized = "/feeds/atom/personalized"
+	UrlFeedJsonPersonalized = "/feeds/json/personalized"
+	UrlFeedAtomRanked       = "/feeds/atom/ranked"
+	UrlFeedJsonRanked       = "/feeds/json/ranked"
+	UrlFeedAtomTop          = "/feeds/atom/top"
+	UrlFeedJsonTop          = "/feeds/json/top"
+)
+
+const (
+	UrlWebFinger            = "/.well-known/webfinger"
+	UrlActivityPubActor     = "/ap/actor"
+	UrlActivityPubInbox     = "/ap/inbox"
+	UrlActivityPubOutbox    = "/ap/outbox"
+	UrlActivityPubFollowers = "/ap/followers"
+	UrlActivityPubFollowing = "/ap/following"
+	UrlActivityPubNote      = "/ap/note"
+	UrlActivityPubLike      = "/ap/like"
+)
+
 const (
 	TmplSnippetsPost                 = "/post/post"
 	TmplSnippetsPostThreaded         = "/post/post-threaded"
@@ -162,6 +262,15 @@ func GetBaseUrl(r *web.Response) string {
 	return baseUrl
 }
 
+// GetAbsoluteBaseUrl returns the base url including scheme and host, for links used outside the site.
+func GetAbsoluteBaseUrl(r *web.Response) string {
+	var scheme = "http"
+	if r.Request.HttpRequest.TLS != nil || r.Request.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Request.HttpRequest.Host + GetBaseUrl(r)
+}
+
 func GetUrlWithBaseUrl(url string, r *web.Response) string {
 	baseUrl := GetBaseUrl(r)
 	baseUrl = baseUrl[:len(baseUrl)-1]
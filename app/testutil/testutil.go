@@ -0,0 +1,124 @@
+package testutil
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/config"
+	"github.com/memocash/memo/app/db"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// UseSqlite points the db at a new temp file SQLite database. The returned func closes the connection, removes the
+// database and restores the env.
+func UseSqlite(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "memo-test")
+	if err != nil {
+		t.Fatal(jerr.Get("error creating temp dir", err))
+	}
+	restoreEnv := setEnv(map[string]string{
+		config.EnvDbType:     config.DbTypeSqlite,
+		config.EnvSqlitePath: filepath.Join(dir, "memo.db"),
+	})
+	return func() {
+		err := db.Close()
+		if err != nil {
+			t.Error(jerr.Get("error closing db", err))
+		}
+		restoreEnv()
+		os.RemoveAll(dir)
+	}
+}
+
+var missMemcacheAddress string
+
+var missMemcacheOnce sync.Once
+
+// UseMissMemcache points the cache at a memcache server that never stores anything, so cached values are always
+// read from the db. The server is shared by every test in the process, the returned func restores the env.
+func UseMissMemcache(t *testing.T) func() {
+	missMemcacheOnce.Do(func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(jerr.Get("error starting memcache listener", err))
+		}
+		missMemcacheAddress = listener.Addr().String()
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				go handleMissMemcacheConn(conn)
+			}
+		}()
+	})
+	host, port, err := net.SplitHostPort(missMemcacheAddress)
+	if err != nil {
+		t.Fatal(jerr.Get("error splitting memcache address", err))
+	}
+	return setEnv(map[string]string{
+		config.EnvMemcacheHost: host,
+		config.EnvMemcachePort: port,
+	})
+}
+
+func handleMissMemcacheConn(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "get", "gets":
+			fmt.Fprint(conn, "END\r\n")
+		case "set":
+			if len(fields) < 5 {
+				fmt.Fprint(conn, "ERROR\r\n")
+				continue
+			}
+			size, _ := strconv.Atoi(fields[4])
+			io.CopyN(ioutil.Discard, reader, int64(size+2))
+			fmt.Fprint(conn, "STORED\r\n")
+		case "delete":
+			fmt.Fprint(conn, "NOT_FOUND\r\n")
+		default:
+			fmt.Fprint(conn, "ERROR\r\n")
+		}
+	}
+}
+
+func setEnv(values map[string]string) func() {
+	var previous = make(map[string]*string)
+	for name, value := range values {
+		if oldValue, ok := os.LookupEnv(name); ok {
+			previous[name] = &oldValue
+		} else {
+			previous[name] = nil
+		}
+		os.Setenv(name, value)
+	}
+	return func() {
+		for name, oldValue := range previous {
+			if oldValue == nil {
+				os.Unsetenv(name)
+			} else {
+				os.Setenv(name, *oldValue)
+			}
+		}
+	}
+}
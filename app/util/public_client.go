@@ -0,0 +1,81 @@
+package util
+
+import (
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// Loopback, private, link-local, shared, multicast and reserved ranges. User supplied urls are never fetched from
+// these so they can't be used to reach internal services.
+var nonPublicNetworks = parseCidrs([]string{
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/128",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+})
+
+func parseCidrs(cidrs []string) []*net.IPNet {
+	var ipNets []*net.IPNet
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		ipNets = append(ipNets, ipNet)
+	}
+	return ipNets
+}
+
+func IsPublicIp(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range nonPublicNetworks {
+		if ipNet.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetPublicHttpClient returns a client that only connects to public IPs. The check runs at dial time, after DNS
+// resolution, so a public host name can't resolve to an internal address. Redirects and proxies are not used.
+func GetPublicHttpClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network string, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ! IsPublicIp(net.ParseIP(host)) {
+				return &net.AddrError{Err: "address not public", Addr: address}
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
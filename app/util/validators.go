@@ -18,3 +18,9 @@ func ValidateImgurDirectLink(url string) bool {
 	var re = regexp.MustCompile(`(^https://i\.imgur\.com/[a-zA-Z0-9]+\.(jpg|png)$)`)
 	return re.MatchString(url)
 }
+
+// Only the default https port is allowed, hosts are also checked at fetch time to be public.
+func ValidateImageLink(url string) bool {
+	var re = regexp.MustCompile(`(^https://[a-zA-Z0-9.\-]+/[^\s?#]+\.(jpg|png)$)`)
+	return re.MatchString(url)
+}
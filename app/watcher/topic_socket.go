@@ -1,29 +1,80 @@
 package watcher
 
 import (
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/jgo/jerr"
 	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/bus"
+	"github.com/memocash/memo/app/db"
 	"github.com/memocash/memo/app/html-parser"
 )
 
-type TopicSocket struct {
-	Socket     *web.Socket
-	Topic      string
-	LastPostId uint
-	LastLikeId uint
-	Error      chan error
-}
+const (
+	txSendTypePost = 1
+	txSendTypeLike = 2
+)
 
-var topicSockets []*TopicSocket
+type txSend struct {
+	Hash string
+	Type uint
+}
 
+// RegisterSocket sends any topic posts and likes newer than the last ids, then streams new ones as they are saved.
 func RegisterSocket(socket *web.Socket, topic string, lastPostId uint, lastLikeId uint) error {
 	topic = html_parser.EscapeWithEmojis(topic)
-	var topicSocket = &TopicSocket{
-		Socket:     socket,
-		Topic:      topic,
-		LastPostId: lastPostId,
-		LastLikeId: lastLikeId,
-		Error:      make(chan error),
+	subscription := bus.Subscribe(bus.TopicChannel(topic))
+	var postsSent = make(map[string]bool)
+	var write = func(hash string, sendType uint) error {
+		if sendType == txSendTypePost {
+			if postsSent[hash] {
+				return nil
+			}
+			postsSent[hash] = true
+		}
+		return socket.WriteJSON(txSend{
+			Hash: hash,
+			Type: sendType,
+		})
+	}
+	recentPosts, err := db.GetRecentPostsForTopic(topic, lastPostId)
+	if err != nil && ! db.IsRecordNotFoundError(err) {
+		subscription.Close()
+		return jerr.Get("error getting recent posts for topic", err)
+	}
+	for _, recentPost := range recentPosts {
+		err = write(recentPost.GetTransactionHashString(), txSendTypePost)
+		if err != nil {
+			subscription.Close()
+			return jerr.Get("error writing post to socket", err)
+		}
+	}
+	recentLikes, err := db.GetRecentLikesForTopic(topic, lastLikeId)
+	if err != nil && ! db.IsRecordNotFoundError(err) {
+		subscription.Close()
+		return jerr.Get("error getting recent likes for topic", err)
+	}
+	for _, recentLike := range recentLikes {
+		err = write(recentLike.GetLikeTransactionHashString(), txSendTypeLike)
+		if err != nil {
+			subscription.Close()
+			return jerr.Get("error writing like to socket", err)
+		}
 	}
-	topicSockets = append(topicSockets, topicSocket)
-	return <-topicSocket.Error
+	return streamToSocket(socket, subscription, func(event bus.Event) error {
+		switch event.Type {
+		case bus.EventPost:
+			return write(event.TxHash, txSendTypePost)
+		case bus.EventLike:
+			likeTxHash, err := chainhash.NewHashFromStr(event.TxHash)
+			if err != nil {
+				return jerr.Get("error parsing like tx hash", err)
+			}
+			memoLike, err := db.GetStore().GetMemoLike(likeTxHash.CloneBytes())
+			if err != nil {
+				return jerr.Get("error getting memo like", err)
+			}
+			return write(memoLike.GetLikeTransactionHashString(), txSendTypeLike)
+		}
+		return nil
+	})
 }
@@ -0,0 +1,140 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/util"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderEvent     = "X-Memo-Event"
+	HeaderDelivery  = "X-Memo-Delivery"
+	HeaderTimestamp = "X-Memo-Timestamp"
+	HeaderSignature = "X-Memo-Signature"
+
+	MaxAttempts = 6
+
+	initialBackoff      = time.Minute
+	requestTimeout      = 10 * time.Second
+	retryInterval       = 30 * time.Second
+	retryBatchSize      = 100
+	maxErrorLength      = 255
+	webhookRemovedError = "webhook removed"
+	signaturePrefix     = "sha256="
+)
+
+// Webhook urls are user supplied so only public addresses are dialed and redirects are not followed.
+var client = util.GetPublicHttpClient(requestTimeout)
+
+// Sign returns the hex HMAC-SHA256 of the timestamp and payload joined by a period, keyed with the webhook secret.
+// Receivers should recompute it from the X-Memo-Timestamp header and raw request body.
+func Sign(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// RetryPending attempts any failed deliveries that are due. Intended to run for the life of the process.
+func RetryPending() {
+	for {
+		err := retryPendingBatch()
+		if err != nil {
+			jerr.Get("error retrying pending webhook deliveries", err).Print()
+		}
+		time.Sleep(retryInterval)
+	}
+}
+
+func retryPendingBatch() error {
+	deliveries, err := db.GetPendingWebhookDeliveries(retryBatchSize)
+	if err != nil {
+		return jerr.Get("error getting pending webhook deliveries", err)
+	}
+	for _, delivery := range deliveries {
+		webhook, err := db.GetWebhook(delivery.WebhookId)
+		if err != nil {
+			if ! db.IsRecordNotFoundError(err) {
+				return jerr.Get("error getting webhook", err)
+			}
+			delivery.Error = webhookRemovedError
+			delivery.NextAttemptAt = nil
+			err = delivery.Save()
+			if err != nil {
+				return jerr.Get("error saving webhook delivery", err)
+			}
+			continue
+		}
+		err = deliver(webhook, delivery)
+		if err != nil {
+			jerr.Get("error delivering webhook", err).Print()
+		}
+	}
+	return nil
+}
+
+// Records the result of an attempt. Failures are retried with exponential backoff up to MaxAttempts.
+func deliver(webhook *db.Webhook, delivery *db.WebhookDelivery) error {
+	responseCode, sendErr := send(webhook, delivery)
+	var now = time.Now()
+	delivery.Attempts++
+	delivery.ResponseCode = responseCode
+	if sendErr == nil {
+		delivery.Error = ""
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+	} else {
+		delivery.Error = sendErr.Error()
+		if len(delivery.Error) > maxErrorLength {
+			delivery.Error = delivery.Error[:maxErrorLength]
+		}
+		if delivery.Attempts >= MaxAttempts {
+			delivery.NextAttemptAt = nil
+		} else {
+			nextAttemptAt := now.Add(initialBackoff << (delivery.Attempts - 1))
+			delivery.NextAttemptAt = &nextAttemptAt
+		}
+	}
+	err := delivery.Save()
+	if err != nil {
+		return jerr.Get("error saving webhook delivery", err)
+	}
+	if sendErr != nil {
+		return jerr.Get("error sending webhook", sendErr)
+	}
+	return nil
+}
+
+func send(webhook *db.Webhook, delivery *db.WebhookDelivery) (int, error) {
+	var payload = []byte(delivery.Payload)
+	if ! strings.HasPrefix(webhook.Url, "https://") {
+		return 0, jerr.New("webhook url is not https")
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request, err := http.NewRequest(http.MethodPost, webhook.Url, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, jerr.Get("error creating webhook request", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(HeaderEvent, delivery.Event)
+	request.Header.Set(HeaderDelivery, fmt.Sprintf("%d", delivery.Id))
+	request.Header.Set(HeaderTimestamp, timestamp)
+	request.Header.Set(HeaderSignature, signaturePrefix+Sign(webhook.Secret, timestamp, payload))
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, jerr.Get("error posting webhook", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response.StatusCode, jerr.Newf("unexpected webhook response code: %d", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
@@ -0,0 +1,148 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/db"
+	"time"
+)
+
+// Payload is the JSON body posted to webhooks. Address is the address the webhook is registered for and
+// FromAddress is the address that created the transaction.
+type Payload struct {
+	Event        string    `json:"event"`
+	Address      string    `json:"address"`
+	TxHash       string    `json:"tx_hash"`
+	FromAddress  string    `json:"from_address"`
+	Message      string    `json:"message,omitempty"`
+	PostTxHash   string    `json:"post_tx_hash,omitempty"`
+	ParentTxHash string    `json:"parent_tx_hash,omitempty"`
+	Topic        string    `json:"topic,omitempty"`
+	Tip          int64     `json:"tip,omitempty"`
+	Time         time.Time `json:"time"`
+}
+
+func GenerateSecret() (string, error) {
+	var secret = make([]byte, 32)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", jerr.Get("error generating webhook secret", err)
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// AddPost queues post events for followers of the author and topic message events for followers of the topic.
+func AddPost(memoPost *db.MemoPost) error {
+	payload := Payload{
+		TxHash:      memoPost.GetTransactionHashString(),
+		FromAddress: memoPost.GetAddressString(),
+		Message:     memoPost.Message,
+		Topic:       memoPost.Topic,
+		Time:        memoPost.CreatedAt,
+	}
+	if len(memoPost.ParentTxHash) > 0 {
+		payload.ParentTxHash = memoPost.GetParentTransactionHashString()
+	}
+	memoFollows, err := db.GetStore().GetFollowingForPkHash(memoPost.PkHash, -1)
+	if err != nil {
+		return jerr.Get("error getting followers for post author", err)
+	}
+	var followerPkHashes [][]byte
+	for _, memoFollow := range memoFollows {
+		followerPkHashes = append(followerPkHashes, memoFollow.PkHash)
+	}
+	err = queue(followerPkHashes, db.WebhookEventPost, memoPost.TxHash, payload)
+	if err != nil {
+		return jerr.Get("error queueing post webhooks", err)
+	}
+	if memoPost.Topic == "" {
+		return nil
+	}
+	memoTopicFollows, err := db.GetFollowersForTopic(memoPost.Topic)
+	if err != nil {
+		return jerr.Get("error getting followers for topic", err)
+	}
+	var topicFollowerPkHashes [][]byte
+	for _, memoTopicFollow := range memoTopicFollows {
+		topicFollowerPkHashes = append(topicFollowerPkHashes, memoTopicFollow.PkHash)
+	}
+	err = queue(topicFollowerPkHashes, db.WebhookEventTopicMessage, memoPost.TxHash, payload)
+	if err != nil {
+		return jerr.Get("error queueing topic message webhooks", err)
+	}
+	return nil
+}
+
+func AddReply(reply *db.MemoPost, parent *db.MemoPost) error {
+	err := queue([][]byte{parent.PkHash}, db.WebhookEventReply, reply.TxHash, Payload{
+		TxHash:       reply.GetTransactionHashString(),
+		FromAddress:  reply.GetAddressString(),
+		Message:      reply.Message,
+		ParentTxHash: parent.GetTransactionHashString(),
+		Topic:        reply.Topic,
+		Time:         reply.CreatedAt,
+	})
+	if err != nil {
+		return jerr.Get("error queueing reply webhooks", err)
+	}
+	return nil
+}
+
+func AddLike(like *db.MemoLike, post *db.MemoPost) error {
+	err := queue([][]byte{post.PkHash}, db.WebhookEventLike, like.TxHash, Payload{
+		TxHash:      like.GetTransactionHashString(),
+		FromAddress: like.GetAddressString(),
+		Message:     post.Message,
+		PostTxHash:  post.GetTransactionHashString(),
+		Tip:         like.TipAmount,
+		Time:        like.CreatedAt,
+	})
+	if err != nil {
+		return jerr.Get("error queueing like webhooks", err)
+	}
+	return nil
+}
+
+func AddFollow(follow *db.MemoFollow) error {
+	err := queue([][]byte{follow.FollowPkHash}, db.WebhookEventFollow, follow.TxHash, Payload{
+		TxHash:      follow.GetTransactionHashString(),
+		FromAddress: follow.GetAddressString(),
+		Time:        follow.CreatedAt,
+	})
+	if err != nil {
+		return jerr.Get("error queueing follow webhooks", err)
+	}
+	return nil
+}
+
+func queue(pkHashes [][]byte, event string, txHash []byte, payload Payload) error {
+	webhooks, err := db.GetWebhooksForPkHashes(pkHashes, event)
+	if err != nil {
+		return jerr.Get("error getting webhooks", err)
+	}
+	for _, webhook := range webhooks {
+		payload.Event = event
+		payload.Address = webhook.GetAddressString()
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return jerr.Get("error marshalling webhook payload", err)
+		}
+		// Attempted immediately, the retry time only applies if this process exits before the attempt finishes.
+		delivery, err := db.AddWebhookDelivery(webhook.Id, event, txHash, string(body), time.Now().Add(initialBackoff))
+		if err != nil {
+			return jerr.Get("error adding webhook delivery", err)
+		}
+		if delivery == nil {
+			continue
+		}
+		go func(webhook *db.Webhook) {
+			err := deliver(webhook, delivery)
+			if err != nil {
+				jerr.Get("error delivering webhook", err).Print()
+			}
+		}(webhook)
+	}
+	return nil
+}
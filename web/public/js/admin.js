@@ -0,0 +1,119 @@
+(function () {
+
+    /**
+     * @param {jQuery} $actionLinks
+     */
+    MemoApp.Form.AdminReports = function ($actionLinks) {
+        $actionLinks.click(function (e) {
+            e.preventDefault();
+            var $link = $(this);
+            var action = $link.attr("data-action");
+            var txHash = $link.attr("data-tx-hash");
+            var url;
+            var data;
+            if (action === "dismiss") {
+                url = MemoApp.URL.AdminReportDismissSubmit;
+                data = {
+                    txHash: txHash
+                };
+            } else {
+                var reason = prompt("Reason", $link.attr("data-reason"));
+                if (reason === null) {
+                    return;
+                }
+                url = MemoApp.URL.AdminModerationAddSubmit;
+                data = {
+                    report: txHash,
+                    reason: reason
+                };
+                if (action === "ban") {
+                    data.address = $link.attr("data-address");
+                } else {
+                    data.txHash = txHash;
+                }
+            }
+            $.ajax({
+                type: "POST",
+                url: MemoApp.GetBaseUrl() + url,
+                data: data,
+                success: function () {
+                    window.location.reload();
+                },
+                /**
+                 * @param {XMLHttpRequest} xhr
+                 */
+                error: function (xhr) {
+                    var errorMessage =
+                        "Error updating report:\nCode: " + xhr.responseText + "\n" +
+                        "If this problem persists, try refreshing the page.";
+                    MemoApp.AddAlert(errorMessage);
+                }
+            });
+        });
+    };
+
+    /**
+     * @param {jQuery} $addForm
+     * @param {jQuery} $deleteLinks
+     */
+    MemoApp.Form.AdminModeration = function ($addForm, $deleteLinks) {
+        $addForm.submit(function (e) {
+            e.preventDefault();
+            var txHash = $addForm.find("[name=txHash]").val();
+            var address = $addForm.find("[name=address]").val();
+            var topic = $addForm.find("[name=topic]").val();
+            var reason = $addForm.find("[name=reason]").val();
+            if (!txHash.length && !address.length && !topic.length) {
+                MemoApp.AddAlert("Must enter a tx hash, address or topic.");
+                return;
+            }
+            $.ajax({
+                type: "POST",
+                url: MemoApp.GetBaseUrl() + MemoApp.URL.AdminModerationAddSubmit,
+                data: {
+                    txHash: txHash,
+                    address: address,
+                    topic: topic,
+                    reason: reason
+                },
+                success: function () {
+                    window.location.reload();
+                },
+                /**
+                 * @param {XMLHttpRequest} xhr
+                 */
+                error: function (xhr) {
+                    var errorMessage =
+                        "Error hiding:\nCode: " + xhr.responseText + "\n" +
+                        "If this problem persists, try refreshing the page.";
+                    MemoApp.AddAlert(errorMessage);
+                }
+            });
+        });
+        $deleteLinks.click(function (e) {
+            e.preventDefault();
+            if (!confirm("Show this again?")) {
+                return;
+            }
+            $.ajax({
+                type: "POST",
+                url: MemoApp.GetBaseUrl() + MemoApp.URL.AdminModerationDeleteSubmit,
+                data: {
+                    id: $(this).attr("data-id")
+                },
+                success: function () {
+                    window.location.reload();
+                },
+                /**
+                 * @param {XMLHttpRequest} xhr
+                 */
+                error: function (xhr) {
+                    var errorMessage =
+                        "Error removing moderation:\nCode: " + xhr.responseText + "\n" +
+                        "If this problem persists, try refreshing the page.";
+                    MemoApp.AddAlert(errorMessage);
+                }
+            });
+        });
+    };
+})();
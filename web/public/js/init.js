@@ -180,6 +180,20 @@ var MemoApp = {
         return socket;
     };
 
+    /**
+     * Keeps the header notification count up to date as new notifications arrive.
+     */
+    MemoApp.WatchNotifications = function () {
+        if (!window.EventSource) {
+            return;
+        }
+        var source = new EventSource(MemoApp.URL.NotificationsStream);
+        source.addEventListener("notification", function (e) {
+            var data = JSON.parse(e.data);
+            $(".notifications .unread-count").text(data.unread_count.toLocaleString());
+        });
+    };
+
     MemoApp.Events = {};
 
     MemoApp.URL = {
@@ -202,12 +216,20 @@ var MemoApp = {
         MemoSetNameSubmit: "memo/set-name-submit",
         MemoSetProfileSubmit: "memo/set-profile-submit",
         MemoLikeSubmit: "memo/like-submit",
+        MemoRepostSubmit: "memo/repost-submit",
         MemoWait: "memo/wait",
         MemoWaitSubmit: "memo/wait-submit",
         PollCreateSubmit: "poll/create-submit",
         PollVoteSubmit: "poll/vote-submit",
         PollVotesAjax: "poll/votes-ajax",
         ProfileSettingsSubmit: "settings-submit",
+        ProfileWebhookAddSubmit: "settings/webhook-add-submit",
+        ProfileWebhookDeleteSubmit: "settings/webhook-delete-submit",
+        ProfileMuteAddSubmit: "settings/mute-add-submit",
+        ProfileMuteDeleteSubmit: "settings/mute-delete-submit",
+        ProfileCoinsSelectSubmit: "coins/select-submit",
+        ProfileCoinsConsolidateSubmit: "coins/consolidate-submit",
+        ProfileCoinsSendSubmit: "coins/send-submit",
         KeyChangePasswordSubmit: "key/change-password-submit",
         KeyDeleteAccountSubmit: "key/delete-account-submit",
         TopicsSocket: "topics/socket",
@@ -216,5 +238,14 @@ var MemoApp = {
         TopicsFollowSubmit: "topics/follow-submit",
         TopicsCreateSubmit: "topics/create-submit",
         MemoSetProfilePicSubmit: "memo/set-profile-pic-submit",
+        MemoSetImageBaseUrlSubmit: "memo/set-image-base-url-submit",
+        MemoAttachPictureSubmit: "memo/attach-picture-submit",
+        MemoReportSubmit: "memo/report-submit",
+        AdminReportDismissSubmit: "admin/report-dismiss-submit",
+        AdminModerationAddSubmit: "admin/moderation-add-submit",
+        AdminModerationDeleteSubmit: "admin/moderation-delete-submit",
+        NotificationsStream: "api/v1/notifications/stream",
+        MessagesDecrypt: "messages/decrypt",
+        MessagesNewSubmit: "messages/new-submit",
     };
 })();
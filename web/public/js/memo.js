@@ -596,6 +596,313 @@
             });
         });
     };
+    /**
+     * @param {jQuery} $form
+     */
+    MemoApp.Form.Repost = function ($form) {
+        var $message = $form.find("[name=message]");
+        var $msgByteCount = $form.find(".message-byte-count");
+        $message.on("input", function () {
+            setMsgByteCount();
+        });
+
+        function setMsgByteCount() {
+            var cnt = maxReplyBytes - MemoApp.utf8ByteLength($message.val());
+            $msgByteCount.html("[" + cnt + "]");
+            if (cnt < 0) {
+                $msgByteCount.addClass("red");
+            } else {
+                $msgByteCount.removeClass("red");
+            }
+        }
+
+        setMsgByteCount();
+        var submitting = false;
+        $form.submit(function (e) {
+            e.preventDefault();
+            if (submitting) {
+                return
+            }
+
+            var txHash = $form.find("[name=tx-hash]").val();
+            if (txHash.length === 0) {
+                MemoApp.AddAlert("Form error, tx hash not set.");
+                return;
+            }
+
+            var message = $message.val();
+            if (maxReplyBytes - MemoApp.utf8ByteLength(message) < 0) {
+                MemoApp.AddAlert("Maximum repost message is " + maxReplyBytes + " bytes. Note that some characters are more than 1 byte." +
+                    " Emojis are usually 4 bytes, for example.");
+                return;
+            }
+
+            var password = MemoApp.GetPassword();
+            if (!password.length) {
+                MemoApp.AddAlert("Password not set. Please re-enter and submit again.");
+                MemoApp.ReEnterPassword(function() {
+                    $form.submit();
+                });
+                return;
+            }
+
+            submitting = true;
+            $.ajax({
+                type: "POST",
+                url: MemoApp.GetBaseUrl() + MemoApp.URL.MemoRepostSubmit,
+                data: {
+                    txHash: txHash,
+                    message: message,
+                    password: password
+                },
+                success: function (txHash) {
+                    submitting = false;
+                    if (!txHash || txHash.length === 0) {
+                        MemoApp.AddAlert("Server error. Please try refreshing the page.");
+                        return
+                    }
+                    window.location = MemoApp.GetBaseUrl() + MemoApp.URL.MemoWait + "/" + txHash
+                },
+                error: function (xhr) {
+                    submitting = false;
+                    if (xhr.status === 401) {
+                        MemoApp.AddAlert("Error unlocking key. " +
+                            "Please verify your password is correct. " +
+                            "If this problem persists, please try refreshing the page.");
+                        MemoApp.ReEnterPassword(function() {
+                            $form.submit();
+                        });
+                        return;
+                    } else if (xhr.status === 402) {
+                        MemoApp.AddAlert("Please make sure your account has enough funds.");
+                        return;
+                    }
+                    var errorMessage =
+                        "Error with request (response code " + xhr.status + "):\n" +
+                        (xhr.responseText !== "" ? xhr.responseText + "\n" : "") +
+                        "If this problem persists, try refreshing the page.";
+                    MemoApp.AddAlert(errorMessage);
+                }
+            });
+        });
+    };
+    /**
+     * @param {jQuery} $form
+     */
+    MemoApp.Form.Report = function ($form) {
+        var submitting = false;
+        $form.submit(function (e) {
+            e.preventDefault();
+            if (submitting) {
+                return
+            }
+            var txHash = $form.find("[name=tx-hash]").val();
+            var reason = $form.find("[name=reason]").val();
+            if (reason.length === 0) {
+                MemoApp.AddAlert("Must enter a reason.");
+                return;
+            }
+            submitting = true;
+            $.ajax({
+                type: "POST",
+                url: MemoApp.GetBaseUrl() + MemoApp.URL.MemoReportSubmit,
+                data: {
+                    txHash: txHash,
+                    reason: reason
+                },
+                success: function () {
+                    window.location = MemoApp.GetBaseUrl() + "post/" + txHash;
+                },
+                error: function (xhr) {
+                    submitting = false;
+                    var errorMessage =
+                        "Error with request (response code " + xhr.status + "):\n" +
+                        (xhr.responseText !== "" ? xhr.responseText + "\n" : "") +
+                        "If this problem persists, try refreshing the page.";
+                    MemoApp.AddAlert(errorMessage);
+                }
+            });
+        });
+    };
+    /**
+     * @param {jQuery} $form
+     */
+    MemoApp.Form.SetImageBaseUrl = function ($form) {
+        var $url = $form.find("[name=url]");
+        var $msgByteCount = $form.find(".message-byte-count");
+        $url.on("input", function () {
+            setMsgByteCount();
+        });
+
+        function setMsgByteCount() {
+            var cnt = maxPostBytes - MemoApp.utf8ByteLength($url.val());
+            $msgByteCount.html("[" + cnt + "]");
+            if (cnt < 0) {
+                $msgByteCount.addClass("red");
+            } else {
+                $msgByteCount.removeClass("red");
+            }
+        }
+
+        setMsgByteCount();
+        var submitting = false;
+        $form.submit(function (e) {
+            e.preventDefault();
+            if (submitting) {
+                return
+            }
+
+            var url = $url.val();
+            if (url.length === 0) {
+                MemoApp.AddAlert("Must enter a URL.");
+                return;
+            }
+            if (maxPostBytes - MemoApp.utf8ByteLength(url) < 0) {
+                MemoApp.AddAlert("Maximum URL is " + maxPostBytes + " bytes.");
+                return;
+            }
+
+            var password = MemoApp.GetPassword();
+            if (!password.length) {
+                MemoApp.AddAlert("Password not set. Please re-enter and submit again.");
+                MemoApp.ReEnterPassword(function() {
+                    $form.submit();
+                });
+                return;
+            }
+
+            submitting = true;
+            $.ajax({
+                type: "POST",
+                url: MemoApp.GetBaseUrl() + MemoApp.URL.MemoSetImageBaseUrlSubmit,
+                data: {
+                    url: url,
+                    password: password
+                },
+                success: function (txHash) {
+                    submitting = false;
+                    if (!txHash || txHash.length === 0) {
+                        MemoApp.AddAlert("Server error. Please try refreshing the page.");
+                        return
+                    }
+                    window.location = MemoApp.GetBaseUrl() + MemoApp.URL.MemoWait + "/" + txHash
+                },
+                error: function (xhr) {
+                    submitting = false;
+                    if (xhr.status === 401) {
+                        MemoApp.AddAlert("Error unlocking key. " +
+                            "Please verify your password is correct. " +
+                            "If this problem persists, please try refreshing the page.");
+                        MemoApp.ReEnterPassword(function() {
+                            $form.submit();
+                        });
+                        return;
+                    } else if (xhr.status === 402) {
+                        MemoApp.AddAlert("Please make sure your account has enough funds.");
+                        return;
+                    }
+                    var errorMessage =
+                        "Error with request (response code " + xhr.status + "):\n" +
+                        (xhr.responseText !== "" ? xhr.responseText + "\n" : "") +
+                        "If this problem persists, try refreshing the page.";
+                    MemoApp.AddAlert(errorMessage);
+                }
+            });
+        });
+    };
+    /**
+     * @param {jQuery} $form
+     */
+    MemoApp.Form.AttachPicture = function ($form) {
+        var $url = $form.find("[name=url]");
+        var $msgByteCount = $form.find(".message-byte-count");
+        $url.on("input", function () {
+            setMsgByteCount();
+        });
+
+        function setMsgByteCount() {
+            var cnt = maxReplyBytes - MemoApp.utf8ByteLength($url.val());
+            $msgByteCount.html("[" + cnt + "]");
+            if (cnt < 0) {
+                $msgByteCount.addClass("red");
+            } else {
+                $msgByteCount.removeClass("red");
+            }
+        }
+
+        setMsgByteCount();
+        var submitting = false;
+        $form.submit(function (e) {
+            e.preventDefault();
+            if (submitting) {
+                return
+            }
+
+            var txHash = $form.find("[name=tx-hash]").val();
+            if (txHash.length === 0) {
+                MemoApp.AddAlert("Form error, tx hash not set.");
+                return;
+            }
+
+            var url = $url.val();
+            if (url.length === 0) {
+                MemoApp.AddAlert("Must enter a URL.");
+                return;
+            }
+            if (maxReplyBytes - MemoApp.utf8ByteLength(url) < 0) {
+                MemoApp.AddAlert("Maximum URL is " + maxReplyBytes + " bytes.");
+                return;
+            }
+
+            var password = MemoApp.GetPassword();
+            if (!password.length) {
+                MemoApp.AddAlert("Password not set. Please re-enter and submit again.");
+                MemoApp.ReEnterPassword(function() {
+                    $form.submit();
+                });
+                return;
+            }
+
+            submitting = true;
+            $.ajax({
+                type: "POST",
+                url: MemoApp.GetBaseUrl() + MemoApp.URL.MemoAttachPictureSubmit,
+                data: {
+                    txHash: txHash,
+                    url: url,
+                    password: password
+                },
+                success: function (txHash) {
+                    submitting = false;
+                    if (!txHash || txHash.length === 0) {
+                        MemoApp.AddAlert("Server error. Please try refreshing the page.");
+                        return
+                    }
+                    window.location = MemoApp.GetBaseUrl() + MemoApp.URL.MemoWait + "/" + txHash
+                },
+                error: function (xhr) {
+                    submitting = false;
+                    if (xhr.status === 401) {
+                        MemoApp.AddAlert("Error unlocking key. " +
+                            "Please verify your password is correct. " +
+                            "If this problem persists, please try refreshing the page.");
+                        MemoApp.ReEnterPassword(function() {
+                            $form.submit();
+                        });
+                        return;
+                    } else if (xhr.status === 402) {
+                        MemoApp.AddAlert("Please make sure your account has enough funds.");
+                        return;
+                    }
+                    var errorMessage =
+                        "Error with request (response code " + xhr.status + "):\n" +
+                        (xhr.responseText !== "" ? xhr.responseText + "\n" : "") +
+                        "If this problem persists, try refreshing the page.";
+                    MemoApp.AddAlert(errorMessage);
+                }
+            });
+        });
+    };
     /**
      * @param {string} txHash
      * @param {string} formHash
@@ -777,7 +1084,14 @@
                     }
                     window.location = MemoApp.GetBaseUrl() + url
                 },
-                error: function () {
+                /**
+                 * @param {XMLHttpRequest} xhr
+                 */
+                error: function (xhr) {
+                    if (xhr.status === 422) {
+                        $notify.text("Transaction rejected by the network: " + xhr.responseText);
+                        return;
+                    }
                     $notify.html(
                         "Transaction propagation taking longer than normal. " +
                         "You can continue waiting or try again. " +
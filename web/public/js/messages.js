@@ -0,0 +1,149 @@
+(function () {
+
+    var maxDirectMessageBytes = 63;
+
+    /**
+     * @param {jQuery} $form
+     */
+    MemoApp.Form.NewDirectMessage = function ($form) {
+        var $message = $form.find("[name=message]");
+        var $msgByteCount = $form.find(".message-byte-count");
+        $message.on("input", function () {
+            setMsgByteCount();
+        });
+
+        function setMsgByteCount() {
+            var cnt = maxDirectMessageBytes - MemoApp.utf8ByteLength($message.val());
+            $msgByteCount.html("[" + cnt + "]");
+            if (cnt < 0) {
+                $msgByteCount.addClass("red");
+            } else {
+                $msgByteCount.removeClass("red");
+            }
+        }
+
+        setMsgByteCount();
+        var submitting = false;
+        $form.submit(function (e) {
+            e.preventDefault();
+            if (submitting) {
+                return
+            }
+
+            var address = $form.find("[name=address]").val();
+            if (address.length === 0) {
+                MemoApp.AddAlert("Must enter an address.");
+                return;
+            }
+
+            var message = $message.val();
+            if (maxDirectMessageBytes - MemoApp.utf8ByteLength(message) < 0) {
+                MemoApp.AddAlert("Maximum message is " + maxDirectMessageBytes + " bytes. Note that some characters are more than 1 byte." +
+                    " Emojis are usually 4 bytes, for example.");
+                return;
+            }
+
+            if (message.length === 0) {
+                MemoApp.AddAlert("Must enter a message.");
+                return;
+            }
+
+            var password = MemoApp.GetPassword();
+            if (!password.length) {
+                MemoApp.AddAlert("Password not set. Please re-enter and submit again.");
+                MemoApp.ReEnterPassword(function () {
+                    $form.submit();
+                });
+                return;
+            }
+
+            submitting = true;
+            $.ajax({
+                type: "POST",
+                url: MemoApp.GetBaseUrl() + MemoApp.URL.MessagesNewSubmit,
+                data: {
+                    address: address,
+                    message: message,
+                    password: password
+                },
+                success: function (txHash) {
+                    submitting = false;
+                    if (!txHash || txHash.length === 0) {
+                        MemoApp.AddAlert("Server error. Please try refreshing the page.");
+                        return
+                    }
+                    window.location = MemoApp.GetBaseUrl() + MemoApp.URL.MemoWait + "/" + txHash
+                },
+                /**
+                 * @param {XMLHttpRequest} xhr
+                 */
+                error: function (xhr) {
+                    submitting = false;
+                    if (xhr.status === 401) {
+                        MemoApp.AddAlert("Error unlocking key. " +
+                            "Please verify your password is correct. " +
+                            "If this problem persists, please try refreshing the page.");
+                        MemoApp.ReEnterPassword(function () {
+                            $form.submit();
+                        });
+                        return;
+                    } else if (xhr.status === 402) {
+                        MemoApp.AddAlert("Please make sure your account has enough funds.");
+                        return;
+                    } else if (xhr.status === 422) {
+                        MemoApp.AddAlert(xhr.responseText);
+                        return;
+                    }
+                    var errorMessage =
+                        "Error with request (response code " + xhr.status + "):\n" +
+                        (xhr.responseText !== "" ? xhr.responseText + "\n" : "") +
+                        "If this problem persists, try refreshing the page.";
+                    MemoApp.AddAlert(errorMessage);
+                }
+            });
+        });
+    };
+    /**
+     * @param {int} offset
+     * @param {jQuery} $form
+     * @param {jQuery} $messagesDiv
+     */
+    MemoApp.Form.DecryptMessages = function (offset, $form, $messagesDiv) {
+        $form.submit(function (e) {
+            e.preventDefault();
+            var password = MemoApp.GetPassword();
+            if (!password.length) {
+                MemoApp.AddAlert("Password not set. Please re-enter and submit again.");
+                MemoApp.ReEnterPassword(function () {
+                    $form.submit();
+                });
+                return;
+            }
+
+            $.ajax({
+                type: "POST",
+                url: MemoApp.GetBaseUrl() + MemoApp.URL.MessagesDecrypt,
+                data: {
+                    offset: offset,
+                    password: password
+                },
+                success: function (messagesHtml) {
+                    $messagesDiv.html(messagesHtml);
+                },
+                /**
+                 * @param {XMLHttpRequest} xhr
+                 */
+                error: function (xhr) {
+                    if (xhr.status === 401) {
+                        MemoApp.AddAlert("Error unlocking key. Please verify your password is correct.");
+                        MemoApp.ReEnterPassword(function () {
+                            $form.submit();
+                        });
+                    } else {
+                        MemoApp.Form.ErrorHandler(xhr);
+                    }
+                }
+            });
+        });
+    };
+})();
@@ -10,6 +10,8 @@
             var defaultTip = parseInt(defaultTipRaw);
             var integrations = $form.find("[name=integrations]:checked").val();
             var theme = $form.find("[name=theme]:checked").val();
+            var feePriority = $form.find("[name=fee-priority]:checked").val();
+            var hideUntrusted = $form.find("[name=hide-untrusted]").is(":checked");
 
             if (defaultTipRaw.length > 0) {
                 if (isNaN(defaultTip)) {
@@ -31,7 +33,9 @@
                 data: {
                     defaultTip: defaultTip,
                     integrations: integrations,
-                    theme: theme
+                    theme: theme,
+                    feePriority: feePriority,
+                    hideUntrusted: hideUntrusted
                 },
                 success: function () {
                     $saved.removeClass("hidden");
@@ -51,4 +55,244 @@
             });
         });
     };
+
+    /**
+     * @param {jQuery} $addForm
+     * @param {jQuery} $deleteLinks
+     */
+    MemoApp.Form.Webhooks = function ($addForm, $deleteLinks) {
+        $addForm.submit(function (e) {
+            e.preventDefault();
+            var url = $addForm.find("[name=url]").val();
+            var events = [];
+            $addForm.find("[name=events]:checked").each(function () {
+                events.push($(this).val());
+            });
+            if (url.length === 0) {
+                MemoApp.AddAlert("Must enter a webhook URL.");
+                return;
+            }
+            if (events.length === 0) {
+                MemoApp.AddAlert("Must select at least one event.");
+                return;
+            }
+            $.ajax({
+                type: "POST",
+                url: MemoApp.GetBaseUrl() + MemoApp.URL.ProfileWebhookAddSubmit,
+                data: {
+                    url: url,
+                    events: events
+                },
+                success: function () {
+                    window.location.reload();
+                },
+                /**
+                 * @param {XMLHttpRequest} xhr
+                 */
+                error: function (xhr) {
+                    var errorMessage =
+                        "Error adding webhook:\nCode: " + xhr.responseText + "\n" +
+                        "If this problem persists, try refreshing the page.";
+                    MemoApp.AddAlert(errorMessage);
+                }
+            });
+        });
+        $deleteLinks.click(function (e) {
+            e.preventDefault();
+            if (!confirm("Delete this webhook?")) {
+                return;
+            }
+            $.ajax({
+                type: "POST",
+                url: MemoApp.GetBaseUrl() + MemoApp.URL.ProfileWebhookDeleteSubmit,
+                data: {
+                    id: $(this).attr("data-id")
+                },
+                success: function () {
+                    window.location.reload();
+                },
+                /**
+                 * @param {XMLHttpRequest} xhr
+                 */
+                error: function (xhr) {
+                    var errorMessage =
+                        "Error deleting webhook:\nCode: " + xhr.responseText + "\n" +
+                        "If this problem persists, try refreshing the page.";
+                    MemoApp.AddAlert(errorMessage);
+                }
+            });
+        });
+    };
+    /**
+     * @param {jQuery} $addForm
+     * @param {jQuery} $deleteLinks
+     */
+    MemoApp.Form.Mutes = function ($addForm, $deleteLinks) {
+        $addForm.submit(function (e) {
+            e.preventDefault();
+            var address = $addForm.find("[name=address]").val();
+            var topic = $addForm.find("[name=topic]").val();
+            var block = $addForm.find("[name=block]").is(":checked");
+            if ((address.length === 0) === (topic.length === 0)) {
+                MemoApp.AddAlert("Must enter an address or a topic.");
+                return;
+            }
+            $.ajax({
+                type: "POST",
+                url: MemoApp.GetBaseUrl() + MemoApp.URL.ProfileMuteAddSubmit,
+                data: {
+                    address: address,
+                    topic: topic,
+                    block: block
+                },
+                success: function () {
+                    window.location = MemoApp.GetBaseUrl() + "settings/mutes";
+                },
+                /**
+                 * @param {XMLHttpRequest} xhr
+                 */
+                error: function (xhr) {
+                    var errorMessage =
+                        "Error muting:\nCode: " + xhr.responseText + "\n" +
+                        "If this problem persists, try refreshing the page.";
+                    MemoApp.AddAlert(errorMessage);
+                }
+            });
+        });
+        $deleteLinks.click(function (e) {
+            e.preventDefault();
+            $.ajax({
+                type: "POST",
+                url: MemoApp.GetBaseUrl() + MemoApp.URL.ProfileMuteDeleteSubmit,
+                data: {
+                    id: $(this).attr("data-id")
+                },
+                success: function () {
+                    window.location.reload();
+                },
+                /**
+                 * @param {XMLHttpRequest} xhr
+                 */
+                error: function (xhr) {
+                    var errorMessage =
+                        "Error removing mute:\nCode: " + xhr.responseText + "\n" +
+                        "If this problem persists, try refreshing the page.";
+                    MemoApp.AddAlert(errorMessage);
+                }
+            });
+        });
+    };
+    /**
+     * @param {jQuery} $selectForm
+     * @param {jQuery} $consolidateForm
+     * @param {jQuery} $sendForm
+     */
+    MemoApp.Form.Coins = function ($selectForm, $consolidateForm, $sendForm) {
+        $selectForm.submit(function (e) {
+            e.preventDefault();
+            var coins = [];
+            $selectForm.find("[name=coins]:checked").each(function () {
+                coins.push($(this).val());
+            });
+            $.ajax({
+                type: "POST",
+                url: MemoApp.GetBaseUrl() + MemoApp.URL.ProfileCoinsSelectSubmit,
+                data: {
+                    coins: coins
+                },
+                success: function () {
+                    window.location.reload();
+                },
+                /**
+                 * @param {XMLHttpRequest} xhr
+                 */
+                error: function (xhr) {
+                    var errorMessage =
+                        "Error selecting coins:\nCode: " + xhr.responseText + "\n" +
+                        "If this problem persists, try refreshing the page.";
+                    MemoApp.AddAlert(errorMessage);
+                }
+            });
+        });
+
+        /**
+         * @param {jQuery} $form
+         * @param {string} url
+         * @param {object} data
+         */
+        function submitTx($form, url, data) {
+            var password = MemoApp.GetPassword();
+            if (!password.length) {
+                MemoApp.AddAlert("Password not set. Please re-enter and submit again.");
+                MemoApp.ReEnterPassword(function () {
+                    $form.submit();
+                });
+                return;
+            }
+            data.password = password;
+            var $submit = $form.find("[type=submit]");
+            $submit.prop("disabled", true);
+            $.ajax({
+                type: "POST",
+                url: MemoApp.GetBaseUrl() + url,
+                data: data,
+                success: function () {
+                    window.location.reload();
+                },
+                /**
+                 * @param {XMLHttpRequest} xhr
+                 */
+                error: function (xhr) {
+                    $submit.prop("disabled", false);
+                    if (xhr.status === 401) {
+                        MemoApp.AddAlert("Error unlocking key. " +
+                            "Please verify your password is correct. " +
+                            "If this problem persists, please try refreshing the page.");
+                        MemoApp.ReEnterPassword(function () {
+                            $form.submit();
+                        });
+                        return;
+                    } else if (xhr.status === 402) {
+                        MemoApp.AddAlert("Please make sure your account has enough funds.");
+                        return;
+                    } else if (xhr.status === 422) {
+                        MemoApp.AddAlert(xhr.responseText);
+                        return;
+                    }
+                    var errorMessage =
+                        "Error with request:\nCode: " + xhr.responseText + "\n" +
+                        "If this problem persists, try refreshing the page.";
+                    MemoApp.AddAlert(errorMessage);
+                }
+            });
+        }
+
+        $consolidateForm.submit(function (e) {
+            e.preventDefault();
+            submitTx($consolidateForm, MemoApp.URL.ProfileCoinsConsolidateSubmit, {
+                feeRate: $consolidateForm.find("[name=feeRate]").val()
+            });
+        });
+        $sendForm.submit(function (e) {
+            e.preventDefault();
+            var address = $sendForm.find("[name=address]").val();
+            var amount = $sendForm.find("[name=amount]").val();
+            if (address.length === 0) {
+                MemoApp.AddAlert("Must enter an address.");
+                return;
+            }
+            if (amount.length === 0) {
+                MemoApp.AddAlert("Must enter an amount.");
+                return;
+            }
+            if (!confirm("Send " + amount + " satoshis to " + address + "?")) {
+                return;
+            }
+            submitTx($sendForm, MemoApp.URL.ProfileCoinsSendSubmit, {
+                address: address,
+                amount: amount,
+                feeRate: $sendForm.find("[name=feeRate]").val()
+            });
+        });
+    };
 })();
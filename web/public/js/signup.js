@@ -50,16 +50,14 @@
     /**
      * @param {jQuery} $form
      * @param {jQuery} $privateKeyField
+     * @param {jQuery} $mnemonicField
      */
-    MemoApp.Form.Signup = function ($form, $privateKeyField) {
+    MemoApp.Form.Signup = function ($form, $privateKeyField, $mnemonicField) {
         var $radio = $form.find("[name=key-type]");
         $radio.change(function () {
-            console.log($radio.val());
-            if ($radio.filter(':checked').val() === "import") {
-                $privateKeyField.show();
-            } else {
-                $privateKeyField.hide();
-            }
+            var keyType = $radio.filter(':checked').val();
+            $privateKeyField.toggle(keyType === "import");
+            $mnemonicField.toggle(keyType === "restore");
         });
 
         var $passwordWarning = $form.find("#password-warning")
@@ -108,19 +106,28 @@
                 }
             }
 
+            var mnemonic;
+            if ($radio.filter(':checked').val() === "restore") {
+                mnemonic = $form.find("[name=mnemonic]").val();
+                if (mnemonic.trim().length === 0) {
+                    MemoApp.AddAlert("Must enter a recovery phrase to restore.");
+                    return;
+                }
+            }
+
             $.ajax({
                 type: "POST",
                 url: MemoApp.GetBaseUrl() + MemoApp.URL.SignupSubmit,
                 data: {
                     username: username,
                     password: password,
-                    wif: privateKey
+                    wif: privateKey,
+                    mnemonic: mnemonic
                 },
-                success: function () {
+                success: function (html) {
                     MemoApp.SetPassword(password);
-                    setTimeout(function() {
-                        window.location = MemoApp.GetBaseUrl() + MemoApp.URL.Index
-                    });
+                    $form.html(html);
+                    $form.find(".signup-continue").attr("href", MemoApp.GetBaseUrl() + MemoApp.URL.Index);
                 },
                 /**
                  * @param {XMLHttpRequest} xhr
@@ -128,7 +135,7 @@
                 error: function (xhr) {
                     switch (xhr.status) {
                         case 422:
-                            MemoApp.AddAlert("Could not parse the WIF. Please check the WIF and try again.");
+                            MemoApp.AddAlert("Could not parse the WIF or recovery phrase. Please check it and try again.");
                             return;
                         case 403:
                             MemoApp.AddAlert("Username is not available. Please try a different username.");
@@ -59,10 +59,40 @@
                 return
             }
 
-            var option = $form.find("[name=option]:checked").val();
-            if (!option || option.length === 0) {
-                MemoApp.AddAlert("Please select an option.");
-                return;
+            var $ranks = $form.find("[name=rank]");
+            var option;
+            var ranking = [];
+            if ($ranks.length) {
+                var ranked = [];
+                for (var i = 0; i < $ranks.length; i++) {
+                    var rank = parseInt($ranks.eq(i).val());
+                    if (isNaN(rank)) {
+                        continue;
+                    }
+                    for (var j = 0; j < ranked.length; j++) {
+                        if (ranked[j].rank === rank) {
+                            MemoApp.AddAlert("Each rank can only be used once.");
+                            return;
+                        }
+                    }
+                    ranked.push({rank: rank, option: $ranks.eq(i).data("option")});
+                }
+                if (ranked.length === 0) {
+                    MemoApp.AddAlert("Please rank at least one option.");
+                    return;
+                }
+                ranked.sort(function (a, b) {
+                    return a.rank - b.rank;
+                });
+                for (var k = 0; k < ranked.length; k++) {
+                    ranking.push(String(ranked[k].option));
+                }
+            } else {
+                option = $form.find("[name=option]:checked").val();
+                if (!option || option.length === 0) {
+                    MemoApp.AddAlert("Please select an option.");
+                    return;
+                }
             }
 
             var tip = $form.find("[name=tip]").val();
@@ -87,16 +117,17 @@
                 return;
             }
 
-            postForm(option, tip, message, password);
+            postForm(option, ranking, tip, message, password);
         }
 
         /**
          * @param {string} option
+         * @param {[string]} ranking
          * @param {string} tip
          * @param {string} message
          * @param {string} password
          */
-        function postForm(option, tip, message, password) {
+        function postForm(option, ranking, tip, message, password) {
             submitting = true;
             $form.addClass("hidden");
             $results.removeClass("hidden");
@@ -107,6 +138,7 @@
                 data: {
                     txHash: txHash,
                     option: option,
+                    ranking: ranking,
                     tip: tip,
                     message: message,
                     password: password
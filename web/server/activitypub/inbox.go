@@ -0,0 +1,48 @@
+package activitypub
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/activitypub"
+	"github.com/memocash/memo/app/res"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+const maxInboxBodySize = 1 << 20
+
+var inboxRoute = web.Route{
+	Pattern: res.UrlActivityPubInbox + "/" + urlAddress.UrlPart(),
+	Handler: func(r *web.Response) {
+		if r.Request.HttpRequest.Method != http.MethodPost {
+			r.SetResponseCode(http.StatusMethodNotAllowed)
+			return
+		}
+		pkHash := getPkHash(r)
+		if len(pkHash) == 0 {
+			r.Error(jerr.New("invalid address"), http.StatusNotFound)
+			return
+		}
+		body, err := ioutil.ReadAll(io.LimitReader(r.Request.HttpRequest.Body, maxInboxBodySize))
+		if err != nil {
+			r.Error(jerr.Get("error reading inbox body", err), http.StatusBadRequest)
+			return
+		}
+		signer, err := activitypub.VerifyRequest(r.Request.HttpRequest, body)
+		if err != nil {
+			r.Error(jerr.Get("error verifying inbox request", err), http.StatusUnauthorized)
+			return
+		}
+		err = activitypub.HandleInbox(getBaseUrl(r), pkHash, signer, body)
+		if err != nil {
+			if activitypub.IsInvalidActivityError(err) {
+				r.Error(jerr.Get("invalid inbox activity", err), http.StatusBadRequest)
+				return
+			}
+			r.Error(jerr.Get("error handling inbox activity", err), http.StatusInternalServerError)
+			return
+		}
+		r.SetResponseCode(http.StatusAccepted)
+	},
+}
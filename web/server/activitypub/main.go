@@ -0,0 +1,59 @@
+package activitypub
+
+import (
+	"encoding/json"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/activitypub"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"github.com/memocash/memo/app/config"
+	"github.com/memocash/memo/app/res"
+	"net/http"
+)
+
+var urlAddress = web.UrlParam{
+	Id:   "address",
+	Type: web.UrlParamString,
+}
+
+var urlTxHash = web.UrlParam{
+	Id:   "txHash",
+	Type: web.UrlParamString,
+}
+
+func GetRoutes() []web.Route {
+	return []web.Route{
+		webFingerRoute,
+		actorRoute,
+		outboxRoute,
+		followersRoute,
+		followingRoute,
+		noteRoute,
+		likeRoute,
+		inboxRoute,
+	}
+}
+
+// Ids must stay the same for remote servers, so a configured base url is preferred over the request host.
+func getBaseUrl(r *web.Response) string {
+	baseUrl := config.GetActivityPubBaseUrl()
+	if baseUrl != "" {
+		return baseUrl
+	}
+	return res.GetAbsoluteBaseUrl(r)
+}
+
+func getPkHash(r *web.Response) []byte {
+	address := wallet.GetAddressFromString(r.Request.GetUrlNamedQueryVariable(urlAddress.Id))
+	return address.GetScriptAddress()
+}
+
+func writeActivityJson(r *web.Response, contentType string, data interface{}) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		r.Error(jerr.Get("error marshalling activity pub json", err), http.StatusInternalServerError)
+		return
+	}
+	r.Writer.Header().Set("Content-Type", contentType)
+	r.Write(string(body))
+}
@@ -0,0 +1,155 @@
+package activitypub
+
+import (
+	"github.com/jchavannes/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/activitypub"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/profile"
+	"github.com/memocash/memo/app/res"
+	"net/http"
+	"net/url"
+)
+
+var webFingerRoute = web.Route{
+	Pattern: res.UrlWebFinger,
+	Handler: func(r *web.Response) {
+		baseUrl := getBaseUrl(r)
+		host := r.Request.HttpRequest.Host
+		if parsedUrl, err := url.Parse(baseUrl); err == nil && parsedUrl.Host != "" {
+			host = parsedUrl.Host
+		}
+		webFinger, err := activitypub.GetWebFinger(baseUrl, host, r.Request.GetUrlParameter("resource"))
+		if err != nil {
+			r.Error(jerr.Get("error getting webfinger", err), http.StatusNotFound)
+			return
+		}
+		writeActivityJson(r, "application/jrd+json", webFinger)
+	},
+}
+
+var actorRoute = web.Route{
+	Pattern: res.UrlActivityPubActor + "/" + urlAddress.UrlPart(),
+	Handler: func(r *web.Response) {
+		pkHash := getPkHash(r)
+		if len(pkHash) == 0 {
+			r.Error(jerr.New("invalid address"), http.StatusNotFound)
+			return
+		}
+		actor, err := activitypub.GetActor(getBaseUrl(r), pkHash)
+		if err != nil {
+			if activitypub.IsActorNotFoundError(err) {
+				r.Error(jerr.Get("error actor not found", err), http.StatusNotFound)
+				return
+			}
+			r.Error(jerr.Get("error getting actor", err), http.StatusInternalServerError)
+			return
+		}
+		writeActivityJson(r, activitypub.ContentType, actor)
+	},
+}
+
+var outboxRoute = web.Route{
+	Pattern: res.UrlActivityPubOutbox + "/" + urlAddress.UrlPart(),
+	Handler: func(r *web.Response) {
+		pkHash := getPkHash(r)
+		if len(pkHash) == 0 {
+			r.Error(jerr.New("invalid address"), http.StatusNotFound)
+			return
+		}
+		var outbox *activitypub.OrderedCollection
+		var err error
+		page := r.Request.GetUrlParameterUInt("page")
+		if page == 0 {
+			outbox, err = activitypub.GetOutbox(getBaseUrl(r), pkHash)
+		} else {
+			outbox, err = activitypub.GetOutboxPage(getBaseUrl(r), pkHash, page)
+		}
+		if err != nil {
+			r.Error(jerr.Get("error getting outbox", err), http.StatusInternalServerError)
+			return
+		}
+		writeActivityJson(r, activitypub.ContentType, outbox)
+	},
+}
+
+var followersRoute = web.Route{
+	Pattern: res.UrlActivityPubFollowers + "/" + urlAddress.UrlPart(),
+	Handler: func(r *web.Response) {
+		pkHash := getPkHash(r)
+		if len(pkHash) == 0 {
+			r.Error(jerr.New("invalid address"), http.StatusNotFound)
+			return
+		}
+		followers, err := activitypub.GetFollowers(getBaseUrl(r), pkHash)
+		if err != nil {
+			r.Error(jerr.Get("error getting followers", err), http.StatusInternalServerError)
+			return
+		}
+		writeActivityJson(r, activitypub.ContentType, followers)
+	},
+}
+
+var followingRoute = web.Route{
+	Pattern: res.UrlActivityPubFollowing + "/" + urlAddress.UrlPart(),
+	Handler: func(r *web.Response) {
+		pkHash := getPkHash(r)
+		if len(pkHash) == 0 {
+			r.Error(jerr.New("invalid address"), http.StatusNotFound)
+			return
+		}
+		following, err := activitypub.GetFollowing(getBaseUrl(r), pkHash)
+		if err != nil {
+			r.Error(jerr.Get("error getting following", err), http.StatusInternalServerError)
+			return
+		}
+		writeActivityJson(r, activitypub.ContentType, following)
+	},
+}
+
+var noteRoute = web.Route{
+	Pattern: res.UrlActivityPubNote + "/" + urlTxHash.UrlPart(),
+	Handler: func(r *web.Response) {
+		txHash, err := chainhash.NewHashFromStr(r.Request.GetUrlNamedQueryVariable(urlTxHash.Id))
+		if err != nil {
+			r.Error(jerr.Get("error parsing transaction hash", err), http.StatusNotFound)
+			return
+		}
+		note, err := activitypub.GetNote(getBaseUrl(r), txHash.CloneBytes())
+		if err != nil {
+			if db.IsRecordNotFoundError(err) {
+				r.Error(jerr.Get("post not found", err), http.StatusNotFound)
+				return
+			}
+			if profile.IsPostHiddenError(err) {
+				r.Error(jerr.Get("post hidden", err), http.StatusGone)
+				return
+			}
+			r.Error(jerr.Get("error getting note", err), http.StatusInternalServerError)
+			return
+		}
+		writeActivityJson(r, activitypub.ContentType, note)
+	},
+}
+
+var likeRoute = web.Route{
+	Pattern: res.UrlActivityPubLike + "/" + urlTxHash.UrlPart(),
+	Handler: func(r *web.Response) {
+		txHash, err := chainhash.NewHashFromStr(r.Request.GetUrlNamedQueryVariable(urlTxHash.Id))
+		if err != nil {
+			r.Error(jerr.Get("error parsing transaction hash", err), http.StatusNotFound)
+			return
+		}
+		like, err := activitypub.GetLike(getBaseUrl(r), txHash.CloneBytes())
+		if err != nil {
+			if db.IsRecordNotFoundError(err) {
+				r.Error(jerr.Get("like not found", err), http.StatusNotFound)
+				return
+			}
+			r.Error(jerr.Get("error getting like", err), http.StatusInternalServerError)
+			return
+		}
+		writeActivityJson(r, activitypub.ContentType, like)
+	},
+}
@@ -0,0 +1,33 @@
+package admin
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/auth"
+	"github.com/memocash/memo/app/db"
+	"net/http"
+)
+
+func GetRoutes() []web.Route {
+	return []web.Route{
+		reportsRoute,
+		reportDismissSubmitRoute,
+		moderationRoute,
+		moderationAddSubmitRoute,
+		moderationDeleteSubmitRoute,
+	}
+}
+
+// getAdminUser writes an error and returns nil if the session user is not an admin.
+func getAdminUser(r *web.Response) *db.User {
+	user, err := auth.GetSessionUser(r.Session.CookieId)
+	if err != nil {
+		r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+		return nil
+	}
+	if ! user.Admin {
+		r.Error(jerr.New("user is not an admin"), http.StatusForbidden)
+		return nil
+	}
+	return user
+}
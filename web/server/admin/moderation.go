@@ -0,0 +1,137 @@
+package admin
+
+import (
+	"github.com/jchavannes/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"github.com/memocash/memo/app/cache"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/res"
+	"net/http"
+)
+
+const maxModerationReasonSize = 255
+
+var moderationRoute = web.Route{
+	Pattern:    res.UrlAdminModeration,
+	NeedsLogin: true,
+	Handler: func(r *web.Response) {
+		if getAdminUser(r) == nil {
+			return
+		}
+		moderationList, err := db.GetModerationList()
+		if err != nil {
+			r.Error(jerr.Get("error getting moderation list", err), http.StatusInternalServerError)
+			return
+		}
+		r.Helper["Hides"] = moderationList.Hides
+		r.RenderTemplate(res.TmplAdminModeration)
+	},
+}
+
+// Exactly one of a post tx hash, address or topic is hidden. Open reports for the post are resolved, as is the report
+// the action was taken from.
+var moderationAddSubmitRoute = web.Route{
+	Pattern:     res.UrlAdminModerationAddSubmit,
+	NeedsLogin:  true,
+	CsrfProtect: true,
+	Handler: func(r *web.Response) {
+		txHashString := r.Request.GetFormValue("txHash")
+		addressString := r.Request.GetFormValue("address")
+		topic := r.Request.GetFormValue("topic")
+		reportTxHashString := r.Request.GetFormValue("report")
+		reason := r.Request.GetFormValue("reason")
+		var setCount int
+		for _, value := range []string{txHashString, addressString, topic} {
+			if value != "" {
+				setCount++
+			}
+		}
+		if setCount != 1 {
+			r.Error(jerr.New("must enter one of a tx hash, address or topic"), http.StatusUnprocessableEntity)
+			return
+		}
+		if len(reason) > maxModerationReasonSize {
+			r.Error(jerr.New("reason too long"), http.StatusUnprocessableEntity)
+			return
+		}
+		if len(topic) > memo.MaxTagMessageSize {
+			r.Error(jerr.New("topic too long"), http.StatusUnprocessableEntity)
+			return
+		}
+		var txHash []byte
+		if txHashString != "" {
+			hash, err := chainhash.NewHashFromStr(txHashString)
+			if err != nil {
+				r.Error(jerr.Get("error parsing transaction hash", err), http.StatusUnprocessableEntity)
+				return
+			}
+			txHash = hash.CloneBytes()
+		}
+		var pkHash []byte
+		if addressString != "" {
+			address := wallet.GetAddressFromString(addressString)
+			if address.GetEncoded() != addressString {
+				r.Error(jerr.New("error parsing address"), http.StatusUnprocessableEntity)
+				return
+			}
+			pkHash = address.GetScriptAddress()
+		}
+		var reportTxHash []byte
+		if reportTxHashString != "" {
+			hash, err := chainhash.NewHashFromStr(reportTxHashString)
+			if err != nil {
+				r.Error(jerr.Get("error parsing report transaction hash", err), http.StatusUnprocessableEntity)
+				return
+			}
+			reportTxHash = hash.CloneBytes()
+		} else if len(txHash) > 0 {
+			reportTxHash = txHash
+		}
+		user := getAdminUser(r)
+		if user == nil {
+			return
+		}
+		_, err := db.AddModeration(user.Id, txHash, pkHash, topic, reason)
+		if err != nil {
+			r.Error(jerr.Get("error adding moderation", err), http.StatusInternalServerError)
+			return
+		}
+		err = cache.ClearModerationList()
+		if err != nil {
+			r.Error(jerr.Get("error clearing moderation list cache", err), http.StatusInternalServerError)
+			return
+		}
+		if len(reportTxHash) > 0 {
+			err = db.ResolvePostReports(reportTxHash)
+			if err != nil {
+				r.Error(jerr.Get("error resolving post reports", err), http.StatusInternalServerError)
+				return
+			}
+		}
+	},
+}
+
+var moderationDeleteSubmitRoute = web.Route{
+	Pattern:     res.UrlAdminModerationDeleteSubmit,
+	NeedsLogin:  true,
+	CsrfProtect: true,
+	Handler: func(r *web.Response) {
+		id := r.Request.GetFormValueUint("id")
+		if getAdminUser(r) == nil {
+			return
+		}
+		err := db.DeleteModeration(id)
+		if err != nil {
+			r.Error(jerr.Get("error deleting moderation", err), http.StatusUnprocessableEntity)
+			return
+		}
+		err = cache.ClearModerationList()
+		if err != nil {
+			r.Error(jerr.Get("error clearing moderation list cache", err), http.StatusInternalServerError)
+			return
+		}
+	},
+}
@@ -0,0 +1,86 @@
+package admin
+
+import (
+	"bytes"
+	"github.com/jchavannes/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/res"
+	"net/http"
+)
+
+type report struct {
+	PostReport *db.PostReport
+	Post       *db.MemoPost
+	Username   string
+}
+
+var reportsRoute = web.Route{
+	Pattern:    res.UrlAdminReports,
+	NeedsLogin: true,
+	Handler: func(r *web.Response) {
+		offset := r.Request.GetUrlParameterInt("offset")
+		if getAdminUser(r) == nil {
+			return
+		}
+		postReports, err := db.GetOpenPostReports(uint(offset))
+		if err != nil {
+			r.Error(jerr.Get("error getting open post reports", err), http.StatusInternalServerError)
+			return
+		}
+		var txHashes [][]byte
+		for _, postReport := range postReports {
+			txHashes = append(txHashes, postReport.TxHash)
+		}
+		memoPosts, err := db.GetStore().GetPostsByTxHashes(txHashes)
+		if err != nil {
+			r.Error(jerr.Get("error getting reported posts", err), http.StatusInternalServerError)
+			return
+		}
+		var reports []*report
+		for _, postReport := range postReports {
+			var rpt = &report{
+				PostReport: postReport,
+			}
+			for _, memoPost := range memoPosts {
+				if bytes.Equal(memoPost.TxHash, postReport.TxHash) {
+					rpt.Post = memoPost
+				}
+			}
+			user, err := db.GetUserById(postReport.UserId)
+			if err != nil && ! db.IsRecordNotFoundError(err) {
+				r.Error(jerr.Get("error getting reporting user", err), http.StatusInternalServerError)
+				return
+			}
+			if user != nil {
+				rpt.Username = user.Username
+			}
+			reports = append(reports, rpt)
+		}
+		r.Helper["Reports"] = reports
+		res.SetPageAndOffset(r, offset)
+		r.RenderTemplate(res.TmplAdminReports)
+	},
+}
+
+var reportDismissSubmitRoute = web.Route{
+	Pattern:     res.UrlAdminReportDismissSubmit,
+	NeedsLogin:  true,
+	CsrfProtect: true,
+	Handler: func(r *web.Response) {
+		txHash, err := chainhash.NewHashFromStr(r.Request.GetFormValue("txHash"))
+		if err != nil {
+			r.Error(jerr.Get("error parsing transaction hash", err), http.StatusUnprocessableEntity)
+			return
+		}
+		if getAdminUser(r) == nil {
+			return
+		}
+		err = db.ResolvePostReports(txHash.CloneBytes())
+		if err != nil {
+			r.Error(jerr.Get("error resolving post reports", err), http.StatusInternalServerError)
+			return
+		}
+	},
+}
@@ -0,0 +1,4 @@
+package api
+
+// GetPostErrorStatus lets tests check the status returned for single post lookups.
+var GetPostErrorStatus = getPostErrorStatus
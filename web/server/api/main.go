@@ -0,0 +1,39 @@
+package api
+
+import "github.com/jchavannes/jgo/web"
+
+var urlAddress = web.UrlParam{
+	Id:   "address",
+	Type: web.UrlParamString,
+}
+
+var urlTxHash = web.UrlParam{
+	Id:   "tx-hash",
+	Type: web.UrlParamString,
+}
+
+var urlTopicName = web.UrlParam{
+	Id:   "topicName",
+	Type: web.UrlParamAny,
+}
+
+func GetRoutes() []web.Route {
+	return []web.Route{
+		postRoute,
+		postsNewRoute,
+		postsRankedRoute,
+		postsTopRoute,
+		profileRoute,
+		profileFollowersRoute,
+		profileFollowingRoute,
+		profilePostsRoute,
+		topicsRoute,
+		topicPostsRoute,
+		topicThreadsRoute,
+		pollRoute,
+		notificationsRoute,
+		notificationsStreamRoute,
+		txUnsignedRoute,
+		txSubmitRoute,
+	}
+}
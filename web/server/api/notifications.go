@@ -0,0 +1,125 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jchavannes/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/auth"
+	"github.com/memocash/memo/app/bus"
+	"github.com/memocash/memo/app/cache"
+	"github.com/memocash/memo/app/notify"
+	"github.com/memocash/memo/app/res"
+	"net/http"
+	"time"
+)
+
+var notificationsRoute = web.Route{
+	Pattern: res.UrlApiNotifications,
+	Handler: func(r *web.Response) {
+		selfPkHash, err := getSelfPkHash(r)
+		if err != nil {
+			writeError(r, jerr.Get("error getting self pk hash", err), http.StatusInternalServerError)
+			return
+		}
+		if len(selfPkHash) == 0 {
+			writeError(r, jerr.New("not logged in"), http.StatusUnauthorized)
+			return
+		}
+		offset, err := getCursorOffset(r)
+		if err != nil {
+			writeError(r, jerr.Get("error getting cursor offset", err), http.StatusUnprocessableEntity)
+			return
+		}
+		notifications, scanned, err := notify.GetNotificationsFeed(selfPkHash, offset)
+		if err != nil {
+			writeError(r, jerr.Get("error getting notifications feed", err), http.StatusInternalServerError)
+			return
+		}
+		writePage(r, getNotifications(notifications), scanned, offset)
+	},
+}
+
+const notificationsStreamPingInterval = 15 * time.Second
+
+// Server-sent events stream of new notifications for the logged in user. Each event includes the notification
+// and the user's current unread count.
+var notificationsStreamRoute = web.Route{
+	Pattern: res.UrlApiNotificationsStream,
+	Handler: func(r *web.Response) {
+		if ! auth.IsLoggedIn(r.Session.CookieId) {
+			writeError(r, jerr.New("not logged in"), http.StatusUnauthorized)
+			return
+		}
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			writeError(r, jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		selfPkHash, err := cache.GetUserPkHash(user.Id)
+		if err != nil {
+			writeError(r, jerr.Get("error getting user pk hash", err), http.StatusInternalServerError)
+			return
+		}
+		flusher, ok := r.Writer.(http.Flusher)
+		if ! ok {
+			writeError(r, jerr.New("streaming not supported"), http.StatusInternalServerError)
+			return
+		}
+		subscription := bus.Subscribe(bus.NotificationChannel(selfPkHash))
+		defer subscription.Close()
+		r.Writer.Header().Set("Content-Type", "text/event-stream")
+		r.Writer.Header().Set("Cache-Control", "no-cache")
+		r.Writer.Header().Set("Connection", "keep-alive")
+		r.Writer.WriteHeader(http.StatusOK)
+		flusher.Flush()
+		ticker := time.NewTicker(notificationsStreamPingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.Request.HttpRequest.Context().Done():
+				return
+			case <-ticker.C:
+				_, err = fmt.Fprint(r.Writer, ": ping\n\n")
+				if err != nil {
+					return
+				}
+			case event := <-subscription.Events:
+				data, err := getNotificationEventData(selfPkHash, user.Id, event)
+				if err != nil {
+					jerr.Get("error getting notification event data", err).Print()
+					continue
+				}
+				_, err = fmt.Fprintf(r.Writer, "event: notification\ndata: %s\n\n", data)
+				if err != nil {
+					return
+				}
+			}
+			flusher.Flush()
+		}
+	},
+}
+
+func getNotificationEventData(selfPkHash []byte, userId uint, event bus.Event) ([]byte, error) {
+	txHash, err := chainhash.NewHashFromStr(event.TxHash)
+	if err != nil {
+		return nil, jerr.Get("error parsing notification tx hash", err)
+	}
+	notification, err := notify.GetNotification(selfPkHash, txHash.CloneBytes())
+	if err != nil {
+		return nil, jerr.Get("error getting notification", err)
+	}
+	unreadCount, err := cache.GetUnreadNotificationCount(userId)
+	if err != nil {
+		return nil, jerr.Get("error getting unread notification count", err)
+	}
+	data, err := json.Marshal(NotificationEvent{
+		Notification: getNotifications([]*notify.Notification{notification})[0],
+		UnreadCount:  unreadCount,
+	})
+	if err != nil {
+		return nil, jerr.Get("error marshalling notification event", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/profile"
+	"github.com/memocash/memo/app/res"
+	"net/http"
+)
+
+var pollRoute = web.Route{
+	Pattern: res.UrlApiPoll + "/" + urlTxHash.UrlPart(),
+	Handler: func(r *web.Response) {
+		txHash, err := getTxHash(r)
+		if err != nil {
+			writeError(r, jerr.Get("error getting tx hash", err), http.StatusUnprocessableEntity)
+			return
+		}
+		selfPkHash, err := getSelfPkHash(r)
+		if err != nil {
+			writeError(r, jerr.Get("error getting self pk hash", err), http.StatusInternalServerError)
+			return
+		}
+		post, err := profile.GetPostByTxHash(txHash, selfPkHash)
+		if err != nil {
+			writeError(r, jerr.Get("error getting poll post", err), getPostErrorStatus(err))
+			return
+		}
+		var posts = []*profile.Post{post}
+		err = profile.AttachLikesToPosts(posts)
+		if err != nil {
+			writeError(r, jerr.Get("error attaching likes to post", err), http.StatusInternalServerError)
+			return
+		}
+		err = profile.AttachPollsToPosts(posts)
+		if err != nil {
+			writeError(r, jerr.Get("error attaching poll to post", err), http.StatusInternalServerError)
+			return
+		}
+		if ! post.IsPoll() {
+			writeError(r, jerr.New("post is not a poll"), http.StatusNotFound)
+			return
+		}
+		votes, err := profile.GetVotesForTxHash(txHash)
+		if err != nil {
+			writeError(r, jerr.Get("error getting votes for tx hash", err), http.StatusInternalServerError)
+			return
+		}
+		var poll = Poll{
+			Post:    getPost(post),
+			Type:    getPollType(post.Poll.Question.PollType),
+			Options: getPollOptions(post.Poll.GetOptions()),
+			Votes:   getPollVotes(votes),
+		}
+		if post.Poll.IsRank() {
+			poll.Rounds = getPollRounds(post.Poll.GetRankRounds())
+		}
+		writeJson(r, poll)
+	},
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/obj/rank"
+	"github.com/memocash/memo/app/profile"
+	"github.com/memocash/memo/app/res"
+	"net/http"
+)
+
+var postRoute = web.Route{
+	Pattern: res.UrlApiPost + "/" + urlTxHash.UrlPart(),
+	Handler: func(r *web.Response) {
+		txHash, err := getTxHash(r)
+		if err != nil {
+			writeError(r, jerr.Get("error getting tx hash", err), http.StatusUnprocessableEntity)
+			return
+		}
+		selfPkHash, err := getSelfPkHash(r)
+		if err != nil {
+			writeError(r, jerr.Get("error getting self pk hash", err), http.StatusInternalServerError)
+			return
+		}
+		post, err := profile.GetPostByTxHash(txHash, selfPkHash)
+		if err != nil {
+			writeError(r, jerr.Get("error getting post", err), getPostErrorStatus(err))
+			return
+		}
+		err = profile.AttachLikesToPosts([]*profile.Post{post})
+		if err != nil {
+			writeError(r, jerr.Get("error attaching likes to post", err), http.StatusInternalServerError)
+			return
+		}
+		writeJson(r, getPost(post))
+	},
+}
+
+var postsNewRoute = web.Route{
+	Pattern: res.UrlApiPostsNew,
+	Handler: func(r *web.Response) {
+		writePostsPage(r, func(selfPkHash []byte, offset uint) ([]*profile.Post, int, error) {
+			return profile.GetRecentPosts(selfPkHash, offset, "")
+		})
+	},
+}
+
+// Ranking can be set with ?sort=likes|tips|discussion|personal, defaults to likes.
+var postsRankedRoute = web.Route{
+	Pattern: res.UrlApiPostsRanked,
+	Handler: func(r *web.Response) {
+		ranker := rank.GetRanker(r.Request.GetUrlParameter("sort"))
+		writePostsPage(r, func(selfPkHash []byte, offset uint) ([]*profile.Post, int, error) {
+			// Candidates are filtered before ranking so pages are full until the candidates run out.
+			posts, err := profile.GetRankedPosts(selfPkHash, offset, "", ranker)
+			return posts, len(posts), err
+		})
+	},
+}
+
+var postsTopRoute = web.Route{
+	Pattern: res.UrlApiPostsTop,
+	Handler: func(r *web.Response) {
+		timeRange := r.Request.GetUrlParameter("range")
+		if timeRange == "" {
+			timeRange = profile.TimeRange24Hours
+		} else if ! profile.StringIsTimeRange(timeRange) {
+			writeError(r, jerr.New("range not valid time range"), http.StatusUnprocessableEntity)
+			return
+		}
+		writePostsPage(r, func(selfPkHash []byte, offset uint) ([]*profile.Post, int, error) {
+			return profile.GetTopPostsNamedRange(selfPkHash, offset, timeRange, false)
+		})
+	},
+}
+
+func writePostsPage(r *web.Response, getPosts func(selfPkHash []byte, offset uint) ([]*profile.Post, int, error)) {
+	offset, err := getCursorOffset(r)
+	if err != nil {
+		writeError(r, jerr.Get("error getting cursor offset", err), http.StatusUnprocessableEntity)
+		return
+	}
+	selfPkHash, err := getSelfPkHash(r)
+	if err != nil {
+		writeError(r, jerr.Get("error getting self pk hash", err), http.StatusInternalServerError)
+		return
+	}
+	posts, scanned, err := getPosts(selfPkHash, offset)
+	if err != nil {
+		writeError(r, jerr.Get("error getting posts", err), http.StatusInternalServerError)
+		return
+	}
+	err = profile.AttachLikesToPosts(posts)
+	if err != nil {
+		writeError(r, jerr.Get("error attaching likes to posts", err), http.StatusInternalServerError)
+		return
+	}
+	writePage(r, getPostsList(posts), scanned, offset)
+}
@@ -0,0 +1,69 @@
+package api_test
+
+import (
+	"bytes"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/profile"
+	"github.com/memocash/memo/app/testutil"
+	"github.com/memocash/memo/web/server/api"
+	"net/http"
+	"testing"
+)
+
+// A post hidden by tx hash and a post from a banned address are not served, other posts are.
+func TestGetPostHidden(t *testing.T) {
+	defer testutil.UseSqlite(t)()
+	defer testutil.UseMissMemcache(t)()
+
+	visiblePost := &db.MemoPost{
+		TxHash:  bytes.Repeat([]byte{0x01}, 32),
+		PkHash:  bytes.Repeat([]byte{0x11}, 20),
+		Message: "visible",
+	}
+	hiddenPost := &db.MemoPost{
+		TxHash:  bytes.Repeat([]byte{0x02}, 32),
+		PkHash:  bytes.Repeat([]byte{0x11}, 20),
+		Message: "hidden",
+	}
+	bannedPost := &db.MemoPost{
+		TxHash:  bytes.Repeat([]byte{0x03}, 32),
+		PkHash:  bytes.Repeat([]byte{0x12}, 20),
+		Message: "banned",
+	}
+	for _, memoPost := range []*db.MemoPost{visiblePost, hiddenPost, bannedPost} {
+		err := memoPost.Save()
+		if err != nil {
+			t.Fatal(jerr.Get("error saving memo post", err))
+		}
+	}
+	_, err := db.AddModeration(1, hiddenPost.TxHash, nil, "", "test")
+	if err != nil {
+		t.Fatal(jerr.Get("error hiding post", err))
+	}
+	_, err = db.AddModeration(1, nil, bannedPost.PkHash, "", "test")
+	if err != nil {
+		t.Fatal(jerr.Get("error banning address", err))
+	}
+
+	post, err := profile.GetPostByTxHash(visiblePost.TxHash, nil)
+	if err != nil {
+		t.Fatal(jerr.Get("error getting visible post", err))
+	}
+	if post.Memo.Message != visiblePost.Message {
+		t.Fatal(jerr.New("unexpected visible post message"))
+	}
+	for _, memoPost := range []*db.MemoPost{hiddenPost, bannedPost} {
+		_, err = profile.GetPostByTxHash(memoPost.TxHash, nil)
+		if err == nil {
+			t.Fatal(jerr.Newf("expected %s post not to be served", memoPost.Message))
+		}
+		if status := api.GetPostErrorStatus(err); status != http.StatusGone {
+			t.Fatal(jerr.Newf("expected %s post status %d, got %d", memoPost.Message, http.StatusGone, status))
+		}
+	}
+	_, err = profile.GetPostByTxHash(bytes.Repeat([]byte{0x04}, 32), nil)
+	if status := api.GetPostErrorStatus(err); status != http.StatusNotFound {
+		t.Fatal(jerr.Newf("expected missing post status %d, got %d", http.StatusNotFound, status))
+	}
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/profile"
+	"github.com/memocash/memo/app/res"
+	"net/http"
+)
+
+var profileRoute = web.Route{
+	Pattern: res.UrlApiProfile + "/" + urlAddress.UrlPart(),
+	Handler: func(r *web.Response) {
+		pkHash, err := getPkHash(r)
+		if err != nil {
+			writeError(r, jerr.Get("error getting pk hash", err), http.StatusUnprocessableEntity)
+			return
+		}
+		pf, err := profile.GetProfile(pkHash, nil)
+		if err != nil {
+			writeError(r, jerr.Get("error getting profile", err), http.StatusInternalServerError)
+			return
+		}
+		// Only the counts in the response are set, reputation and can follow are skipped.
+		err = pf.SetFollowerCount()
+		if err != nil {
+			writeError(r, jerr.Get("error setting follower count", err), http.StatusInternalServerError)
+			return
+		}
+		err = pf.SetFollowingCount()
+		if err != nil {
+			writeError(r, jerr.Get("error setting following count", err), http.StatusInternalServerError)
+			return
+		}
+		err = pf.SetTopicsFollowingCount()
+		if err != nil {
+			writeError(r, jerr.Get("error setting topics following count", err), http.StatusInternalServerError)
+			return
+		}
+		err = pf.SetUserStats()
+		if err != nil {
+			writeError(r, jerr.Get("error setting user stats", err), http.StatusInternalServerError)
+			return
+		}
+		writeJson(r, getProfile(pf))
+	},
+}
+
+var profileFollowersRoute = web.Route{
+	Pattern: res.UrlApiProfileFollowers + "/" + urlAddress.UrlPart(),
+	Handler: func(r *web.Response) {
+		writeFollowersPage(r, profile.GetFollowers)
+	},
+}
+
+var profileFollowingRoute = web.Route{
+	Pattern: res.UrlApiProfileFollowing + "/" + urlAddress.UrlPart(),
+	Handler: func(r *web.Response) {
+		writeFollowersPage(r, profile.GetFollowing)
+	},
+}
+
+var profilePostsRoute = web.Route{
+	Pattern: res.UrlApiProfilePosts + "/" + urlAddress.UrlPart(),
+	Handler: func(r *web.Response) {
+		pkHash, err := getPkHash(r)
+		if err != nil {
+			writeError(r, jerr.Get("error getting pk hash", err), http.StatusUnprocessableEntity)
+			return
+		}
+		writePostsPage(r, func(selfPkHash []byte, offset uint) ([]*profile.Post, int, error) {
+			return profile.GetPostsForHash(pkHash, selfPkHash, offset)
+		})
+	},
+}
+
+func writeFollowersPage(r *web.Response, getProfileFollowers func(selfPkHash []byte, pkHash []byte, offset int) ([]*profile.Follower, int, error)) {
+	pkHash, err := getPkHash(r)
+	if err != nil {
+		writeError(r, jerr.Get("error getting pk hash", err), http.StatusUnprocessableEntity)
+		return
+	}
+	offset, err := getCursorOffset(r)
+	if err != nil {
+		writeError(r, jerr.Get("error getting cursor offset", err), http.StatusUnprocessableEntity)
+		return
+	}
+	selfPkHash, err := getSelfPkHash(r)
+	if err != nil {
+		writeError(r, jerr.Get("error getting self pk hash", err), http.StatusInternalServerError)
+		return
+	}
+	followers, scanned, err := getProfileFollowers(selfPkHash, pkHash, int(offset))
+	if err != nil {
+		writeError(r, jerr.Get("error getting followers", err), http.StatusInternalServerError)
+		return
+	}
+	writePage(r, getFollowers(followers), scanned, offset)
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/jchavannes/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/auth"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"github.com/memocash/memo/app/cache"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/profile"
+	"net/http"
+	"strconv"
+)
+
+const pageSize = 25
+
+type Page struct {
+	Data       interface{} `json:"data"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+type Error struct {
+	Error string `json:"error"`
+}
+
+func writeJson(r *web.Response, data interface{}) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		writeError(r, jerr.Get("error marshalling json response", err), http.StatusInternalServerError)
+		return
+	}
+	r.Writer.Header().Set("Content-Type", "application/json")
+	r.Write(string(body))
+}
+
+// Cursors are opaque to clients so the paging strategy can change without breaking them. The scanned count is the
+// number of rows read before any filtering, so filtered rows don't end paging early or get read again.
+func writePage(r *web.Response, data interface{}, scanned int, offset uint) {
+	var page = Page{
+		Data: data,
+	}
+	if scanned >= pageSize {
+		page.NextCursor = encodeCursor(offset + uint(scanned))
+	}
+	writeJson(r, page)
+}
+
+func writeError(r *web.Response, err error, status int) {
+	jerr.Get("api error", err).Print()
+	body, _ := json.Marshal(Error{
+		Error: http.StatusText(status),
+	})
+	r.Writer.Header().Set("Content-Type", "application/json")
+	r.SetResponseCode(status)
+	r.Write(string(body))
+}
+
+// Posts and topics hidden by moderation, including every post from a banned address, are gone rather than missing.
+func getPostErrorStatus(err error) int {
+	if db.IsRecordNotFoundError(err) {
+		return http.StatusNotFound
+	}
+	if profile.IsPostHiddenError(err) || profile.IsTopicHiddenError(err) {
+		return http.StatusGone
+	}
+	return http.StatusInternalServerError
+}
+
+func encodeCursor(offset uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(offset), 10)))
+}
+
+func getCursorOffset(r *web.Response) (uint, error) {
+	cursor := r.Request.GetUrlParameter("cursor")
+	if cursor == "" {
+		return 0, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, jerr.Get("error decoding cursor", err)
+	}
+	offset, err := strconv.ParseUint(string(decoded), 10, 32)
+	if err != nil {
+		return 0, jerr.Get("error parsing cursor offset", err)
+	}
+	return uint(offset), nil
+}
+
+func getTxHash(r *web.Response) ([]byte, error) {
+	txHash, err := chainhash.NewHashFromStr(r.Request.GetUrlNamedQueryVariable(urlTxHash.Id))
+	if err != nil {
+		return nil, jerr.Get("error parsing transaction hash", err)
+	}
+	return txHash.CloneBytes(), nil
+}
+
+func getPkHash(r *web.Response) ([]byte, error) {
+	address := wallet.GetAddressFromString(r.Request.GetUrlNamedQueryVariable(urlAddress.Id))
+	pkHash := address.GetScriptAddress()
+	if len(pkHash) == 0 {
+		return nil, jerr.New("invalid address")
+	}
+	return pkHash, nil
+}
+
+// Returns nil when not logged in, so responses can still be personalized for session users.
+func getSelfPkHash(r *web.Response) ([]byte, error) {
+	if ! auth.IsLoggedIn(r.Session.CookieId) {
+		return nil, nil
+	}
+	user, err := auth.GetSessionUser(r.Session.CookieId)
+	if err != nil {
+		return nil, jerr.Get("error getting session user", err)
+	}
+	pkHash, err := cache.GetUserPkHash(user.Id)
+	if err != nil {
+		return nil, jerr.Get("error getting user pk hash", err)
+	}
+	return pkHash, nil
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/db/view"
+	"github.com/memocash/memo/app/profile"
+	"github.com/memocash/memo/app/res"
+	"net/http"
+	"net/url"
+)
+
+var topicsRoute = web.Route{
+	Pattern: res.UrlApiTopics,
+	Handler: func(r *web.Response) {
+		offset, err := getCursorOffset(r)
+		if err != nil {
+			writeError(r, jerr.Get("error getting cursor offset", err), http.StatusUnprocessableEntity)
+			return
+		}
+		var orderType = view.TopicOrderTypeRecent
+		switch r.Request.GetUrlParameter("order") {
+		case "", "recent":
+		case "followers":
+			orderType = view.TopicOrderTypeFollowers
+		case "posts":
+			orderType = view.TopicOrderTypePosts
+		default:
+			writeError(r, jerr.New("invalid topic order"), http.StatusUnprocessableEntity)
+			return
+		}
+		topics, err := db.GetTopicInfo(offset, r.Request.GetUrlParameter("s"), nil, orderType)
+		if err != nil {
+			writeError(r, jerr.Get("error getting topic info", err), http.StatusInternalServerError)
+			return
+		}
+		writePage(r, getTopics(topics), len(topics), offset)
+	},
+}
+
+var topicPostsRoute = web.Route{
+	Pattern: res.UrlApiTopic + "/" + urlTopicName.UrlPart(),
+	Handler: func(r *web.Response) {
+		topic, err := getTopicName(r)
+		if err != nil {
+			writeError(r, jerr.Get("error getting topic name", err), http.StatusUnprocessableEntity)
+			return
+		}
+		err = profile.CheckTopicHidden(topic)
+		if err != nil {
+			writeError(r, jerr.Get("error checking topic", err), getPostErrorStatus(err))
+			return
+		}
+		writePostsPage(r, func(selfPkHash []byte, offset uint) ([]*profile.Post, int, error) {
+			return profile.GetPostsForTopic(topic, selfPkHash, offset)
+		})
+	},
+}
+
+var topicThreadsRoute = web.Route{
+	Pattern: res.UrlApiTopicThreads + "/" + urlTopicName.UrlPart(),
+	Handler: func(r *web.Response) {
+		topic, err := getTopicName(r)
+		if err != nil {
+			writeError(r, jerr.Get("error getting topic name", err), http.StatusUnprocessableEntity)
+			return
+		}
+		err = profile.CheckTopicHidden(topic)
+		if err != nil {
+			writeError(r, jerr.Get("error checking topic", err), getPostErrorStatus(err))
+			return
+		}
+		offset, err := getCursorOffset(r)
+		if err != nil {
+			writeError(r, jerr.Get("error getting cursor offset", err), http.StatusUnprocessableEntity)
+			return
+		}
+		threads, err := db.GetStore().GetThreads(offset, topic)
+		if err != nil {
+			writeError(r, jerr.Get("error getting threads", err), http.StatusInternalServerError)
+			return
+		}
+		writePage(r, getThreads(threads), len(threads), offset)
+	},
+}
+
+func getTopicName(r *web.Response) (string, error) {
+	topic, err := url.QueryUnescape(r.Request.GetUrlNamedQueryVariable(urlTopicName.Id))
+	if err != nil {
+		return "", jerr.Get("error unescaping topic", err)
+	}
+	if topic == "" {
+		return "", jerr.New("empty topic")
+	}
+	return topic, nil
+}
@@ -0,0 +1,183 @@
+package api
+
+import (
+	"encoding/hex"
+	"github.com/jchavannes/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"github.com/memocash/memo/app/bitcoin/transaction"
+	"github.com/memocash/memo/app/bitcoin/transaction/build"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/res"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Signed txs must be submitted with the build id of their unsigned tx before it expires.
+const unsignedBuildTimeout = 5 * time.Minute
+
+var txUnsignedRoute = web.Route{
+	Pattern: res.UrlApiTxUnsigned,
+	Handler: func(r *web.Response) {
+		address := wallet.GetAddressFromString(r.Request.GetFormValue("address"))
+		if len(address.GetScriptAddress()) == 0 {
+			writeError(r, jerr.New("invalid address"), http.StatusUnprocessableEntity)
+			return
+		}
+		outputs, err := getUnsignedOutputs(r)
+		if err != nil {
+			writeError(r, jerr.Get("error getting outputs", err), http.StatusUnprocessableEntity)
+			return
+		}
+		tx, err := build.Unsigned(outputs, address)
+		if err != nil {
+			if build.IsNotEnoughValueError(err) {
+				writeError(r, jerr.Get("not enough value to build tx", err), http.StatusPaymentRequired)
+				return
+			}
+			writeError(r, jerr.Get("error building unsigned tx", err), http.StatusInternalServerError)
+			return
+		}
+		unsignedTx, err := getUnsignedTx(tx)
+		if err != nil {
+			writeError(r, jerr.Get("error getting unsigned tx", err), http.StatusInternalServerError)
+			return
+		}
+		txHash := tx.MsgTx.TxHash()
+		unsignedTx.BuildId, err = db.SaveUnsignedBuild(txHash.CloneBytes(), time.Now().Add(unsignedBuildTimeout))
+		if err != nil {
+			writeError(r, jerr.Get("error saving unsigned build", err), http.StatusInternalServerError)
+			return
+		}
+		writeJson(r, unsignedTx)
+	},
+}
+
+var txSubmitRoute = web.Route{
+	Pattern: res.UrlApiTxSubmit,
+	Handler: func(r *web.Response) {
+		raw, err := hex.DecodeString(r.Request.GetFormValue("raw"))
+		if err != nil {
+			writeError(r, jerr.Get("error decoding raw tx hex", err), http.StatusUnprocessableEntity)
+			return
+		}
+		buildId := r.Request.GetFormValue("build_id")
+		tx, err := transaction.GetSignedMemoTx(raw, buildId)
+		if err != nil {
+			writeError(r, jerr.Get("error validating signed tx", err), http.StatusUnprocessableEntity)
+			return
+		}
+		err = db.RemoveUnsignedBuild(buildId)
+		if err != nil {
+			writeError(r, jerr.Get("error removing unsigned build", err), http.StatusInternalServerError)
+			return
+		}
+		transaction.GetTxInfo(tx).Print()
+		transaction.QueueTx(tx)
+		writeJson(r, SubmittedTx{
+			TxHash: tx.MsgTx.TxHash().String(),
+		})
+	},
+}
+
+// Polls are not supported since their option txs chain off of the unsigned question tx.
+func getUnsignedOutputs(r *web.Response) ([]memo.Output, error) {
+	outputType := r.Request.GetFormValue("type")
+	switch outputType {
+	case memo.StringMemoMessage:
+		return []memo.Output{{
+			Type: memo.OutputTypeMemoMessage,
+			Data: []byte(r.Request.GetFormValue("message")),
+		}}, nil
+	case memo.StringMemoSetName:
+		return []memo.Output{{
+			Type: memo.OutputTypeMemoSetName,
+			Data: []byte(r.Request.GetFormValue("name")),
+		}}, nil
+	case memo.StringMemoSetProfile:
+		return []memo.Output{{
+			Type: memo.OutputTypeMemoSetProfile,
+			Data: []byte(r.Request.GetFormValue("profile")),
+		}}, nil
+	case memo.StringMemoSetProfilePic:
+		return []memo.Output{{
+			Type: memo.OutputTypeMemoSetProfilePic,
+			Data: []byte(r.Request.GetFormValue("url")),
+		}}, nil
+	case memo.StringMemoImageBaseUrl:
+		return []memo.Output{{
+			Type: memo.OutputTypeMemoSetImageBaseUrl,
+			Data: []byte(r.Request.GetFormValue("url")),
+		}}, nil
+	case memo.StringMemoFollow, memo.StringMemoUnfollow:
+		followAddress := wallet.GetAddressFromString(r.Request.GetFormValue("follow_address"))
+		if len(followAddress.GetScriptAddress()) == 0 {
+			return nil, jerr.New("invalid follow address")
+		}
+		var followType = memo.OutputTypeMemoFollow
+		if outputType == memo.StringMemoUnfollow {
+			followType = memo.OutputTypeMemoUnfollow
+		}
+		return []memo.Output{{
+			Type: followType,
+			Data: followAddress.GetScriptAddress(),
+		}}, nil
+	case memo.StringMemoTopicMessage:
+		return []memo.Output{{
+			Type:    memo.OutputTypeMemoTopicMessage,
+			RefData: []byte(r.Request.GetFormValue("topic")),
+			Data:    []byte(r.Request.GetFormValue("message")),
+		}}, nil
+	case memo.StringMemoTopicFollow:
+		return []memo.Output{{
+			Type: memo.OutputTypeMemoTopicFollow,
+			Data: []byte(r.Request.GetFormValue("topic")),
+		}}, nil
+	case memo.StringMemoTopicUnfollow:
+		return []memo.Output{{
+			Type: memo.OutputTypeMemoTopicUnfollow,
+			Data: []byte(r.Request.GetFormValue("topic")),
+		}}, nil
+	}
+	txHash, err := chainhash.NewHashFromStr(r.Request.GetFormValue("tx_hash"))
+	if err != nil {
+		return nil, jerr.Get("error parsing tx hash", err)
+	}
+	switch outputType {
+	case memo.StringMemoReply:
+		return []memo.Output{{
+			Type:    memo.OutputTypeMemoReply,
+			RefData: txHash.CloneBytes(),
+			Data:    []byte(r.Request.GetFormValue("message")),
+		}}, nil
+	case memo.StringMemoRepost:
+		return []memo.Output{{
+			Type:    memo.OutputTypeMemoRepost,
+			RefData: txHash.CloneBytes(),
+			Data:    []byte(r.Request.GetFormValue("message")),
+		}}, nil
+	case memo.StringMemoAttachPicture:
+		return []memo.Output{{
+			Type:    memo.OutputTypeMemoAttachPicture,
+			RefData: txHash.CloneBytes(),
+			Data:    []byte(r.Request.GetFormValue("url")),
+		}}, nil
+	case memo.StringMemoLike:
+		var tip int64
+		if tipString := r.Request.GetFormValue("tip"); tipString != "" {
+			tip, err = strconv.ParseInt(tipString, 10, 64)
+			if err != nil {
+				return nil, jerr.Get("error parsing tip", err)
+			}
+		}
+		outputs, err := build.LikeOutputs(txHash.CloneBytes(), tip)
+		if err != nil {
+			return nil, jerr.Get("error getting like outputs", err)
+		}
+		return outputs, nil
+	}
+	return nil, jerr.Newf("unsupported output type: %s", outputType)
+}
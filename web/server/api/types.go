@@ -0,0 +1,337 @@
+package api
+
+import (
+	"bytes"
+	"encoding/hex"
+	"github.com/jchavannes/btcd/txscript"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/db/view"
+	"github.com/memocash/memo/app/notify"
+	"github.com/memocash/memo/app/profile"
+	"time"
+)
+
+// Types in this file are the public api contract, fields should only ever be added.
+
+type Post struct {
+	TxHash       string    `json:"tx_hash"`
+	Address      string    `json:"address"`
+	Name         string    `json:"name"`
+	Message      string    `json:"message"`
+	Topic        string    `json:"topic,omitempty"`
+	ParentTxHash string    `json:"parent_tx_hash,omitempty"`
+	Time         time.Time `json:"time"`
+	BlockHeight  uint      `json:"block_height,omitempty"`
+	Likes        int       `json:"likes"`
+	Tips         int64     `json:"tips"`
+	Replies      uint      `json:"replies"`
+	IsPoll       bool      `json:"is_poll"`
+	Pictures     []string  `json:"pictures"`
+}
+
+type Profile struct {
+	Address        string     `json:"address"`
+	Name           string     `json:"name"`
+	Profile        string     `json:"profile"`
+	Pic            string     `json:"pic,omitempty"`
+	Followers      int        `json:"followers"`
+	Following      int        `json:"following"`
+	TopicsFollowed int        `json:"topics_followed"`
+	Posts          int        `json:"posts"`
+	FirstPost      *time.Time `json:"first_post,omitempty"`
+	LastPost       *time.Time `json:"last_post,omitempty"`
+}
+
+type Follower struct {
+	Address string `json:"address"`
+	Name    string `json:"name"`
+}
+
+type Topic struct {
+	Name      string    `json:"name"`
+	Posts     int       `json:"posts"`
+	Followers int       `json:"followers"`
+	Recent    time.Time `json:"recent"`
+}
+
+type Thread struct {
+	Topic       string    `json:"topic"`
+	Message     string    `json:"message"`
+	RootTxHash  string    `json:"root_tx_hash"`
+	Replies     int       `json:"replies"`
+	RecentReply time.Time `json:"recent_reply"`
+}
+
+type Poll struct {
+	Post    Post         `json:"post"`
+	Type    string       `json:"type"`
+	Options []PollOption `json:"options"`
+	Rounds  []PollRound  `json:"rounds,omitempty"`
+	Votes   []PollVote   `json:"votes"`
+}
+
+type PollOption struct {
+	Name        string `json:"name"`
+	Votes       int    `json:"votes"`
+	UniqueVotes int    `json:"unique_votes"`
+	Satoshis    int64  `json:"satoshis"`
+}
+
+type PollRound struct {
+	Number     int          `json:"number"`
+	Options    []PollOption `json:"options"`
+	Exhausted  int          `json:"exhausted"`
+	Eliminated string       `json:"eliminated,omitempty"`
+	Winner     string       `json:"winner,omitempty"`
+}
+
+type PollVote struct {
+	TxHash  string `json:"tx_hash"`
+	Address string `json:"address"`
+	Name    string `json:"name"`
+	Option  string `json:"option"`
+	Message string `json:"message"`
+	Tip     int64  `json:"tip"`
+}
+
+type Notification struct {
+	Id            uint      `json:"id"`
+	Type          string    `json:"type"`
+	Address       string    `json:"address"`
+	Name          string    `json:"name"`
+	PostTxHash    string    `json:"post_tx_hash,omitempty"`
+	ParentTxHash  string    `json:"parent_tx_hash,omitempty"`
+	ParentMessage string    `json:"parent_message,omitempty"`
+	Message       string    `json:"message,omitempty"`
+	Tip           int64     `json:"tip,omitempty"`
+	Time          time.Time `json:"time"`
+}
+
+type NotificationEvent struct {
+	Notification Notification `json:"notification"`
+	UnreadCount  uint         `json:"unread_count"`
+}
+
+type UnsignedTx struct {
+	BuildId     string            `json:"build_id"`
+	Type        string            `json:"type"`
+	Raw         string            `json:"raw"`
+	SigHashType uint32            `json:"sig_hash_type"`
+	Inputs      []UnsignedTxInput `json:"inputs"`
+}
+
+type UnsignedTxInput struct {
+	PrevTxHash string `json:"prev_tx_hash"`
+	PrevIndex  uint32 `json:"prev_index"`
+	Value      int64  `json:"value"`
+	PkScript   string `json:"pk_script"`
+}
+
+type SubmittedTx struct {
+	TxHash string `json:"tx_hash"`
+}
+
+func getBlockTime(block *db.Block, createdAt time.Time) time.Time {
+	if block != nil && block.Timestamp.Before(createdAt) {
+		return block.Timestamp
+	}
+	return createdAt
+}
+
+func getPost(post *profile.Post) Post {
+	var apiPost = Post{
+		TxHash:  post.Memo.GetTransactionHashString(),
+		Address: post.Memo.GetAddressString(),
+		Name:    post.Name,
+		Message: post.Memo.Message,
+		Topic:   post.Memo.Topic,
+		Time:    getBlockTime(post.Memo.Block, post.Memo.CreatedAt),
+		Likes:   len(post.Likes),
+		Tips:    post.GetTotalTip(),
+		Replies: post.ReplyCount,
+		IsPoll:  post.Memo.IsPoll,
+	}
+	if len(post.Memo.ParentTxHash) > 0 {
+		apiPost.ParentTxHash = post.Memo.GetParentTransactionHashString()
+	}
+	if post.Memo.Block != nil {
+		apiPost.BlockHeight = post.Memo.Block.Height
+	}
+	apiPost.Pictures = []string{}
+	for _, picture := range post.Pictures {
+		apiPost.Pictures = append(apiPost.Pictures, picture.Url)
+	}
+	return apiPost
+}
+
+func getPostsList(posts []*profile.Post) []Post {
+	var apiPosts = []Post{}
+	for _, post := range posts {
+		apiPosts = append(apiPosts, getPost(post))
+	}
+	return apiPosts
+}
+
+func getProfile(pf *profile.Profile) Profile {
+	var apiProfile = Profile{
+		Address:        pf.GetAddressString(),
+		Name:           pf.Name,
+		Profile:        pf.Profile,
+		Followers:      pf.FollowerCount,
+		Following:      pf.FollowingCount,
+		TopicsFollowed: pf.TopicsFollowingCount,
+		Posts:          pf.NumPosts,
+	}
+	if pf.Pic != nil {
+		apiProfile.Pic = pf.Pic.Url
+	}
+	if ! pf.FirstPost.IsZero() {
+		apiProfile.FirstPost = &pf.FirstPost
+	}
+	if ! pf.LastPost.IsZero() {
+		apiProfile.LastPost = &pf.LastPost
+	}
+	return apiProfile
+}
+
+func getFollowers(followers []*profile.Follower) []Follower {
+	var apiFollowers = []Follower{}
+	for _, follower := range followers {
+		apiFollowers = append(apiFollowers, Follower{
+			Address: follower.GetAddressString(),
+			Name:    follower.Name,
+		})
+	}
+	return apiFollowers
+}
+
+func getTopics(topics []*view.Topic) []Topic {
+	var apiTopics = []Topic{}
+	for _, topic := range topics {
+		apiTopics = append(apiTopics, Topic{
+			Name:      topic.Name,
+			Posts:     topic.CountPosts,
+			Followers: topic.CountFollows,
+			Recent:    topic.RecentTime,
+		})
+	}
+	return apiTopics
+}
+
+func getThreads(threads []*view.Thread) []Thread {
+	var apiThreads = []Thread{}
+	for _, thread := range threads {
+		apiThreads = append(apiThreads, Thread{
+			Topic:       thread.Topic,
+			Message:     thread.Message,
+			RootTxHash:  thread.GetTransactionHashString(),
+			Replies:     thread.NumReplies,
+			RecentReply: thread.RecentReply,
+		})
+	}
+	return apiThreads
+}
+
+func getPollType(pollType int) string {
+	switch pollType {
+	case memo.CodePollTypeMulti:
+		return string(memo.PollTypeAny)
+	case memo.CodePollTypeRank:
+		return string(memo.PollTypeRank)
+	}
+	return string(memo.PollTypeOne)
+}
+
+func getPollOptions(options []profile.Option) []PollOption {
+	var apiOptions = []PollOption{}
+	for _, option := range options {
+		apiOptions = append(apiOptions, PollOption{
+			Name:        option.Name,
+			Votes:       option.Votes,
+			UniqueVotes: option.UniqueVotes,
+			Satoshis:    option.Satoshis,
+		})
+	}
+	return apiOptions
+}
+
+func getPollRounds(rounds []profile.RankRound) []PollRound {
+	var apiRounds []PollRound
+	for _, round := range rounds {
+		var apiRound = PollRound{
+			Number:     round.Number,
+			Options:    []PollOption{},
+			Exhausted:  round.Exhausted,
+			Eliminated: round.Eliminated,
+			Winner:     round.Winner,
+		}
+		for _, option := range round.Options {
+			apiRound.Options = append(apiRound.Options, PollOption{
+				Name:  option.Name,
+				Votes: option.Votes,
+			})
+		}
+		apiRounds = append(apiRounds, apiRound)
+	}
+	return apiRounds
+}
+
+func getPollVotes(votes []*profile.Vote) []PollVote {
+	var apiVotes = []PollVote{}
+	for _, vote := range votes {
+		apiVotes = append(apiVotes, PollVote{
+			TxHash:  vote.GetTxHashString(),
+			Address: vote.GetProfileHashString(),
+			Name:    vote.Name,
+			Option:  vote.Option,
+			Message: vote.Message,
+			Tip:     vote.Tip,
+		})
+	}
+	return apiVotes
+}
+
+func getUnsignedTx(tx *memo.Tx) (UnsignedTx, error) {
+	var raw bytes.Buffer
+	err := tx.MsgTx.Serialize(&raw)
+	if err != nil {
+		return UnsignedTx{}, jerr.Get("error serializing unsigned tx", err)
+	}
+	var unsignedTx = UnsignedTx{
+		Type:        tx.Type.String(),
+		Raw:         hex.EncodeToString(raw.Bytes()),
+		SigHashType: uint32(txscript.SigHashAll + wallet.SigHashForkID),
+		Inputs:      []UnsignedTxInput{},
+	}
+	for i, in := range tx.MsgTx.TxIn {
+		unsignedTx.Inputs = append(unsignedTx.Inputs, UnsignedTxInput{
+			PrevTxHash: in.PreviousOutPoint.Hash.String(),
+			PrevIndex:  in.PreviousOutPoint.Index,
+			Value:      tx.Inputs[i].Value,
+			PkScript:   hex.EncodeToString(tx.Inputs[i].PkScript),
+		})
+	}
+	return unsignedTx, nil
+}
+
+func getNotifications(notifications []*notify.Notification) []Notification {
+	var apiNotifications = []Notification{}
+	for _, notification := range notifications {
+		apiNotifications = append(apiNotifications, Notification{
+			Id:            notification.GetId(),
+			Type:          string(notification.Type),
+			Address:       notification.AddressString,
+			Name:          notification.Name,
+			PostTxHash:    notification.PostHashString,
+			ParentTxHash:  notification.ParentHashString,
+			ParentMessage: notification.ParentMessage,
+			Message:       notification.Message,
+			Tip:           notification.TipAmount,
+			Time:          notification.Time,
+		})
+	}
+	return apiNotifications
+}
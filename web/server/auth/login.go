@@ -32,7 +32,7 @@ var loginSubmitRoute = web.Route{
 		// Protects against some session hi-jacking attacks
 		oldCookieId := r.Session.CookieId
 		r.ResetOrCreateSession()
-		db.UpdateCsrfTokenSession(oldCookieId, r.Session.CookieId)
+		db.GetStore().UpdateCsrfTokenSession(oldCookieId, r.Session.CookieId)
 		username := r.Request.GetFormValue("username")
 		password := r.Request.GetFormValue("password")
 
@@ -8,14 +8,18 @@ import (
 	"github.com/memocash/memo/app/db"
 	"github.com/memocash/memo/app/res"
 	"net/http"
+	"strings"
 )
 
 const (
 	MsgErrorParsingWif         = "error parsing wif"
+	MsgErrorParsingMnemonic    = "error parsing mnemonic"
 	MsgErrorGettingSessionUser = "error getting session user"
 	MsgErrorCreatingNewPrivKey = "error creating new private key"
 	MsgErrorSavingKey          = "error saving key"
 	MsgErrorImportingKey       = "error importing key"
+	MsgErrorRestoringKey       = "error restoring key"
+	MsgErrorGettingMnemonic    = "error getting mnemonic"
 	MsgErrorUserAlreadyExists  = "user already exists"
 	MsgErrorSigningUp          = "error signing up"
 )
@@ -44,10 +48,11 @@ var signupSubmitRoute = web.Route{
 		// Protects against some session hi-jacking attacks
 		oldCookieId := r.Session.CookieId
 		r.ResetOrCreateSession()
-		db.UpdateCsrfTokenSession(oldCookieId, r.Session.CookieId)
+		db.GetStore().UpdateCsrfTokenSession(oldCookieId, r.Session.CookieId)
 		username := r.Request.GetFormValue("username")
 		password := r.Request.GetFormValue("password")
 		wif := r.Request.GetFormValue("wif")
+		mnemonic := strings.Join(strings.Fields(strings.ToLower(r.Request.GetFormValue("mnemonic"))), " ")
 
 		// Before creating account, make sure we have a valid private key
 		if wif != "" {
@@ -56,6 +61,12 @@ var signupSubmitRoute = web.Route{
 				r.Error(jerr.Get(MsgErrorParsingWif, err), http.StatusUnprocessableEntity)
 				return
 			}
+		} else if mnemonic != "" {
+			_, err := wallet.GetWallet(mnemonic, "")
+			if err != nil {
+				r.Error(jerr.Get(MsgErrorParsingMnemonic, err), http.StatusUnprocessableEntity)
+				return
+			}
 		}
 
 		err := auth.Signup(r.Session.CookieId, username, password)
@@ -71,13 +82,25 @@ var signupSubmitRoute = web.Route{
 			r.Error(jerr.Get(MsgErrorGettingSessionUser, err), http.StatusInternalServerError)
 			return
 		}
-		if wif == "" {
-			key, err := db.GenerateKey(username+"-generated", password, user.Id)
+		if wif != "" {
+			_, err = db.GetStore().ImportKey(username+"-imported", password, wif, user.Id)
+			if err != nil {
+				r.Error(jerr.Get(MsgErrorImportingKey, err), http.StatusInternalServerError)
+				return
+			}
+		} else if mnemonic != "" {
+			_, err = db.GetStore().RestoreKey(username+"-restored", password, mnemonic, user.Id)
+			if err != nil {
+				r.Error(jerr.Get(MsgErrorRestoringKey, err), http.StatusInternalServerError)
+				return
+			}
+		} else {
+			key, err := db.GetStore().GenerateKey(username+"-generated", password, user.Id)
 			if err != nil {
 				r.Error(jerr.Get(MsgErrorCreatingNewPrivKey, err), http.StatusInternalServerError)
 				return
 			}
-			recentBlock, err := db.GetRecentBlock()
+			recentBlock, err := db.GetStore().GetRecentBlock()
 			// No need to check back for a new key
 			key.MaxCheck = recentBlock.Height
 			err = key.Save()
@@ -85,12 +108,19 @@ var signupSubmitRoute = web.Route{
 				r.Error(jerr.Get(MsgErrorSavingKey, err), http.StatusInternalServerError)
 				return
 			}
-		} else {
-			_, err = db.ImportKey(username+"-imported", password, wif, user.Id)
+			hdWallet, err := key.GetWallet(password)
 			if err != nil {
-				r.Error(jerr.Get(MsgErrorImportingKey, err), http.StatusInternalServerError)
+				r.Error(jerr.Get(MsgErrorGettingMnemonic, err), http.StatusInternalServerError)
+				return
+			}
+			generatedMnemonic, err := hdWallet.GetMnemonic()
+			if err != nil {
+				r.Error(jerr.Get(MsgErrorGettingMnemonic, err), http.StatusInternalServerError)
 				return
 			}
+			// Also shown on the key export page after unlocking
+			r.Helper["Mnemonic"] = generatedMnemonic
 		}
+		r.RenderTemplate(res.TmplSignupSubmit)
 	},
 }
@@ -0,0 +1,103 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"net/http"
+	"time"
+)
+
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	Id      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Id        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Published string      `xml:"published"`
+	Updated   string      `xml:"updated"`
+	Link      atomLink    `xml:"link"`
+	Author    atomAuthor  `xml:"author"`
+	Content   atomContent `xml:"content"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+	Uri  string `xml:"uri,omitempty"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// Atom ids must be IRIs so the tx hash is wrapped in a URN.
+func getAtomId(txHash string) string {
+	return "urn:memo:tx:" + txHash
+}
+
+func writeAtom(r *web.Response, getFeed getFeedFunc) {
+	f, ok := loadFeed(r, getFeed)
+	if ! ok {
+		return
+	}
+	feedUrl := getFeedUrl(r)
+	var atom = atomFeed{
+		Xmlns:   atomNamespace,
+		Id:      feedUrl,
+		Title:   f.Title,
+		Updated: f.GetUpdated().UTC().Format(time.RFC3339),
+		Links: []atomLink{{
+			Href: feedUrl,
+			Rel:  "self",
+			Type: "application/atom+xml",
+		}, {
+			Href: f.PageUrl,
+			Rel:  "alternate",
+			Type: "text/html",
+		}},
+	}
+	for _, feedItem := range f.Items {
+		itemTime := feedItem.Time.UTC().Format(time.RFC3339)
+		atom.Entries = append(atom.Entries, atomEntry{
+			Id:        getAtomId(feedItem.TxHash),
+			Title:     feedItem.Title,
+			Published: itemTime,
+			Updated:   itemTime,
+			Link: atomLink{
+				Href: feedItem.Url,
+				Rel:  "alternate",
+			},
+			Author: atomAuthor{
+				Name: feedItem.AuthorName,
+				Uri:  feedItem.AuthorUrl,
+			},
+			Content: atomContent{
+				Type: "html",
+				Body: feedItem.ContentHtml,
+			},
+		})
+	}
+	body, err := xml.MarshalIndent(atom, "", "  ")
+	if err != nil {
+		r.Error(jerr.Get("error marshalling atom feed", err), http.StatusInternalServerError)
+		return
+	}
+	r.Writer.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	r.Write(xml.Header + string(body))
+}
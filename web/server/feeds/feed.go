@@ -0,0 +1,109 @@
+package feeds
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/profile"
+	"github.com/memocash/memo/app/res"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxTitleLength        = 80
+	invalidParamErrorText = "invalid feed parameter"
+)
+
+var invalidParamError = jerr.New(invalidParamErrorText)
+
+type feed struct {
+	Title   string
+	PageUrl string
+	Items   []*item
+}
+
+type item struct {
+	TxHash      string
+	Url         string
+	Title       string
+	ContentHtml string
+	ContentText string
+	AuthorName  string
+	AuthorUrl   string
+	Time        time.Time
+}
+
+type getFeedFunc func(r *web.Response, baseUrl string) (*feed, error)
+
+func (f feed) GetUpdated() time.Time {
+	var updated time.Time
+	for _, feedItem := range f.Items {
+		if feedItem.Time.After(updated) {
+			updated = feedItem.Time
+		}
+	}
+	if updated.IsZero() {
+		return time.Now()
+	}
+	return updated
+}
+
+func getItems(posts []*profile.Post, baseUrl string) []*item {
+	var items []*item
+	for _, post := range posts {
+		txHash := post.Memo.GetTransactionHashString()
+		address := post.Memo.GetAddressString()
+		authorName := post.Name
+		if authorName == "" {
+			authorName = address
+		}
+		contentText := html.UnescapeString(post.Memo.Message)
+		items = append(items, &item{
+			TxHash:      txHash,
+			Url:         baseUrl + strings.TrimLeft(res.UrlMemoPost, "/") + "/" + txHash,
+			Title:       getTitle(contentText),
+			ContentHtml: post.GetMessage(),
+			ContentText: contentText,
+			AuthorName:  authorName,
+			AuthorUrl:   baseUrl + strings.TrimLeft(res.UrlProfileView, "/") + "/" + address,
+			Time:        getPostTime(post),
+		})
+	}
+	return items
+}
+
+// Block time once confirmed, otherwise the time the post was first seen.
+func getPostTime(post *profile.Post) time.Time {
+	if post.Memo.BlockId != 0 && post.Memo.Block != nil {
+		return post.Memo.Block.Timestamp
+	}
+	return post.Memo.CreatedAt
+}
+
+func getTitle(message string) string {
+	title := strings.Join(strings.Fields(message), " ")
+	if utf8.RuneCountInString(title) <= maxTitleLength {
+		return title
+	}
+	return string([]rune(title)[:maxTitleLength-3]) + "..."
+}
+
+func getFeedUrl(r *web.Response) string {
+	return res.GetAbsoluteBaseUrl(r) + strings.TrimLeft(r.Request.HttpRequest.URL.RequestURI(), "/")
+}
+
+func loadFeed(r *web.Response, getFeed getFeedFunc) (*feed, bool) {
+	f, err := getFeed(r, res.GetAbsoluteBaseUrl(r))
+	if err != nil {
+		if jerr.HasError(err, invalidParamErrorText) {
+			r.Error(jerr.Get("error getting feed", err), http.StatusUnprocessableEntity)
+		} else {
+			r.Error(jerr.Get("error getting feed", err), http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+	return f, true
+}
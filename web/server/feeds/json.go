@@ -0,0 +1,69 @@
+package feeds
+
+import (
+	"encoding/json"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"net/http"
+	"time"
+)
+
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageUrl string         `json:"home_page_url"`
+	FeedUrl     string         `json:"feed_url"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	Id            string           `json:"id"`
+	Url           string           `json:"url"`
+	Title         string           `json:"title"`
+	ContentHtml   string           `json:"content_html"`
+	ContentText   string           `json:"content_text"`
+	DatePublished time.Time        `json:"date_published"`
+	Authors       []jsonFeedAuthor `json:"authors"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+func writeJsonFeed(r *web.Response, getFeed getFeedFunc) {
+	f, ok := loadFeed(r, getFeed)
+	if ! ok {
+		return
+	}
+	var feedJson = jsonFeed{
+		Version:     jsonFeedVersion,
+		Title:       f.Title,
+		HomePageUrl: f.PageUrl,
+		FeedUrl:     getFeedUrl(r),
+		Items:       []jsonFeedItem{},
+	}
+	for _, feedItem := range f.Items {
+		feedJson.Items = append(feedJson.Items, jsonFeedItem{
+			Id:            feedItem.TxHash,
+			Url:           feedItem.Url,
+			Title:         feedItem.Title,
+			ContentHtml:   feedItem.ContentHtml,
+			ContentText:   feedItem.ContentText,
+			DatePublished: feedItem.Time,
+			Authors: []jsonFeedAuthor{{
+				Name: feedItem.AuthorName,
+				Url:  feedItem.AuthorUrl,
+			}},
+		})
+	}
+	body, err := json.Marshal(feedJson)
+	if err != nil {
+		r.Error(jerr.Get("error marshalling json feed", err), http.StatusInternalServerError)
+		return
+	}
+	r.Writer.Header().Set("Content-Type", "application/feed+json; charset=utf-8")
+	r.Write(string(body))
+}
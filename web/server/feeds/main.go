@@ -0,0 +1,28 @@
+package feeds
+
+import "github.com/jchavannes/jgo/web"
+
+var urlAddress = web.UrlParam{
+	Id:   "address",
+	Type: web.UrlParamString,
+}
+
+var urlTopicName = web.UrlParam{
+	Id:   "topicName",
+	Type: web.UrlParamAny,
+}
+
+func GetRoutes() []web.Route {
+	return []web.Route{
+		profileAtomRoute,
+		profileJsonRoute,
+		topicAtomRoute,
+		topicJsonRoute,
+		personalizedAtomRoute,
+		personalizedJsonRoute,
+		rankedAtomRoute,
+		rankedJsonRoute,
+		topAtomRoute,
+		topJsonRoute,
+	}
+}
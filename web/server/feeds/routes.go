@@ -0,0 +1,175 @@
+package feeds
+
+import (
+	"fmt"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/obj/rank"
+	"github.com/memocash/memo/app/profile"
+	"github.com/memocash/memo/app/res"
+	"net/url"
+	"strings"
+)
+
+var profileAtomRoute = web.Route{
+	Pattern: res.UrlFeedAtomProfile + "/" + urlAddress.UrlPart(),
+	Handler: func(r *web.Response) {
+		writeAtom(r, getProfileFeed)
+	},
+}
+
+var profileJsonRoute = web.Route{
+	Pattern: res.UrlFeedJsonProfile + "/" + urlAddress.UrlPart(),
+	Handler: func(r *web.Response) {
+		writeJsonFeed(r, getProfileFeed)
+	},
+}
+
+var topicAtomRoute = web.Route{
+	Pattern: res.UrlFeedAtomTopic + "/" + urlTopicName.UrlPart(),
+	Handler: func(r *web.Response) {
+		writeAtom(r, getTopicFeed)
+	},
+}
+
+var topicJsonRoute = web.Route{
+	Pattern: res.UrlFeedJsonTopic + "/" + urlTopicName.UrlPart(),
+	Handler: func(r *web.Response) {
+		writeJsonFeed(r, getTopicFeed)
+	},
+}
+
+var personalizedAtomRoute = web.Route{
+	Pattern: res.UrlFeedAtomPersonalized + "/" + urlAddress.UrlPart(),
+	Handler: func(r *web.Response) {
+		writeAtom(r, getPersonalizedFeed)
+	},
+}
+
+var personalizedJsonRoute = web.Route{
+	Pattern: res.UrlFeedJsonPersonalized + "/" + urlAddress.UrlPart(),
+	Handler: func(r *web.Response) {
+		writeJsonFeed(r, getPersonalizedFeed)
+	},
+}
+
+var rankedAtomRoute = web.Route{
+	Pattern: res.UrlFeedAtomRanked,
+	Handler: func(r *web.Response) {
+		writeAtom(r, getRankedFeed)
+	},
+}
+
+var rankedJsonRoute = web.Route{
+	Pattern: res.UrlFeedJsonRanked,
+	Handler: func(r *web.Response) {
+		writeJsonFeed(r, getRankedFeed)
+	},
+}
+
+var topAtomRoute = web.Route{
+	Pattern: res.UrlFeedAtomTop,
+	Handler: func(r *web.Response) {
+		writeAtom(r, getTopFeed)
+	},
+}
+
+var topJsonRoute = web.Route{
+	Pattern: res.UrlFeedJsonTop,
+	Handler: func(r *web.Response) {
+		writeJsonFeed(r, getTopFeed)
+	},
+}
+
+func getProfileFeed(r *web.Response, baseUrl string) (*feed, error) {
+	address := wallet.GetAddressFromString(r.Request.GetUrlNamedQueryVariable(urlAddress.Id))
+	pkHash := address.GetScriptAddress()
+	if len(pkHash) == 0 {
+		return nil, invalidParamError
+	}
+	posts, _, err := profile.GetPostsForHash(pkHash, nil, 0)
+	if err != nil {
+		return nil, jerr.Get("error getting posts for hash", err)
+	}
+	var name = address.GetEncoded()
+	setName, err := db.GetNameForPkHash(pkHash)
+	if err != nil {
+		return nil, jerr.Get("error getting name for hash", err)
+	}
+	if setName != nil {
+		name = setName.Name
+	}
+	return &feed{
+		Title:   fmt.Sprintf("%s - Memo", name),
+		PageUrl: baseUrl + strings.TrimLeft(res.UrlProfileView, "/") + "/" + address.GetEncoded(),
+		Items:   getItems(posts, baseUrl),
+	}, nil
+}
+
+func getTopicFeed(r *web.Response, baseUrl string) (*feed, error) {
+	topic, err := url.QueryUnescape(r.Request.GetUrlNamedQueryVariable(urlTopicName.Id))
+	if err != nil || topic == "" {
+		return nil, invalidParamError
+	}
+	posts, _, err := profile.GetPostsForTopic(topic, nil, 0)
+	if err != nil {
+		return nil, jerr.Get("error getting posts for topic", err)
+	}
+	return &feed{
+		Title:   fmt.Sprintf("%s - Memo Topic", topic),
+		PageUrl: baseUrl + strings.TrimLeft(res.UrlTopicView, "/") + "/" + url.QueryEscape(topic),
+		Items:   getItems(posts, baseUrl),
+	}, nil
+}
+
+// Follows are public so the personalized feed for any address can be syndicated without logging in.
+func getPersonalizedFeed(r *web.Response, baseUrl string) (*feed, error) {
+	address := wallet.GetAddressFromString(r.Request.GetUrlNamedQueryVariable(urlAddress.Id))
+	pkHash := address.GetScriptAddress()
+	if len(pkHash) == 0 {
+		return nil, invalidParamError
+	}
+	posts, err := profile.GetPostsFeed(pkHash, 0)
+	if err != nil {
+		return nil, jerr.Get("error getting posts feed", err)
+	}
+	return &feed{
+		Title:   fmt.Sprintf("Feed for %s - Memo", address.GetEncoded()),
+		PageUrl: baseUrl + strings.TrimLeft(res.UrlProfileView, "/") + "/" + address.GetEncoded(),
+		Items:   getItems(posts, baseUrl),
+	}, nil
+}
+
+func getRankedFeed(r *web.Response, baseUrl string) (*feed, error) {
+	posts, err := profile.GetRankedPosts(nil, 0, "", rank.GetRanker(rank.NameLikes))
+	if err != nil {
+		return nil, jerr.Get("error getting ranked posts", err)
+	}
+	return &feed{
+		Title:   "Ranked Posts - Memo",
+		PageUrl: baseUrl + strings.TrimLeft(res.UrlPostsRanked, "/"),
+		Items:   getItems(posts, baseUrl),
+	}, nil
+}
+
+// Time range can be set with ?range=1h|24h|7d|all, defaults to 24h.
+func getTopFeed(r *web.Response, baseUrl string) (*feed, error) {
+	timeRange := r.Request.GetUrlParameter("range")
+	if timeRange == "" {
+		timeRange = profile.TimeRange24Hours
+	}
+	if ! profile.StringIsTimeRange(timeRange) {
+		return nil, invalidParamError
+	}
+	posts, _, err := profile.GetTopPostsNamedRange(nil, 0, timeRange, false)
+	if err != nil {
+		return nil, jerr.Get("error getting top posts", err)
+	}
+	return &feed{
+		Title:   fmt.Sprintf("Top Posts (%s) - Memo", timeRange),
+		PageUrl: baseUrl + strings.TrimLeft(res.UrlPostsTop, "/") + "?range=" + timeRange,
+		Items:   getItems(posts, baseUrl),
+	}, nil
+}
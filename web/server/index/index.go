@@ -24,7 +24,7 @@ var indexRoute = web.Route{
 			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 			return
 		}
-		key, err := db.GetKeyForUser(user.Id)
+		key, err := db.GetStore().GetKeyForUser(user.Id)
 		if err != nil {
 			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 			return
@@ -53,6 +53,7 @@ var indexRoute = web.Route{
 		}
 		r.Helper["Key"] = key
 		r.Helper["OffsetLink"] = res.UrlIndex
+		r.Helper["FeedPath"] = "personalized/" + key.GetAddress().GetEncoded()
 		setFeed(r, key.PkHash, user.Id)
 		r.RenderTemplate(res.TmplDashboard)
 	},
@@ -17,5 +17,6 @@ func GetRoutes() []web.Route {
 		statsRoute,
 		chartsRoute,
 		allRoute,
+		socketRoute,
 	}
 }
@@ -19,7 +19,7 @@ var needFundsRoute = web.Route{
 			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 			return
 		}
-		key, err := db.GetKeyForUser(user.Id)
+		key, err := db.GetStore().GetKeyForUser(user.Id)
 		if err != nil {
 			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 			return
@@ -0,0 +1,75 @@
+package index
+
+import (
+	"github.com/jchavannes/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/auth"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"github.com/memocash/memo/app/bus"
+	"github.com/memocash/memo/app/cache"
+	"github.com/memocash/memo/app/html-parser"
+	"github.com/memocash/memo/app/res"
+	"github.com/memocash/memo/app/watcher"
+	"net/http"
+)
+
+// Subscribe to any combination of topics, users and threads, e.g. /socket?topic=memo&user=1abc&thread=abc.
+// Logged in users can also subscribe to their own notifications with notifications=1.
+var socketRoute = web.Route{
+	Pattern: res.UrlSocket,
+	Handler: func(r *web.Response) {
+		var channels []string
+		query := r.Request.HttpRequest.URL.Query()
+		for _, topic := range query["topic"] {
+			channels = append(channels, bus.TopicChannel(html_parser.EscapeWithEmojis(topic)))
+		}
+		for _, addressString := range query["user"] {
+			address := wallet.GetAddressFromString(addressString)
+			if len(address.GetScriptAddress()) == 0 {
+				r.SetResponseCode(http.StatusUnprocessableEntity)
+				return
+			}
+			channels = append(channels, bus.UserChannel(address.GetScriptAddress()))
+		}
+		for _, threadTxHash := range query["thread"] {
+			txHash, err := chainhash.NewHashFromStr(threadTxHash)
+			if err != nil {
+				r.Error(jerr.Get("error parsing thread tx hash", err), http.StatusUnprocessableEntity)
+				return
+			}
+			channels = append(channels, bus.ThreadChannel(txHash.CloneBytes()))
+		}
+		if r.Request.GetUrlParameterBool("notifications") {
+			if ! auth.IsLoggedIn(r.Session.CookieId) {
+				r.SetResponseCode(http.StatusUnauthorized)
+				return
+			}
+			user, err := auth.GetSessionUser(r.Session.CookieId)
+			if err != nil {
+				r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+				return
+			}
+			pkHash, err := cache.GetUserPkHash(user.Id)
+			if err != nil {
+				r.Error(jerr.Get("error getting pk hash from cache", err), http.StatusInternalServerError)
+				return
+			}
+			channels = append(channels, bus.NotificationChannel(pkHash))
+		}
+		if len(channels) == 0 {
+			r.SetResponseCode(http.StatusUnprocessableEntity)
+			return
+		}
+		socket, err := r.GetWebSocket()
+		if err != nil {
+			r.Error(jerr.Get("error getting socket", err), http.StatusUnprocessableEntity)
+			return
+		}
+		err = watcher.RegisterChannelsSocket(socket, channels)
+		if err != nil {
+			r.Error(jerr.Get("error writing to socket", err), http.StatusInternalServerError)
+			return
+		}
+	},
+}
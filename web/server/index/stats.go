@@ -11,17 +11,22 @@ import (
 var statsRoute = web.Route{
 	Pattern: res.UrlStats,
 	Handler: func(r *web.Response) {
-		memoFollowCount, err := db.GetCountMemoFollows()
+		memoFollowCount, err := db.GetStore().GetCountMemoFollows()
 		if err != nil {
 			r.Error(jerr.Get("error getting memo follow count", err), http.StatusInternalServerError)
 			return
 		}
-		memoLikeCount, err := db.GetCountMemoLikes()
+		memoLikeCount, err := db.GetStore().GetCountMemoLikes()
 		if err != nil {
 			r.Error(jerr.Get("error getting memo like count", err), http.StatusInternalServerError)
 			return
 		}
-		memoPostCount, memoVotePostCount, memoTopicPostCount, memoReplyPostCount, err := db.GetCountMemoPosts()
+		memoRepostCount, err := db.GetCountMemoReposts()
+		if err != nil {
+			r.Error(jerr.Get("error getting memo repost count", err), http.StatusInternalServerError)
+			return
+		}
+		memoPostCount, memoVotePostCount, memoTopicPostCount, memoReplyPostCount, err := db.GetStore().GetCountMemoPosts()
 		if err != nil {
 			r.Error(jerr.Get("error getting memo post count", err), http.StatusInternalServerError)
 			return
@@ -68,6 +73,7 @@ var statsRoute = web.Route{
 		}
 		r.Helper["MemoFollowCount"] = int64(memoFollowCount)
 		r.Helper["MemoLikeCount"] = int64(memoLikeCount)
+		r.Helper["MemoRepostCount"] = int64(memoRepostCount)
 		r.Helper["MemoPostCount"] = int64(memoPostCount)
 		r.Helper["MemoVotePostCount"] = int64(memoVotePostCount)
 		r.Helper["MemoReplyPostCount"] = int64(memoReplyPostCount)
@@ -86,6 +92,7 @@ var statsRoute = web.Route{
 			int64(memoTopicPostCount)
 		r.Helper["MemoTotalActionCount"] = int64(memoFollowCount +
 			memoLikeCount +
+			memoRepostCount +
 			memoPostCount +
 			memoReplyPostCount +
 			memoTopicPostCount +
@@ -31,7 +31,7 @@ var changePasswordSubmitRoute = web.Route{
 		oldPassword := r.Request.GetFormValue("oldPassword")
 		newPassword := r.Request.GetFormValue("newPassword")
 
-		key, err := db.GetKeyForUser(user.Id)
+		key, err := db.GetStore().GetKeyForUser(user.Id)
 		if err != nil {
 			r.Error(jerr.Get("error getting key", err), http.StatusInternalServerError)
 			return
@@ -36,7 +36,7 @@ var deleteAccountSubmitRoute = web.Route{
 			r.Error(jerr.New("delete account confirmation did not match"), http.StatusUnprocessableEntity)
 		}
 
-		key, err := db.GetKeyForUser(user.Id)
+		key, err := db.GetStore().GetKeyForUser(user.Id)
 		if err != nil {
 			r.Error(jerr.Get("error getting key", err), http.StatusInternalServerError)
 			return
@@ -20,7 +20,7 @@ var viewKeyRoute = web.Route{
 			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 			return
 		}
-		key, err := db.GetKeyForUser(user.Id)
+		key, err := db.GetStore().GetKeyForUser(user.Id)
 		if err != nil {
 			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 			return
@@ -44,7 +44,7 @@ var loadKeyRoute = web.Route{
 		id := r.Request.GetFormValueUint("id")
 		password := r.Request.GetFormValue("password")
 
-		dbPrivateKey, err := db.GetKey(uint(id), user.Id)
+		dbPrivateKey, err := db.GetStore().GetKey(uint(id), user.Id)
 		if err != nil {
 			r.Error(jerr.Get("error getting key", err), http.StatusInternalServerError)
 			return
@@ -55,6 +55,19 @@ var loadKeyRoute = web.Route{
 			return
 		}
 		r.Helper["PrivateKey"] = privateKey
+		if dbPrivateKey.IsHd() {
+			hdWallet, err := dbPrivateKey.GetWallet(password)
+			if err != nil {
+				r.Error(jerr.Get("error unlocking wallet", err), http.StatusUnauthorized)
+				return
+			}
+			mnemonic, err := hdWallet.GetMnemonic()
+			if err != nil {
+				r.Error(jerr.Get("error getting mnemonic", err), http.StatusInternalServerError)
+				return
+			}
+			r.Helper["Mnemonic"] = mnemonic
+		}
 
 		var qr *qrcode.QRCode
 		qr, err = qrcode.New(privateKey.GetBase58Compressed(), qrcode.Medium)
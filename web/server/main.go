@@ -5,18 +5,26 @@ import (
 	"github.com/jchavannes/jgo/web"
 	"github.com/memocash/memo/app/auth"
 	"github.com/memocash/memo/app/bitcoin/queuer"
+	"github.com/memocash/memo/app/bitcoin/transaction"
+	"github.com/memocash/memo/app/bus"
 	"github.com/memocash/memo/app/cache"
 	"github.com/memocash/memo/app/config"
 	"github.com/memocash/memo/app/db"
 	"github.com/memocash/memo/app/metric"
 	"github.com/memocash/memo/app/res"
+	"github.com/memocash/memo/web/server/activitypub"
+	"github.com/memocash/memo/web/server/admin"
+	"github.com/memocash/memo/web/server/api"
 	auth2 "github.com/memocash/memo/web/server/auth"
+	"github.com/memocash/memo/web/server/feeds"
 	"github.com/memocash/memo/web/server/index"
 	"github.com/memocash/memo/web/server/key"
 	"github.com/memocash/memo/web/server/memo"
+	"github.com/memocash/memo/web/server/messages"
 	"github.com/memocash/memo/web/server/poll"
 	"github.com/memocash/memo/web/server/posts"
 	"github.com/memocash/memo/web/server/profile"
+	"github.com/memocash/memo/web/server/search"
 	"github.com/memocash/memo/web/server/topics"
 	"github.com/nicksnyder/go-i18n/i18n"
 	"io/ioutil"
@@ -37,7 +45,7 @@ func isLoggedIn(r *web.Response) bool {
 }
 
 func getCsrfToken(cookieId string) string {
-	token, err := db.GetCsrfTokenString(cookieId)
+	token, err := db.GetStore().GetCsrfTokenString(cookieId)
 	if err != nil {
 		jerr.Get("error getting csrf token", err).Print()
 		return ""
@@ -64,6 +72,7 @@ func preHandler(r *web.Response) {
 			return
 		}
 		r.Helper["Username"] = user.Username
+		r.Helper["IsAdmin"] = user.Admin
 		userAddress, err := cache.GetUserAddress(user.Id)
 		if err != nil {
 			r.Error(jerr.Get("error getting user address from cache", err), http.StatusInternalServerError)
@@ -92,6 +101,7 @@ func preHandler(r *web.Response) {
 	} else {
 		r.Helper["UserSettings"] = db.GetDefaultUserSettings()
 		r.Helper["IsLoggedIn"] = false
+		r.Helper["IsAdmin"] = false
 	}
 	memoContext := r.Request.GetHeader("memo-context")
 	if memoContext == "mobile-app" {
@@ -191,6 +201,10 @@ func Run(sessionCookieInsecure bool, port int) {
 	go func() {
 		queuer.StartAndKeepAlive()
 	}()
+	go transaction.RebroadcastPending()
+	if busAddress := config.GetBusAddress(); busAddress != "" {
+		go bus.Connect(busAddress)
+	}
 
 	var langDir = "web/lang"
 	files, err := ioutil.ReadDir(langDir)
@@ -221,7 +235,13 @@ func Run(sessionCookieInsecure bool, port int) {
 			key.GetRoutes(),
 			auth2.GetRoutes(),
 			memo.GetRoutes(),
+			messages.GetRoutes(),
+			admin.GetRoutes(),
 			profile.GetRoutes(),
+			search.GetRoutes(),
+			feeds.GetRoutes(),
+			activitypub.GetRoutes(),
+			api.GetRoutes(),
 		),
 		StaticFilesDir: "web/public",
 		TemplatesDir:   "web/templates",
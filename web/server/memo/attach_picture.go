@@ -0,0 +1,152 @@
+package memo
+
+import (
+	"bytes"
+	"github.com/jchavannes/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/auth"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"github.com/memocash/memo/app/bitcoin/transaction"
+	"github.com/memocash/memo/app/bitcoin/transaction/build"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/mutex"
+	"github.com/memocash/memo/app/profile"
+	"github.com/memocash/memo/app/res"
+	"github.com/memocash/memo/app/util"
+	"net/http"
+	"strings"
+)
+
+var attachPictureRoute = web.Route{
+	Pattern:    res.UrlMemoAttachPicture + "/" + urlTxHash.UrlPart(),
+	NeedsLogin: true,
+	Handler: func(r *web.Response) {
+		txHashString := r.Request.GetUrlNamedQueryVariable(urlTxHash.Id)
+		txHash, err := chainhash.NewHashFromStr(txHashString)
+		if err != nil {
+			r.Error(jerr.Get("error getting transaction hash", err), http.StatusInternalServerError)
+			return
+		}
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		key, err := db.GetStore().GetKeyForUser(user.Id)
+		if err != nil {
+			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
+			return
+		}
+		hasSpendableTxOut, err := db.HasSpendable(key.PkHash)
+		if err != nil {
+			r.Error(jerr.Get("error getting spendable tx out", err), http.StatusInternalServerError)
+			return
+		}
+		if ! hasSpendableTxOut {
+			r.SetRedirect(res.UrlNeedFunds)
+			return
+		}
+		post, err := profile.GetPostByTxHash(txHash.CloneBytes(), key.PkHash)
+		if err != nil {
+			if profile.IsPostHiddenError(err) {
+				r.SetResponseCode(http.StatusUnavailableForLegalReasons)
+				r.RenderTemplate(res.TmplMemoPostHidden)
+				return
+			}
+			r.Error(jerr.Get("error getting post", err), http.StatusInternalServerError)
+			return
+		}
+		if ! post.IsSelf() {
+			r.Error(jerr.New("pictures can only be attached to your own posts"), http.StatusUnprocessableEntity)
+			return
+		}
+		imageBaseUrl, err := db.GetImageBaseUrlForPkHash(key.PkHash)
+		if err != nil {
+			r.Error(jerr.Get("error getting image base url", err), http.StatusInternalServerError)
+			return
+		}
+		r.Helper["Post"] = post
+		r.Helper["ImageBaseUrl"] = imageBaseUrl
+		r.RenderTemplate(res.UrlMemoAttachPicture)
+	},
+}
+
+var attachPictureSubmitRoute = web.Route{
+	Pattern:     res.UrlMemoAttachPictureSubmit,
+	NeedsLogin:  true,
+	CsrfProtect: true,
+	Handler: func(r *web.Response) {
+		txHashString := r.Request.GetFormValue("txHash")
+		txHash, err := chainhash.NewHashFromStr(txHashString)
+		if err != nil {
+			r.Error(jerr.Get("error getting transaction hash", err), http.StatusInternalServerError)
+			return
+		}
+		url := strings.TrimSpace(r.Request.GetFormValue("url"))
+		if len(url) == 0 || len(url) > memo.MaxPictureUrlSize {
+			r.Error(jerr.New("invalid picture url length"), http.StatusUnprocessableEntity)
+			return
+		}
+		password := r.Request.GetFormValue("password")
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		key, err := db.GetStore().GetKeyForUser(user.Id)
+		if err != nil {
+			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
+			return
+		}
+		memoPost, err := db.GetStore().GetMemoPost(txHash.CloneBytes())
+		if err != nil {
+			r.Error(jerr.Get("error getting memo post", err), http.StatusInternalServerError)
+			return
+		}
+		if ! bytes.Equal(memoPost.PkHash, key.PkHash) {
+			r.Error(jerr.New("pictures can only be attached to your own posts"), http.StatusUnprocessableEntity)
+			return
+		}
+		var fullUrl = url
+		if ! strings.HasPrefix(url, "https://") {
+			imageBaseUrl, err := db.GetImageBaseUrlForPkHash(key.PkHash)
+			if err != nil {
+				r.Error(jerr.Get("error getting image base url", err), http.StatusInternalServerError)
+				return
+			}
+			if imageBaseUrl == nil {
+				r.Error(jerr.New("relative url requires an image base url"), http.StatusUnprocessableEntity)
+				return
+			}
+			fullUrl = strings.TrimRight(imageBaseUrl.Url, "/") + "/" + strings.TrimLeft(url, "/")
+		}
+		if ! util.ValidateImageLink(fullUrl) {
+			r.Error(jerr.New("invalid picture url"), http.StatusUnprocessableEntity)
+			return
+		}
+		privateKeys, err := key.GetSpendingKeys(password)
+		if err != nil {
+			r.Error(jerr.Get("error getting spending keys", err), http.StatusUnauthorized)
+			return
+		}
+
+		pkHash := privateKeys[0].GetPublicKey().GetAddress().GetScriptAddress()
+		mutex.Lock(pkHash)
+
+		tx, err := build.AttachPicture(txHash.CloneBytes(), url, privateKeys)
+		if err != nil {
+			var statusCode = http.StatusInternalServerError
+			if build.IsNotEnoughValueError(err) {
+				statusCode = http.StatusPaymentRequired
+			}
+			mutex.Unlock(pkHash)
+			r.Error(jerr.Get("error building attach picture tx", err), statusCode)
+			return
+		}
+
+		transaction.GetTxInfo(tx).Print()
+		transaction.QueueTx(tx)
+		r.Write(tx.MsgTx.TxHash().String())
+	},
+}
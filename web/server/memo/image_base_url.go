@@ -0,0 +1,101 @@
+package memo
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/auth"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"github.com/memocash/memo/app/bitcoin/transaction"
+	"github.com/memocash/memo/app/bitcoin/transaction/build"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/mutex"
+	"github.com/memocash/memo/app/res"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+var setImageBaseUrlRoute = web.Route{
+	Pattern:    res.UrlMemoImageBaseUrl,
+	NeedsLogin: true,
+	Handler: func(r *web.Response) {
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		key, err := db.GetStore().GetKeyForUser(user.Id)
+		if err != nil {
+			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
+			return
+		}
+		hasSpendableTxOut, err := db.HasSpendable(key.PkHash)
+		if err != nil {
+			r.Error(jerr.Get("error getting spendable tx out", err), http.StatusInternalServerError)
+			return
+		}
+		if ! hasSpendableTxOut {
+			r.SetRedirect(res.UrlNeedFunds)
+			return
+		}
+		imageBaseUrl, err := db.GetImageBaseUrlForPkHash(key.PkHash)
+		if err != nil {
+			r.Error(jerr.Get("error getting image base url", err), http.StatusInternalServerError)
+			return
+		}
+		r.Helper["ImageBaseUrl"] = imageBaseUrl
+		r.Render()
+	},
+}
+
+var setImageBaseUrlSubmitRoute = web.Route{
+	Pattern:     res.UrlMemoImageBaseUrlSubmit,
+	NeedsLogin:  true,
+	CsrfProtect: true,
+	Handler: func(r *web.Response) {
+		url := strings.TrimSpace(r.Request.GetFormValue("url"))
+		if len(url) > memo.MaxImageBaseUrlSize {
+			r.Error(jerr.New("image base url too long"), http.StatusUnprocessableEntity)
+			return
+		}
+		urlMatch, err := regexp.MatchString(`^https://[^\s?#]+$`, url)
+		if err != nil || ! urlMatch {
+			r.Error(jerr.Get("must pass an https url", err), http.StatusUnprocessableEntity)
+			return
+		}
+		password := r.Request.GetFormValue("password")
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		key, err := db.GetStore().GetKeyForUser(user.Id)
+		if err != nil {
+			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
+			return
+		}
+		privateKeys, err := key.GetSpendingKeys(password)
+		if err != nil {
+			r.Error(jerr.Get("error getting spending keys", err), http.StatusUnauthorized)
+			return
+		}
+
+		pkHash := privateKeys[0].GetPublicKey().GetAddress().GetScriptAddress()
+		mutex.Lock(pkHash)
+
+		tx, err := build.ImageBaseUrl(url, privateKeys)
+		if err != nil {
+			var statusCode = http.StatusInternalServerError
+			if build.IsNotEnoughValueError(err) {
+				statusCode = http.StatusPaymentRequired
+			}
+			mutex.Unlock(pkHash)
+			r.Error(jerr.Get("error building set image base url tx", err), statusCode)
+			return
+		}
+
+		transaction.GetTxInfo(tx).Print()
+		transaction.QueueTx(tx)
+		r.Write(tx.MsgTx.TxHash().String())
+	},
+}
@@ -29,7 +29,7 @@ var likeRoute = web.Route{
 			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 			return
 		}
-		key, err := db.GetKeyForUser(user.Id)
+		key, err := db.GetStore().GetKeyForUser(user.Id)
 		if err != nil {
 			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 			return
@@ -45,6 +45,11 @@ var likeRoute = web.Route{
 		}
 		post, err := profile.GetPostByTxHashWithReplies(txHash.CloneBytes(), key.PkHash, 0)
 		if err != nil {
+			if profile.IsPostHiddenError(err) {
+				r.SetResponseCode(http.StatusUnavailableForLegalReasons)
+				r.RenderTemplate(res.TmplMemoPostHidden)
+				return
+			}
 			r.Error(jerr.Get("error getting post", err), http.StatusInternalServerError)
 			return
 		}
@@ -86,25 +91,25 @@ var likeSubmitRoute = web.Route{
 			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 			return
 		}
-		key, err := db.GetKeyForUser(user.Id)
+		key, err := db.GetStore().GetKeyForUser(user.Id)
 		if err != nil {
 			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 			return
 		}
 
-		privateKey, err := key.GetPrivateKey(password)
+		privateKeys, err := key.GetSpendingKeys(password)
 		if err != nil {
-			r.Error(jerr.Get("error getting private key", err), http.StatusUnauthorized)
+			r.Error(jerr.Get("error getting spending keys", err), http.StatusUnauthorized)
 			return
 		}
 
 		var likeTxBytes = txHash.CloneBytes()
 		var tip = int64(r.Request.GetFormValueInt("tip"))
 
-		pkHash := privateKey.GetPublicKey().GetAddress().GetScriptAddress()
+		pkHash := privateKeys[0].GetPublicKey().GetAddress().GetScriptAddress()
 		mutex.Lock(pkHash)
 
-		tx, err := build.Like(likeTxBytes, tip, privateKey)
+		tx, err := build.Like(likeTxBytes, tip, privateKeys)
 		if err != nil {
 			var statusCode = http.StatusInternalServerError
 			if build.IsNotEnoughValueError(err) {
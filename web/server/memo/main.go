@@ -31,12 +31,20 @@ func GetRoutes() []web.Route {
 		likeSubmitRoute,
 		replyRoute,
 		replySubmitRoute,
+		repostRoute,
+		repostSubmitRoute,
 		waitRoute,
 		waitSubmitRoute,
 		setProfileRoute,
 		setProfileSubmitRoute,
 		setPicRoute,
 		setPicSubmitRoute,
+		setImageBaseUrlRoute,
+		setImageBaseUrlSubmitRoute,
+		attachPictureRoute,
+		attachPictureSubmitRoute,
+		reportRoute,
+		reportSubmitRoute,
 		setLangRoute,
 	}
 }
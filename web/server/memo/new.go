@@ -22,7 +22,7 @@ var newRoute = web.Route{
 			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 			return
 		}
-		key, err := db.GetKeyForUser(user.Id)
+		key, err := db.GetStore().GetKeyForUser(user.Id)
 		if err != nil {
 			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 			return
@@ -52,22 +52,22 @@ var newSubmitRoute = web.Route{
 			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 			return
 		}
-		key, err := db.GetKeyForUser(user.Id)
+		key, err := db.GetStore().GetKeyForUser(user.Id)
 		if err != nil {
 			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 			return
 		}
 
-		privateKey, err := key.GetPrivateKey(password)
+		privateKeys, err := key.GetSpendingKeys(password)
 		if err != nil {
-			r.Error(jerr.Get("error getting private key", err), http.StatusUnauthorized)
+			r.Error(jerr.Get("error getting spending keys", err), http.StatusUnauthorized)
 			return
 		}
 
-		pkHash := privateKey.GetPublicKey().GetAddress().GetScriptAddress()
+		pkHash := privateKeys[0].GetPublicKey().GetAddress().GetScriptAddress()
 		mutex.Lock(pkHash)
 
-		tx, err := build.MemoMessage(message, privateKey)
+		tx, err := build.MemoMessage(message, privateKeys)
 		if err != nil {
 			var statusCode = http.StatusInternalServerError
 			if build.IsNotEnoughValueError(err) {
@@ -24,7 +24,7 @@ var setPicRoute = web.Route{
 			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 			return
 		}
-		key, err := db.GetKeyForUser(user.Id)
+		key, err := db.GetStore().GetKeyForUser(user.Id)
 		if err != nil {
 			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 			return
@@ -78,15 +78,15 @@ var setPicSubmitRoute = web.Route{
 			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 			return
 		}
-		key, err := db.GetKeyForUser(user.Id)
+		key, err := db.GetStore().GetKeyForUser(user.Id)
 		if err != nil {
 			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 			return
 		}
 
-		privateKey, err := key.GetPrivateKey(password)
+		privateKeys, err := key.GetSpendingKeys(password)
 		if err != nil {
-			r.Error(jerr.Get("error getting private key", err), http.StatusUnauthorized)
+			r.Error(jerr.Get("error getting spending keys", err), http.StatusUnauthorized)
 			return
 		}
 
@@ -108,10 +108,10 @@ var setPicSubmitRoute = web.Route{
 		}
 		response.Body.Close()
 
-		pkHash := privateKey.GetPublicKey().GetAddress().GetScriptAddress()
+		pkHash := privateKeys[0].GetPublicKey().GetAddress().GetScriptAddress()
 		mutex.Lock(pkHash)
 
-		tx, err := build.ProfilePic(url, privateKey)
+		tx, err := build.ProfilePic(url, privateKeys)
 		if err != nil {
 			var statusCode = http.StatusInternalServerError
 			if build.IsNotEnoughValueError(err) {
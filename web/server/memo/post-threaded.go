@@ -19,6 +19,11 @@ var postThreadedRoute = web.Route{
 		txHashString := r.Request.GetUrlNamedQueryVariable(urlTxHash.Id)
 		post, err := getPostWithThreads(r, txHashString, offset, true)
 		if err != nil {
+			if profile.IsPostHiddenError(err) {
+				r.SetResponseCode(http.StatusUnavailableForLegalReasons)
+				r.RenderTemplate(res.TmplMemoPostHidden)
+				return
+			}
 			r.Error(jerr.Get("error getting post with threads", err), http.StatusInternalServerError)
 			return
 		}
@@ -67,7 +72,7 @@ func getPostWithThreads(r *web.Response, txHashString string, offset int, showPa
 		if err != nil {
 			return nil, jerr.Get("error getting session user", err)
 		}
-		key, err := db.GetKeyForUser(user.Id)
+		key, err := db.GetStore().GetKeyForUser(user.Id)
 		if err != nil {
 			return nil, jerr.Get("error getting key for user", err)
 		}
@@ -19,6 +19,11 @@ var postRoute = web.Route{
 		txHashString := r.Request.GetUrlNamedQueryVariable(urlTxHash.Id)
 		post, err := getPostWithThreads(r, txHashString, offset, true)
 		if err != nil {
+			if profile.IsPostHiddenError(err) {
+				r.SetResponseCode(http.StatusUnavailableForLegalReasons)
+				r.RenderTemplate(res.TmplMemoPostHidden)
+				return
+			}
 			if db.IsRecordNotFoundError(err) {
 				r.Error(jerr.Get("error post not found", err), http.StatusNotFound)
 				r.RenderTemplate(res.UrlNotFound)
@@ -56,7 +61,7 @@ var postAjaxRoute = web.Route{
 				r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 				return
 			}
-			key, err := db.GetKeyForUser(user.Id)
+			key, err := db.GetStore().GetKeyForUser(user.Id)
 			if err != nil {
 				r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 				return
@@ -66,6 +71,9 @@ var postAjaxRoute = web.Route{
 		}
 		post, err := profile.GetPostByTxHash(txHash.CloneBytes(), pkHash)
 		if err != nil {
+			if profile.IsPostHiddenError(err) {
+				return
+			}
 			r.Error(jerr.Get("error getting post", err), http.StatusInternalServerError)
 			return
 		}
@@ -110,6 +118,9 @@ var postThreadedAjaxRoute = web.Route{
 		showParent := r.Request.GetUrlParameterBool("showParent")
 		post, err := getPostWithThreads(r, txHashString, offset, showParent)
 		if err != nil {
+			if profile.IsPostHiddenError(err) {
+				return
+			}
 			r.Error(jerr.Get("error getting post with threads", err), http.StatusInternalServerError)
 			return
 		}
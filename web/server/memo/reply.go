@@ -31,7 +31,7 @@ var replyRoute = web.Route{
 				r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 				return
 			}
-			key, err := db.GetKeyForUser(user.Id)
+			key, err := db.GetStore().GetKeyForUser(user.Id)
 			if err != nil {
 				r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 				return
@@ -40,6 +40,11 @@ var replyRoute = web.Route{
 		}
 		post, err := profile.GetPostByTxHashWithReplies(txHash.CloneBytes(), pkHash, 0)
 		if err != nil {
+			if profile.IsPostHiddenError(err) {
+				r.SetResponseCode(http.StatusUnavailableForLegalReasons)
+				r.RenderTemplate(res.TmplMemoPostHidden)
+				return
+			}
 			r.Error(jerr.Get("error getting post", err), http.StatusInternalServerError)
 			return
 		}
@@ -65,7 +70,7 @@ var replyRoute = web.Route{
 			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 			return
 		}
-		key, err := db.GetKeyForUser(user.Id)
+		key, err := db.GetStore().GetKeyForUser(user.Id)
 		if err != nil {
 			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 			return
@@ -101,21 +106,21 @@ var replySubmitRoute = web.Route{
 			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 			return
 		}
-		key, err := db.GetKeyForUser(user.Id)
+		key, err := db.GetStore().GetKeyForUser(user.Id)
 		if err != nil {
 			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 			return
 		}
-		privateKey, err := key.GetPrivateKey(password)
+		privateKeys, err := key.GetSpendingKeys(password)
 		if err != nil {
-			r.Error(jerr.Get("error getting private key", err), http.StatusUnauthorized)
+			r.Error(jerr.Get("error getting spending keys", err), http.StatusUnauthorized)
 			return
 		}
 
-		pkHash := privateKey.GetPublicKey().GetAddress().GetScriptAddress()
+		pkHash := privateKeys[0].GetPublicKey().GetAddress().GetScriptAddress()
 		mutex.Lock(pkHash)
 
-		tx, err := build.MemoReply(txHash.CloneBytes(), message, privateKey)
+		tx, err := build.MemoReply(txHash.CloneBytes(), message, privateKeys)
 		if err != nil {
 			var statusCode = http.StatusInternalServerError
 			if build.IsNotEnoughValueError(err) {
@@ -0,0 +1,92 @@
+package memo
+
+import (
+	"github.com/jchavannes/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/auth"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/profile"
+	"github.com/memocash/memo/app/res"
+	"net/http"
+)
+
+const maxReportReasonSize = 255
+
+var reportRoute = web.Route{
+	Pattern:    res.UrlMemoReport + "/" + urlTxHash.UrlPart(),
+	NeedsLogin: true,
+	Handler: func(r *web.Response) {
+		txHashString := r.Request.GetUrlNamedQueryVariable(urlTxHash.Id)
+		txHash, err := chainhash.NewHashFromStr(txHashString)
+		if err != nil {
+			r.Error(jerr.Get("error getting transaction hash", err), http.StatusUnprocessableEntity)
+			return
+		}
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		key, err := db.GetStore().GetKeyForUser(user.Id)
+		if err != nil {
+			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
+			return
+		}
+		post, err := profile.GetPostByTxHash(txHash.CloneBytes(), key.PkHash)
+		if err != nil {
+			if profile.IsPostHiddenError(err) {
+				r.SetResponseCode(http.StatusUnavailableForLegalReasons)
+				r.RenderTemplate(res.TmplMemoPostHidden)
+				return
+			}
+			if db.IsRecordNotFoundError(err) {
+				r.Error(jerr.Get("error post not found", err), http.StatusNotFound)
+				return
+			}
+			r.Error(jerr.Get("error getting post", err), http.StatusInternalServerError)
+			return
+		}
+		r.Helper["Post"] = post
+		r.Helper["MaxReasonSize"] = maxReportReasonSize
+		r.RenderTemplate(res.TmplMemoReport)
+	},
+}
+
+var reportSubmitRoute = web.Route{
+	Pattern:     res.UrlMemoReportSubmit,
+	NeedsLogin:  true,
+	CsrfProtect: true,
+	Handler: func(r *web.Response) {
+		txHashString := r.Request.GetFormValue("txHash")
+		txHash, err := chainhash.NewHashFromStr(txHashString)
+		if err != nil {
+			r.Error(jerr.Get("error parsing transaction hash", err), http.StatusUnprocessableEntity)
+			return
+		}
+		reason := r.Request.GetFormValue("reason")
+		if len(reason) == 0 || len(reason) > maxReportReasonSize {
+			r.Error(jerr.Newf("reason must be between 1 and %d characters", maxReportReasonSize), http.StatusUnprocessableEntity)
+			return
+		}
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		_, err = db.GetStore().GetMemoPost(txHash.CloneBytes())
+		if err != nil {
+			if db.IsRecordNotFoundError(err) {
+				r.Error(jerr.Get("post not found", err), http.StatusNotFound)
+				return
+			}
+			r.Error(jerr.Get("error getting post", err), http.StatusInternalServerError)
+			return
+		}
+		_, err = db.AddPostReport(txHash.CloneBytes(), user.Id, reason)
+		if err != nil {
+			r.Error(jerr.Get("error adding post report", err), http.StatusInternalServerError)
+			return
+		}
+	},
+}
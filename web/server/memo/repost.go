@@ -0,0 +1,123 @@
+package memo
+
+import (
+	"github.com/jchavannes/btcd/chaincfg/chainhash"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/auth"
+	"github.com/memocash/memo/app/bitcoin/transaction"
+	"github.com/memocash/memo/app/bitcoin/transaction/build"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/mutex"
+	"github.com/memocash/memo/app/profile"
+	"github.com/memocash/memo/app/res"
+	"net/http"
+)
+
+var repostRoute = web.Route{
+	Pattern:    res.UrlMemoRepost + "/" + urlTxHash.UrlPart(),
+	NeedsLogin: true,
+	Handler: func(r *web.Response) {
+		txHashString := r.Request.GetUrlNamedQueryVariable(urlTxHash.Id)
+		txHash, err := chainhash.NewHashFromStr(txHashString)
+		if err != nil {
+			r.Error(jerr.Get("error getting transaction hash", err), http.StatusInternalServerError)
+			return
+		}
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		key, err := db.GetStore().GetKeyForUser(user.Id)
+		if err != nil {
+			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
+			return
+		}
+		hasSpendableTxOut, err := db.HasSpendable(key.PkHash)
+		if err != nil {
+			r.Error(jerr.Get("error getting spendable tx out", err), http.StatusInternalServerError)
+			return
+		}
+		if ! hasSpendableTxOut {
+			r.SetRedirect(res.UrlNeedFunds)
+			return
+		}
+		post, err := profile.GetPostByTxHash(txHash.CloneBytes(), key.PkHash)
+		if err != nil {
+			if profile.IsPostHiddenError(err) {
+				r.SetResponseCode(http.StatusUnavailableForLegalReasons)
+				r.RenderTemplate(res.TmplMemoPostHidden)
+				return
+			}
+			r.Error(jerr.Get("error getting post", err), http.StatusInternalServerError)
+			return
+		}
+		err = profile.AttachParentToPosts([]*profile.Post{post})
+		if err != nil {
+			r.Error(jerr.Get("error attaching parent to post", err), http.StatusInternalServerError)
+			return
+		}
+		err = profile.AttachPollsToPosts([]*profile.Post{post})
+		if err != nil {
+			r.Error(jerr.Get("error attaching polls to posts", err), http.StatusInternalServerError)
+			return
+		}
+		err = profile.AttachLikesToPosts([]*profile.Post{post})
+		if err != nil {
+			r.Error(jerr.Get("error attaching likes to posts", err), http.StatusInternalServerError)
+			return
+		}
+		r.Helper["Post"] = post
+		r.RenderTemplate(res.UrlMemoRepost)
+	},
+}
+
+var repostSubmitRoute = web.Route{
+	Pattern:     res.UrlMemoRepostSubmit,
+	NeedsLogin:  true,
+	CsrfProtect: true,
+	Handler: func(r *web.Response) {
+		txHashString := r.Request.GetFormValue("txHash")
+		txHash, err := chainhash.NewHashFromStr(txHashString)
+		if err != nil {
+			r.Error(jerr.Get("error getting transaction hash", err), http.StatusInternalServerError)
+			return
+		}
+		message := r.Request.GetFormValue("message")
+		password := r.Request.GetFormValue("password")
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		key, err := db.GetStore().GetKeyForUser(user.Id)
+		if err != nil {
+			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
+			return
+		}
+		privateKeys, err := key.GetSpendingKeys(password)
+		if err != nil {
+			r.Error(jerr.Get("error getting spending keys", err), http.StatusUnauthorized)
+			return
+		}
+
+		pkHash := privateKeys[0].GetPublicKey().GetAddress().GetScriptAddress()
+		mutex.Lock(pkHash)
+
+		tx, err := build.Repost(txHash.CloneBytes(), message, privateKeys)
+		if err != nil {
+			var statusCode = http.StatusInternalServerError
+			if build.IsNotEnoughValueError(err) {
+				statusCode = http.StatusPaymentRequired
+			}
+			mutex.Unlock(pkHash)
+			r.Error(jerr.Get("error building repost tx", err), statusCode)
+			return
+		}
+
+		transaction.GetTxInfo(tx).Print()
+		transaction.QueueTx(tx)
+		r.Write(tx.MsgTx.TxHash().String())
+	},
+}
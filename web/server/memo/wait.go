@@ -39,7 +39,11 @@ var waitSubmitRoute = web.Route{
 			r.Error(jerr.Get("error getting transaction hash", err), http.StatusInternalServerError)
 			return
 		}
-		err = transaction.WaitForTx(txHash)
+		txHash, err = transaction.WaitForTx(txHash)
+		if transaction.IsTxRejectedError(err) {
+			r.Error(jerr.Getf(err, "transaction rejected (%s)", txHashString), http.StatusUnprocessableEntity)
+			return
+		}
 		if err != nil {
 			r.Error(jerr.Getf(err, "error waiting for transaction (%s)", txHashString), http.StatusInternalServerError)
 			return
@@ -57,21 +61,21 @@ var waitSubmitRoute = web.Route{
 		mutex.Unlock(out.KeyPkHash)
 		switch out.PkScript[3] {
 		case memo.CodeFollow, memo.CodeUnfollow:
-			follow, err := db.GetMemoFollow(txHash.CloneBytes())
+			follow, err := db.GetStore().GetMemoFollow(txHash.CloneBytes())
 			if err != nil {
 				r.Error(jerr.Get("error getting follow from db", err), http.StatusInternalServerError)
 				return
 			}
 			r.Write(strings.TrimLeft(res.UrlProfileView + "/" + follow.GetFollowAddressString(), "/"))
 		case memo.CodeLike:
-			like, err := db.GetMemoLike(txHash.CloneBytes())
+			like, err := db.GetStore().GetMemoLike(txHash.CloneBytes())
 			if err != nil {
 				r.Error(jerr.Get("error getting like from db", err), http.StatusInternalServerError)
 				return
 			}
 			r.Write(strings.TrimLeft(res.UrlMemoPost + "/" + like.GetLikeTransactionHashString(), "/"))
 		case memo.CodePost:
-			post, err := db.GetMemoPost(txHash.CloneBytes())
+			post, err := db.GetStore().GetMemoPost(txHash.CloneBytes())
 			if err != nil {
 				r.Error(jerr.Get("error getting post from db", err), http.StatusInternalServerError)
 				return
@@ -92,14 +96,14 @@ var waitSubmitRoute = web.Route{
 			}
 			r.Write(strings.TrimLeft(res.UrlProfileAccount, "/"))
 		case memo.CodeReply:
-			post, err := db.GetMemoPost(txHash.CloneBytes())
+			post, err := db.GetStore().GetMemoPost(txHash.CloneBytes())
 			if err != nil {
 				r.Error(jerr.Get("error getting post from db", err), http.StatusInternalServerError)
 				return
 			}
 			r.Write(strings.TrimLeft(res.UrlMemoPost + "/" + post.GetTransactionHashString(), "/"))
 		case memo.CodeTopicMessage:
-			post, err := db.GetMemoPost(txHash.CloneBytes())
+			post, err := db.GetStore().GetMemoPost(txHash.CloneBytes())
 			if err != nil {
 				r.Error(jerr.Get("error getting post from db", err), http.StatusInternalServerError)
 				return
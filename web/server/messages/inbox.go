@@ -0,0 +1,113 @@
+package messages
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/auth"
+	"github.com/memocash/memo/app/cache"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/res"
+	"net/http"
+)
+
+type decryptedMessage struct {
+	DirectMessage *db.MemoDirectMessage
+	Message       string
+}
+
+var inboxRoute = web.Route{
+	Pattern:    res.UrlMessages,
+	NeedsLogin: true,
+	Handler: func(r *web.Response) {
+		offset := r.Request.GetUrlParameterInt("offset")
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		key, err := db.GetStore().GetKeyForUser(user.Id)
+		if err != nil {
+			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
+			return
+		}
+		directMessages, err := db.GetDirectMessagesForRecipient(key.PkHash, uint(offset))
+		if err != nil {
+			r.Error(jerr.Get("error getting direct messages", err), http.StatusInternalServerError)
+			return
+		}
+		directMessages, err = filterBlockedMessages(key.PkHash, directMessages)
+		if err != nil {
+			r.Error(jerr.Get("error filtering blocked messages", err), http.StatusInternalServerError)
+			return
+		}
+		r.Helper["Nav"] = "messages"
+		r.Helper["DirectMessages"] = directMessages
+		res.SetPageAndOffset(r, offset)
+		r.RenderTemplate(res.TmplMessagesInbox)
+	},
+}
+
+// Messages are decrypted with the user's key on request and never stored unencrypted.
+var decryptRoute = web.Route{
+	Pattern:     res.UrlMessagesDecrypt,
+	NeedsLogin:  true,
+	CsrfProtect: true,
+	Handler: func(r *web.Response) {
+		offset := r.Request.GetFormValueInt("offset")
+		password := r.Request.GetFormValue("password")
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		key, err := db.GetStore().GetKeyForUser(user.Id)
+		if err != nil {
+			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
+			return
+		}
+		privateKey, err := key.GetPrivateKey(password)
+		if err != nil {
+			r.Error(jerr.Get("error getting private key", err), http.StatusUnauthorized)
+			return
+		}
+		directMessages, err := db.GetDirectMessagesForRecipient(key.PkHash, uint(offset))
+		if err != nil {
+			r.Error(jerr.Get("error getting direct messages", err), http.StatusInternalServerError)
+			return
+		}
+		directMessages, err = filterBlockedMessages(key.PkHash, directMessages)
+		if err != nil {
+			r.Error(jerr.Get("error filtering blocked messages", err), http.StatusInternalServerError)
+			return
+		}
+		var decryptedMessages []*decryptedMessage
+		for _, directMessage := range directMessages {
+			message, err := privateKey.Decrypt(directMessage.Encrypted)
+			if err != nil {
+				jerr.Getf(err, "error decrypting direct message (%s)", directMessage.GetTransactionHashString()).Print()
+				continue
+			}
+			decryptedMessages = append(decryptedMessages, &decryptedMessage{
+				DirectMessage: directMessage,
+				Message:       string(message),
+			})
+		}
+		r.Helper["DecryptedMessages"] = decryptedMessages
+		r.RenderTemplate(res.TmplMessagesDecrypt)
+	},
+}
+
+// Messages from blocked addresses are hidden.
+func filterBlockedMessages(pkHash []byte, directMessages []*db.MemoDirectMessage) ([]*db.MemoDirectMessage, error) {
+	muteList, err := cache.GetMuteList(pkHash)
+	if err != nil {
+		return nil, jerr.Get("error getting mute list", err)
+	}
+	var filteredMessages []*db.MemoDirectMessage
+	for _, directMessage := range directMessages {
+		if ! muteList.IsPkHashBlocked(directMessage.PkHash) {
+			filteredMessages = append(filteredMessages, directMessage)
+		}
+	}
+	return filteredMessages, nil
+}
@@ -0,0 +1,13 @@
+package messages
+
+import "github.com/jchavannes/jgo/web"
+
+func GetRoutes() []web.Route {
+	return []web.Route{
+		inboxRoute,
+		sentRoute,
+		decryptRoute,
+		newRoute,
+		newSubmitRoute,
+	}
+}
@@ -0,0 +1,108 @@
+package messages
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/auth"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"github.com/memocash/memo/app/bitcoin/transaction"
+	"github.com/memocash/memo/app/bitcoin/transaction/build"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/mutex"
+	"github.com/memocash/memo/app/res"
+	"net/http"
+)
+
+var newRoute = web.Route{
+	Pattern:    res.UrlMessagesNew,
+	NeedsLogin: true,
+	Handler: func(r *web.Response) {
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		key, err := db.GetStore().GetKeyForUser(user.Id)
+		if err != nil {
+			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
+			return
+		}
+		hasSpendableTxOut, err := db.HasSpendable(key.PkHash)
+		if err != nil {
+			r.Error(jerr.Get("error getting spendable tx out", err), http.StatusInternalServerError)
+			return
+		}
+		if ! hasSpendableTxOut {
+			r.SetRedirect(res.UrlNeedFunds)
+			return
+		}
+		r.Helper["Nav"] = "messages"
+		r.Helper["Address"] = r.Request.GetUrlParameter("address")
+		r.RenderTemplate(res.TmplMessagesNew)
+	},
+}
+
+// The recipient's public key is needed to encrypt the message, so they must have signed a transaction.
+var newSubmitRoute = web.Route{
+	Pattern:     res.UrlMessagesNewSubmit,
+	NeedsLogin:  true,
+	CsrfProtect: true,
+	Handler: func(r *web.Response) {
+		addressString := r.Request.GetFormValue("address")
+		recipientAddress := wallet.GetAddressFromString(addressString)
+		if recipientAddress.GetEncoded() != addressString {
+			r.Error(jerr.New("error parsing address"), http.StatusUnprocessableEntity)
+			return
+		}
+		message := r.Request.GetFormValue("message")
+		if len(message) == 0 || len(message) > memo.MaxDirectMessageSize {
+			r.Error(jerr.Newf("message must be between 1 and %d bytes", memo.MaxDirectMessageSize), http.StatusUnprocessableEntity)
+			return
+		}
+		password := r.Request.GetFormValue("password")
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		key, err := db.GetStore().GetKeyForUser(user.Id)
+		if err != nil {
+			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
+			return
+		}
+		privateKeys, err := key.GetSpendingKeys(password)
+		if err != nil {
+			r.Error(jerr.Get("error getting spending keys", err), http.StatusUnauthorized)
+			return
+		}
+		recipientPkHash := recipientAddress.GetScriptAddress()
+		recipientPublicKey, err := db.GetPublicKeyForPkHash(recipientPkHash)
+		if err != nil {
+			if db.IsRecordNotFoundError(err) {
+				r.Error(jerr.New("recipient public key not found, the address has not sent a transaction"), http.StatusUnprocessableEntity)
+				return
+			}
+			r.Error(jerr.Get("error getting recipient public key", err), http.StatusInternalServerError)
+			return
+		}
+
+		pkHash := privateKeys[0].GetPublicKey().GetAddress().GetScriptAddress()
+		mutex.Lock(pkHash)
+
+		tx, err := build.DirectMessage(recipientPkHash, wallet.GetPublicKey(recipientPublicKey), message, privateKeys)
+		if err != nil {
+			var statusCode = http.StatusInternalServerError
+			if build.IsNotEnoughValueError(err) {
+				statusCode = http.StatusPaymentRequired
+			}
+			mutex.Unlock(pkHash)
+			r.Error(jerr.Get("error building direct message tx", err), statusCode)
+			return
+		}
+
+		transaction.GetTxInfo(tx).Print()
+		transaction.QueueTx(tx)
+		r.Write(tx.MsgTx.TxHash().String())
+	},
+}
@@ -0,0 +1,37 @@
+package messages
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/auth"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/res"
+	"net/http"
+)
+
+var sentRoute = web.Route{
+	Pattern:    res.UrlMessagesSent,
+	NeedsLogin: true,
+	Handler: func(r *web.Response) {
+		offset := r.Request.GetUrlParameterInt("offset")
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		key, err := db.GetStore().GetKeyForUser(user.Id)
+		if err != nil {
+			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
+			return
+		}
+		directMessages, err := db.GetDirectMessagesFromSender(key.PkHash, uint(offset))
+		if err != nil {
+			r.Error(jerr.Get("error getting sent direct messages", err), http.StatusInternalServerError)
+			return
+		}
+		r.Helper["Nav"] = "messages"
+		r.Helper["DirectMessages"] = directMessages
+		res.SetPageAndOffset(r, offset)
+		r.RenderTemplate(res.TmplMessagesSent)
+	},
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/jchavannes/jgo/jerr"
 	"github.com/jchavannes/jgo/web"
 	"github.com/memocash/memo/app/auth"
+	"github.com/memocash/memo/app/bitcoin/memo"
 	"github.com/memocash/memo/app/bitcoin/transaction"
 	"github.com/memocash/memo/app/bitcoin/transaction/build"
 	"github.com/memocash/memo/app/db"
@@ -25,39 +26,69 @@ var voteSubmitRoute = web.Route{
 			r.Error(jerr.Get("error getting transaction hash", err), http.StatusInternalServerError)
 			return
 		}
-		option := html_parser.EscapeWithEmojis(r.Request.GetFormValue("option"))
 		message := r.Request.GetFormValue("message")
 
-		memoPollOption, err := db.GetMemoPollOptionByOption(txHash.CloneBytes(), option)
+		question, err := db.GetMemoPollQuestion(txHash.CloneBytes())
 		if err != nil {
-			r.Error(jerr.Get("error getting memo_post", err), http.StatusInternalServerError)
+			r.Error(jerr.Get("error getting memo poll question", err), http.StatusInternalServerError)
 			return
 		}
 
+		var rankedTxHashes [][]byte
+		var optionTxHash []byte
+		if question.PollType == memo.CodePollTypeRank {
+			for _, rankedOption := range r.Request.GetFormValueSlice("ranking") {
+				rankedOption = html_parser.EscapeWithEmojis(rankedOption)
+				for _, option := range question.Options {
+					if option.Option == rankedOption {
+						rankedTxHashes = append(rankedTxHashes, option.TxHash)
+						break
+					}
+				}
+			}
+			if len(rankedTxHashes) == 0 {
+				r.Error(jerr.New("must rank at least one option"), http.StatusUnprocessableEntity)
+				return
+			}
+		} else {
+			option := html_parser.EscapeWithEmojis(r.Request.GetFormValue("option"))
+			memoPollOption, err := db.GetMemoPollOptionByOption(txHash.CloneBytes(), option)
+			if err != nil {
+				r.Error(jerr.Get("error getting memo_post", err), http.StatusInternalServerError)
+				return
+			}
+			optionTxHash = memoPollOption.TxHash
+		}
+
 		password := r.Request.GetFormValue("password")
 		user, err := auth.GetSessionUser(r.Session.CookieId)
 		if err != nil {
 			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 			return
 		}
-		key, err := db.GetKeyForUser(user.Id)
+		key, err := db.GetStore().GetKeyForUser(user.Id)
 		if err != nil {
 			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 			return
 		}
 
-		privateKey, err := key.GetPrivateKey(password)
+		privateKeys, err := key.GetSpendingKeys(password)
 		if err != nil {
-			r.Error(jerr.Get("error getting private key", err), http.StatusUnauthorized)
+			r.Error(jerr.Get("error getting spending keys", err), http.StatusUnauthorized)
 			return
 		}
 
 		var tip = int64(r.Request.GetFormValueInt("tip"))
 
-		pkHash := privateKey.GetPublicKey().GetAddress().GetScriptAddress()
+		pkHash := privateKeys[0].GetPublicKey().GetAddress().GetScriptAddress()
 		mutex.Lock(pkHash)
 
-		tx, err := build.Vote(memoPollOption.TxHash, message, tip, privateKey)
+		var tx *memo.Tx
+		if len(rankedTxHashes) > 0 {
+			tx, err = build.RankVote(question, rankedTxHashes, message, tip, privateKeys)
+		} else {
+			tx, err = build.Vote(optionTxHash, message, tip, privateKeys)
+		}
 		if err != nil {
 			mutex.Unlock(pkHash)
 			r.Error(jerr.Get("error building vote tx", err), http.StatusInternalServerError)
@@ -23,7 +23,13 @@ var votesAjaxRoute = web.Route{
 			r.Error(jerr.Get("error getting votes for tx hash", err), http.StatusInternalServerError)
 			return
 		}
+		rounds, err := profile.GetRankRoundsForTxHash(txHash.CloneBytes())
+		if err != nil {
+			r.Error(jerr.Get("error getting rank rounds for tx hash", err), http.StatusInternalServerError)
+			return
+		}
 		r.Helper["Votes"] = votes
+		r.Helper["Rounds"] = rounds
 		r.Render()
 	},
 }
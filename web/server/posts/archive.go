@@ -33,7 +33,7 @@ var archiveRoute = web.Route{
 				r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 				return
 			}
-			key, err := db.GetKeyForUser(user.Id)
+			key, err := db.GetStore().GetKeyForUser(user.Id)
 			if err != nil {
 				r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 				return
@@ -52,7 +52,7 @@ var archiveRoute = web.Route{
 		} else {
 			r.Helper["Today"] = false
 		}
-		posts, err := profile.GetTopPosts(userPkHash, uint(offset), timeStart, timeEnd, false)
+		posts, _, err := profile.GetTopPosts(userPkHash, uint(offset), timeStart, timeEnd, false)
 		if err != nil {
 			r.Error(jerr.Get("error getting recent posts", err), http.StatusInternalServerError)
 			return
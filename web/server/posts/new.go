@@ -28,7 +28,7 @@ var newRoute = web.Route{
 				r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 				return
 			}
-			key, err := db.GetKeyForUser(user.Id)
+			key, err := db.GetStore().GetKeyForUser(user.Id)
 			if err != nil {
 				r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 				return
@@ -36,7 +36,7 @@ var newRoute = web.Route{
 			userPkHash = key.PkHash
 			userId = user.Id
 		}
-		posts, err := profile.GetRecentPosts(userPkHash, uint(offset), searchString)
+		posts, _, err := profile.GetRecentPosts(userPkHash, uint(offset), searchString)
 		if err != nil {
 			r.Error(jerr.Get("error getting recent posts", err), http.StatusInternalServerError)
 			return
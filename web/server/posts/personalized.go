@@ -33,7 +33,7 @@ var personalizedRoute = web.Route{
 				r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 				return
 			}
-			key, err := db.GetKeyForUser(user.Id)
+			key, err := db.GetStore().GetKeyForUser(user.Id)
 			if err != nil {
 				r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 				return
@@ -41,7 +41,7 @@ var personalizedRoute = web.Route{
 			userPkHash = key.PkHash
 			userId = user.Id
 		}
-		posts, err := profile.GetTopPostsNamedRange(userPkHash, uint(offset), timeRange, true)
+		posts, _, err := profile.GetTopPostsNamedRange(userPkHash, uint(offset), timeRange, true)
 		if err != nil {
 			r.Error(jerr.Get("error getting top posts", err), http.StatusInternalServerError)
 			return
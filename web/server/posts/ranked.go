@@ -8,18 +8,21 @@ import (
 	"github.com/memocash/memo/app/db"
 	"github.com/memocash/memo/app/html-parser"
 	"github.com/memocash/memo/app/metric"
+	"github.com/memocash/memo/app/obj/rank"
 	"github.com/memocash/memo/app/profile"
 	"github.com/memocash/memo/app/res"
 	"net/http"
 	"strings"
 )
 
+// Ranking can be set with ?sort=likes|tips|discussion|personal, defaults to likes.
 var rankedRoute = web.Route{
 	Pattern: res.UrlPostsRanked,
 	Handler: func(r *web.Response) {
 		preHandler(r)
 		offset := r.Request.GetUrlParameterInt("offset")
 		searchString := html_parser.EscapeWithEmojis(r.Request.GetUrlParameter("s"))
+		ranker := rank.GetRanker(r.Request.GetUrlParameter("sort"))
 		var userPkHash []byte
 		var userId uint
 		if auth.IsLoggedIn(r.Session.CookieId) {
@@ -28,7 +31,7 @@ var rankedRoute = web.Route{
 				r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 				return
 			}
-			key, err := db.GetKeyForUser(user.Id)
+			key, err := db.GetStore().GetKeyForUser(user.Id)
 			if err != nil {
 				r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 				return
@@ -36,7 +39,7 @@ var rankedRoute = web.Route{
 			userPkHash = key.PkHash
 			userId = user.Id
 		}
-		posts, err := profile.GetRankedPosts(userPkHash, uint(offset), searchString)
+		posts, err := profile.GetRankedPosts(userPkHash, uint(offset), searchString, ranker)
 		if err != nil {
 			r.Error(jerr.Get("error getting ranked posts", err), http.StatusInternalServerError)
 			return
@@ -70,16 +73,18 @@ var rankedRoute = web.Route{
 		}
 		res.SetPageAndOffset(r, offset)
 		if searchString != "" {
-			r.Helper["OffsetLink"] = fmt.Sprintf("%s?s=%s", strings.TrimLeft(res.UrlPostsRanked, "/"), searchString)
+			r.Helper["OffsetLink"] = fmt.Sprintf("%s?sort=%s&s=%s", strings.TrimLeft(res.UrlPostsRanked, "/"), ranker.Name, searchString)
 			go func () {
 				metric.AddMemoPostSearch(searchString, res.UrlPostsRanked)
 			}()
 		} else {
-			r.Helper["OffsetLink"] = fmt.Sprintf("%s?", res.UrlPostsRanked)
+			r.Helper["OffsetLink"] = fmt.Sprintf("%s?sort=%s", res.UrlPostsRanked, ranker.Name)
 		}
 		r.Helper["SearchString"] = searchString
+		r.Helper["Sort"] = ranker.Name
 		r.Helper["Posts"] = posts
 		r.Helper["Title"] = "Memo - Ranked Posts"
+		r.Helper["FeedPath"] = "ranked"
 		r.Render()
 	},
 }
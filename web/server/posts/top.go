@@ -32,7 +32,7 @@ var topRoute = web.Route{
 				r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 				return
 			}
-			key, err := db.GetKeyForUser(user.Id)
+			key, err := db.GetStore().GetKeyForUser(user.Id)
 			if err != nil {
 				r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 				return
@@ -40,7 +40,7 @@ var topRoute = web.Route{
 			userPkHash = key.PkHash
 			userId = user.Id
 		}
-		posts, err := profile.GetTopPostsNamedRange(userPkHash, uint(offset), timeRange, false)
+		posts, _, err := profile.GetTopPostsNamedRange(userPkHash, uint(offset), timeRange, false)
 		if err != nil {
 			r.Error(jerr.Get("error getting top posts", err), http.StatusInternalServerError)
 			return
@@ -77,6 +77,7 @@ var topRoute = web.Route{
 		r.Helper["Posts"] = posts
 		r.Helper["Range"] = timeRange
 		r.Helper["Title"] = "Memo - Top Posts"
+		r.Helper["FeedPath"] = "top?range=" + timeRange
 		r.Render()
 	},
 }
@@ -4,7 +4,13 @@ import (
 	"github.com/jchavannes/jgo/jerr"
 	"github.com/jchavannes/jgo/web"
 	"github.com/memocash/memo/app/auth"
+	"github.com/memocash/memo/app/bitcoin/fee"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"github.com/memocash/memo/app/bitcoin/transaction"
+	"github.com/memocash/memo/app/bitcoin/transaction/build"
+	"github.com/memocash/memo/app/bitcoin/wallet"
 	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/mutex"
 	"github.com/memocash/memo/app/res"
 	"net/http"
 )
@@ -18,22 +24,214 @@ var coinsRoute = web.Route{
 			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 			return
 		}
-		key, err := db.GetKeyForUser(user.Id)
+		key, err := db.GetStore().GetKeyForUser(user.Id)
 		if err != nil {
 			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 			return
 		}
-		txOuts, err := db.GetSpendableTransactionOutputsForPkHash(key.PkHash)
+		txOuts, err := getSpendableTxOuts(key)
 		if err != nil {
 			r.Error(jerr.Get("error getting spendable tx outputs for user", err), http.StatusInternalServerError)
 			return
 		}
+		coinSelections, err := db.GetCoinSelection(key.PkHash)
+		if err != nil {
+			r.Error(jerr.Get("error getting coin selection", err), http.StatusInternalServerError)
+			return
+		}
 		var totalValue int64
 		for _, txOut := range txOuts {
 			totalValue += txOut.Value
 		}
+		var selected = make(map[string]bool)
+		for _, coinSelection := range coinSelections {
+			selected[db.TransactionOut{TransactionHash: coinSelection.TxHash, Index: coinSelection.OutIndex}.GetHashString()] = true
+		}
 		r.Helper["TxOuts"] = txOuts
 		r.Helper["TotalValue"] = totalValue
+		r.Helper["Selected"] = selected
+		r.Helper["ProfileAddress"] = key.GetAddress().GetEncoded()
+		r.Helper["MaxConsolidateInputs"] = build.MaxConsolidateInputs
+		r.Helper["MaxFeeRate"] = fee.MaxRate
 		r.RenderTemplate(res.TmplProfileCoins)
 	},
 }
+
+var coinsSelectSubmitRoute = web.Route{
+	Pattern:     res.UrlProfileCoinsSelectSubmit,
+	NeedsLogin:  true,
+	CsrfProtect: true,
+	Handler: func(r *web.Response) {
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		key, err := db.GetStore().GetKeyForUser(user.Id)
+		if err != nil {
+			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
+			return
+		}
+		txOuts, err := getSpendableTxOuts(key)
+		if err != nil {
+			r.Error(jerr.Get("error getting spendable tx outputs for user", err), http.StatusInternalServerError)
+			return
+		}
+		var coins = make(map[string]bool)
+		for _, coin := range r.Request.GetFormValueSlice("coins") {
+			coins[coin] = true
+		}
+		var selectedTxOuts []*db.TransactionOut
+		for _, txOut := range txOuts {
+			if coins[txOut.GetHashString()] {
+				selectedTxOuts = append(selectedTxOuts, txOut)
+			}
+		}
+		if len(selectedTxOuts) != len(coins) {
+			r.Error(jerr.New("selected coins are not spendable"), http.StatusUnprocessableEntity)
+			return
+		}
+		err = db.SetCoinSelection(key.PkHash, selectedTxOuts)
+		if err != nil {
+			r.Error(jerr.Get("error setting coin selection", err), http.StatusInternalServerError)
+			return
+		}
+	},
+}
+
+var coinsConsolidateSubmitRoute = web.Route{
+	Pattern:     res.UrlProfileCoinsConsolidateSubmit,
+	NeedsLogin:  true,
+	CsrfProtect: true,
+	Handler: func(r *web.Response) {
+		feeRate, ok := getFeeRate(r)
+		if ! ok {
+			return
+		}
+		privateKeys, ok := getSpendingKeys(r)
+		if ! ok {
+			return
+		}
+		pkHash := privateKeys[0].GetPublicKey().GetAddress().GetScriptAddress()
+		mutex.Lock(pkHash)
+		tx, err := build.Consolidate(feeRate, privateKeys)
+		if err != nil {
+			var statusCode = http.StatusInternalServerError
+			if build.IsNotEnoughValueError(err) {
+				statusCode = http.StatusPaymentRequired
+			} else if build.IsNothingToConsolidateError(err) {
+				statusCode = http.StatusUnprocessableEntity
+			}
+			mutex.Unlock(pkHash)
+			r.Error(jerr.Get("error building consolidate tx", err), statusCode)
+			return
+		}
+		queueAndWait(r, tx, pkHash)
+	},
+}
+
+var coinsSendSubmitRoute = web.Route{
+	Pattern:     res.UrlProfileCoinsSendSubmit,
+	NeedsLogin:  true,
+	CsrfProtect: true,
+	Handler: func(r *web.Response) {
+		address := wallet.GetAddressFromString(r.Request.GetFormValue("address"))
+		if len(address.GetScriptAddress()) == 0 {
+			r.Error(jerr.New("invalid address"), http.StatusUnprocessableEntity)
+			return
+		}
+		amount := int64(r.Request.GetFormValueInt("amount"))
+		if amount < memo.DustMinimumOutput {
+			r.Error(jerr.Newf("amount must be at least %d", memo.DustMinimumOutput), http.StatusUnprocessableEntity)
+			return
+		}
+		feeRate, ok := getFeeRate(r)
+		if ! ok {
+			return
+		}
+		privateKeys, ok := getSpendingKeys(r)
+		if ! ok {
+			return
+		}
+		pkHash := privateKeys[0].GetPublicKey().GetAddress().GetScriptAddress()
+		mutex.Lock(pkHash)
+		tx, err := build.Send(address, amount, feeRate, privateKeys)
+		if err != nil {
+			var statusCode = http.StatusInternalServerError
+			if build.IsNotEnoughValueError(err) {
+				statusCode = http.StatusPaymentRequired
+			}
+			mutex.Unlock(pkHash)
+			r.Error(jerr.Get("error building send tx", err), statusCode)
+			return
+		}
+		queueAndWait(r, tx, pkHash)
+	},
+}
+
+// Includes outputs on HD change addresses.
+func getSpendableTxOuts(key *db.Key) ([]*db.TransactionOut, error) {
+	var pkHashes = [][]byte{key.PkHash}
+	keyAddresses, err := db.GetKeyAddresses(key.Id)
+	if err != nil {
+		return nil, jerr.Get("error getting key addresses", err)
+	}
+	for _, keyAddress := range keyAddresses {
+		pkHashes = append(pkHashes, keyAddress.PkHash)
+	}
+	txOuts, err := db.GetSpendableTransactionOutputsForPkHashes(pkHashes)
+	if err != nil {
+		return nil, jerr.Get("error getting spendable tx outputs", err)
+	}
+	return txOuts, nil
+}
+
+func getSpendingKeys(r *web.Response) ([]*wallet.PrivateKey, bool) {
+	user, err := auth.GetSessionUser(r.Session.CookieId)
+	if err != nil {
+		r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+		return nil, false
+	}
+	key, err := db.GetStore().GetKeyForUser(user.Id)
+	if err != nil {
+		r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
+		return nil, false
+	}
+	privateKeys, err := key.GetSpendingKeys(r.Request.GetFormValue("password"))
+	if err != nil {
+		r.Error(jerr.Get("error getting spending keys", err), http.StatusUnauthorized)
+		return nil, false
+	}
+	return privateKeys, true
+}
+
+// A blank fee rate returns 0, which builds with the fee priority from the user's settings.
+func getFeeRate(r *web.Response) (int64, bool) {
+	feeRate := int64(r.Request.GetFormValueInt("feeRate"))
+	if feeRate == 0 {
+		return 0, true
+	}
+	if feeRate < fee.MinRate || feeRate > fee.MaxRate {
+		r.Error(jerr.Newf("fee rate must be between %d and %d", fee.MinRate, fee.MaxRate), http.StatusUnprocessableEntity)
+		return 0, false
+	}
+	return feeRate, true
+}
+
+// There is no memo output to wait for on the memo wait page, so the lock is released once the tx is seen here.
+func queueAndWait(r *web.Response, tx *memo.Tx, pkHash []byte) {
+	transaction.GetTxInfo(tx).Print()
+	transaction.QueueTx(tx)
+	txHash := tx.MsgTx.TxHash()
+	foundTxHash, err := transaction.WaitForTx(transaction.ConvertChainHashToBTC(&txHash))
+	mutex.Unlock(pkHash)
+	if transaction.IsTxRejectedError(err) {
+		r.Error(jerr.Getf(err, "transaction rejected (%s)", txHash.String()), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		r.Error(jerr.Getf(err, "error waiting for transaction (%s)", txHash.String()), http.StatusInternalServerError)
+		return
+	}
+	r.Write(foundTxHash.String())
+}
@@ -27,7 +27,7 @@ var followersRoute = web.Route{
 				r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 				return
 			}
-			key, err := db.GetKeyForUser(user.Id)
+			key, err := db.GetStore().GetKeyForUser(user.Id)
 			if err != nil {
 				r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 				return
@@ -41,7 +41,7 @@ var followersRoute = web.Route{
 			return
 		}
 		r.Helper["Profile"] = pf
-		followers, err := profile.GetFollowers(userPkHash, pkHash, offset)
+		followers, _, err := profile.GetFollowers(userPkHash, pkHash, offset)
 		if err != nil {
 			r.Error(jerr.Get("error setting followers for hash", err), http.StatusInternalServerError)
 			return
@@ -75,7 +75,7 @@ var followingRoute = web.Route{
 				r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 				return
 			}
-			key, err := db.GetKeyForUser(user.Id)
+			key, err := db.GetStore().GetKeyForUser(user.Id)
 			if err != nil {
 				r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 				return
@@ -89,7 +89,7 @@ var followingRoute = web.Route{
 			return
 		}
 		r.Helper["Profile"] = pf
-		following, err := profile.GetFollowing(userPkHash, pkHash, offset)
+		following, _, err := profile.GetFollowing(userPkHash, pkHash, offset)
 		if err != nil {
 			r.Error(jerr.Get("error setting following for hash", err), http.StatusInternalServerError)
 			return
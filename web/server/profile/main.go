@@ -18,9 +18,20 @@ func GetRoutes() []web.Route {
 		accountRoute,
 		settingsRoute,
 		settingsSubmitRoute,
+		webhooksRoute,
+		webhookDeliveriesRoute,
+		webhookAddSubmitRoute,
+		webhookDeleteSubmitRoute,
+		mutesRoute,
+		muteAddSubmitRoute,
+		muteDeleteSubmitRoute,
 		notificationsRoute,
 		topicsFollowingRoute,
 		coinsRoute,
+		coinsSelectSubmitRoute,
+		coinsConsolidateSubmitRoute,
+		coinsSendSubmitRoute,
+		transactionsRoute,
 		miniRoute,
 		newRoute,
 	}
@@ -0,0 +1,131 @@
+package profile
+
+import (
+	"bytes"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/auth"
+	"github.com/memocash/memo/app/bitcoin/memo"
+	"github.com/memocash/memo/app/bitcoin/wallet"
+	"github.com/memocash/memo/app/cache"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/res"
+	"net/http"
+)
+
+var mutesRoute = web.Route{
+	Pattern:    res.UrlProfileMutes,
+	NeedsLogin: true,
+	Handler: func(r *web.Response) {
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		pkHash, err := cache.GetUserPkHash(user.Id)
+		if err != nil {
+			r.Error(jerr.Get("error getting user pk hash", err), http.StatusInternalServerError)
+			return
+		}
+		muteList, err := db.GetMuteListForPkHash(pkHash)
+		if err != nil {
+			r.Error(jerr.Get("error getting mute list", err), http.StatusInternalServerError)
+			return
+		}
+		r.Helper["Mutes"] = muteList.Mutes
+		r.Helper["MaxMutes"] = db.MaxMutesPerUser
+		r.Helper["Address"] = r.Request.GetUrlParameter("address")
+		r.Helper["Topic"] = r.Request.GetUrlParameter("topic")
+		r.RenderTemplate(res.TmplProfileMutes)
+	},
+}
+
+// Either an address or a topic is muted. Only addresses can be blocked.
+var muteAddSubmitRoute = web.Route{
+	Pattern:     res.UrlProfileMuteAddSubmit,
+	NeedsLogin:  true,
+	CsrfProtect: true,
+	Handler: func(r *web.Response) {
+		addressString := r.Request.GetFormValue("address")
+		topic := r.Request.GetFormValue("topic")
+		block := r.Request.GetFormValueBool("block")
+		if (addressString == "") == (topic == "") {
+			r.Error(jerr.New("must enter an address or a topic"), http.StatusUnprocessableEntity)
+			return
+		}
+		if len(topic) > memo.MaxTagMessageSize {
+			r.Error(jerr.New("topic too long"), http.StatusUnprocessableEntity)
+			return
+		}
+		if topic != "" && block {
+			r.Error(jerr.New("topics can only be muted"), http.StatusUnprocessableEntity)
+			return
+		}
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		pkHash, err := cache.GetUserPkHash(user.Id)
+		if err != nil {
+			r.Error(jerr.Get("error getting user pk hash", err), http.StatusInternalServerError)
+			return
+		}
+		var mutePkHash []byte
+		if addressString != "" {
+			address := wallet.GetAddressFromString(addressString)
+			if address.GetEncoded() != addressString {
+				r.Error(jerr.New("error parsing address"), http.StatusUnprocessableEntity)
+				return
+			}
+			mutePkHash = address.GetScriptAddress()
+			if bytes.Equal(mutePkHash, pkHash) {
+				r.Error(jerr.New("cannot mute own address"), http.StatusUnprocessableEntity)
+				return
+			}
+		}
+		muteList, err := db.GetMuteListForPkHash(pkHash)
+		if err != nil {
+			r.Error(jerr.Get("error getting mute list", err), http.StatusInternalServerError)
+			return
+		}
+		if len(muteList.Mutes) >= db.MaxMutesPerUser {
+			r.Error(jerr.New("maximum mutes reached"), http.StatusUnprocessableEntity)
+			return
+		}
+		_, err = db.AddUserMute(user.Id, pkHash, mutePkHash, topic, block)
+		if err != nil {
+			r.Error(jerr.Get("error adding user mute", err), http.StatusInternalServerError)
+			return
+		}
+		err = cache.ClearMuteList(pkHash)
+		if err != nil {
+			r.Error(jerr.Get("error clearing mute list cache", err), http.StatusInternalServerError)
+			return
+		}
+	},
+}
+
+var muteDeleteSubmitRoute = web.Route{
+	Pattern:     res.UrlProfileMuteDeleteSubmit,
+	NeedsLogin:  true,
+	CsrfProtect: true,
+	Handler: func(r *web.Response) {
+		id := r.Request.GetFormValueUint("id")
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		userMute, err := db.DeleteUserMute(id, user.Id)
+		if err != nil {
+			r.Error(jerr.Get("error deleting user mute", err), http.StatusUnprocessableEntity)
+			return
+		}
+		err = cache.ClearMuteList(userMute.PkHash)
+		if err != nil {
+			r.Error(jerr.Get("error clearing mute list cache", err), http.StatusInternalServerError)
+			return
+		}
+	},
+}
@@ -26,7 +26,7 @@ var notificationsRoute = web.Route{
 			r.Error(jerr.Get("error getting address", err), http.StatusInternalServerError)
 			return
 		}
-		notifications, err := notify.GetNotificationsFeed(pkHash, uint(offset))
+		notifications, _, err := notify.GetNotificationsFeed(pkHash, uint(offset))
 		if err != nil {
 			r.Error(jerr.Get("error getting recent notifications for user", err), http.StatusInternalServerError)
 			return
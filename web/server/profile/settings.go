@@ -4,6 +4,7 @@ import (
 	"github.com/jchavannes/jgo/jerr"
 	"github.com/jchavannes/jgo/web"
 	"github.com/memocash/memo/app/auth"
+	"github.com/memocash/memo/app/bitcoin/fee"
 	"github.com/memocash/memo/app/cache"
 	"github.com/memocash/memo/app/db"
 	"github.com/memocash/memo/app/res"
@@ -26,6 +27,8 @@ var settingsSubmitRoute = web.Route{
 		defaultTip := r.Request.GetFormValueUint("defaultTip")
 		integrations := r.Request.GetFormValue("integrations")
 		theme := r.Request.GetFormValue("theme")
+		feePriority := r.Request.GetFormValue("feePriority")
+		hideUntrusted := r.Request.GetFormValueBool("hideUntrusted")
 		user, err := auth.GetSessionUser(r.Session.CookieId)
 		if err != nil {
 			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
@@ -43,7 +46,11 @@ var settingsSubmitRoute = web.Route{
 			r.Error(jerr.New("invalid default tip"), http.StatusUnprocessableEntity)
 			return
 		}
-		userSettings, err := db.SaveSettingsForUser(user.Id, defaultTip, integrations, theme)
+		if ! fee.IsValidPriority(feePriority) {
+			r.Error(jerr.New("invalid fee priority"), http.StatusUnprocessableEntity)
+			return
+		}
+		userSettings, err := db.SaveSettingsForUser(user.Id, defaultTip, integrations, theme, feePriority, hideUntrusted)
 		if err != nil {
 			r.Error(jerr.Get("error saving settings for user", err), http.StatusInternalServerError)
 			return
@@ -0,0 +1,34 @@
+package profile
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/auth"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/res"
+	"net/http"
+)
+
+var transactionsRoute = web.Route{
+	Pattern:    res.UrlProfileCoinsTransactions,
+	NeedsLogin: true,
+	Handler: func(r *web.Response) {
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		key, err := db.GetStore().GetKeyForUser(user.Id)
+		if err != nil {
+			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
+			return
+		}
+		outboundTxs, err := db.GetRecentOutboundTxs(key.PkHash)
+		if err != nil {
+			r.Error(jerr.Get("error getting recent outbound txs", err), http.StatusInternalServerError)
+			return
+		}
+		r.Helper["OutboundTxs"] = outboundTxs
+		r.RenderTemplate(res.TmplProfileTransactions)
+	},
+}
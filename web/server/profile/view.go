@@ -42,7 +42,7 @@ var viewRoute = web.Route{
 				r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 				return
 			}
-			key, err := db.GetKeyForUser(user.Id)
+			key, err := db.GetStore().GetKeyForUser(user.Id)
 			if err != nil {
 				r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 				return
@@ -93,6 +93,7 @@ var viewRoute = web.Route{
 		r.Helper["PageType"] = pageType
 		r.Helper["OffsetLink"] = fmt.Sprintf("%s/%s?p=%s", res.UrlProfileView, address.GetEncoded(), pageType)
 		r.Helper["Title"] = fmt.Sprintf("Memo - %s's Profile", pf.Name)
+		r.Helper["FeedPath"] = "profile/" + address.GetEncoded()
 		res.SetPageAndOffset(r, offset)
 		r.RenderTemplate(res.UrlProfileView)
 	},
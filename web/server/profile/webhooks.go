@@ -0,0 +1,162 @@
+package profile
+
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/auth"
+	"github.com/memocash/memo/app/cache"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/res"
+	"github.com/memocash/memo/app/util"
+	"github.com/memocash/memo/app/webhook"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+var webhooksRoute = web.Route{
+	Pattern:    res.UrlProfileWebhooks,
+	NeedsLogin: true,
+	Handler: func(r *web.Response) {
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		webhooks, err := db.GetWebhooksForUser(user.Id)
+		if err != nil {
+			r.Error(jerr.Get("error getting webhooks for user", err), http.StatusInternalServerError)
+			return
+		}
+		r.Helper["Webhooks"] = webhooks
+		r.Helper["WebhookEvents"] = db.WebhookEvents
+		r.Helper["MaxWebhooks"] = db.MaxWebhooksPerUser
+		r.Helper["SignatureHeader"] = webhook.HeaderSignature
+		r.Helper["TimestampHeader"] = webhook.HeaderTimestamp
+		r.RenderTemplate(res.TmplProfileWebhooks)
+	},
+}
+
+var webhookDeliveriesRoute = web.Route{
+	Pattern:    res.UrlProfileWebhookDeliveries,
+	NeedsLogin: true,
+	Handler: func(r *web.Response) {
+		id := r.Request.GetUrlParameterUInt("id")
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		userWebhook, err := db.GetWebhook(id)
+		if err != nil {
+			if db.IsRecordNotFoundError(err) {
+				r.Error(jerr.Get("webhook not found", err), http.StatusNotFound)
+				return
+			}
+			r.Error(jerr.Get("error getting webhook", err), http.StatusInternalServerError)
+			return
+		}
+		if userWebhook.UserId != user.Id {
+			r.Error(jerr.New("webhook not found"), http.StatusNotFound)
+			return
+		}
+		deliveries, err := db.GetRecentWebhookDeliveries(userWebhook.Id)
+		if err != nil {
+			r.Error(jerr.Get("error getting webhook deliveries", err), http.StatusInternalServerError)
+			return
+		}
+		r.Helper["Webhook"] = userWebhook
+		r.Helper["Deliveries"] = deliveries
+		r.RenderTemplate(res.TmplProfileWebhookDeliveries)
+	},
+}
+
+var webhookAddSubmitRoute = web.Route{
+	Pattern:     res.UrlProfileWebhookAddSubmit,
+	NeedsLogin:  true,
+	CsrfProtect: true,
+	Handler: func(r *web.Response) {
+		webhookUrl := r.Request.GetFormValue("url")
+		events := r.Request.GetFormValueSlice("events")
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		if ! isValidWebhookUrl(webhookUrl) {
+			r.Error(jerr.New("invalid webhook url"), http.StatusUnprocessableEntity)
+			return
+		}
+		if len(events) == 0 {
+			r.Error(jerr.New("no webhook events selected"), http.StatusUnprocessableEntity)
+			return
+		}
+		for _, event := range events {
+			if ! db.IsValidWebhookEvent(event) {
+				r.Error(jerr.Newf("invalid webhook event: %s", event), http.StatusUnprocessableEntity)
+				return
+			}
+		}
+		webhooks, err := db.GetWebhooksForUser(user.Id)
+		if err != nil {
+			r.Error(jerr.Get("error getting webhooks for user", err), http.StatusInternalServerError)
+			return
+		}
+		if len(webhooks) >= db.MaxWebhooksPerUser {
+			r.Error(jerr.New("maximum webhooks reached"), http.StatusUnprocessableEntity)
+			return
+		}
+		pkHash, err := cache.GetUserPkHash(user.Id)
+		if err != nil {
+			r.Error(jerr.Get("error getting user pk hash", err), http.StatusInternalServerError)
+			return
+		}
+		secret, err := webhook.GenerateSecret()
+		if err != nil {
+			r.Error(jerr.Get("error generating webhook secret", err), http.StatusInternalServerError)
+			return
+		}
+		_, err = db.AddWebhook(user.Id, pkHash, webhookUrl, secret, events)
+		if err != nil {
+			r.Error(jerr.Get("error adding webhook", err), http.StatusInternalServerError)
+			return
+		}
+	},
+}
+
+var webhookDeleteSubmitRoute = web.Route{
+	Pattern:     res.UrlProfileWebhookDeleteSubmit,
+	NeedsLogin:  true,
+	CsrfProtect: true,
+	Handler: func(r *web.Response) {
+		id := r.Request.GetFormValueUint("id")
+		user, err := auth.GetSessionUser(r.Session.CookieId)
+		if err != nil {
+			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+			return
+		}
+		err = db.DeleteWebhook(id, user.Id)
+		if err != nil {
+			r.Error(jerr.Get("error deleting webhook", err), http.StatusUnprocessableEntity)
+			return
+		}
+	},
+}
+
+func isValidWebhookUrl(webhookUrl string) bool {
+	if len(webhookUrl) == 0 || len(webhookUrl) > 500 {
+		return false
+	}
+	parsedUrl, err := url.Parse(webhookUrl)
+	if err != nil {
+		return false
+	}
+	if parsedUrl.Scheme != "https" || parsedUrl.Hostname() == "" || parsedUrl.Hostname() == "localhost" {
+		return false
+	}
+	// Host names are checked again when delivering since they can resolve to anything.
+	if ip := net.ParseIP(parsedUrl.Hostname()); ip != nil && ! util.IsPublicIp(ip) {
+		return false
+	}
+	return true
+}
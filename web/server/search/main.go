@@ -0,0 +1,9 @@
+package search
+
+import "github.com/jchavannes/jgo/web"
+
+func GetRoutes() []web.Route {
+	return []web.Route{
+		searchRoute,
+	}
+}
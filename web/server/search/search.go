@@ -0,0 +1,205 @@
+package search
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/auth"
+	"github.com/memocash/memo/app/cache"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/db/view"
+	"github.com/memocash/memo/app/html-parser"
+	"github.com/memocash/memo/app/metric"
+	"github.com/memocash/memo/app/profile"
+	"github.com/memocash/memo/app/res"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	TypeAll      = "all"
+	TypePosts    = "posts"
+	TypeProfiles = "profiles"
+	TypeTopics   = "topics"
+)
+
+const (
+	pageSize    = 25
+	previewSize = 5
+)
+
+var searchRoute = web.Route{
+	Pattern: res.UrlSearch,
+	Handler: func(r *web.Response) {
+		r.Helper["Nav"] = "search"
+		offset := r.Request.GetUrlParameterInt("offset")
+		searchString := html_parser.EscapeWithEmojis(r.Request.GetUrlParameter("s"))
+		searchType := r.Request.GetUrlParameter("type")
+		switch searchType {
+		case TypePosts, TypeProfiles, TypeTopics:
+		default:
+			searchType = TypeAll
+		}
+		var userPkHash []byte
+		var userId uint
+		if auth.IsLoggedIn(r.Session.CookieId) {
+			user, err := auth.GetSessionUser(r.Session.CookieId)
+			if err != nil {
+				r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+				return
+			}
+			userPkHash, err = cache.GetUserPkHash(user.Id)
+			if err != nil {
+				r.Error(jerr.Get("error getting pk hash from cache", err), http.StatusInternalServerError)
+				return
+			}
+			userId = user.Id
+		}
+		var limit uint = pageSize
+		if searchType == TypeAll {
+			offset = 0
+			limit = previewSize
+		}
+		var posts []*profile.Post
+		var profiles []*profile.Profile
+		var topics []*view.Topic
+		if searchString != "" {
+			var err error
+			if searchType == TypeAll || searchType == TypePosts {
+				posts, err = getPosts(searchString, userPkHash, userId, uint(offset), limit)
+				if err != nil {
+					r.Error(jerr.Get("error getting posts", err), http.StatusInternalServerError)
+					return
+				}
+			}
+			if searchType == TypeAll || searchType == TypeProfiles {
+				profiles, err = getProfiles(searchString, userPkHash, uint(offset), limit)
+				if err != nil {
+					r.Error(jerr.Get("error getting profiles", err), http.StatusInternalServerError)
+					return
+				}
+			}
+			if searchType == TypeAll || searchType == TypeTopics {
+				topics, err = getTopics(searchString, uint(offset), limit)
+				if err != nil {
+					r.Error(jerr.Get("error getting topics", err), http.StatusInternalServerError)
+					return
+				}
+			}
+			go func() {
+				metric.AddMemoPostSearch(searchString, res.UrlSearch)
+			}()
+		}
+		res.SetPageAndOffset(r, offset)
+		r.Helper["Title"] = "Memo - Search"
+		r.Helper["SearchString"] = searchString
+		r.Helper["SearchType"] = searchType
+		searchLink := fmt.Sprintf("%s?s=%s", strings.TrimLeft(res.UrlSearch, "/"), url.QueryEscape(searchString))
+		r.Helper["SearchLink"] = searchLink
+		r.Helper["OffsetLink"] = fmt.Sprintf("%s&type=%s", searchLink, searchType)
+		r.Helper["Posts"] = posts
+		r.Helper["Profiles"] = profiles
+		r.Helper["Topics"] = topics
+		r.Render()
+	},
+}
+
+func getSearchKeys(searchString string, itemTypes []string, offset uint, limit uint) ([][]byte, error) {
+	searchResults, err := db.Search(searchString, itemTypes, offset, limit)
+	if err != nil {
+		return nil, jerr.Get("error searching", err)
+	}
+	var keys [][]byte
+	for _, searchResult := range searchResults {
+		keys = append(keys, searchResult.ItemKey)
+	}
+	return keys, nil
+}
+
+func getPosts(searchString string, userPkHash []byte, userId uint, offset uint, limit uint) ([]*profile.Post, error) {
+	txHashes, err := getSearchKeys(searchString, []string{db.SearchTypePost}, offset, limit)
+	if err != nil {
+		return nil, jerr.Get("error getting post tx hashes", err)
+	}
+	if len(txHashes) == 0 {
+		return nil, nil
+	}
+	unsortedPosts, err := profile.GetPostsByTxHashes(txHashes, userPkHash)
+	if err != nil {
+		return nil, jerr.Get("error getting posts by tx hashes", err)
+	}
+	var posts []*profile.Post
+	for _, txHash := range txHashes {
+		for _, post := range unsortedPosts {
+			if bytes.Equal(post.Memo.TxHash, txHash) {
+				posts = append(posts, post)
+			}
+		}
+	}
+	err = profile.AttachProfilePicsToPosts(posts)
+	if err != nil {
+		return nil, jerr.Get("error attaching profile pics to posts", err)
+	}
+	err = profile.AttachParentToPosts(posts)
+	if err != nil {
+		return nil, jerr.Get("error attaching parent to posts", err)
+	}
+	err = profile.AttachLikesToPosts(posts)
+	if err != nil {
+		return nil, jerr.Get("error attaching likes to posts", err)
+	}
+	err = profile.AttachPollsToPosts(posts)
+	if err != nil {
+		return nil, jerr.Get("error attaching polls to posts", err)
+	}
+	err = profile.SetShowMediaForPosts(posts, userId)
+	if err != nil {
+		return nil, jerr.Get("error setting show media for posts", err)
+	}
+	return posts, nil
+}
+
+func getProfiles(searchString string, userPkHash []byte, offset uint, limit uint) ([]*profile.Profile, error) {
+	pkHashes, err := getSearchKeys(searchString, []string{db.SearchTypeName, db.SearchTypeProfile}, offset, limit)
+	if err != nil {
+		return nil, jerr.Get("error getting profile pk hashes", err)
+	}
+	var profiles []*profile.Profile
+	for _, pkHash := range pkHashes {
+		pf, err := profile.GetProfile(pkHash, userPkHash)
+		if err != nil {
+			return nil, jerr.Get("error getting profile", err)
+		}
+		profiles = append(profiles, pf)
+	}
+	return profiles, nil
+}
+
+func getTopics(searchString string, offset uint, limit uint) ([]*view.Topic, error) {
+	topicKeys, err := getSearchKeys(searchString, []string{db.SearchTypeTopic}, offset, limit)
+	if err != nil {
+		return nil, jerr.Get("error getting topic names", err)
+	}
+	if len(topicKeys) == 0 {
+		return nil, nil
+	}
+	var topicNames []string
+	for _, topicKey := range topicKeys {
+		topicNames = append(topicNames, string(topicKey))
+	}
+	unsortedTopics, err := db.GetTopicInfoFromPosts(topicNames...)
+	if err != nil {
+		return nil, jerr.Get("error getting topic info", err)
+	}
+	var topics []*view.Topic
+	for _, topicName := range topicNames {
+		for _, topic := range unsortedTopics {
+			if topic.Name == topicName {
+				topics = append(topics, topic)
+			}
+		}
+	}
+	return topics, nil
+}
@@ -26,26 +26,26 @@ var followSubmitRoute = web.Route{
 			r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 			return
 		}
-		key, err := db.GetKeyForUser(user.Id)
+		key, err := db.GetStore().GetKeyForUser(user.Id)
 		if err != nil {
 			r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 			return
 		}
 
-		privateKey, err := key.GetPrivateKey(password)
+		privateKeys, err := key.GetSpendingKeys(password)
 		if err != nil {
-			r.Error(jerr.Get("error getting private key", err), http.StatusUnauthorized)
+			r.Error(jerr.Get("error getting spending keys", err), http.StatusUnauthorized)
 			return
 		}
 
-		pkHash := privateKey.GetPublicKey().GetAddress().GetScriptAddress()
+		pkHash := privateKeys[0].GetPublicKey().GetAddress().GetScriptAddress()
 		mutex.Lock(pkHash)
 
 		var tx *memo.Tx
 		if unfollow {
-			tx, err = build.UnfollowTopic(topicName, privateKey)
+			tx, err = build.UnfollowTopic(topicName, privateKeys)
 		} else {
-			tx, err = build.FollowTopic(topicName, privateKey)
+			tx, err = build.FollowTopic(topicName, privateKeys)
 		}
 		if err != nil {
 			var statusCode = http.StatusInternalServerError
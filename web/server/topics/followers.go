@@ -20,6 +20,9 @@ var followersRoute = web.Route{
 		preHandler(r)
 		topicRaw := r.Request.GetUrlNamedQueryVariable(urlTopicName.Id)
 		unescaped, err := url.QueryUnescape(topicRaw)
+		if ! checkTopicVisible(r, unescaped) {
+			return
+		}
 		offset := r.Request.GetUrlParameterInt("offset")
 		var userPkHash []byte
 		if auth.IsLoggedIn(r.Session.CookieId) {
@@ -28,7 +31,7 @@ var followersRoute = web.Route{
 				r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 				return
 			}
-			key, err := db.GetKeyForUser(user.Id)
+			key, err := db.GetStore().GetKeyForUser(user.Id)
 			if err != nil {
 				r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 				return
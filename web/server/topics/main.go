@@ -1,6 +1,12 @@
 package topics
 
-import "github.com/jchavannes/jgo/web"
+import (
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/profile"
+	"github.com/memocash/memo/app/res"
+	"net/http"
+)
 
 var urlTopicName = web.UrlParam{
 	Id:   "topic",
@@ -28,9 +34,25 @@ func GetRoutes() []web.Route {
 		mostPostsRoute,
 		followersRoute,
 		threadsRoute,
+		rankedRoute,
 	}
 }
 
 func preHandler(r *web.Response) {
 	r.Helper["Nav"] = "topics"
 }
+
+// Hidden topics render a notice instead of their posts, returns false when the response has been written.
+func checkTopicVisible(r *web.Response, topic string) bool {
+	err := profile.CheckTopicHidden(topic)
+	if err == nil {
+		return true
+	}
+	if profile.IsTopicHiddenError(err) {
+		r.SetResponseCode(http.StatusUnavailableForLegalReasons)
+		r.RenderTemplate(res.TmplTopicHidden)
+		return false
+	}
+	r.Error(jerr.Get("error checking if topic hidden", err), http.StatusInternalServerError)
+	return false
+}
@@ -26,7 +26,7 @@ var postsMoreRoute = web.Route{
 				r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 				return
 			}
-			key, err := db.GetKeyForUser(user.Id)
+			key, err := db.GetStore().GetKeyForUser(user.Id)
 			if err != nil {
 				r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 				return
@@ -108,7 +108,7 @@ var postAjaxRoute = web.Route{
 				r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 				return
 			}
-			key, err := db.GetKeyForUser(user.Id)
+			key, err := db.GetStore().GetKeyForUser(user.Id)
 			if err != nil {
 				r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 				return
@@ -118,9 +118,20 @@ var postAjaxRoute = web.Route{
 		}
 		post, err := profile.GetPostByTxHashWithReplies(txHash.CloneBytes(), pkHash, uint(offset))
 		if err != nil {
+			if profile.IsPostHiddenError(err) {
+				return
+			}
 			r.Error(jerr.Get("error getting post", err), http.StatusInternalServerError)
 			return
 		}
+		muteList, err := profile.GetMuteList(pkHash)
+		if err != nil {
+			r.Error(jerr.Get("error getting mute list", err), http.StatusInternalServerError)
+			return
+		}
+		if muteList.IsPkHashMuted(post.Memo.PkHash) {
+			return
+		}
 		err = profile.AttachParentToPosts([]*profile.Post{post})
 		if err != nil {
 			r.Error(jerr.Get("error attaching parent to post", err), http.StatusInternalServerError)
@@ -0,0 +1,99 @@
+package topics
+
+import (
+	"fmt"
+	"github.com/jchavannes/jgo/jerr"
+	"github.com/jchavannes/jgo/web"
+	"github.com/memocash/memo/app/auth"
+	"github.com/memocash/memo/app/cache"
+	"github.com/memocash/memo/app/db"
+	"github.com/memocash/memo/app/html-parser"
+	"github.com/memocash/memo/app/obj/rank"
+	"github.com/memocash/memo/app/profile"
+	"github.com/memocash/memo/app/res"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Ranking can be set with ?sort=likes|tips|discussion|personal, defaults to likes.
+var rankedRoute = web.Route{
+	Pattern: res.UrlTopicRanked + "/" + urlTopicName.UrlPart(),
+	Handler: func(r *web.Response) {
+		preHandler(r)
+		topicRaw := r.Request.GetUrlNamedQueryVariable(urlTopicName.Id)
+		unescaped, err := url.QueryUnescape(topicRaw)
+		if err != nil {
+			r.Error(jerr.Get("error unescaping topic", err), http.StatusUnprocessableEntity)
+			return
+		}
+		if ! checkTopicVisible(r, unescaped) {
+			return
+		}
+		safeTopic := html_parser.EscapeWithEmojis(unescaped)
+		urlEncodedTopic := url.QueryEscape(safeTopic)
+		offset := r.Request.GetUrlParameterInt("offset")
+		ranker := rank.GetRanker(r.Request.GetUrlParameter("sort"))
+		var userPkHash []byte
+		var userId uint
+		if auth.IsLoggedIn(r.Session.CookieId) {
+			user, err := auth.GetSessionUser(r.Session.CookieId)
+			if err != nil {
+				r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
+				return
+			}
+			key, err := db.GetStore().GetKeyForUser(user.Id)
+			if err != nil {
+				r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
+				return
+			}
+			userPkHash = key.PkHash
+			userId = user.Id
+		}
+		posts, err := profile.GetRankedPostsForTopic(unescaped, userPkHash, uint(offset), ranker)
+		if err != nil {
+			r.Error(jerr.Get("error getting ranked posts for topic", err), http.StatusInternalServerError)
+			return
+		}
+		err = profile.AttachParentToPosts(posts)
+		if err != nil {
+			r.Error(jerr.Get("error attaching parent to posts", err), http.StatusInternalServerError)
+			return
+		}
+		err = profile.AttachLikesToPosts(posts)
+		if err != nil {
+			r.Error(jerr.Get("error attaching likes to posts", err), http.StatusInternalServerError)
+			return
+		}
+		err = profile.AttachPollsToPosts(posts)
+		if err != nil {
+			r.Error(jerr.Get("error attaching polls to posts", err), http.StatusInternalServerError)
+			return
+		}
+		if len(userPkHash) > 0 {
+			err = profile.AttachReputationToPosts(posts)
+			if err != nil {
+				r.Error(jerr.Get("error attaching reputation to posts", err), http.StatusInternalServerError)
+				return
+			}
+		}
+		err = profile.SetShowMediaForPosts(posts, userId)
+		if err != nil {
+			r.Error(jerr.Get("error setting show media for posts", err), http.StatusInternalServerError)
+			return
+		}
+		lastTopicList, err := cache.GetLastTopicList(r.Session.CookieId)
+		if err != nil {
+			jerr.Get("error getting last topic list", err).Print()
+		}
+		r.Helper["LastTopicList"] = lastTopicList
+		r.Helper["Posts"] = posts
+		r.Helper["Title"] = "Memo - Ranked Topic Posts - " + safeTopic
+		r.Helper["Topic"] = safeTopic
+		r.Helper["TopicEncoded"] = urlEncodedTopic
+		r.Helper["Sort"] = ranker.Name
+		r.Helper["OffsetLink"] = fmt.Sprintf("%s/%s?sort=%s", strings.TrimLeft(res.UrlTopicRanked, "/"), urlEncodedTopic, ranker.Name)
+		res.SetPageAndOffset(r, offset)
+		r.RenderTemplate(res.UrlTopicRanked)
+	},
+}
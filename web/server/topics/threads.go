@@ -23,10 +23,13 @@ var threadsRoute = web.Route{
 			r.Error(jerr.Get("error unescaping topic", err), http.StatusUnprocessableEntity)
 			return
 		}
+		if ! checkTopicVisible(r, unescaped) {
+			return
+		}
 		safeTopic := html_parser.EscapeWithEmojis(unescaped)
 		urlEncodedTopic := url.QueryEscape(safeTopic)
 		offset := r.Request.GetUrlParameterInt("offset")
-		threads, err := db.GetThreads(uint(offset), unescaped)
+		threads, err := db.GetStore().GetThreads(uint(offset), unescaped)
 		if err != nil {
 			r.Error(jerr.Get("error getting threads from db", err), http.StatusInternalServerError)
 			return
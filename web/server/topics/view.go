@@ -22,6 +22,9 @@ var viewRoute = web.Route{
 			r.Error(jerr.Get("error unescaping topic", err), http.StatusUnprocessableEntity)
 			return
 		}
+		if ! checkTopicVisible(r, unescaped) {
+			return
+		}
 		var userPkHash []byte
 		var userId uint
 		if auth.IsLoggedIn(r.Session.CookieId) {
@@ -30,7 +33,7 @@ var viewRoute = web.Route{
 				r.Error(jerr.Get("error getting session user", err), http.StatusInternalServerError)
 				return
 			}
-			key, err := db.GetKeyForUser(user.Id)
+			key, err := db.GetStore().GetKeyForUser(user.Id)
 			if err != nil {
 				r.Error(jerr.Get("error getting key for user", err), http.StatusInternalServerError)
 				return
@@ -38,7 +41,7 @@ var viewRoute = web.Route{
 			userPkHash = key.PkHash
 			userId = user.Id
 		}
-		topicPosts, err := profile.GetPostsForTopic(unescaped, userPkHash, 0)
+		topicPosts, _, err := profile.GetPostsForTopic(unescaped, userPkHash, 0)
 		if err != nil {
 			r.Error(jerr.Get("error getting topic posts from db", err), http.StatusInternalServerError)
 			return
@@ -118,6 +121,7 @@ var viewRoute = web.Route{
 		r.Helper["Title"] = "Memo - Topic - " + topicPosts[0].Memo.Topic
 		r.Helper["Topic"] = topicPosts[0].Memo.Topic
 		r.Helper["TopicEncoded"] = topicPosts[0].Memo.GetUrlEncodedTopic()
+		r.Helper["FeedPath"] = "topic/" + topicPosts[0].Memo.GetUrlEncodedTopic()
 		r.Helper["Posts"] = topicPosts
 		r.Helper["FollowerCount"] = followerCount
 		r.Helper["FirstPostId"] = topicPosts[0].Memo.Id